@@ -0,0 +1,7 @@
+//go:build !windows
+
+package main
+
+// enableVirtualTerminal is a no-op outside of Windows, where terminals
+// process ANSI escape sequences natively.
+func enableVirtualTerminal() {}
@@ -4,16 +4,60 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/urfave/cli/v2"
+	"github.com/wayneashleyberry/gh-arc/pkg/adhoc"
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
+	"github.com/wayneashleyberry/gh-arc/pkg/badge"
+	"github.com/wayneashleyberry/gh-arc/pkg/baseline"
+	"github.com/wayneashleyberry/gh-arc/pkg/check"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/completion"
+	"github.com/wayneashleyberry/gh-arc/pkg/config"
+	"github.com/wayneashleyberry/gh-arc/pkg/depgraph"
+	"github.com/wayneashleyberry/gh-arc/pkg/diff"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/freshness"
+	"github.com/wayneashleyberry/gh-arc/pkg/ghext"
+	"github.com/wayneashleyberry/gh-arc/pkg/gitprobe"
 	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/issues"
+	"github.com/wayneashleyberry/gh-arc/pkg/notify"
+	"github.com/wayneashleyberry/gh-arc/pkg/org"
+	"github.com/wayneashleyberry/gh-arc/pkg/owners"
+	"github.com/wayneashleyberry/gh-arc/pkg/policy"
+	"github.com/wayneashleyberry/gh-arc/pkg/prcomment"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+	"github.com/wayneashleyberry/gh-arc/pkg/remote"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/sbom"
+	"github.com/wayneashleyberry/gh-arc/pkg/serve"
+	"github.com/wayneashleyberry/gh-arc/pkg/telemetry"
+	"github.com/wayneashleyberry/gh-arc/pkg/tui"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+	"github.com/wayneashleyberry/gh-arc/pkg/watch"
+	"golang.org/x/term"
 )
 
+// setDefaultLogger logs to stderr, so that logs never mix with findings
+// written to stdout.
 func setDefaultLogger(level slog.Leveler) {
-	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
 		Level: level,
 	})
 
@@ -22,62 +66,1993 @@ func setDefaultLogger(level slog.Leveler) {
 	slog.SetDefault(logger)
 }
 
+// goModFlags returns the flags choosing which go modules are read, shared by
+// every command that reads go.mod files.
+func goModFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "indirect",
+			Usage: "Include indirect dependencies",
+		},
+		&cli.BoolFlag{
+			Name:  "vendor",
+			Usage: "Read vendor/modules.txt instead of go.mod where present",
+		},
+		&cli.BoolFlag{
+			Name:  "module-proxy",
+			Usage: "Resolve the repository of Go modules through the origin recorded by $GOPROXY",
+		},
+	}
+}
+
+// goModOptions reads the flags returned by goModFlags into opts.
+func goModOptions(c *cli.Context, opts *gomod.Options) {
+	opts.Indirect = c.Bool("indirect")
+	opts.Vendor = c.Bool("vendor")
+	opts.ModuleProxy = c.Bool("module-proxy")
+}
+
+// lookupFlags returns the flags controlling how repositories are looked up,
+// shared by every command that looks them up. checkOptions reads them.
+func lookupFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "stale-after",
+			Usage: "Report repositories without a push for longer than this duration, e.g. 8760h (disabled by default)",
+		},
+		&cli.StringFlag{
+			Name:  "provider",
+			Value: gomod.ProviderAuto,
+			Usage: "Repository metadata provider (" + strings.Join(gomod.Providers, ", ") + ")",
+		},
+		&cli.IntFlag{
+			Name:  "max-api-calls",
+			Usage: "Maximum number of repositories to look up, direct dependencies first (0 for no limit)",
+		},
+	}
+}
+
+// checkFlags returns the flags shared by every command that checks repositories.
+func checkFlags() []cli.Flag {
+	return append(lookupFlags(), []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "quiet",
+			Usage: "Print nothing and only set the exit status",
+		},
+		&cli.BoolFlag{
+			Name:  "summary",
+			Usage: "Print a one-line summary instead of every finding",
+		},
+		&cli.StringFlag{
+			Name:  "comment-pr",
+			Usage: "Publish findings as a single comment on this pull request, edited on every run, or \"" + prcomment.Auto + "\" to detect it in GitHub Actions",
+		},
+		&cli.StringFlag{
+			Name:  "notify-webhook",
+			Usage: "Post a summary of findings to this webhook URL, such as a Slack incoming webhook, when there are any",
+		},
+		&cli.StringFlag{
+			Name:  "notify-format",
+			Value: notify.FormatAuto,
+			Usage: "Webhook payload format (" + strings.Join(notify.Formats, ", ") + "), auto uses slack for Slack webhook URLs",
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Value: render.FormatText,
+			Usage: "Output format (" + strings.Join(render.Formats, ", ") + ")",
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Value: finding.OrderFile,
+			Usage: "Order of findings (" + strings.Join(finding.Orders, ", ") + ")",
+		},
+		&cli.StringFlag{
+			Name:  "group-by",
+			Usage: "Group text and table output (" + strings.Join(finding.Groupings, ", ") + ")",
+		},
+		&cli.BoolFlag{
+			Name:  "codeowners",
+			Usage: "Attribute findings to the code owners of their file, implied by --group-by team",
+		},
+		&cli.BoolFlag{
+			Name:  "automation",
+			Usage: "Note whether Dependabot or Renovate keeps each finding's dependency up to date",
+		},
+		&cli.IntFlag{
+			Name:        "width",
+			Usage:       "Maximum line width of table output",
+			DefaultText: "terminal width",
+		},
+		&cli.BoolFlag{
+			Name:  "wide",
+			Usage: "Never truncate table columns",
+		},
+		&cli.StringFlag{
+			Name:  "path-style",
+			Value: files.PathStyleNative,
+			Usage: "How file paths are printed (" + strings.Join(files.PathStyles, ", ") + ")",
+		},
+		&cli.BoolFlag{
+			Name:  "no-self-check",
+			Usage: "Do not warn when the repository being scanned is archived or its default branch changed",
+		},
+		&cli.StringSliceFlag{
+			Name:  "ignore-archived-owners",
+			Usage: "Repository owners whose findings are informational and never fail the run, e.g. an org that moved its repositories",
+		},
+		&cli.IntFlag{
+			Name:  "suggest-forks",
+			Usage: "Rank up to this many forks of each archived repository as replacements, by activity, pull request responsiveness, releases and stars",
+		},
+		&cli.BoolFlag{
+			Name:  "details",
+			Usage: "Show open issues, the latest release and the default branch of unhealthy repositories, one more API request each",
+		},
+		&cli.BoolFlag{
+			Name:  "resolve-mirrors",
+			Usage: "Check the upstream of read-only mirrors hosted on GitHub instead of the mirror, one more API request each",
+		},
+		&cli.BoolFlag{
+			Name:  "advisories",
+			Usage: "Look up unpatched security advisories affecting the required version of archived dependencies, which makes them errors",
+		},
+		&cli.BoolFlag{
+			Name:  "licenses",
+			Usage: "Also report dependencies without a license, with a disallowed license or with a copyleft license",
+		},
+		&cli.StringSliceFlag{
+			Name:  "disallow-license",
+			Usage: "SPDX identifier, or pattern such as 'AGPL-*', of a license that fails the run with --licenses, may be repeated",
+		},
+		&cli.StringFlag{
+			Name:  "baseline",
+			Usage: "Baseline file of acknowledged findings, which are reported but never fail the run",
+		},
+		&cli.StringFlag{
+			Name:  "write-baseline",
+			Usage: "Write every finding to this baseline file and exit successfully",
+		},
+		&cli.StringFlag{
+			Name:  "fail-on",
+			Value: policy.FailOnAny,
+			Usage: "Which findings fail the run (" + strings.Join(policy.FailOnValues, ", ") + ")",
+		},
+		&cli.IntFlag{
+			Name:  "max-archived",
+			Usage: "Number of failing findings tolerated before the run fails",
+		},
+	}...)
+}
+
+// checkPolicy reads the exit code policy flags returned by checkFlags.
+func checkPolicy(c *cli.Context) (policy.Policy, error) {
+	p := policy.Policy{
+		FailOn:      c.String("fail-on"),
+		MaxFindings: c.Int("max-archived"),
+	}
+
+	if err := p.Validate(); err != nil {
+		return policy.Policy{}, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	return p, nil
+}
+
+// loadConfig reads the configuration file and applies the global timeout
+// flags to hosts without timeouts of their own. A missing configuration file
+// is only an error if its path was given explicitly.
+func loadConfig(c *cli.Context) (config.Config, error) {
+	cfg, err := config.Load(c.String("config"))
+	if err != nil && (c.IsSet("config") || !errors.Is(err, fs.ErrNotExist)) {
+		return config.Config{}, err
+	}
+
+	timeouts := cfg.Timeouts
+	if timeouts == nil {
+		timeouts = config.Timeouts{}
+	}
+
+	fallback := timeouts[config.DefaultHost]
+
+	if c.IsSet("connect-timeout") {
+		fallback.Connect = c.Duration("connect-timeout")
+	}
+
+	if c.IsSet("timeout") {
+		fallback.Read = c.Duration("timeout")
+	}
+
+	timeouts[config.DefaultHost] = fallback
+	cfg.Timeouts = timeouts
+
+	return cfg, nil
+}
+
+// goVersionPolicy reads the supported range of Go releases from the
+// --min-go-version and --max-go-version flags, falling back to the
+// configuration file.
+func goVersionPolicy(c *cli.Context) (gomod.GoVersionPolicy, error) {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return gomod.GoVersionPolicy{}, err
+	}
+
+	p := gomod.GoVersionPolicy{Min: cfg.GoVersion.Min, Max: cfg.GoVersion.Max}
+
+	if c.IsSet("min-go-version") {
+		p.Min = c.String("min-go-version")
+	}
+
+	if c.IsSet("max-go-version") {
+		p.Max = c.String("max-go-version")
+	}
+
+	if !p.Enabled() {
+		return gomod.GoVersionPolicy{}, errors.New("--check-go-version requires --min-go-version, --max-go-version or go_version in the configuration file")
+	}
+
+	return p, nil
+}
+
+// licensePolicy reads the --licenses and --disallow-license flags, falling
+// back to the disallowed licenses of the configuration file.
+func licensePolicy(c *cli.Context, cfg config.Config) gomod.LicensePolicy {
+	disallowed := cfg.Licenses.Disallowed
+	if c.IsSet("disallow-license") {
+		disallowed = c.StringSlice("disallow-license")
+	}
+
+	return gomod.LicensePolicy{Enabled: c.Bool("licenses"), Disallowed: disallowed}
+}
+
+// progressWriter returns where scan progress is shown for the given output
+// format, or nil when the run is not interactive.
+func progressWriter(format string) io.Writer {
+	if !progress.Interactive(format) {
+		return nil
+	}
+
+	return os.Stderr
+}
+
+// checkProgressWriter returns where the progress of a check is shown, like
+// progressWriter for --format, or nil when --quiet is set.
+func checkProgressWriter(c *cli.Context) io.Writer {
+	if c.Bool("quiet") {
+		return nil
+	}
+
+	return progressWriter(c.String("format"))
+}
+
+// terminalWriter returns the file descriptor of the writer findings are
+// written to, stdout or the file given with --output, if it is a terminal.
+func terminalWriter(c *cli.Context) (int, bool) {
+	f, ok := c.App.Writer.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return 0, false
+	}
+
+	return int(f.Fd()), true
+}
+
+// findingsWriter returns where findings are rendered: nowhere with --quiet
+// or --summary, and stdout or the file given with --output otherwise.
+func findingsWriter(c *cli.Context) io.Writer {
+	if c.Bool("quiet") || c.Bool("summary") {
+		return io.Discard
+	}
+
+	return c.App.Writer
+}
+
+// useColor reports whether text output should be colored: only on terminals,
+// and never with --no-color or the NO_COLOR environment variable set.
+func useColor(c *cli.Context) bool {
+	_, tty := terminalWriter(c)
+
+	return !c.Bool("no-color") && os.Getenv("NO_COLOR") == "" && tty
+}
+
+// scanScope reads the global flags limiting where manifests are searched for.
+func scanScope(c *cli.Context) files.Scope {
+	return files.Scope{
+		Paths:    c.StringSlice("path"),
+		Exclude:  c.StringSlice("exclude"),
+		NoIgnore: c.Bool("no-ignore"),
+	}
+}
+
+// selfCheckWriter returns where warnings about the repository being scanned
+// are written, or nil when --no-self-check or --quiet is set.
+func selfCheckWriter(c *cli.Context) io.Writer {
+	if c.Bool("no-self-check") || c.Bool("quiet") {
+		return nil
+	}
+
+	return c.App.ErrWriter
+}
+
+// tableWidth returns the maximum line width of table output: --width if set,
+// the terminal width if stdout is a terminal, and no limit with --wide or
+// when output is redirected.
+func tableWidth(c *cli.Context) int {
+	if c.Bool("wide") {
+		return 0
+	}
+
+	if c.IsSet("width") {
+		return c.Int("width")
+	}
+
+	fd, tty := terminalWriter(c)
+	if !tty {
+		return 0
+	}
+
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// checkOptions reads the flags returned by checkFlags.
+func checkOptions(c *cli.Context) (gomod.Options, error) {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return gomod.Options{}, err
+	}
+
+	correlationID := c.String("correlation-id")
+	if correlationID == "" {
+		correlationID = client.NewCorrelationID()
+	}
+
+	slog.DebugContext(c.Context, "correlation id", slog.String("id", correlationID))
+
+	var b baseline.Baseline
+
+	if path := c.String("baseline"); path != "" {
+		b, err = baseline.Load(path)
+		if err != nil {
+			return gomod.Options{}, err
+		}
+	}
+
+	var metadataCache client.Cache
+
+	switch u := c.String("cache-url"); {
+	case u != "":
+		metadataCache, err = client.NewHTTPCache(cfg.Timeouts.HTTPClient(u), u, c.String("cache-token"))
+		if err != nil {
+			return gomod.Options{}, err
+		}
+	case !c.Bool("no-cache"):
+		cacheDir, err := files.CacheDir()
+		if err != nil {
+			// Without a cache directory metadata is only cached in memory.
+			slog.DebugContext(c.Context, err.Error())
+
+			break
+		}
+
+		metadataCache = client.NewDiskCache(filepath.Join(cacheDir, "repos"))
+	}
+
+	return gomod.Options{
+		Format:               c.String("format"),
+		StaleAfter:           c.Duration("stale-after"),
+		Provider:             c.String("provider"),
+		MaxAPICalls:          c.Int("max-api-calls"),
+		Order:                c.String("sort"),
+		GroupBy:              c.String("group-by"),
+		CodeOwners:           c.Bool("codeowners") || c.String("group-by") == finding.GroupTeam,
+		Automation:           c.Bool("automation"),
+		PathStyle:            c.String("path-style"),
+		Timeouts:             cfg.Timeouts,
+		Color:                useColor(c),
+		Width:                tableWidth(c),
+		Output:               findingsWriter(c),
+		Progress:             checkProgressWriter(c),
+		IgnoreArchivedOwners: c.StringSlice("ignore-archived-owners"),
+		Scope:                scanScope(c),
+		SelfCheck:            selfCheckWriter(c),
+		Baseline:             b,
+		Licenses:             licensePolicy(c, cfg),
+		SuggestForks:         c.Int("suggest-forks"),
+		Advisories:           c.Bool("advisories"),
+		Details:              c.Bool("details"),
+		ResolveMirrors:       c.Bool("resolve-mirrors"),
+		Client: client.Options{
+			UserAgent:     c.String("user-agent"),
+			CorrelationID: correlationID,
+			Retries:       c.Int("retries"),
+			RetryDelay:    c.Duration("retry-delay"),
+			Cache:         metadataCache,
+			CacheTTL:      c.Duration("cache-ttl"),
+		},
+	}, nil
+}
+
+// diffRef scans ref and the working tree with the same provider, so
+// repositories used by both are looked up once.
+func diffRef(c *cli.Context, opts gomod.Options, ref string) (finding.Report, finding.Report, error) {
+	goModOptions(c, &opts)
+
+	provider, err := gomod.NewProvider(c.Context, opts)
+	if err != nil {
+		return finding.Report{}, finding.Report{}, err
+	}
+
+	scan := func(ctx context.Context, opts gomod.Options) (finding.Report, error) {
+		repos, err := check.Repos(ctx, check.Ecosystems, opts)
+		if err != nil {
+			return finding.Report{}, err
+		}
+
+		s := gomod.NewScanner(opts, io.Discard)
+		s.Provider = provider
+
+		return s.Check(ctx, repos)
+	}
+
+	before, err := diff.ScanRef(c.Context, ref, opts, scan)
+	if err != nil {
+		return finding.Report{}, finding.Report{}, err
+	}
+
+	after, err := scan(c.Context, opts)
+	if err != nil {
+		return finding.Report{}, finding.Report{}, err
+	}
+
+	return before, after, nil
+}
+
+// recordTelemetry counts the scan run by c if telemetry is enabled. Telemetry
+// never fails a scan, errors are only logged.
+func recordTelemetry(c *cli.Context) {
+	path, err := telemetry.Path()
+	if err != nil {
+		slog.DebugContext(c.Context, fmt.Sprintf("error finding telemetry state: %v", err))
+
+		return
+	}
+
+	cfg, err := loadConfig(c)
+	if err != nil {
+		slog.DebugContext(c.Context, fmt.Sprintf("error loading config for telemetry: %v", err))
+
+		return
+	}
+
+	endpoint := cfg.Telemetry.Endpoint
+
+	err = telemetry.Record(c.Context, path, c.Command.Name, c.String("format"), cfg.Timeouts.HTTPClient(endpoint), endpoint, time.Now())
+	if err != nil {
+		slog.DebugContext(c.Context, fmt.Sprintf("error recording telemetry: %v", err))
+	}
+}
+
+// commentPR publishes report on the pull request given with --comment-pr, in
+// $GITHUB_REPOSITORY or the repository checked out in the current directory,
+// through the API client of opts, the options report was scanned with.
+func commentPR(c *cli.Context, opts gomod.Options, report finding.Report) error {
+	number, err := prcomment.Number(c.String("comment-pr"), os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if repo == "" {
+		checkout, err := gitprobe.LocalCheckout(c.Context, ".")
+		if err != nil {
+			return fmt.Errorf("failed to find the repository of the pull request: %w", err)
+		}
+
+		repo = checkout.Repo
+	}
+
+	api, err := gomod.NewGitHubClient(c.Context, opts)
+	if err != nil {
+		return err
+	}
+
+	comment, err := prcomment.Publish(c.Context, api, repo, number, report)
+	if err != nil {
+		return fmt.Errorf("failed to comment on pull request: %w", err)
+	}
+
+	slog.DebugContext(c.Context, fmt.Sprintf("published findings to %s", comment.HTMLURL))
+
+	return nil
+}
+
+// notifyWebhook posts the enforced findings in report to --notify-webhook.
+// Nothing is posted when there are none, so scheduled audits stay quiet
+// until a dependency needs attention.
+func notifyWebhook(c *cli.Context, report finding.Report) error {
+	enforced := report.Enforced()
+	if len(enforced.Findings) == 0 {
+		slog.DebugContext(c.Context, "no findings to notify about")
+
+		return nil
+	}
+
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.String("notify-webhook")
+
+	return notify.Post(c.Context, cfg.Timeouts.HTTPClient(endpoint), endpoint, c.String("notify-format"), enforced)
+}
+
+// watchGoMod scans again whenever module files change, printing how the
+// findings differ from report and then from each previous scan, until the
+// command is interrupted. Every scan looks repositories up with provider, the
+// one of the first scan, so only repositories it has not cached are fetched.
+func watchGoMod(c *cli.Context, opts gomod.Options, provider client.Provider, report finding.Report) error {
+	if c.Duration("watch-interval") <= 0 {
+		return cli.Exit("--watch-interval must be positive", 1)
+	}
+
+	recordTelemetry(c)
+
+	opts.Output = io.Discard
+	// The self-check was done by the first scan.
+	opts.SelfCheck = nil
+
+	scan := func(ctx context.Context) (finding.Report, error) {
+		s := gomod.NewScanner(opts, opts.Output)
+		s.Provider = provider
+
+		return s.Scan(ctx)
+	}
+
+	return watch.Watch(c.Context, findingsWriter(c), opts.Scope, c.Duration("watch-interval"), report, scan)
+}
+
+// serveMetrics scans the local paths and the organizations given with --org
+// every --interval, serving the results on --listen until interrupted.
+func serveMetrics(c *cli.Context, opts gomod.Options) error {
+	orgs := c.StringSlice("org")
+	local := len(orgs) == 0 || len(opts.Scope.Paths) > 0
+
+	scan := func(ctx context.Context) (finding.Report, error) {
+		var report finding.Report
+
+		if local {
+			result, err := check.ListArchived(ctx, opts)
+			if err != nil {
+				return finding.Report{}, fmt.Errorf("failed to list archived dependencies: %w", err)
+			}
+
+			report = result
+		}
+
+		if len(orgs) == 0 {
+			return report, nil
+		}
+
+		api, err := gomod.NewGitHubClient(ctx, opts)
+		if err != nil {
+			return finding.Report{}, err
+		}
+
+		for _, name := range orgs {
+			result, err := org.ListArchived(ctx, api, name, opts)
+			if err != nil {
+				return finding.Report{}, fmt.Errorf("failed to list archived dependencies of %s: %w", name, err)
+			}
+
+			report.Findings = append(report.Findings, result.Findings...)
+			report.Unchecked += result.Unchecked
+			report.Degraded += result.Degraded
+			report.Unreadable += result.Unreadable
+		}
+
+		return report, nil
+	}
+
+	ctx := c.Context
+
+	s := serve.New(scan, c.Duration("interval"))
+	s.Token = c.String("token")
+
+	if c.Bool("api") {
+		api, err := scanAPI(ctx, opts, c.String("api-token"))
+		if err != nil {
+			return err
+		}
+
+		s.API = api
+	}
+
+	srv := &http.Server{Addr: c.String("listen"), Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	go s.Run(ctx)
+
+	go func() {
+		<-ctx.Done()
+
+		_ = srv.Shutdown(context.WithoutCancel(ctx))
+	}()
+
+	slog.InfoContext(ctx, fmt.Sprintf("serving metrics on %s", srv.Addr))
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve metrics: %w", err)
+	}
+
+	return nil
+}
+
+// scanAPI returns the API of the serve command, accepting requests that carry
+// token. Requests share one provider, so repositories looked up recently are
+// answered from its cache.
+func scanAPI(ctx context.Context, opts gomod.Options, token string) (*serve.API, error) {
+	provider, err := gomod.NewProvider(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := gomod.NewGitHubClient(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The self-check is about the current directory, not the requested
+	// dependencies.
+	opts.SelfCheck = nil
+
+	scan := func(ctx context.Context, repos map[string][]gomod.RepoInfo) (finding.Report, error) {
+		s := gomod.NewScanner(opts, io.Discard)
+		s.Provider = provider
+
+		return s.Check(ctx, repos)
+	}
+
+	return &serve.API{
+		Token: token,
+		GoMod: func(ctx context.Context, data []byte) (finding.Report, error) {
+			repos := map[string][]gomod.RepoInfo{}
+			if err := gomod.ParseGoMod("go.mod", data, repos); err != nil {
+				return finding.Report{}, fmt.Errorf("%w: %w", serve.ErrInvalidRequest, err)
+			}
+
+			return scan(ctx, repos)
+		},
+		Repo: func(ctx context.Context, arg string) (finding.Report, error) {
+			repo, ref, err := remote.Parse(arg)
+			if err != nil {
+				return finding.Report{}, fmt.Errorf("%w: %w", serve.ErrInvalidRequest, err)
+			}
+
+			repos, err := remote.Repos(ctx, api, check.Ecosystems, repo, ref, opts)
+			if err != nil {
+				return finding.Report{}, err
+			}
+
+			return scan(ctx, repos)
+		},
+	}, nil
+}
+
+// createIssues opens issues about the archived dependencies in report in
+// --issues-repo, or the repository checked out in the current directory.
+func createIssues(c *cli.Context, opts gomod.Options, report finding.Report) error {
+	repo := c.String("issues-repo")
+	if repo == "" {
+		checkout, err := gitprobe.LocalCheckout(c.Context, ".")
+		if err != nil {
+			return fmt.Errorf("failed to find the repository to open issues in, use --issues-repo: %w", err)
+		}
+
+		repo = checkout.Repo
+	}
+
+	api, err := gomod.NewGitHubClient(c.Context, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := issues.Create(c.Context, api, repo, report, c.App.ErrWriter); err != nil {
+		return fmt.Errorf("failed to create issues: %w", err)
+	}
+
+	return nil
+}
+
+// exitWithResult records telemetry for the scan, prints the --summary line,
+// sets GitHub Actions step outputs, comments on the pull request given with
+// --comment-pr, posts to --notify-webhook, writes the baseline if asked to and otherwise fails the
+// command when the policy says the findings should fail the run.
+func exitWithResult(c *cli.Context, opts gomod.Options, p policy.Policy, report finding.Report) error {
+	recordTelemetry(c)
+
+	if c.Bool("summary") && !c.Bool("quiet") {
+		fmt.Fprintln(c.App.Writer, report.Summary())
+	}
+
+	reportPath := c.String("output")
+	if c.String("format") == render.FormatGitHubSummary {
+		reportPath = os.Getenv("GITHUB_STEP_SUMMARY")
+	}
+
+	if err := render.GitHubOutputs(report, reportPath); err != nil {
+		return err
+	}
+
+	if c.String("comment-pr") != "" {
+		if err := commentPR(c, opts, report); err != nil {
+			return err
+		}
+	}
+
+	if c.String("notify-webhook") != "" {
+		if err := notifyWebhook(c, report); err != nil {
+			return err
+		}
+	}
+
+	if path := c.String("write-baseline"); path != "" {
+		return baseline.Write(path, baseline.New(report))
+	}
+
+	enforced := report.Enforced()
+	failed := p.Failed(enforced.Counts(), enforced.DirectCounts())
+
+	decision := fmt.Sprintf("passed with %d enforced findings", len(enforced.Findings))
+	if failed {
+		decision = fmt.Sprintf("failed with %d enforced findings", len(enforced.Findings))
+	}
+
+	audit.Record(c.Context, audit.PolicyApplied, fmt.Sprintf("fail-on=%s max-archived=%d", p.FailOn, p.MaxFindings), decision)
+
+	if failed {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// completionValues returns the values shell completion offers for the flags
+// and arguments of commands.
+func completionValues(ctx context.Context) completion.Values {
+	return func(cmd *cli.Command, flag string) []string {
+		switch flag {
+		case "":
+			switch cmd.Name {
+			case "module", "why":
+				paths, err := gomod.ModulePaths(ctx, files.Scope{})
+				if err != nil {
+					slog.DebugContext(ctx, err.Error())
+				}
+
+				return paths
+			case "completion":
+				return completion.Shells
+			}
+		case "format":
+			switch cmd.Name {
+			case "graph":
+				return depgraph.Formats
+			case "heatmap":
+				return []string{"json", "csv"}
+			case "version":
+				return []string{"text", "json"}
+			}
+
+			return render.Formats
+		case "provider":
+			return gomod.Providers
+		case "path-style":
+			return files.PathStyles
+		case "sort":
+			return finding.Orders
+		case "group-by":
+			return finding.Groupings
+		case "fail-on":
+			return policy.FailOnValues
+		case "notify-format":
+			return notify.Formats
+		}
+
+		return nil
+	}
+}
+
+// ecosystemCommands returns the commands that scan a single ecosystem of
+// check.Ecosystems, for every ecosystem with a Usage.
+func ecosystemCommands() []*cli.Command {
+	var commands []*cli.Command
+
+	for _, eco := range check.Ecosystems {
+		if eco.Usage == "" {
+			continue
+		}
+
+		flags := checkFlags()
+		if eco.Indirect != "" {
+			flags = append([]cli.Flag{
+				&cli.BoolFlag{
+					Name:  "indirect",
+					Usage: eco.Indirect,
+				},
+			}, flags...)
+		}
+
+		commands = append(commands, &cli.Command{
+			Name:  eco.Name,
+			Usage: eco.Usage,
+			Flags: flags,
+			Action: func(c *cli.Context) error {
+				p, err := checkPolicy(c)
+				if err != nil {
+					return err
+				}
+
+				opts, err := checkOptions(c)
+				if err != nil {
+					return err
+				}
+
+				opts.Indirect = c.Bool("indirect")
+
+				result, err := eco.ListArchived(c.Context, opts)
+				if err != nil {
+					return fmt.Errorf("failed to list archived %s dependencies: %w", eco.Name, err)
+				}
+
+				return exitWithResult(c, opts, p, result)
+			},
+		})
+	}
+
+	return commands
+}
+
 func main() {
 	ctx := context.Background()
 
-	if err := run(ctx); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-}
+	if err := run(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	setDefaultLogger(slog.LevelInfo)
+	enableVirtualTerminal()
+
+	// Interrupting the run cancels in-flight requests, so the audit log and
+	// output file are still closed. A second interrupt exits immediately.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	context.AfterFunc(ctx, stop)
+
+	cancelRun := context.CancelFunc(func() {})
+
+	cli.VersionPrinter = func(c *cli.Context) {
+		fmt.Fprintln(c.App.Writer, version.Get())
+	}
+
+	app := &cli.App{
+		Name:                 "arc",
+		HelpName:             ghext.CommandName(),
+		Usage:                "List archived dependencies",
+		EnableBashCompletion: true,
+		Version:              version.Get().Version,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "debug",
+				Value: false,
+				Usage: "Print debug logs",
+				Action: func(_ *cli.Context, v bool) error {
+					if v {
+						setDefaultLogger(slog.LevelDebug)
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:    "user-agent",
+				EnvVars: []string{"ARC_USER_AGENT"},
+				Usage:   "Identifier appended to the User-Agent of API requests, e.g. acme-ci/1.0",
+			},
+			&cli.StringFlag{
+				Name:    "cache-url",
+				EnvVars: []string{"ARC_CACHE_URL"},
+				Usage:   "HTTP key-value store to share repository metadata through across runs, read with GET and written with PUT",
+			},
+			&cli.StringFlag{
+				Name:    "cache-token",
+				EnvVars: []string{"ARC_CACHE_TOKEN"},
+				Usage:   "Bearer token sent to --cache-url",
+			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "Cache repository metadata in memory for this run only, instead of in the user cache directory between runs",
+			},
+			&cli.DurationFlag{
+				Name:  "cache-ttl",
+				Value: client.DefaultCacheTTL,
+				Usage: "How long cached repository metadata is used before it is revalidated with a conditional request",
+			},
+			&cli.StringFlag{
+				Name:        "correlation-id",
+				EnvVars:     []string{"ARC_CORRELATION_ID"},
+				Usage:       "Correlation ID sent with every API request",
+				DefaultText: "random",
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				EnvVars: []string{"ARC_CONFIG"},
+				Value:   config.DefaultPath,
+				Usage:   "Configuration file, ignored if the default does not exist",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Time to wait for each network response, for hosts without a timeout in the configuration file",
+			},
+			&cli.DurationFlag{
+				Name:  "total-timeout",
+				Usage: "Time after which the whole run is cancelled, including requests in flight (disabled by default)",
+			},
+			&cli.DurationFlag{
+				Name:  "connect-timeout",
+				Usage: "Time to wait for each network connection, for hosts without a timeout in the configuration file",
+			},
+			&cli.BoolFlag{
+				Name:  "no-color",
+				Usage: "Disable colored output, which is also disabled by NO_COLOR or when stdout is not a terminal",
+			},
+			&cli.IntFlag{
+				Name:  "retries",
+				Value: client.DefaultRetries,
+				Usage: "Times to retry a repository lookup after a server or connection error",
+			},
+			&cli.DurationFlag{
+				Name:  "retry-delay",
+				Value: client.DefaultRetryDelay,
+				Usage: "Wait before the first retry, doubled for every further retry",
+			},
+			&cli.StringSliceFlag{
+				Name:        "path",
+				Usage:       "Directory to search for manifests, may be repeated",
+				DefaultText: "current directory",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "Glob pattern of files and directories to skip, may be repeated, e.g. 'vendor/**' or '**/testdata'",
+			},
+			&cli.BoolFlag{
+				Name:  "no-ignore",
+				Usage: "Also search paths listed in .gitignore and .arcignore-paths files",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write findings to this file instead of stdout",
+			},
+			&cli.StringFlag{
+				Name:    "audit-log",
+				EnvVars: []string{"ARC_AUDIT_LOG"},
+				Usage:   "Append every decision of the run, such as skipped files and repositories, cache hits and the policy applied, to this JSON lines file",
+			},
+		},
+		Before: func(c *cli.Context) error {
+			if path := c.String("output"); path != "" {
+				f, err := os.Create(path) // #nosec G304
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+
+				c.App.Writer = f
+			}
+
+			if d := c.Duration("total-timeout"); d > 0 {
+				c.Context, cancelRun = context.WithTimeoutCause(c.Context, d, fmt.Errorf("run exceeded --total-timeout of %s", d))
+			}
+
+			path := c.String("audit-log")
+			if path == "" {
+				return nil
+			}
+
+			l, err := audit.Open(path)
+			if err != nil {
+				return err
+			}
+
+			c.Context = audit.WithLog(c.Context, l)
+
+			return nil
+		},
+		After: func(c *cli.Context) error {
+			cancelRun()
+
+			if f, ok := c.App.Writer.(*os.File); ok && c.String("output") != "" {
+				if err := f.Close(); err != nil {
+					return fmt.Errorf("failed to write output file: %w", err)
+				}
+			}
+
+			return audit.FromContext(c.Context).Close()
+		},
+		Commands: slices.Concat([]*cli.Command{
+			{
+				Name:  "gomod",
+				Usage: "List archived go modules",
+				Flags: slices.Concat(goModFlags(), []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "suggest-alternatives",
+						Usage: "Print deps.dev and pkg.go.dev pointers for archived modules",
+					},
+					&cli.BoolFlag{
+						Name:  "from-gosum",
+						Usage: "Check every module in the build graph, listed with go list -m all or read from go.sum, implies --indirect",
+					},
+					&cli.BoolFlag{
+						Name:  "go-list",
+						Usage: "Resolve Go modules with go list -m all, checking the versions that are actually built",
+					},
+					&cli.BoolFlag{
+						Name:  "tools-only",
+						Usage: "Check only tool dependencies, declared with tool directives or imported by tools.go files",
+					},
+					&cli.BoolFlag{
+						Name:  "create-issues",
+						Usage: "Open an issue labeled " + issues.Label + " for every archived dependency without an open one",
+					},
+					&cli.StringFlag{
+						Name:        "issues-repo",
+						Usage:       "Repository to open issues in with --create-issues",
+						DefaultText: "origin of the current checkout",
+					},
+					&cli.BoolFlag{
+						Name:  "outdated",
+						Usage: "Report modules several major versions behind the latest tag of their repository",
+					},
+					&cli.IntFlag{
+						Name:  "outdated-majors",
+						Value: 2,
+						Usage: "Major versions behind the latest tag that --outdated reports",
+					},
+					&cli.BoolFlag{
+						Name:  "check-go-version",
+						Usage: "Report go.mod files whose go directive is outside the supported range of Go releases",
+					},
+					&cli.StringFlag{
+						Name:  "min-go-version",
+						Usage: "Oldest supported Go release for --check-go-version, e.g. 1.22",
+					},
+					&cli.StringFlag{
+						Name:  "max-go-version",
+						Usage: "Newest supported Go release for --check-go-version, e.g. 1.24",
+					},
+					&cli.BoolFlag{
+						Name:  "watch",
+						Usage: "Scan again whenever go.mod, go.sum or go.work files change and print the findings introduced and resolved",
+					},
+					&cli.DurationFlag{
+						Name:  "watch-interval",
+						Value: watch.DefaultInterval,
+						Usage: "How long module files must stop changing before --watch scans again",
+					},
+				}, checkFlags()),
+				Action: func(c *cli.Context) error {
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					goModOptions(c, &opts)
+					opts.Indirect = opts.Indirect || c.Bool("from-gosum")
+					opts.FromGoSum = c.Bool("from-gosum")
+					opts.GoList = c.Bool("go-list")
+					opts.ToolsOnly = c.Bool("tools-only")
+					opts.SuggestAlternatives = c.Bool("suggest-alternatives")
+
+					if c.Bool("outdated") {
+						if c.Int("outdated-majors") < 1 {
+							return cli.Exit("--outdated-majors must be at least 1", 1)
+						}
+
+						opts.OutdatedMajors = c.Int("outdated-majors")
+					}
+
+					if c.Bool("check-go-version") {
+						opts.GoVersion, err = goVersionPolicy(c)
+						if err != nil {
+							return err
+						}
+					}
+
+					scanner := gomod.NewScanner(opts, opts.Output)
+
+					// Watching scans again with the same provider, so
+					// repositories are only looked up once.
+					if c.Bool("watch") {
+						scanner.Provider, err = gomod.NewProvider(c.Context, opts)
+						if err != nil {
+							return err
+						}
+					}
+
+					result, err := scanner.Scan(c.Context)
+					if err != nil {
+						return fmt.Errorf("failed to list archived go modules: %w", err)
+					}
+
+					if c.Bool("create-issues") {
+						if err := createIssues(c, opts, result); err != nil {
+							return err
+						}
+					}
+
+					if c.Bool("watch") {
+						return watchGoMod(c, opts, scanner.Provider, result)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:      "binary",
+				Usage:     "List archived go modules compiled into go binaries",
+				ArgsUsage: "<path> [path...]",
+				Flags:     checkFlags(),
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return cli.Exit("at least one binary path is required", 1)
+					}
+
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					result, err := gomod.ListArchivedInBinaries(c.Context, c.Args().Slice(), opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived go modules in binaries: %w", err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+		}, ecosystemCommands(), []*cli.Command{
+			{
+				Name:  "check",
+				Usage: "List archived dependencies of every supported ecosystem in one pass",
+				Flags: slices.Concat(goModFlags(), []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "from-gosum",
+						Usage: "Check every module in the build graph, listed with go list -m all or read from go.sum, implies --indirect",
+					},
+					&cli.BoolFlag{
+						Name:  "go-list",
+						Usage: "Resolve Go modules with go list -m all, checking the versions that are actually built",
+					},
+				}, checkFlags()),
+				Action: func(c *cli.Context) error {
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					goModOptions(c, &opts)
+					opts.Indirect = opts.Indirect || c.Bool("from-gosum")
+					opts.FromGoSum = c.Bool("from-gosum")
+					opts.GoList = c.Bool("go-list")
+
+					result, err := check.ListArchived(c.Context, opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived dependencies: %w", err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:      "sbom",
+				Usage:     "List archived components of CycloneDX SBOMs",
+				ArgsUsage: "<file> [file...]",
+				Flags: append([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "indirect",
+						Usage: "Include components the SBOM's root does not depend on directly",
+					},
+				}, checkFlags()...),
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return cli.Exit("at least one sbom path is required", 1)
+					}
+
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					opts.Indirect = c.Bool("indirect")
+
+					result, err := sbom.ListArchived(c.Context, c.Args().Slice(), opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived sbom components: %w", err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:      "repo",
+				Usage:     "Check repositories named on the command line for archived or stale status",
+				ArgsUsage: "<owner/name> [owner/name...]",
+				Flags:     checkFlags(),
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return cli.Exit("at least one repository is required", 1)
+					}
+
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					result, err := adhoc.ListArchived(c.Context, c.Args().Slice(), opts)
+					if err != nil {
+						return fmt.Errorf("failed to check repositories: %w", err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:      "module",
+				Usage:     "Check the repository of a module, including vanity import paths, and whether its version is still tagged",
+				ArgsUsage: "<module>[@version]",
+				Flags:     checkFlags(),
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("exactly one module path is required, e.g. github.com/owner/repo@v1.2.3", 1)
+					}
+
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					path, version, _ := strings.Cut(c.Args().First(), "@")
+
+					result, err := gomod.NewScanner(opts, opts.Output).CheckModule(c.Context, path, version)
+					if err != nil {
+						return fmt.Errorf("failed to check module: %w", err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:  "verify",
+				Usage: "Report go modules whose required version is no longer tagged in their repository",
+				Flags: append(goModFlags(), checkFlags()...),
+				Action: func(c *cli.Context) error {
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					goModOptions(c, &opts)
+
+					result, err := gomod.NewScanner(opts, opts.Output).Verify(c.Context)
+					if err != nil {
+						return fmt.Errorf("failed to verify go modules: %w", err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:      "org",
+				Usage:     "List archived dependencies of every Go repository in a GitHub organization, without cloning them",
+				ArgsUsage: "<org>",
+				Flags: append([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "indirect",
+						Usage: "Include indirect dependencies",
+					},
+				}, checkFlags()...),
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("exactly one organization is required", 1)
+					}
+
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					opts.Indirect = c.Bool("indirect")
+
+					api, err := gomod.NewGitHubClient(c.Context, opts)
+					if err != nil {
+						return err
+					}
+
+					result, err := org.ListArchived(c.Context, api, c.Args().First(), opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived dependencies of %s: %w", c.Args().First(), err)
+					}
+
+					if opts.Format == render.FormatText {
+						org.WriteSummary(opts.Output, org.Summarize(result))
+					}
 
-func run(_ context.Context) error {
-	setDefaultLogger(slog.LevelInfo)
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:      "remote",
+				Usage:     "List archived dependencies of a GitHub repository, read through the API without cloning it",
+				ArgsUsage: "<owner/repo>[@ref]",
+				Flags:     append(goModFlags(), checkFlags()...),
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("exactly one repository is required", 1)
+					}
 
-	app := &cli.App{
-		Name:  "arc",
-		Usage: "List archived dependencies",
-		Flags: []cli.Flag{
-			&cli.BoolFlag{
-				Name:  "debug",
-				Value: false,
-				Usage: "Print debug logs",
-				Action: func(_ *cli.Context, v bool) error {
-					if v {
-						setDefaultLogger(slog.LevelDebug)
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					goModOptions(c, &opts)
+
+					api, err := gomod.NewGitHubClient(c.Context, opts)
+					if err != nil {
+						return err
+					}
+
+					result, err := remote.ListArchived(c.Context, api, c.Args().First(), opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived dependencies of %s: %w", c.Args().First(), err)
+					}
+
+					return exitWithResult(c, opts, p, result)
+				},
+			},
+			{
+				Name:  "badge",
+				Usage: "Write a shields.io endpoint file, or an SVG image, counting archived dependencies of every supported ecosystem",
+				Flags: slices.Concat([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "svg",
+						Usage: "Write an SVG image instead of a shields.io endpoint file",
+					},
+					&cli.StringFlag{
+						Name:  "label",
+						Value: badge.DefaultLabel,
+						Usage: "Text on the left-hand side of the badge",
+					},
+				}, goModFlags(), lookupFlags()),
+				Action: func(c *cli.Context) error {
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					opts.Format = render.FormatJSON
+					opts.Output = io.Discard
+					goModOptions(c, &opts)
+
+					result, err := check.ListArchived(c.Context, opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived dependencies: %w", err)
+					}
+
+					recordTelemetry(c)
+
+					b := badge.New(result, c.String("label"))
+
+					if c.Bool("svg") {
+						return b.WriteSVG(c.App.Writer)
+					}
+
+					return b.WriteJSON(c.App.Writer)
+				},
+			},
+			{
+				Name:  "serve",
+				Usage: "Scan periodically and serve the results as Prometheus metrics and a JSON status document",
+				Description: "Scans the directories given with --path, or the current directory, and every organization given with --org.\n" +
+					"Metrics are served at /metrics and the latest report at /status.",
+				Flags: slices.Concat([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Value: "127.0.0.1:9464",
+						Usage: "Address to serve metrics on, e.g. :9464 for every interface, which requires --token",
+					},
+					&cli.DurationFlag{
+						Name:  "interval",
+						Value: serve.DefaultInterval,
+						Usage: "How often to scan",
+					},
+					&cli.StringSliceFlag{
+						Name:  "org",
+						Usage: "GitHub organization to scan without cloning its repositories, may be repeated",
+					},
+					&cli.BoolFlag{
+						Name:  "api",
+						Usage: "Serve POST /api/gomod and POST /api/repo, scanning an uploaded go.mod or a GitHub repository on request",
+					},
+					&cli.StringFlag{
+						Name:    "api-token",
+						Usage:   "Bearer token that requests to --api must carry, required with --api",
+						EnvVars: []string{"ARC_API_TOKEN"},
+					},
+					&cli.StringFlag{
+						Name:    "token",
+						Usage:   "Bearer token that requests to /metrics and /status must carry, required when --listen is not a loopback address",
+						EnvVars: []string{"ARC_SERVE_TOKEN"},
+					},
+				}, goModFlags(), lookupFlags()),
+				Action: func(c *cli.Context) error {
+					if c.Duration("interval") <= 0 {
+						return cli.Exit("--interval must be positive", 1)
+					}
+
+					if c.Bool("api") && c.String("api-token") == "" {
+						return cli.Exit("--api requires --api-token or ARC_API_TOKEN, since requests scan with your GitHub credentials", 1)
+					}
+
+					if c.String("token") == "" && !serve.Loopback(c.String("listen")) {
+						return cli.Exit("--listen on a non-loopback address requires --token or ARC_SERVE_TOKEN, since /metrics and /status name your dependencies", 1)
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					opts.Format = render.FormatJSON
+					opts.Output = io.Discard
+					opts.Progress = nil
+					goModOptions(c, &opts)
+
+					recordTelemetry(c)
+
+					return serveMetrics(c, opts)
+				},
+			},
+			{
+				Name:  "owners",
+				Usage: "Summarize findings of every supported ecosystem per repository owner",
+				Description: "Counts the repositories with findings, and the archived ones, of each GitHub user or organization,\n" +
+					"with the oldest last push among them, to show when many dependencies share an owner that stopped maintaining them.",
+				Flags: slices.Concat([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print as JSON",
+					},
+				}, goModFlags(), lookupFlags()),
+				Action: func(c *cli.Context) error {
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					opts.Format = render.FormatJSON
+					opts.Output = io.Discard
+					goModOptions(c, &opts)
+
+					result, err := check.ListArchived(c.Context, opts)
+					if err != nil {
+						return fmt.Errorf("failed to list archived dependencies: %w", err)
+					}
+
+					recordTelemetry(c)
+
+					summaries := owners.Summarize(result)
+
+					if c.Bool("json") {
+						return owners.JSON(c.App.Writer, summaries)
+					}
+
+					return owners.Text(c.App.Writer, summaries)
+				},
+			},
+			{
+				Name:      "diff",
+				Usage:     "List findings introduced and resolved between two scans",
+				ArgsUsage: "[before.json after.json]",
+				Description: "Compares two reports saved with --format json, or with --ref the given git ref with the working tree.\n" +
+					"Only new findings fail the run, and other formats than text and json render only the new findings.",
+				Flags: slices.Concat([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "ref",
+						Usage: "Git ref to compare the working tree with, e.g. origin/main",
+					},
+				}, goModFlags(), checkFlags()),
+				Action: func(c *cli.Context) error {
+					p, err := checkPolicy(c)
+					if err != nil {
+						return err
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					var before, after finding.Report
+
+					switch ref := c.String("ref"); {
+					case ref == "" && c.NArg() == 2:
+						if before, err = diff.Load(c.Args().Get(0)); err != nil {
+							return err
+						}
+
+						if after, err = diff.Load(c.Args().Get(1)); err != nil {
+							return err
+						}
+					case ref != "" && c.NArg() == 0:
+						before, after, err = diffRef(c, opts, ref)
+						if err != nil {
+							return fmt.Errorf("failed to compare with %s: %w", ref, err)
+						}
+					default:
+						return errors.New("expected two reports, or --ref without arguments")
+					}
+
+					result := diff.Compare(before, after)
+
+					out := findingsWriter(c)
+
+					switch format := c.String("format"); format {
+					case render.FormatText:
+						err = diff.Text(out, result)
+					case render.FormatJSON:
+						err = diff.JSON(out, result)
+					default:
+						err = render.RenderWith(out, format, result.Report(), render.Options{Color: useColor(c), Width: tableWidth(c), Order: c.String("sort"), GroupBy: c.String("group-by")})
+					}
+
+					if err != nil {
+						return err
+					}
+
+					return exitWithResult(c, opts, p, result.Report())
+				},
+			},
+			{
+				Name:  "heatmap",
+				Usage: "Export the age of the last push of every dependency, bucketed for dashboards",
+				Flags: slices.Concat([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "json",
+						Usage: "Output format (json, csv)",
+					},
+					&cli.StringFlag{
+						Name:  "path-style",
+						Value: files.PathStyleNative,
+						Usage: "How file paths are printed (" + strings.Join(files.PathStyles, ", ") + ")",
+					},
+				}, goModFlags(), lookupFlags()),
+				Action: func(c *cli.Context) error {
+					write := freshness.JSON
+
+					switch format := c.String("format"); format {
+					case "json":
+					case "csv":
+						write = freshness.CSV
+					default:
+						return fmt.Errorf("unsupported format %q, expected one of: json, csv", format)
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					goModOptions(c, &opts)
+
+					repos, err := check.Repos(c.Context, check.Ecosystems, opts)
+					if err != nil {
+						return err
+					}
+
+					heatmap, err := gomod.NewScanner(opts, c.App.Writer).Freshness(c.Context, repos)
+					if err != nil {
+						return fmt.Errorf("failed to look up dependencies: %w", err)
+					}
+
+					return write(c.App.Writer, heatmap)
+				},
+			},
+			{
+				Name:        "tui",
+				Usage:       "Browse dependencies of every ecosystem interactively as they are checked",
+				Description: "Lists dependencies as they are looked up. Use up and down to move, enter to show a dependency's repository, latest release, advisories and the files requiring it, tab to filter by status and q to quit.",
+				Flags: slices.Concat([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "details",
+						Usage: "Look up the latest release of unhealthy repositories, one more API request each",
+					},
+					&cli.BoolFlag{
+						Name:  "advisories",
+						Usage: "Look up unpatched security advisories affecting the required version of archived dependencies",
+					},
+					&cli.BoolFlag{
+						Name:  "resolve-mirrors",
+						Usage: "Check the upstream of read-only mirrors hosted on GitHub instead of the mirror, one more API request each",
+					},
+				}, goModFlags(), lookupFlags()),
+				Action: func(c *cli.Context) error {
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					// Nothing but the interface may write to the terminal.
+					opts.Format = render.FormatText
+					opts.Output = io.Discard
+					opts.Progress = nil
+					opts.SelfCheck = nil
+					goModOptions(c, &opts)
+
+					return tui.Run(c.Context, os.Stdin, os.Stdout, func(ctx context.Context, send func(tui.Checked)) error {
+						repos, err := check.Repos(ctx, check.Ecosystems, opts)
+						if err != nil {
+							return err
+						}
+
+						s := gomod.NewScanner(opts, io.Discard)
+						s.Checked = func(r gomod.CheckedRepo) { send(tui.Checked(r)) }
+
+						_, err = s.Check(ctx, repos)
+
+						return err
+					})
+				},
+			},
+			{
+				Name:  "duplicates",
+				Usage: "List modules required at different versions across go.mod files",
+				Action: func(c *cli.Context) error {
+					if _, err := gomod.ListVersionSkew(c.Context, c.App.Writer, scanScope(c)); err != nil {
+						return fmt.Errorf("failed to list duplicate go modules: %w", err)
 					}
 
 					return nil
 				},
 			},
-		},
-		Commands: []*cli.Command{
 			{
-				Name:  "gomod",
-				Usage: "List archived go modules",
+				Name:        "why",
+				Usage:       "Show which direct requirements pull in a go module, such as an archived indirect one",
+				ArgsUsage:   "<module>[@version]",
+				Description: "Prints the shortest chain of requirements from each direct requirement to the module, as listed by go mod graph, with the module highlighted if its repository is archived or missing.",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "path-style",
+						Value: files.PathStyleNative,
+						Usage: "How file paths are printed (" + strings.Join(files.PathStyles, ", ") + ")",
+					},
+				}, lookupFlags()...),
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("exactly one module path is required, e.g. github.com/owner/repo", 1)
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					n, err := gomod.NewScanner(opts, c.App.Writer).Why(c.Context, c.App.Writer, c.Args().First())
+					if err != nil {
+						return fmt.Errorf("failed to explain requirement: %w", err)
+					}
+
+					if n == 0 {
+						return cli.Exit(fmt.Sprintf("no go.mod file requires %s", c.Args().First()), 1)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:        "graph",
+				Usage:       "Export the go module graph with archived and stale modules colored",
+				Description: "Prints the requirements listed by go mod graph as a Graphviz digraph or a Mermaid flowchart, for embedding the risk in a dependency tree in architecture documents.",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: depgraph.FormatDOT,
+						Usage: "Output format (" + strings.Join(depgraph.Formats, ", ") + ")",
+					},
+				}, lookupFlags()...),
+				Action: func(c *cli.Context) error {
+					format := c.String("format")
+					if !slices.Contains(depgraph.Formats, format) {
+						return fmt.Errorf("unsupported format %q, expected one of: %s", format, strings.Join(depgraph.Formats, ", "))
+					}
+
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					// Graph formats are not interactive, but progress is still
+					// shown on terminals like for text output.
+					opts.Progress = progressWriter(render.FormatText)
+
+					g, err := gomod.NewScanner(opts, c.App.Writer).Graph(c.Context)
+					if err != nil {
+						return fmt.Errorf("failed to list the module graph: %w", err)
+					}
+
+					return depgraph.Write(c.App.Writer, format, g)
+				},
+			},
+			{
+				Name:  "providers",
+				Usage: "List repository metadata providers with their authentication and rate limit state",
 				Flags: []cli.Flag{
 					&cli.BoolFlag{
-						Name:  "indirect",
-						Usage: "Include indirect go modules",
+						Name:  "json",
+						Usage: "Print as JSON",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					opts, err := checkOptions(c)
+					if err != nil {
+						return err
+					}
+
+					if !c.Bool("json") {
+						if _, err := gomod.ListProviders(c.Context, c.App.Writer, opts); err != nil {
+							return fmt.Errorf("failed to list providers: %w", err)
+						}
+
+						return nil
+					}
+
+					enc := json.NewEncoder(c.App.Writer)
+					enc.SetIndent("", "  ")
+
+					if err := enc.Encode(gomod.ProviderStatuses(c.Context, opts)); err != nil {
+						return fmt.Errorf("failed to encode providers: %w", err)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "telemetry",
+				Usage: "Manage anonymous usage counters, which are only collected after opting in",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "show",
+						Usage: "Print whether telemetry is enabled and exactly what would be sent",
+						Action: func(c *cli.Context) error {
+							path, err := telemetry.Path()
+							if err != nil {
+								return err
+							}
+
+							st, err := telemetry.Load(path)
+							if errors.Is(err, telemetry.ErrCorrupt) {
+								return fmt.Errorf("%w, run \"arc telemetry enable\" or \"arc telemetry disable\" to reset it", err)
+							}
+
+							if err != nil {
+								return err
+							}
+
+							cfg, err := loadConfig(c)
+							if err != nil {
+								return err
+							}
+
+							return telemetry.Show(c.App.Writer, st, cfg.Telemetry.Endpoint)
+						},
+					},
+					{
+						Name:  "enable",
+						Usage: "Opt in to counting scans per command and output format",
+						Action: func(c *cli.Context) error {
+							path, err := telemetry.Path()
+							if err != nil {
+								return err
+							}
+
+							if err := telemetry.SetEnabled(path, true, time.Now()); err != nil {
+								return err
+							}
+
+							fmt.Fprintf(c.App.Writer, "telemetry enabled, run \"%s telemetry show\" to see what is collected\n", ghext.CommandName())
+
+							return nil
+						},
+					},
+					{
+						Name:  "disable",
+						Usage: "Opt out and discard the collected counters",
+						Action: func(c *cli.Context) error {
+							path, err := telemetry.Path()
+							if err != nil {
+								return err
+							}
+
+							if err := telemetry.SetEnabled(path, false, time.Now()); err != nil {
+								return err
+							}
+
+							fmt.Fprintln(c.App.Writer, "telemetry disabled")
+
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "clean",
+				Usage: "Remove cached and generated gh-arc state",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Print what would be removed without removing it",
+					},
+					&cli.StringSliceFlag{
+						Name:  "baseline",
+						Usage: "Baseline file written with --write-baseline to remove too, may be repeated",
+					},
+					&cli.StringSliceFlag{
+						Name:  "report",
+						Usage: "Report file written with --output to remove too, may be repeated",
 					},
 				},
 				Action: func(c *cli.Context) error {
-					checkIndirect := c.Bool("indirect")
+					cacheDir, err := files.CacheDir()
+					if err != nil {
+						return err
+					}
 
-					count, err := gomod.ListArchived(c.Context, checkIndirect)
+					// Whether telemetry is enabled is a choice, not a cache.
+					paths, err := files.CacheEntries(cacheDir, telemetry.FileName)
 					if err != nil {
-						return fmt.Errorf("failed to list archived go modules: %w", err)
+						return err
 					}
 
-					if count > 0 {
-						return cli.Exit("", 1)
+					paths = append(paths, c.StringSlice("baseline")...)
+					paths = append(paths, c.StringSlice("report")...)
+
+					if err := files.Clean(c.App.Writer, paths, c.Bool("dry-run")); err != nil {
+						return fmt.Errorf("failed to clean: %w", err)
 					}
 
 					return nil
 				},
 			},
-		},
+			{
+				Name:      "completion",
+				Usage:     "Print a shell completion script",
+				ArgsUsage: "<" + strings.Join(completion.Shells, "|") + ">",
+				Description: "Completes commands, flags and flag values, such as ecosystems and output formats, by asking the binary, so the script stays current after upgrades. Load it with:\n\n" +
+					"  bash:       source <(arc completion bash)\n" +
+					"  zsh:        source <(arc completion zsh)\n" +
+					"  fish:       arc completion fish | source\n" +
+					"  powershell: arc completion powershell | Out-String | Invoke-Expression",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.Exit("exactly one shell is required, one of: "+strings.Join(completion.Shells, ", "), 1)
+					}
+
+					// Completion runs the binary by the name it was invoked
+					// with, which is gh-arc for the GitHub CLI extension.
+					prog := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+
+					return completion.Script(c.App.Writer, c.Args().First(), prog)
+				},
+			},
+			{
+				Name:  "version",
+				Usage: "Print version and build information",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "Output format (text, json)",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print as JSON, same as --format json",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					info := version.Get()
+
+					format := c.String("format")
+					if c.Bool("json") {
+						format = "json"
+					}
+
+					switch format {
+					case "text":
+						fmt.Fprintln(c.App.Writer, info)
+
+						return nil
+					case "json":
+					default:
+						return fmt.Errorf("unsupported format %q, expected one of: text, json", format)
+					}
+
+					enc := json.NewEncoder(c.App.Writer)
+					enc.SetIndent("", "  ")
+
+					if err := enc.Encode(info); err != nil {
+						return fmt.Errorf("failed to encode version: %w", err)
+					}
+
+					return nil
+				},
+			},
+		}),
 	}
 
-	return app.Run(os.Args)
+	completion.Install(app, completionValues(ctx))
+
+	return app.RunContext(ctx, os.Args)
 }
@@ -0,0 +1,161 @@
+// Package actions provides commands for scanning GitHub Actions workflows and
+// composite actions for archived actions.
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowGlobs match the workflow files of a repository.
+var WorkflowGlobs = []string{
+	filepath.Join(".github", "workflows", "*.yml"),
+	filepath.Join(".github", "workflows", "*.yaml"),
+}
+
+// Reference is an action used by a workflow or composite action step.
+type Reference struct {
+	// Action is the action without its ref, e.g. "actions/cache/save".
+	Action string
+	// Ref is the tag, branch or commit the action is pinned to.
+	Ref string
+	// Repo is the GitHub repository in the form "owner/repo".
+	Repo string
+	// File is the workflow or action metadata file.
+	File string
+	// Line is the line of the uses key in File.
+	Line int
+	// Column is the 1-based column of the uses value on Line.
+	Column int
+}
+
+// IsActionMetadata reports whether a file name is an action metadata file,
+// which declares the steps of composite actions.
+func IsActionMetadata(name string) bool {
+	return name == "action.yml" || name == "action.yaml"
+}
+
+// parseUses parses the value of a uses key, such as "actions/checkout@v4" or
+// "owner/repo/path@sha". Local actions, Docker images and reusable workflows
+// in the same repository are not GitHub repositories and return false.
+func parseUses(uses string) (action, ref, repo string, ok bool) {
+	if strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "docker://") {
+		return "", "", "", false
+	}
+
+	action, ref, _ = strings.Cut(uses, "@")
+
+	parts := strings.Split(action, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", false
+	}
+
+	return action, ref, parts[0] + "/" + parts[1], true
+}
+
+// collect appends the actions used beneath node to refs.
+func collect(name string, node *yaml.Node, refs []Reference) []Reference {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			if key.Value != "uses" || value.Kind != yaml.ScalarNode {
+				continue
+			}
+
+			if action, ref, repo, ok := parseUses(value.Value); ok {
+				refs = append(refs, Reference{action, ref, repo, name, value.Line, value.Column})
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		refs = collect(name, child, refs)
+	}
+
+	return refs
+}
+
+// parse returns the actions used by a workflow or action metadata file.
+func parse(name string, data []byte) ([]Reference, error) {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return collect(name, &doc, nil), nil
+}
+
+// Discover parses the given workflow and action metadata files.
+func Discover(ctx context.Context, names []string) []Reference {
+	var refs []Reference
+
+	for _, name := range names {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		found, err := parse(name, data)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		refs = append(refs, found...)
+	}
+
+	return refs
+}
+
+// Repos returns the repositories of the actions used by the workflows in
+// .github/workflows of each path of opts.Scope, and by composite actions in
+// opts.Scope.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	var names []string
+
+	roots := opts.Scope.Paths
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	for _, root := range roots {
+		for _, pattern := range WorkflowGlobs {
+			matches, err := filepath.Glob(filepath.Join(root, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("failed to find workflows: %w", err)
+			}
+
+			for _, match := range matches {
+				if !opts.Scope.Excluded(match) {
+					names = append(names, match)
+				}
+			}
+		}
+	}
+
+	metadata, err := files.RecursiveMatch(ctx, opts.Scope, IsActionMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find action metadata files: %w", err)
+	}
+
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, ref := range Discover(ctx, append(names, metadata...)) {
+		repos[ref.Repo] = append(repos[ref.Repo], gomod.NewRepoInfo(ref.File, ref.Line, ref.Column, ref.Action, ref.Ref, false))
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	data := `name: ci
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3.20
+      - name: Cache
+        uses: actions/cache/save@0c45773b623bea8c8e75f6c82b208c3cf94ea4f9
+  reuse:
+    uses: owner/workflows/.github/workflows/build.yml@main
+`
+
+	refs, err := parse("ci.yml", []byte(data))
+	require.NoError(t, err)
+	require.Equal(t, []Reference{
+		{"actions/checkout", "v4", "actions/checkout", "ci.yml", 7, 15},
+		{"actions/cache/save", "0c45773b623bea8c8e75f6c82b208c3cf94ea4f9", "actions/cache", "ci.yml", 11, 15},
+		{"owner/workflows/.github/workflows/build.yml", "main", "owner/workflows", "ci.yml", 13, 11},
+	}, refs)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := parse("ci.yml", []byte("jobs: [unclosed"))
+	require.Error(t, err)
+}
+
+func TestIsActionMetadata(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsActionMetadata("action.yml"))
+	require.True(t, IsActionMetadata("action.yaml"))
+	require.False(t, IsActionMetadata("ci.yml"))
+}
@@ -0,0 +1,51 @@
+// Package adhoc checks repositories named on the command line rather than
+// found in manifests, for quick checks and scripts.
+package adhoc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// Parse returns the repositories named by args, each of which is
+// "owner/name", "github.com/owner/name" or a https://github.com URL. Findings
+// about them have no file.
+func Parse(args []string) (map[string][]gomod.RepoInfo, error) {
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, arg := range args {
+		name := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(arg), "/"), ".git")
+		name = strings.TrimPrefix(name, "https://")
+		name = strings.TrimPrefix(name, "http://")
+		name = strings.TrimPrefix(name, "github.com/")
+
+		owner, repo, ok := strings.Cut(name, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid repository %q, expected owner/name", arg)
+		}
+
+		key := owner + "/" + repo
+		if _, seen := repos[key]; seen {
+			continue
+		}
+
+		repos[key] = []gomod.RepoInfo{gomod.NewRepoInfo("", 0, 0, "", "", false)}
+	}
+
+	return repos, nil
+}
+
+// ListArchived checks the repositories named by args and renders the
+// findings.
+func ListArchived(ctx context.Context, args []string, opts gomod.Options) (finding.Report, error) {
+	repos, err := Parse(args)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return gomod.NewScanner(opts, opts.Output).Check(ctx, repos)
+}
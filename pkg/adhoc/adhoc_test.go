@@ -0,0 +1,23 @@
+package adhoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	repos, err := Parse([]string{"owner/a", "github.com/owner/b", "https://github.com/owner/c.git", "https://github.com/owner/a/"})
+	require.NoError(t, err)
+
+	info := []gomod.RepoInfo{gomod.NewRepoInfo("", 0, 0, "", "", false)}
+	require.Equal(t, map[string][]gomod.RepoInfo{"owner/a": info, "owner/b": info, "owner/c": info}, repos)
+
+	for _, arg := range []string{"owner", "owner/", "/repo", "owner/repo/tree/main"} {
+		_, err := Parse([]string{arg})
+		require.Error(t, err, arg)
+	}
+}
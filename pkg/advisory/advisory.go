@@ -0,0 +1,145 @@
+// Package advisory matches GitHub Advisory Database vulnerabilities against
+// the pinned versions of dependencies. An archived dependency with a known,
+// unpatched vulnerability is the most urgent kind of finding, as no fix will
+// ever be released upstream.
+package advisory
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"golang.org/x/mod/semver"
+)
+
+// API looks up the vulnerabilities of a package. client.Client implements it.
+type API interface {
+	Vulnerabilities(ctx context.Context, ecosystem, pkg string) ([]client.Vulnerability, error)
+}
+
+// Advisory is a vulnerability that affects a pinned version.
+type Advisory struct {
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+	URL      string `json:"url"`
+	// VulnerableRange is the affected range, e.g. ">= 1.0.0, < 1.2.3".
+	VulnerableRange string `json:"vulnerable_range"`
+	// FirstPatched is the first version with a fix, if there is one.
+	FirstPatched string `json:"first_patched,omitempty"`
+}
+
+// Affecting returns the advisories among vulns whose vulnerable range
+// includes version. Withdrawn advisories and vulnerabilities whose range
+// cannot be parsed are skipped.
+func Affecting(vulns []client.Vulnerability, version string) []Advisory {
+	var advisories []Advisory
+
+	for _, v := range vulns {
+		if v.Advisory.WithdrawnAt != "" || !InRange(version, v.VulnerableVersionRange) {
+			continue
+		}
+
+		a := Advisory{
+			ID:              v.Advisory.GHSAID,
+			Summary:         v.Advisory.Summary,
+			Severity:        strings.ToLower(v.Advisory.Severity),
+			URL:             v.Advisory.Permalink,
+			VulnerableRange: v.VulnerableVersionRange,
+		}
+
+		if v.FirstPatchedVersion != nil {
+			a.FirstPatched = v.FirstPatchedVersion.Identifier
+		}
+
+		advisories = append(advisories, a)
+	}
+
+	return advisories
+}
+
+// InRange reports whether version satisfies every constraint of rng, a comma
+// separated list such as ">= 1.0.0, < 1.2.3" or "= 0.1.0". An empty version
+// or range never matches.
+func InRange(version, rng string) bool {
+	if version == "" || strings.TrimSpace(rng) == "" {
+		return false
+	}
+
+	for constraint := range strings.SplitSeq(rng, ",") {
+		constraint = strings.TrimSpace(constraint)
+
+		bound := strings.TrimLeft(constraint, "<>=")
+		op := constraint[:len(constraint)-len(bound)]
+		bound = strings.TrimSpace(bound)
+
+		if bound == "" {
+			return false
+		}
+
+		c := compare(version, bound)
+
+		var ok bool
+
+		switch op {
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "=", "==":
+			ok = c == 0
+		default:
+			return false
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compare compares two versions, as semantic versions when both are valid and
+// otherwise segment by segment, numerically where possible. A leading "v" is
+// ignored.
+func compare(a, b string) int {
+	va, vb := "v"+strings.TrimPrefix(a, "v"), "v"+strings.TrimPrefix(b, "v")
+	if semver.IsValid(va) && semver.IsValid(vb) {
+		return semver.Compare(va, vb)
+	}
+
+	sa, sb := strings.Split(va[1:], "."), strings.Split(vb[1:], ".")
+
+	for i := range max(len(sa), len(sb)) {
+		x, y := "0", "0"
+		if i < len(sa) {
+			x = sa[i]
+		}
+
+		if i < len(sb) {
+			y = sb[i]
+		}
+
+		nx, errX := strconv.Atoi(x)
+		ny, errY := strconv.Atoi(y)
+
+		switch {
+		case errX == nil && errY == nil && nx != ny:
+			if nx < ny {
+				return -1
+			}
+
+			return 1
+		case (errX != nil || errY != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return 0
+}
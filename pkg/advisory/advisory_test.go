@@ -0,0 +1,67 @@
+package advisory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+func TestInRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version string
+		rng     string
+		want    bool
+	}{
+		{"v1.0.0", "< 1.2.0", true},
+		{"v1.2.0", "< 1.2.0", false},
+		{"v1.2.0", "<= 1.2.0", true},
+		{"v1.2.0", ">= 1.0.0, < 1.2.3", true},
+		{"v0.9.0", ">= 1.0.0, < 1.2.3", false},
+		{"0.1.0", "= 0.1.0", true},
+		{"v2.0.0", "> 1.9", true},
+		{"1.2.post1", "< 1.3", true},
+		{"v4", "< 4.0.2", true},
+		{"", "< 1.0.0", false},
+		{"v1.0.0", "", false},
+		{"v1.0.0", "~> 1.0", false},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, InRange(tt.version, tt.rng), "%s in %q", tt.version, tt.rng)
+	}
+}
+
+func TestAffecting(t *testing.T) {
+	t.Parallel()
+
+	vuln := func(id, rng, withdrawn string) client.Vulnerability {
+		var v client.Vulnerability
+
+		v.Advisory.GHSAID = id
+		v.Advisory.Severity = "CRITICAL"
+		v.Advisory.WithdrawnAt = withdrawn
+		v.VulnerableVersionRange = rng
+
+		return v
+	}
+
+	patched := vuln("GHSA-1", "< 1.2.0", "")
+	patched.FirstPatchedVersion = &struct {
+		Identifier string `json:"identifier"`
+	}{"1.2.0"}
+
+	got := Affecting([]client.Vulnerability{
+		patched,
+		vuln("GHSA-2", ">= 2.0.0", ""),
+		vuln("GHSA-3", "< 1.2.0", "2024-01-01T00:00:00Z"),
+		vuln("GHSA-4", "< 5.0.0", ""),
+	}, "v1.1.0")
+
+	require.Equal(t, []Advisory{
+		{ID: "GHSA-1", Severity: "critical", VulnerableRange: "< 1.2.0", FirstPatched: "1.2.0"},
+		{ID: "GHSA-4", Severity: "critical", VulnerableRange: "< 5.0.0"},
+	}, got)
+}
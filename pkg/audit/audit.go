@@ -0,0 +1,118 @@
+// Package audit records every decision a scan makes, such as files skipped,
+// repositories not looked up and the policy applied, as JSON lines. Compliance
+// teams use the log to explain why a CI gate passed or failed.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Kinds of events.
+const (
+	// FileFound is a manifest that will be read.
+	FileFound = "file-found"
+	// FileSkipped is a file or directory that was not searched.
+	FileSkipped = "file-skipped"
+	// RepoSkipped is a repository that was not looked up.
+	RepoSkipped = "repo-skipped"
+	// RepoChecked is a repository that was looked up, with the outcome as
+	// the reason.
+	RepoChecked = "repo-checked"
+	// CacheHit is a lookup answered from the cache.
+	CacheHit = "cache-hit"
+	// CacheMiss is a lookup that was sent to the API.
+	CacheMiss = "cache-miss"
+	// CacheRevalidated is an expired cache entry the API confirmed is
+	// unchanged.
+	CacheRevalidated = "cache-revalidated"
+	// FindingExempt is a finding that does not count towards the policy.
+	FindingExempt = "finding-exempt"
+	// PolicyApplied is the decision whether the run fails.
+	PolicyApplied = "policy-applied"
+)
+
+// Event is one line of the log.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"event"`
+	Subject string    `json:"subject"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// Log writes events as JSON lines. A nil Log discards events, so callers need
+// not check whether auditing is enabled. It is safe for concurrent use.
+type Log struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	c   io.Closer
+	now func() time.Time
+}
+
+// New returns a Log that writes to w.
+func New(w io.Writer) *Log {
+	return &Log{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// Open returns a Log that appends to the file at path.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	l := New(f)
+	l.c = f
+
+	return l, nil
+}
+
+// Record writes an event. Write errors are ignored, the log never fails a
+// scan.
+func (l *Log) Record(kind, subject, reason string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_ = l.enc.Encode(Event{Time: l.now().UTC(), Kind: kind, Subject: subject, Reason: reason})
+}
+
+// Close closes the file opened by Open.
+func (l *Log) Close() error {
+	if l == nil || l.c == nil {
+		return nil
+	}
+
+	if err := l.c.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+
+	return nil
+}
+
+type contextKey struct{}
+
+// WithLog returns a context carrying l, see Record.
+func WithLog(ctx context.Context, l *Log) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Log carried by ctx, or nil.
+func FromContext(ctx context.Context) *Log {
+	l, _ := ctx.Value(contextKey{}).(*Log)
+
+	return l
+}
+
+// Record writes an event to the Log carried by ctx, if any.
+func Record(ctx context.Context, kind, subject, reason string) {
+	FromContext(ctx).Record(kind, subject, reason)
+}
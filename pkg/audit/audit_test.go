@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_Record(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	l := New(&buf)
+	l.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	l.Record(FileSkipped, "vendor", "matches an --exclude pattern")
+	l.Record(CacheHit, "owner/repo", "")
+
+	require.Equal(t, `{"time":"2025-01-02T03:04:05Z","event":"file-skipped","subject":"vendor","reason":"matches an --exclude pattern"}
+{"time":"2025-01-02T03:04:05Z","event":"cache-hit","subject":"owner/repo"}
+`, buf.String())
+}
+
+func TestRecord_WithoutLog(t *testing.T) {
+	t.Parallel()
+
+	require.NotPanics(t, func() {
+		Record(context.Background(), RepoChecked, "owner/repo", "healthy")
+	})
+	require.NoError(t, FromContext(context.Background()).Close())
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	for range 2 {
+		l, err := Open(path)
+		require.NoError(t, err)
+
+		Record(WithLog(context.Background(), l), PolicyApplied, "fail-on=any max-archived=0", "passed")
+		require.NoError(t, l.Close())
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304
+	require.NoError(t, err)
+	require.Len(t, bytes.Split(bytes.TrimSpace(data), []byte("\n")), 2)
+}
@@ -0,0 +1,412 @@
+// Package automation reads the Dependabot and Renovate configurations of a
+// repository to tell whether a dependency is kept up to date by a bot, so
+// archived dependencies nobody will be prompted to replace stand out. See
+// https://docs.github.com/en/code-security/dependabot/working-with-dependabot/dependabot-options-reference
+// and https://docs.renovatebot.com/configuration-options/.
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"gopkg.in/yaml.v3"
+)
+
+// Coverage describes whether update automation keeps a dependency up to date.
+type Coverage string
+
+// Supported coverages. The zero value means the repository has no update
+// automation, or the dependency's manifest is not one bots update.
+const (
+	// Covered dependencies are updated by at least one bot.
+	Covered Coverage = "covered"
+	// Excluded dependencies are deliberately ignored by the bots that
+	// would otherwise update them.
+	Excluded Coverage = "excluded"
+	// Uncovered dependencies are in manifests no bot is configured for.
+	Uncovered Coverage = "uncovered"
+)
+
+// DependabotLocations are where GitHub reads the Dependabot configuration
+// from, relative to the root of a repository.
+var DependabotLocations = []string{".github/dependabot.yml", ".github/dependabot.yaml"}
+
+// RenovateLocations are where Renovate reads its configuration from,
+// relative to the root of a repository, in the order it looks. JSON5
+// configurations are not supported.
+var RenovateLocations = []string{"renovate.json", ".github/renovate.json", ".gitlab/renovate.json", ".renovaterc", ".renovaterc.json"}
+
+// renovateManagers are the Renovate managers of each ecosystem.
+var renovateManagers = map[string][]string{
+	"gomod":          {"gomod"},
+	"cargo":          {"cargo"},
+	"pip":            {"pip_requirements", "pep621", "poetry"},
+	"bundler":        {"bundler"},
+	"composer":       {"composer"},
+	"maven":          {"maven"},
+	"gradle":         {"gradle"},
+	"github-actions": {"github-actions"},
+	"docker":         {"dockerfile"},
+	"terraform":      {"terraform"},
+	"helm":           {"helmv3", "helm-requirements"},
+	"pre-commit":     {"pre-commit"},
+}
+
+// dependabot is a Dependabot configuration.
+type dependabot struct {
+	Updates []struct {
+		PackageEcosystem string             `yaml:"package-ecosystem"`
+		Directory        string             `yaml:"directory"`
+		Directories      []string           `yaml:"directories"`
+		Allow            []dependabotRule   `yaml:"allow"`
+		Ignore           []dependabotIgnore `yaml:"ignore"`
+	} `yaml:"updates"`
+}
+
+// dependabotRule is an allow rule of a Dependabot configuration.
+type dependabotRule struct {
+	DependencyName string `yaml:"dependency-name"`
+}
+
+// dependabotIgnore is an ignore rule of a Dependabot configuration. Rules
+// with versions or update types only ignore some updates.
+type dependabotIgnore struct {
+	DependencyName string   `yaml:"dependency-name"`
+	Versions       any      `yaml:"versions"`
+	UpdateTypes    []string `yaml:"update-types"`
+}
+
+// renovate is a Renovate configuration.
+type renovate struct {
+	Enabled         *bool    `json:"enabled"`
+	EnabledManagers []string `json:"enabledManagers"`
+	IgnoreDeps      []string `json:"ignoreDeps"`
+	IgnorePaths     []string `json:"ignorePaths"`
+	PackageRules    []struct {
+		MatchPackageNames    []string `json:"matchPackageNames"`
+		MatchDepNames        []string `json:"matchDepNames"`
+		MatchPackagePatterns []string `json:"matchPackagePatterns"`
+		MatchManagers        []string `json:"matchManagers"`
+		MatchFileNames       []string `json:"matchFileNames"`
+		MatchPaths           []string `json:"matchPaths"`
+		Enabled              *bool    `json:"enabled"`
+	} `json:"packageRules"`
+}
+
+// Config is the update automation of a repository.
+type Config struct {
+	// Root is the directory manifest paths are relative to.
+	Root string
+	// Paths are the configuration files that were read.
+	Paths      []string
+	dependabot *dependabot
+	renovate   *renovate
+}
+
+// Load reads the Dependabot and Renovate configurations of the repository
+// containing dir, whose root is the closest directory with a .git entry, or
+// dir itself outside of a repository.
+func Load(ctx context.Context, dir string) (Config, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	c := Config{Root: abs}
+
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			c.Root = d
+
+			break
+		}
+
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	data, name, err := c.read(DependabotLocations)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if data != nil {
+		c.dependabot = &dependabot{}
+
+		if err := yaml.Unmarshal(data, c.dependabot); err != nil {
+			return Config{}, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		c.Paths = append(c.Paths, name)
+	}
+
+	data, name, err = c.read(RenovateLocations)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if data != nil {
+		c.renovate = &renovate{}
+
+		if err := json.Unmarshal(data, c.renovate); err != nil {
+			return Config{}, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		c.Paths = append(c.Paths, name)
+	}
+
+	slog.DebugContext(ctx, fmt.Sprintf("read update automation from %v", c.Paths))
+
+	return c, nil
+}
+
+// read returns the contents and name of the first of locations that exists
+// beneath Root, or nil if none does.
+func (c Config) read(locations []string) ([]byte, string, error) {
+	for _, location := range locations {
+		name := filepath.Join(c.Root, filepath.FromSlash(location))
+
+		data, err := os.ReadFile(name) // #nosec G304
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		return data, name, nil
+	}
+
+	return nil, "", nil
+}
+
+// Ecosystem returns the Dependabot package ecosystem of a manifest, a
+// slash-separated path relative to the root of a repository, or an empty
+// string if bots do not update it.
+func Ecosystem(p string) string {
+	name := path.Base(p)
+
+	switch {
+	case path.Dir(p) == ".github/workflows", name == "action.yml", name == "action.yaml":
+		return "github-actions"
+	case name == "go.mod", name == "go.sum":
+		return "gomod"
+	case name == "Cargo.toml", name == "Cargo.lock":
+		return "cargo"
+	case name == "requirements.txt", name == "pyproject.toml", name == "poetry.lock":
+		return "pip"
+	case name == "Gemfile", name == "Gemfile.lock":
+		return "bundler"
+	case name == "composer.json", name == "composer.lock":
+		return "composer"
+	case name == "pom.xml":
+		return "maven"
+	case name == "build.gradle", name == "build.gradle.kts", name == "gradle.lockfile":
+		return "gradle"
+	case name == "Dockerfile", strings.HasPrefix(name, "Dockerfile."), strings.HasSuffix(name, ".Dockerfile"):
+		return "docker"
+	case strings.HasSuffix(name, ".tf"):
+		return "terraform"
+	case name == "Chart.yaml", name == "Chart.lock", name == "requirements.yaml", name == "requirements.lock":
+		return "helm"
+	case name == ".pre-commit-config.yaml":
+		return "pre-commit"
+	}
+
+	return ""
+}
+
+// Coverage reports whether the dependency called dep, required by the file
+// name, is kept up to date by a bot. name is relative to the current
+// directory or absolute. A dependency is covered if any bot updates it, and
+// excluded if a bot that would update it has been told to ignore it.
+func (c Config) Coverage(name, dep string) Coverage {
+	if c.dependabot == nil && c.renovate == nil {
+		return ""
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(c.Root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	eco := Ecosystem(rel)
+	if eco == "" {
+		return ""
+	}
+
+	coverages := []Coverage{c.dependabotCoverage(rel, eco, dep), c.renovateCoverage(rel, eco, dep)}
+
+	switch {
+	case slices.Contains(coverages, Covered):
+		return Covered
+	case slices.Contains(coverages, Excluded):
+		return Excluded
+	}
+
+	return Uncovered
+}
+
+// dependabotCoverage reports whether Dependabot updates dep in the manifest
+// rel of ecosystem eco. Ignore rules limited to some versions or update
+// types do not exclude a dependency.
+func (c Config) dependabotCoverage(rel, eco, dep string) Coverage {
+	if c.dependabot == nil {
+		return Uncovered
+	}
+
+	dir := path.Dir(rel)
+	if dir == ".github/workflows" {
+		dir = "."
+	}
+
+	coverage := Uncovered
+
+	for _, u := range c.dependabot.Updates {
+		if u.PackageEcosystem != eco || !slices.ContainsFunc(append([]string{u.Directory}, u.Directories...), func(pattern string) bool {
+			return matchDir(pattern, dir)
+		}) {
+			continue
+		}
+
+		ignored := slices.ContainsFunc(u.Ignore, func(i dependabotIgnore) bool {
+			return i.Versions == nil && len(i.UpdateTypes) == 0 && matchName(i.DependencyName, dep)
+		})
+
+		allowed := len(u.Allow) == 0 || slices.ContainsFunc(u.Allow, func(a dependabotRule) bool {
+			return a.DependencyName == "" || matchName(a.DependencyName, dep)
+		})
+
+		if !ignored && allowed {
+			return Covered
+		}
+
+		coverage = Excluded
+	}
+
+	return coverage
+}
+
+// renovateCoverage reports whether Renovate updates dep in the manifest rel
+// of ecosystem eco. Package rules are applied in order, so a later rule can
+// enable a dependency an earlier one disabled.
+func (c Config) renovateCoverage(rel, eco, dep string) Coverage {
+	r := c.renovate
+	if r == nil || (r.Enabled != nil && !*r.Enabled) {
+		return Uncovered
+	}
+
+	managers := renovateManagers[eco]
+
+	if len(r.EnabledManagers) > 0 && !slices.ContainsFunc(managers, func(m string) bool {
+		return slices.Contains(r.EnabledManagers, m)
+	}) {
+		return Uncovered
+	}
+
+	if slices.ContainsFunc(r.IgnorePaths, func(pattern string) bool {
+		return matchPath(pattern, rel)
+	}) {
+		return Excluded
+	}
+
+	if slices.ContainsFunc(r.IgnoreDeps, func(pattern string) bool {
+		return matchName(pattern, dep)
+	}) {
+		return Excluded
+	}
+
+	enabled := true
+
+	for _, rule := range r.PackageRules {
+		if rule.Enabled == nil {
+			continue
+		}
+
+		names := slices.Concat(rule.MatchPackageNames, rule.MatchDepNames)
+		if len(names) > 0 && !slices.ContainsFunc(names, func(pattern string) bool {
+			return matchName(pattern, dep)
+		}) {
+			continue
+		}
+
+		if len(rule.MatchPackagePatterns) > 0 && !slices.ContainsFunc(rule.MatchPackagePatterns, func(pattern string) bool {
+			return matchName("/"+pattern+"/", dep)
+		}) {
+			continue
+		}
+
+		if len(rule.MatchManagers) > 0 && !slices.ContainsFunc(managers, func(m string) bool {
+			return slices.Contains(rule.MatchManagers, m)
+		}) {
+			continue
+		}
+
+		paths := slices.Concat(rule.MatchFileNames, rule.MatchPaths)
+		if len(paths) > 0 && !slices.ContainsFunc(paths, func(pattern string) bool {
+			return matchPath(pattern, rel)
+		}) {
+			continue
+		}
+
+		enabled = *rule.Enabled
+	}
+
+	if !enabled {
+		return Excluded
+	}
+
+	return Covered
+}
+
+// matchDir reports whether a Dependabot directory pattern, such as "/" or
+// "/services/*", matches dir, a slash-separated path relative to the root.
+func matchDir(pattern, dir string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		pattern = "."
+	}
+
+	return pattern == dir || files.MatchSegments(pattern, dir)
+}
+
+// matchPath reports whether a Renovate path pattern matches rel. Patterns
+// are globs, or plain prefixes of the paths they match.
+func matchPath(pattern, rel string) bool {
+	return files.MatchSegments(pattern, rel) || strings.HasPrefix(rel, pattern)
+}
+
+// matchName reports whether a dependency name pattern matches name. Patterns
+// enclosed in slashes are regular expressions, and "*" matches any sequence
+// of characters otherwise.
+func matchName(pattern, name string) bool {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+
+		return err == nil && re.MatchString(name)
+	}
+
+	re := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+
+	return regexp.MustCompile(re).MatchString(name)
+}
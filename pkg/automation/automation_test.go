@@ -0,0 +1,156 @@
+package automation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage_Dependabot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".github"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "dependabot.yml"), []byte(`version: 2
+updates:
+  - package-ecosystem: gomod
+    directory: /
+    schedule:
+      interval: weekly
+    ignore:
+      - dependency-name: github.com/pkg/errors
+      - dependency-name: golang.org/x/*
+      - dependency-name: github.com/stretchr/testify
+        update-types: ["version-update:semver-major"]
+  - package-ecosystem: gomod
+    directories: ["/services/*"]
+    allow:
+      - dependency-name: github.com/acme/*
+  - package-ecosystem: github-actions
+    directory: /
+`), 0o600))
+
+	c, err := Load(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, c.Paths, 1)
+
+	tests := []struct {
+		file, dep string
+		want      Coverage
+	}{
+		{"go.mod", "github.com/google/uuid", Covered},
+		{"go.mod", "github.com/pkg/errors", Excluded},
+		{"go.mod", "golang.org/x/mod", Excluded},
+		{"go.mod", "github.com/stretchr/testify", Covered},
+		{"services/api/go.mod", "github.com/acme/log", Covered},
+		{"services/api/go.mod", "github.com/pkg/errors", Excluded},
+		{"tools/go.mod", "github.com/pkg/errors", Uncovered},
+		{".github/workflows/ci.yml", "actions/checkout", Covered},
+		{"Cargo.toml", "serde", Uncovered},
+		{"arc", "github.com/pkg/errors", ""},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, c.Coverage(filepath.Join(root, tt.file), tt.dep), tt.file+" "+tt.dep)
+	}
+}
+
+func TestCoverage_Renovate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "renovate.json"), []byte(`{
+  "extends": ["config:recommended"],
+  "enabledManagers": ["gomod", "cargo", "dockerfile"],
+  "ignoreDeps": ["github.com/pkg/errors"],
+  "ignorePaths": ["legacy/**"],
+  "packageRules": [
+    {"matchPackageNames": ["/^golang\\.org/x//"], "enabled": false},
+    {"matchPackageNames": ["golang.org/x/mod"], "enabled": true},
+    {"matchManagers": ["cargo"], "matchFileNames": ["vendored/**"], "enabled": false}
+  ]
+}
+`), 0o600))
+
+	c, err := Load(context.Background(), root)
+	require.NoError(t, err)
+
+	tests := []struct {
+		file, dep string
+		want      Coverage
+	}{
+		{"go.mod", "github.com/google/uuid", Covered},
+		{"go.mod", "github.com/pkg/errors", Excluded},
+		{"go.mod", "golang.org/x/sys", Excluded},
+		{"go.mod", "golang.org/x/mod", Covered},
+		{"legacy/go.mod", "github.com/google/uuid", Excluded},
+		{"Cargo.toml", "serde", Covered},
+		{"vendored/Cargo.toml", "serde", Excluded},
+		{"Dockerfile", "github.com/google/ko", Covered},
+		{"Gemfile", "rails", Uncovered},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, c.Coverage(filepath.Join(root, tt.file), tt.dep), tt.file+" "+tt.dep)
+	}
+}
+
+func TestCoverage_Both(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".github"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "dependabot.yaml"), []byte(`version: 2
+updates:
+  - package-ecosystem: gomod
+    directory: /
+    ignore:
+      - dependency-name: github.com/pkg/errors
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "renovate.json"), []byte(`{"ignoreDeps": ["github.com/google/uuid"]}`), 0o600))
+
+	c, err := Load(context.Background(), root)
+	require.NoError(t, err)
+	require.Len(t, c.Paths, 2)
+
+	require.Equal(t, Covered, c.Coverage(filepath.Join(root, "go.mod"), "github.com/pkg/errors"))
+	require.Equal(t, Covered, c.Coverage(filepath.Join(root, "go.mod"), "github.com/google/uuid"))
+}
+
+func TestCoverage_None(t *testing.T) {
+	t.Parallel()
+
+	c, err := Load(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, c.Paths)
+	require.Equal(t, Coverage(""), c.Coverage("go.mod", "github.com/pkg/errors"))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "renovate.json"), []byte(`{`), 0o600))
+
+	_, err := Load(context.Background(), root)
+	require.Error(t, err)
+}
+
+func TestEcosystem(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "github-actions", Ecosystem(".github/workflows/ci.yml"))
+	require.Equal(t, "github-actions", Ecosystem("actions/setup/action.yml"))
+	require.Equal(t, "gomod", Ecosystem("cmd/go.mod"))
+	require.Equal(t, "docker", Ecosystem("build/Dockerfile.dev"))
+	require.Equal(t, "terraform", Ecosystem("infra/main.tf"))
+	require.Equal(t, "pre-commit", Ecosystem(".pre-commit-config.yaml"))
+	require.Empty(t, Ecosystem("main.go"))
+}
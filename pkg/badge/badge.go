@@ -0,0 +1,106 @@
+// Package badge summarizes a report as a README badge, either as a
+// shields.io endpoint file or as a standalone SVG image.
+package badge
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// DefaultLabel is the left-hand text of the badge.
+const DefaultLabel = "archived deps"
+
+// Badge colors, named as shields.io names them.
+const (
+	ColorGreen = "brightgreen"
+	ColorRed   = "red"
+)
+
+// colors maps the shields.io color names used by badges to hex values for
+// SVG output.
+var colors = map[string]string{
+	ColorGreen: "#4c1",
+	ColorRed:   "#e05d44",
+}
+
+// Endpoint is a shields.io endpoint badge, see
+// https://shields.io/badges/endpoint-badge.
+type Endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// New returns a badge counting the archived dependencies in report. A
+// dependency required in several places is counted once.
+func New(report finding.Report, label string) Endpoint {
+	archived := map[string]bool{}
+
+	for _, f := range report.Findings {
+		if f.Status == status.Archived {
+			archived[cmp.Or(f.Module, f.Repo)] = true
+		}
+	}
+
+	color := ColorGreen
+	if len(archived) > 0 {
+		color = ColorRed
+	}
+
+	return Endpoint{
+		SchemaVersion: 1,
+		Label:         cmp.Or(label, DefaultLabel),
+		Message:       fmt.Sprint(len(archived)),
+		Color:         color,
+	}
+}
+
+// WriteJSON writes the badge as a shields.io endpoint file, to be served
+// from a URL such as a raw file in the repository or GitHub Pages.
+func (e Endpoint) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(e); err != nil {
+		return fmt.Errorf("failed to encode badge: %w", err)
+	}
+
+	return nil
+}
+
+// textWidth estimates the width in pixels of s in 11px Verdana, which is
+// what shields.io badges use.
+func textWidth(s string) int {
+	return len(s)*7 + 10
+}
+
+// WriteSVG writes the badge as a flat SVG image in the style of shields.io,
+// for repositories that commit the image rather than serve an endpoint.
+func (e Endpoint) WriteSVG(w io.Writer) error {
+	label, message := html.EscapeString(e.Label), html.EscapeString(e.Message)
+	lw, mw := textWidth(e.Label), textWidth(e.Message)
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[3]s: %[4]s">
+<title>%[3]s: %[4]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[5]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[7]d" y="14">%[3]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[8]d" y="14">%[4]s</text>
+</g>
+</svg>
+`, lw+mw, lw, label, message, mw, cmp.Or(colors[e.Color], e.Color), lw/2, lw+mw/2)
+	if err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+
+	return nil
+}
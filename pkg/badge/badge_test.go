@@ -0,0 +1,43 @@
+package badge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, Endpoint{SchemaVersion: 1, Label: DefaultLabel, Message: "0", Color: ColorGreen}, New(finding.Report{
+		Findings: []finding.Finding{{Module: "github.com/a/a", Repo: "a/a", Status: status.Stale}},
+	}, ""))
+
+	require.Equal(t, Endpoint{SchemaVersion: 1, Label: "deps", Message: "2", Color: ColorRed}, New(finding.Report{
+		Findings: []finding.Finding{
+			{Module: "github.com/a/a", Repo: "a/a", File: "go.mod", Status: status.Archived},
+			{Module: "github.com/a/a", Repo: "a/a", File: "tools/go.mod", Status: status.Archived},
+			{Repo: "b/b", File: ".github/workflows/ci.yml", Status: status.Archived},
+		},
+	}, "deps"))
+}
+
+func TestEndpoint_Write(t *testing.T) {
+	t.Parallel()
+
+	e := Endpoint{SchemaVersion: 1, Label: DefaultLabel, Message: "0", Color: ColorGreen}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, e.WriteJSON(&buf))
+	require.JSONEq(t, `{"schemaVersion":1,"label":"archived deps","message":"0","color":"brightgreen"}`, buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, e.WriteSVG(&buf))
+	require.Contains(t, buf.String(), `aria-label="archived deps: 0"`)
+	require.Contains(t, buf.String(), `fill="#4c1"`)
+}
@@ -0,0 +1,95 @@
+// Package baseline records the findings of a scan so that later scans only
+// fail on new findings. This lets large codebases adopt the tool without
+// fixing every existing finding first.
+package baseline
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Entry identifies an acknowledged finding. Lines are left out so that
+// editing a manifest does not invalidate the baseline.
+type Entry struct {
+	Repo   string        `json:"repo"`
+	Module string        `json:"module,omitempty"`
+	File   string        `json:"file"`
+	Status status.Status `json:"status"`
+}
+
+// Baseline is the set of acknowledged findings.
+type Baseline struct {
+	Findings []Entry `json:"findings"`
+}
+
+// entry returns the entry of a finding. Paths always use forward slashes, so
+// a baseline written on one platform applies on every other.
+func entry(f finding.Finding) Entry {
+	return Entry{Repo: f.Repo, Module: f.Module, File: filepath.ToSlash(f.File), Status: f.Status}
+}
+
+// New returns a baseline acknowledging every finding of report.
+func New(report finding.Report) Baseline {
+	b := Baseline{Findings: []Entry{}}
+
+	for _, f := range report.Findings {
+		if e := entry(f); !slices.Contains(b.Findings, e) {
+			b.Findings = append(b.Findings, e)
+		}
+	}
+
+	slices.SortFunc(b.Findings, func(x, y Entry) int {
+		return cmp.Or(
+			strings.Compare(x.File, y.File),
+			strings.Compare(x.Repo, y.Repo),
+			strings.Compare(x.Module, y.Module),
+			strings.Compare(x.Status.String(), y.Status.String()),
+		)
+	})
+
+	return b
+}
+
+// Contains reports whether f was acknowledged. A finding whose status changed,
+// for example from stale to archived, is new.
+func (b Baseline) Contains(f finding.Finding) bool {
+	return slices.Contains(b.Findings, entry(f))
+}
+
+// Load reads a baseline written by Write.
+func Load(path string) (Baseline, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return Baseline{}, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Baseline{}, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+// Write writes the baseline to path.
+func Write(path string, b Baseline) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil { // #nosec G306 -- the baseline is meant to be committed
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "owner/b", Module: "github.com/owner/b", File: filepath.Join("svc", "go.mod"), Line: 5, Status: status.Archived},
+		{Repo: "owner/a", Module: "github.com/owner/a", File: "go.mod", Line: 4, Status: status.Stale},
+		{Repo: "owner/a", Module: "github.com/owner/a", File: "go.mod", Line: 9, Status: status.Stale},
+	}}
+
+	b := New(report)
+	require.Equal(t, []Entry{
+		{Repo: "owner/a", Module: "github.com/owner/a", File: "go.mod", Status: status.Stale},
+		{Repo: "owner/b", Module: "github.com/owner/b", File: "svc/go.mod", Status: status.Archived},
+	}, b.Findings)
+
+	path := filepath.Join(t.TempDir(), "arc-baseline.json")
+	require.NoError(t, Write(path, b))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, b, loaded)
+
+	// Moving a requirement to another line keeps it acknowledged, a new
+	// status or repository does not.
+	require.True(t, loaded.Contains(finding.Finding{Repo: "owner/b", Module: "github.com/owner/b", File: filepath.Join("svc", "go.mod"), Line: 20, Status: status.Archived}))
+	require.False(t, loaded.Contains(finding.Finding{Repo: "owner/a", Module: "github.com/owner/a", File: "go.mod", Status: status.Archived}))
+	require.False(t, loaded.Contains(finding.Finding{Repo: "owner/c", Module: "github.com/owner/c", File: "go.mod", Status: status.Archived}))
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "failed to read baseline")
+}
@@ -0,0 +1,234 @@
+// Package cargo provides commands for scanning Rust crate dependencies declared
+// in Cargo.toml and Cargo.lock files and reporting archived GitHub repositories.
+package cargo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/cratesio"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+	"github.com/wayneashleyberry/gh-arc/pkg/tomlscan"
+)
+
+// cratesIOSource is the source of registry crates in Cargo.lock files.
+const cratesIOSource = "registry+https://github.com/rust-lang/crates.io-index"
+
+// dependencyTables are the names of Cargo.toml tables that list dependencies.
+var dependencyTables = []string{"dependencies", "dev-dependencies", "build-dependencies"}
+
+// applyFields sets the crate name, version and git URL of dep from the
+// string fields in s, and reports whether s declares a local path.
+func applyFields(dep *resolve.Dependency, s string) bool {
+	local := false
+
+	for _, f := range tomlscan.StringFields(s) {
+		switch f.Key {
+		case "version":
+			dep.Version = f.Value
+		case "git":
+			dep.URL = f.Value
+		case "package":
+			dep.Name = f.Value
+		case "path":
+			local = true
+		}
+	}
+
+	return local
+}
+
+// parseManifest returns the dependencies declared in a Cargo.toml file.
+// Dependencies on local paths are omitted unless they are also fetched from
+// git.
+func parseManifest(name string, data []byte) []resolve.Dependency {
+	var (
+		deps   []*resolve.Dependency
+		local  = map[*resolve.Dependency]bool{}
+		inDeps bool
+		// table is the dependency declared by a [dependencies.NAME] header,
+		// whose fields follow on their own lines.
+		table *resolve.Dependency
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			keys := tomlscan.TableKeys(line)
+			last := len(keys) - 1
+
+			inDeps = slices.Contains(dependencyTables, keys[last])
+			table = nil
+
+			if last > 0 && slices.Contains(dependencyTables, keys[last-1]) {
+				table = &resolve.Dependency{Name: keys[last], File: name, Line: lineNo}
+				deps = append(deps, table)
+			}
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch {
+		case table != nil:
+			if applyFields(table, line) {
+				local[table] = true
+			}
+		case inDeps:
+			// Dotted keys such as `serde.workspace = true` name the crate
+			// before the first dot.
+			crate := tomlscan.TableKeys(key)[0]
+			dep := &resolve.Dependency{Name: crate, File: name, Line: lineNo}
+
+			value = strings.TrimSpace(value)
+			if strings.HasPrefix(value, "{") {
+				local[dep] = applyFields(dep, value)
+			} else if strings.HasPrefix(value, `"`) {
+				dep.Version = strings.Trim(value, `"`)
+			}
+
+			deps = append(deps, dep)
+		}
+	}
+
+	result := make([]resolve.Dependency, 0, len(deps))
+
+	for _, dep := range deps {
+		if local[dep] && dep.URL == "" {
+			continue
+		}
+
+		result = append(result, *dep)
+	}
+
+	return result
+}
+
+// parseLock returns the crates recorded in a Cargo.lock file that come from
+// crates.io or git. Workspace members and other local crates are omitted.
+func parseLock(name string, data []byte) []resolve.Dependency {
+	var (
+		deps   []resolve.Dependency
+		dep    *resolve.Dependency
+		source string
+	)
+
+	flush := func() {
+		if dep == nil {
+			return
+		}
+
+		switch {
+		case source == cratesIOSource:
+			deps = append(deps, *dep)
+		case strings.HasPrefix(source, "git+"):
+			dep.URL = source
+			deps = append(deps, *dep)
+		}
+
+		dep, source = nil, ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			flush()
+
+			if line == "[[package]]" {
+				dep = &resolve.Dependency{File: name, Line: lineNo, Indirect: true}
+			}
+
+			continue
+		}
+
+		if dep == nil {
+			continue
+		}
+
+		for _, f := range tomlscan.StringFields(line) {
+			switch f.Key {
+			case "name":
+				dep.Name = f.Value
+				dep.Line = lineNo
+			case "version":
+				dep.Version = f.Value
+			case "source":
+				source = f.Value
+			}
+		}
+	}
+
+	flush()
+
+	return deps
+}
+
+// Discover parses the given Cargo.toml and Cargo.lock files. Crates declared in
+// a Cargo.toml are direct dependencies and take their version from a lock file
+// when one records them; crates only found in a Cargo.lock are indirect.
+func Discover(ctx context.Context, manifests, lockfiles []string) []resolve.Dependency {
+	var direct, locked []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		direct = append(direct, parseManifest(name, data)...)
+	}
+
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		locked = append(locked, parseLock(name, data)...)
+	}
+
+	return resolve.Merge(direct, locked, func(name string) string {
+		return name
+	})
+}
+
+// Repos returns the repositories of the crates required in opts.Scope,
+// looking them up on crates.io.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	manifests, err := files.RecursiveFind(ctx, opts.Scope, "Cargo.toml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Cargo.toml files: %w", err)
+	}
+
+	lockfiles, err := files.RecursiveFind(ctx, opts.Scope, "Cargo.lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Cargo.lock files: %w", err)
+	}
+
+	return resolve.Repos(ctx, cratesio.NewWithHTTPClient(opts.Timeouts.HTTPClient(cratesio.DefaultBaseURL), cratesio.DefaultBaseURL), Discover(ctx, manifests, lockfiles), opts)
+}
@@ -0,0 +1,119 @@
+package cargo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/cratesio"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+const manifest = `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0"
+tokio = { version = "1", features = ["full"] }
+local = { path = "../local" }
+renamed = { package = "real-name", version = "2" }
+forked = { git = "https://github.com/owner/forked", branch = "main" }
+anyhow.workspace = true
+
+[target.'cfg(unix)'.dependencies]
+libc = "0.2"
+
+[dev-dependencies.criterion]
+version = "0.5"
+features = [
+    "html_reports",
+]
+`
+
+const lockfile = `version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+
+[[package]]
+name = "forked"
+version = "0.3.0"
+source = "git+https://github.com/owner/forked?branch=main#abc123"
+
+[[package]]
+name = "serde"
+version = "1.0.200"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "abc"
+
+[[package]]
+name = "itoa"
+version = "1.0.11"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	got := parseManifest("Cargo.toml", []byte(manifest))
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "serde", Version: "1.0", File: "Cargo.toml", Line: 6},
+		{Name: "tokio", Version: "1", File: "Cargo.toml", Line: 7},
+		{Name: "real-name", Version: "2", File: "Cargo.toml", Line: 9},
+		{Name: "forked", File: "Cargo.toml", Line: 10, URL: "https://github.com/owner/forked"},
+		{Name: "anyhow", File: "Cargo.toml", Line: 11},
+		{Name: "libc", Version: "0.2", File: "Cargo.toml", Line: 14},
+		{Name: "criterion", Version: "0.5", File: "Cargo.toml", Line: 16},
+	}, got)
+}
+
+func TestParseLock(t *testing.T) {
+	t.Parallel()
+
+	got := parseLock("Cargo.lock", []byte(lockfile))
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "forked", Version: "0.3.0", File: "Cargo.lock", Line: 8, Indirect: true, URL: "git+https://github.com/owner/forked?branch=main#abc123"},
+		{Name: "serde", Version: "1.0.200", File: "Cargo.lock", Line: 13, Indirect: true},
+		{Name: "itoa", Version: "1.0.11", File: "Cargo.lock", Line: 19, Indirect: true},
+	}, got)
+}
+
+func TestDiscoverAndResolve(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "Cargo.toml")
+	lockPath := filepath.Join(dir, "Cargo.lock")
+
+	require.NoError(t, os.WriteFile(manifestPath, []byte("[dependencies]\nserde = \"1\"\n"), 0o600))
+	require.NoError(t, os.WriteFile(lockPath, []byte(lockfile), 0o600))
+
+	deps := Discover(context.Background(), []string{manifestPath}, []string{lockPath})
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "serde", Version: "1.0.200", File: manifestPath, Line: 2},
+		{Name: "forked", Version: "0.3.0", File: lockPath, Line: 8, Indirect: true, URL: "git+https://github.com/owner/forked?branch=main#abc123"},
+		{Name: "itoa", Version: "1.0.11", File: lockPath, Line: 19, Indirect: true},
+	}, deps)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, []string{"itoa", "serde"}, r.URL.Query()["ids[]"])
+
+		_, _ = w.Write([]byte(`{"crates":[{"name":"serde","repository":"https://github.com/serde-rs/serde"},{"name":"itoa","repository":"https://gitlab.com/dtolnay/itoa"}]}`))
+	}))
+	defer srv.Close()
+
+	repos, err := resolve.Resolve(context.Background(), cratesio.NewWithHTTPClient(srv.Client(), srv.URL), deps)
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	require.Contains(t, repos, "serde-rs/serde")
+	require.Contains(t, repos, "owner/forked")
+}
@@ -0,0 +1,143 @@
+// Package chartrepo provides a minimal client for Helm chart repositories,
+// used to resolve charts to their source repositories through the sources
+// and home fields of the repository index.
+package chartrepo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+// concurrency is the number of repository indexes fetched at the same time.
+const concurrency = 4
+
+// Client fetches Helm chart repository indexes.
+type Client struct {
+	httpClient *http.Client
+}
+
+// New creates a Client.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 30 * time.Second})
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client (for testing).
+func NewWithHTTPClient(httpClient *http.Client) *Client {
+	return &Client{httpClient: httpClient}
+}
+
+// Name returns the name a chart is resolved by, its repository URL and chart
+// name joined by a slash, e.g. "https://charts.example.com/stable/redis".
+func Name(repository, chart string) string {
+	return strings.TrimSuffix(repository, "/") + "/" + chart
+}
+
+// entry is the part of a chart version in an index that links to its source.
+type entry struct {
+	Home    string   `yaml:"home"`
+	Sources []string `yaml:"sources"`
+}
+
+// Repositories returns the GitHub repository URL of each named chart, see
+// Name, taken from the newest version of the chart in the index of its
+// repository. Each index is fetched once. Charts that are missing from their
+// index or do not link to GitHub, and charts of indexes that cannot be
+// fetched, are omitted.
+func (c *Client) Repositories(ctx context.Context, names []string) (map[string]string, error) {
+	charts := map[string][]string{}
+
+	for _, name := range names {
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			continue
+		}
+
+		charts[name[:i]] = append(charts[name[:i]], name[i+1:])
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, concurrency)
+		urls = map[string]string{}
+	)
+
+	for repository, chartNames := range charts {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			entries, err := c.index(ctx, repository)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching chart repository %s: %v", repository, err))
+
+				return
+			}
+
+			for _, chart := range chartNames {
+				versions := entries[chart]
+				if len(versions) == 0 {
+					continue
+				}
+
+				for _, u := range append(versions[0].Sources, versions[0].Home) {
+					if _, ok := client.RepoFromURL(u); ok {
+						mu.Lock()
+						urls[Name(repository, chart)] = u
+						mu.Unlock()
+
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return urls, nil
+}
+
+// index returns the chart versions listed by the index.yaml of a chart
+// repository, newest first.
+func (c *Client) index(ctx context.Context, repository string) (map[string][]entry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(repository, "/")+"/index.yaml", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch index of %s: %w", repository, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch index of %s: %s", repository, resp.Status)
+	}
+
+	var body struct {
+		Entries map[string][]entry `yaml:"entries"`
+	}
+
+	if err := yaml.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode index of %s: %w", repository, err)
+	}
+
+	return body.Entries, nil
+}
@@ -0,0 +1,72 @@
+package chartrepo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		require.Equal(t, "/stable/index.yaml", r.URL.Path)
+
+		_, _ = w.Write([]byte(`apiVersion: v1
+entries:
+  redis:
+    - version: 2.0.0
+      home: https://redis.io
+      sources:
+        - https://hub.docker.com/_/redis
+        - https://github.com/owner/redis-chart
+    - version: 1.0.0
+      sources:
+        - https://github.com/owner/old-redis-chart
+  nginx:
+    - version: 1.0.0
+      home: https://github.com/owner/nginx-chart
+  internal:
+    - version: 1.0.0
+      home: https://example.com
+`))
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client())
+
+	got, err := c.Repositories(context.Background(), []string{
+		Name(srv.URL+"/stable", "redis"),
+		Name(srv.URL+"/stable/", "nginx"),
+		Name(srv.URL+"/stable", "internal"),
+		Name(srv.URL+"/stable", "missing"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		srv.URL + "/stable/redis": "https://github.com/owner/redis-chart",
+		srv.URL + "/stable/nginx": "https://github.com/owner/nginx-chart",
+	}, got)
+	require.Equal(t, 1, requests)
+}
+
+func TestRepositories_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client())
+
+	got, err := c.Repositories(context.Background(), []string{Name(srv.URL, "redis")})
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
@@ -0,0 +1,186 @@
+// Package check scans every supported ecosystem in a single pass, so
+// repositories shared between ecosystems are looked up once.
+package check
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/actions"
+	"github.com/wayneashleyberry/gh-arc/pkg/cargo"
+	"github.com/wayneashleyberry/gh-arc/pkg/composer"
+	"github.com/wayneashleyberry/gh-arc/pkg/docker"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gem"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/gradle"
+	"github.com/wayneashleyberry/gh-arc/pkg/helm"
+	"github.com/wayneashleyberry/gh-arc/pkg/maven"
+	"github.com/wayneashleyberry/gh-arc/pkg/pip"
+	"github.com/wayneashleyberry/gh-arc/pkg/precommit"
+	"github.com/wayneashleyberry/gh-arc/pkg/terraform"
+)
+
+// Ecosystem discovers the repositories of one kind of dependency.
+type Ecosystem struct {
+	// Name is the command that scans only this ecosystem, e.g. "gomod".
+	Name string
+	// Usage describes the command that scans only this ecosystem. It is
+	// empty for ecosystems whose command is not generated from their
+	// Ecosystem, such as gomod.
+	Usage string
+	// Indirect describes the --indirect flag of the command, and is empty
+	// for ecosystems without indirect dependencies.
+	Indirect string
+	// Repos returns the repositories of the dependencies beneath the
+	// current directory.
+	Repos func(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error)
+	// IsManifest reports whether Repos reads the file at p, a
+	// slash-separated path relative to the root of a repository.
+	IsManifest func(p string) bool
+}
+
+// named returns an IsManifest func matching files with one of names.
+func named(names ...string) func(p string) bool {
+	return func(p string) bool {
+		return slices.Contains(names, path.Base(p))
+	}
+}
+
+// Ecosystems lists every ecosystem scanned by ListArchived.
+var Ecosystems = []Ecosystem{
+	{
+		Name:       "gomod",
+		Repos:      gomod.Repos,
+		IsManifest: named("go.mod", "go.sum"),
+	},
+	{
+		Name:       "cargo",
+		Usage:      "List archived rust crates from Cargo.toml and Cargo.lock files",
+		Indirect:   "Include crates only found in Cargo.lock files",
+		Repos:      cargo.Repos,
+		IsManifest: named("Cargo.toml", "Cargo.lock"),
+	},
+	{
+		Name:       "pip",
+		Usage:      "List archived python packages from requirements.txt, pyproject.toml and poetry.lock files",
+		Indirect:   "Include packages only found in poetry.lock files",
+		Repos:      pip.Repos,
+		IsManifest: named(append(slices.Clone(pip.Manifests), pip.LockFile)...),
+	},
+	{
+		Name:       "gem",
+		Usage:      "List archived ruby gems from Gemfile and Gemfile.lock files",
+		Indirect:   "Include gems only found in Gemfile.lock files",
+		Repos:      gem.Repos,
+		IsManifest: named(gem.Manifest, gem.LockFile),
+	},
+	{
+		Name:       "composer",
+		Usage:      "List archived php packages from composer.json and composer.lock files",
+		Indirect:   "Include packages only found in composer.lock files",
+		Repos:      composer.Repos,
+		IsManifest: named(composer.Manifest, composer.LockFile),
+	},
+	{
+		Name:       "maven",
+		Usage:      "List archived java dependencies from pom.xml files",
+		Repos:      maven.Repos,
+		IsManifest: named(maven.Manifest),
+	},
+	{
+		Name:       "gradle",
+		Usage:      "List archived java dependencies from gradle build scripts and gradle.lockfile files",
+		Indirect:   "Include dependencies only found in gradle.lockfile files",
+		Repos:      gradle.Repos,
+		IsManifest: named(append(slices.Clone(gradle.Manifests), gradle.LockFile)...),
+	},
+	{
+		Name:  "actions",
+		Usage: "List archived github actions used by workflows and composite actions",
+		Repos: actions.Repos,
+		IsManifest: func(p string) bool {
+			if path.Dir(p) == ".github/workflows" {
+				return path.Ext(p) == ".yml" || path.Ext(p) == ".yaml"
+			}
+
+			return actions.IsActionMetadata(path.Base(p))
+		},
+	},
+	{
+		Name:       "precommit",
+		Usage:      "List archived hook repositories from .pre-commit-config.yaml files",
+		Repos:      precommit.Repos,
+		IsManifest: named(precommit.Config),
+	},
+	{
+		Name:  "docker",
+		Usage: "List archived base images and go tools referenced by Dockerfiles",
+		Repos: docker.Repos,
+		IsManifest: func(p string) bool {
+			return docker.IsDockerfile(path.Base(p))
+		},
+	},
+	{
+		Name:  "terraform",
+		Usage: "List archived modules and providers referenced by Terraform configurations",
+		Repos: terraform.Repos,
+		IsManifest: func(p string) bool {
+			return terraform.IsConfig(path.Base(p))
+		},
+	},
+	{
+		Name:       "helm",
+		Usage:      "List archived charts that Helm charts depend on",
+		Repos:      helm.Repos,
+		IsManifest: named(append(slices.Clone(helm.Manifests), helm.LockFiles...)...),
+	},
+}
+
+// Repos merges the repositories discovered by each ecosystem. A repository
+// used by several ecosystems is returned once, with every place it is used.
+func Repos(ctx context.Context, ecosystems []Ecosystem, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	merged := map[string][]gomod.RepoInfo{}
+
+	for _, eco := range ecosystems {
+		repos, err := eco.Repos(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s dependencies: %w", eco.Name, err)
+		}
+
+		slog.DebugContext(ctx, fmt.Sprintf("found %d repositories in %s dependencies", len(repos), eco.Name))
+
+		for repo, infos := range repos {
+			merged[repo] = append(merged[repo], infos...)
+		}
+	}
+
+	return merged, nil
+}
+
+// ListArchived lists archived, missing and otherwise unhealthy repositories of
+// the dependencies of e beneath the current directory, printing findings to
+// stdout.
+func (e Ecosystem) ListArchived(ctx context.Context, opts gomod.Options) (finding.Report, error) {
+	repos, err := Repos(ctx, []Ecosystem{e}, opts)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return gomod.NewScanner(opts, opts.Output).Check(ctx, repos)
+}
+
+// ListArchived lists archived, missing and otherwise unhealthy repositories of
+// the dependencies of every ecosystem beneath the current directory, printing
+// findings to stdout.
+func ListArchived(ctx context.Context, opts gomod.Options) (finding.Report, error) {
+	repos, err := Repos(ctx, Ecosystems, opts)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return gomod.NewScanner(opts, opts.Output).Check(ctx, repos)
+}
@@ -0,0 +1,58 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+func TestRepos(t *testing.T) {
+	t.Parallel()
+
+	ecosystems := []Ecosystem{
+		{Name: "go", Repos: func(context.Context, gomod.Options) (map[string][]gomod.RepoInfo, error) {
+			return map[string][]gomod.RepoInfo{
+				"owner/shared": {gomod.NewRepoInfo("go.mod", 3, 2, "github.com/owner/shared", "v1.0.0", false)},
+				"owner/go":     {gomod.NewRepoInfo("go.mod", 4, 2, "github.com/owner/go", "v1.0.0", false)},
+			}, nil
+		}},
+		{Name: "docker", Repos: func(context.Context, gomod.Options) (map[string][]gomod.RepoInfo, error) {
+			return map[string][]gomod.RepoInfo{
+				"owner/shared": {gomod.NewRepoInfo("Dockerfile", 5, 8, "github.com/owner/shared/cmd/tool", "v1.0.0", false)},
+			}, nil
+		}},
+	}
+
+	repos, err := Repos(context.Background(), ecosystems, gomod.Options{})
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	require.Len(t, repos["owner/shared"], 2)
+	require.Len(t, repos["owner/go"], 1)
+
+	ecosystems = append(ecosystems, Ecosystem{Name: "broken", Repos: func(context.Context, gomod.Options) (map[string][]gomod.RepoInfo, error) {
+		return nil, errors.New("boom")
+	}})
+
+	_, err = Repos(context.Background(), ecosystems, gomod.Options{})
+	require.ErrorContains(t, err, "failed to scan broken dependencies: boom")
+}
+
+func TestEcosystems(t *testing.T) {
+	t.Parallel()
+
+	names := map[string]bool{}
+
+	for _, eco := range Ecosystems {
+		require.False(t, names[eco.Name], eco.Name)
+		require.NotNil(t, eco.Repos, eco.Name)
+		require.NotNil(t, eco.IsManifest, eco.Name)
+
+		names[eco.Name] = true
+	}
+
+	require.True(t, Ecosystems[0].IsManifest("cmd/go.mod"))
+	require.False(t, Ecosystems[0].IsManifest("cmd/main.go"))
+}
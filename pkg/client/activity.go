@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Fork is a fork of a repository.
+type Fork struct {
+	FullName string `json:"full_name"`
+	Stars    int    `json:"stargazers_count"`
+	PushedAt string `json:"pushed_at"`
+	Archived bool   `json:"archived"`
+}
+
+// PullRequest is the part of a pull request used to judge how responsive
+// maintainers are.
+type PullRequest struct {
+	CreatedAt string `json:"created_at"`
+	ClosedAt  string `json:"closed_at"`
+}
+
+// Release is a published release of a repository.
+type Release struct {
+	TagName     string `json:"tag_name"`
+	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+}
+
+// Forks returns up to limit forks of repo, the most starred first.
+func (c *Client) Forks(ctx context.Context, repo string, limit int) ([]Fork, error) {
+	var forks []Fork
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/forks?sort=stargazers&per_page=%d", repo, limit), &forks); err != nil {
+		return nil, fmt.Errorf("failed to list forks of %s: %w", repo, err)
+	}
+
+	return forks, nil
+}
+
+// CommitsSince returns the number of commits on the default branch of repo
+// since the given time, counting at most 100.
+func (c *Client) CommitsSince(ctx context.Context, repo string, since time.Time) (int, error) {
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+
+	path := fmt.Sprintf("repos/%s/commits?per_page=100&since=%s", repo, url.QueryEscape(since.UTC().Format(time.RFC3339)))
+	if err := c.get(ctx, path, &commits); err != nil {
+		return 0, fmt.Errorf("failed to list commits of %s: %w", repo, err)
+	}
+
+	return len(commits), nil
+}
+
+// ClosedPullRequests returns up to limit of the most recently updated closed
+// pull requests of repo.
+func (c *Client) ClosedPullRequests(ctx context.Context, repo string, limit int) ([]PullRequest, error) {
+	var pulls []PullRequest
+
+	path := fmt.Sprintf("repos/%s/pulls?state=closed&sort=updated&direction=desc&per_page=%d", repo, limit)
+	if err := c.get(ctx, path, &pulls); err != nil {
+		return nil, fmt.Errorf("failed to list pull requests of %s: %w", repo, err)
+	}
+
+	return pulls, nil
+}
+
+// Releases returns up to limit of the most recent releases of repo.
+func (c *Client) Releases(ctx context.Context, repo string, limit int) ([]Release, error) {
+	var releases []Release
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/releases?per_page=%d", repo, limit), &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases of %s: %w", repo, err)
+	}
+
+	return releases, nil
+}
+
+// ErrNoRelease is returned when a repository has no published release.
+var ErrNoRelease = errors.New("no release")
+
+// LatestRelease returns the latest published release of repo, or
+// ErrNoRelease if it has none.
+func (c *Client) LatestRelease(ctx context.Context, repo string) (Release, error) {
+	var release Release
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/releases/latest", repo), &release); err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return Release{}, fmt.Errorf("%w: %s", ErrNoRelease, repo)
+		}
+
+		return Release{}, fmt.Errorf("failed to fetch latest release of %s: %w", repo, err)
+	}
+
+	return release, nil
+}
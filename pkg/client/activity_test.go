@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonRESTClient answers requests with canned JSON responses by path.
+func jsonRESTClient(t *testing.T, responses map[string]string) *mockRESTClient {
+	t.Helper()
+
+	return &mockRESTClient{getFunc: func(path string, v any) error {
+		body, ok := responses[path]
+		require.True(t, ok, "unexpected request %s", path)
+
+		return json.Unmarshal([]byte(body), v)
+	}}
+}
+
+func TestClient_Activity(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(jsonRESTClient(t, map[string]string{
+		"repos/old/repo/forks?sort=stargazers&per_page=5":                            `[{"full_name":"fork/repo","stargazers_count":12,"pushed_at":"2025-06-01T00:00:00Z","archived":false}]`,
+		"repos/fork/repo/commits?per_page=100&since=2025-04-02T00%3A00%3A00Z":        `[{"sha":"a"},{"sha":"b"}]`,
+		"repos/fork/repo/pulls?state=closed&sort=updated&direction=desc&per_page=30": `[{"created_at":"2025-06-01T00:00:00Z","closed_at":"2025-06-03T00:00:00Z"}]`,
+		"repos/fork/repo/releases?per_page=30":                                       `[{"published_at":"2025-05-01T00:00:00Z","draft":false}]`,
+	}))
+
+	forks, err := c.Forks(context.Background(), "old/repo", 5)
+	require.NoError(t, err)
+	require.Equal(t, []Fork{{FullName: "fork/repo", Stars: 12, PushedAt: "2025-06-01T00:00:00Z"}}, forks)
+
+	n, err := c.CommitsSince(context.Background(), "fork/repo", time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	pulls, err := c.ClosedPullRequests(context.Background(), "fork/repo", 30)
+	require.NoError(t, err)
+	require.Equal(t, []PullRequest{{CreatedAt: "2025-06-01T00:00:00Z", ClosedAt: "2025-06-03T00:00:00Z"}}, pulls)
+
+	releases, err := c.Releases(context.Background(), "fork/repo", 30)
+	require.NoError(t, err)
+	require.Equal(t, []Release{{PublishedAt: "2025-05-01T00:00:00Z"}}, releases)
+}
+
+func TestClient_LatestRelease(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{getFunc: func(path string, v any) error {
+		if path == "repos/none/repo/releases/latest" {
+			return &api.HTTPError{StatusCode: http.StatusNotFound}
+		}
+
+		require.Equal(t, "repos/owner/repo/releases/latest", path)
+
+		return json.Unmarshal([]byte(`{"tag_name":"v1.2.3","published_at":"2024-01-02T03:04:05Z","draft":false}`), v)
+	}})
+
+	release, err := c.LatestRelease(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, Release{TagName: "v1.2.3", PublishedAt: "2024-01-02T03:04:05Z"}, release)
+
+	_, err = c.LatestRelease(context.Background(), "none/repo")
+	require.ErrorIs(t, err, ErrNoRelease)
+}
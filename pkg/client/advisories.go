@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Ecosystems of the GitHub Advisory Database.
+const (
+	EcosystemGo      = "GO"
+	EcosystemRust    = "RUST"
+	EcosystemPip     = "PIP"
+	EcosystemActions = "ACTIONS"
+)
+
+// Vulnerability is an advisory affecting a range of versions of a package.
+type Vulnerability struct {
+	Advisory struct {
+		GHSAID      string `json:"ghsaId"`
+		Summary     string `json:"summary"`
+		Severity    string `json:"severity"`
+		Permalink   string `json:"permalink"`
+		WithdrawnAt string `json:"withdrawnAt"`
+	} `json:"advisory"`
+	// VulnerableVersionRange is a comma separated list of constraints,
+	// e.g. ">= 1.0.0, < 1.2.3".
+	VulnerableVersionRange string `json:"vulnerableVersionRange"`
+	FirstPatchedVersion    *struct {
+		Identifier string `json:"identifier"`
+	} `json:"firstPatchedVersion"`
+}
+
+// vulnerabilitiesQuery lists the advisories of a package. A hundred is more
+// than any package in the database has.
+const vulnerabilitiesQuery = `query($ecosystem: SecurityAdvisoryEcosystem!, $package: String!) {
+  securityVulnerabilities(ecosystem: $ecosystem, package: $package, first: 100) {
+    nodes {
+      advisory { ghsaId summary severity permalink withdrawnAt }
+      vulnerableVersionRange
+      firstPatchedVersion { identifier }
+    }
+  }
+}`
+
+// Vulnerabilities returns the advisories of the GitHub Advisory Database
+// affecting any version of pkg in ecosystem, one of the Ecosystem constants.
+func (c *Client) Vulnerabilities(ctx context.Context, ecosystem, pkg string) ([]Vulnerability, error) {
+	if c.graphQL == nil {
+		return nil, errors.New("no graphql client")
+	}
+
+	var resp struct {
+		SecurityVulnerabilities struct {
+			Nodes []Vulnerability `json:"nodes"`
+		} `json:"securityVulnerabilities"`
+	}
+
+	err := c.retry(ctx, "advisories of "+pkg, func() error {
+		return c.graphQL.DoWithContext(ctx, vulnerabilitiesQuery, map[string]any{"ecosystem": ecosystem, "package": pkg}, &resp)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query advisories of %s: %w", pkg, err)
+	}
+
+	return resp.SecurityVulnerabilities.Nodes, nil
+}
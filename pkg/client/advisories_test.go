@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockGraphQLClient answers every query with do.
+type mockGraphQLClient struct {
+	do func(query string, variables map[string]any, response any) error
+}
+
+func (m *mockGraphQLClient) DoWithContext(_ context.Context, query string, variables map[string]any, response any) error {
+	return m.do(query, variables, response)
+}
+
+func TestClient_Vulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClients(jsonRESTClient(t, nil), &mockGraphQLClient{do: func(_ string, variables map[string]any, response any) error {
+		require.Equal(t, map[string]any{"ecosystem": EcosystemGo, "package": "github.com/owner/repo"}, variables)
+
+		return json.Unmarshal([]byte(`{"securityVulnerabilities":{"nodes":[{
+			"advisory":{"ghsaId":"GHSA-aaaa-bbbb-cccc","summary":"Bad","severity":"HIGH","permalink":"https://github.com/advisories/GHSA-aaaa-bbbb-cccc","withdrawnAt":null},
+			"vulnerableVersionRange":"< 1.2.0",
+			"firstPatchedVersion":{"identifier":"1.2.0"}
+		}]}}`), response)
+	}})
+
+	vulns, err := c.Vulnerabilities(context.Background(), EcosystemGo, "github.com/owner/repo")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	require.Equal(t, "GHSA-aaaa-bbbb-cccc", vulns[0].Advisory.GHSAID)
+	require.Equal(t, "< 1.2.0", vulns[0].VulnerableVersionRange)
+	require.Equal(t, "1.2.0", vulns[0].FirstPatchedVersion.Identifier)
+
+	_, err = NewWithClient(jsonRESTClient(t, nil)).Vulnerabilities(context.Background(), EcosystemGo, "github.com/owner/repo")
+	require.Error(t, err)
+}
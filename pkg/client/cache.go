@@ -0,0 +1,310 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/wayneashleyberry/gh-arc/pkg/config"
+)
+
+// DefaultCacheTTL is how long cached repository metadata is used before it
+// is revalidated.
+const DefaultCacheTTL = time.Hour
+
+// CacheEntry is cached repository metadata.
+type CacheEntry struct {
+	// StoredAt is when Result was fetched or last revalidated.
+	StoredAt time.Time `json:"stored_at"`
+	// ETag is the entity tag of the response Result was decoded from, sent
+	// with If-None-Match when the entry is revalidated.
+	ETag   string     `json:"etag,omitempty"`
+	Result RepoResult `json:"result"`
+}
+
+// Cache stores repository metadata between lookups, keyed by "owner/repo".
+// Entries are kept beyond their TTL so they can be revalidated with a
+// conditional request, which does not count against the rate limit.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(ctx context.Context, repo string) (CacheEntry, bool)
+	Set(ctx context.Context, repo string, entry CacheEntry)
+}
+
+// MemoryCache is a Cache private to the process.
+type MemoryCache struct {
+	c *cache.Cache
+}
+
+// NewMemoryCache returns an empty in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{c: cache.New(cache.NoExpiration, 0)}
+}
+
+// Get returns the cached metadata of repo.
+func (m *MemoryCache) Get(_ context.Context, repo string) (CacheEntry, bool) {
+	cached, found := m.c.Get(repo)
+	if !found {
+		return CacheEntry{}, false
+	}
+
+	return cached.(CacheEntry), true
+}
+
+// Set caches the metadata of repo.
+func (m *MemoryCache) Set(_ context.Context, repo string, entry CacheEntry) {
+	m.c.Set(repo, entry, cache.NoExpiration)
+}
+
+// DiskCache is a Cache kept in a directory between runs, with an entry per
+// repository at "owner/repo.json". Entries are written to a temporary file
+// and renamed into place, so concurrent runs never read a partial entry.
+// Read and write errors are only logged since the GitHub API can always
+// answer instead.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a cache stored beneath dir, which is created as
+// entries are written.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+// Get returns the cached metadata of repo from disk.
+func (d *DiskCache) Get(ctx context.Context, repo string) (CacheEntry, bool) {
+	path, err := d.path(repo)
+	if err != nil {
+		slog.DebugContext(ctx, err.Error())
+
+		return CacheEntry{}, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.DebugContext(ctx, fmt.Sprintf("error reading %s from disk cache: %v", repo, err))
+		}
+
+		return CacheEntry{}, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("error decoding %s from disk cache: %v", repo, err))
+
+		return CacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Set writes the metadata of repo to disk.
+func (d *DiskCache) Set(ctx context.Context, repo string, entry CacheEntry) {
+	if err := d.store(repo, entry); err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("error writing %s to disk cache: %v", repo, err))
+	}
+}
+
+// store writes the entry of repo to a temporary file beside its path and
+// renames it into place.
+func (d *DiskCache) store(repo string, entry CacheEntry) error {
+	path, err := d.path(repo)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file holding the entry of repo, refusing names that
+// would resolve outside the cache directory.
+func (d *DiskCache) path(repo string) (string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || !filepath.IsLocal(owner) || !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid repository %q for disk cache", repo)
+	}
+
+	return filepath.Join(d.dir, owner, name+".json"), nil
+}
+
+// HTTPCache is a Cache shared through an HTTP key-value store, so CI jobs on
+// different runners look each repository up once between them instead of
+// each spending their own rate limit. Entries are read with GET and written
+// with PUT to the base URL followed by "owner/repo", which any store
+// speaking plain HTTP, such as a WebDAV server or a bucket behind a proxy,
+// can serve. Store errors are only logged since the GitHub API can always
+// answer instead.
+type HTTPCache struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPCache returns a cache stored beneath base, sending token as a bearer
+// token if it is set. A nil httpClient, or one without a timeout, is given
+// config.DefaultReadTimeout, so a store that stops responding cannot stall a
+// scan.
+func NewHTTPCache(httpClient *http.Client, base, token string) (*HTTPCache, error) {
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid cache url %q, expected an http or https url", base)
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	if httpClient.Timeout == 0 {
+		withTimeout := *httpClient
+		withTimeout.Timeout = config.DefaultReadTimeout
+		httpClient = &withTimeout
+	}
+
+	return &HTTPCache{
+		base:   strings.TrimSuffix(base, "/") + "/",
+		token:  token,
+		client: httpClient,
+	}, nil
+}
+
+// Get returns the cached metadata of repo from the store.
+func (h *HTTPCache) Get(ctx context.Context, repo string) (CacheEntry, bool) {
+	entry, err := h.fetch(ctx, repo)
+	if err != nil {
+		if !errors.Is(err, errCacheMiss) {
+			slog.DebugContext(ctx, fmt.Sprintf("error reading %s from shared cache: %v", repo, err))
+		}
+
+		return CacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Set writes the metadata of repo to the store.
+func (h *HTTPCache) Set(ctx context.Context, repo string, entry CacheEntry) {
+	if err := h.store(ctx, repo, entry); err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("error writing %s to shared cache: %v", repo, err))
+	}
+}
+
+// errCacheMiss is returned by fetch when the store has no entry.
+var errCacheMiss = errors.New("not cached")
+
+// fetch reads the entry of repo from the store.
+func (h *HTTPCache) fetch(ctx context.Context, repo string) (CacheEntry, error) {
+	req, err := h.request(ctx, http.MethodGet, repo, nil)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return CacheEntry{}, fmt.Errorf("failed to fetch cache entry: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return CacheEntry{}, errCacheMiss
+	case resp.StatusCode >= http.StatusBadRequest:
+		return CacheEntry{}, fmt.Errorf("failed to fetch cache entry: %s", resp.Status)
+	}
+
+	var entry CacheEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return CacheEntry{}, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// store writes the entry of repo to the store.
+func (h *HTTPCache) store(ctx context.Context, repo string, entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	req, err := h.request(ctx, http.MethodPut, repo, data)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to store cache entry: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// request returns a request for the entry of repo.
+func (h *HTTPCache) request(ctx context.Context, method, repo string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, h.base+repo, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache request: %w", err)
+	}
+
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	return req, nil
+}
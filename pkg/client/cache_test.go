@@ -0,0 +1,304 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/config"
+)
+
+// kvStore is an HTTP key-value store for HTTPCache tests.
+type kvStore struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	auth    string
+}
+
+func (s *kvStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.auth = r.Header.Get("Authorization")
+
+	switch r.Method {
+	case http.MethodGet:
+
+		data, ok := s.entries[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.entries[r.URL.Path] = data
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	want := CacheEntry{
+		StoredAt: time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC),
+		ETag:     `"abc"`,
+		Result:   RepoResult{FullName: "owner/repo", Archived: true, PushedAt: "2024-01-01T00:00:00Z"},
+	}
+
+	writer := NewDiskCache(dir)
+
+	_, found := writer.Get(context.Background(), "owner/repo")
+	require.False(t, found)
+
+	writer.Set(context.Background(), "owner/repo", want)
+	require.FileExists(t, filepath.Join(dir, "owner", "repo.json"))
+
+	// A later run reads the entry from disk.
+	got, found := NewDiskCache(dir).Get(context.Background(), "owner/repo")
+	require.True(t, found)
+	require.Equal(t, want, got)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "owner"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files are renamed into place")
+}
+
+func TestDiskCache_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c := NewDiskCache(filepath.Join(dir, "cache"))
+
+	c.Set(context.Background(), "../escape", CacheEntry{})
+	require.NoFileExists(t, filepath.Join(dir, "escape.json"))
+
+	_, found := c.Get(context.Background(), "../escape")
+	require.False(t, found)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cache", "owner"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cache", "owner", "repo.json"), []byte(`{"stored_at":`), 0o600))
+
+	_, found = c.Get(context.Background(), "owner/repo")
+	require.False(t, found, "a corrupt entry is a miss")
+}
+
+func TestHTTPCache(t *testing.T) {
+	t.Parallel()
+
+	store := &kvStore{entries: map[string][]byte{}}
+	srv := httptest.NewServer(store)
+	t.Cleanup(srv.Close)
+
+	want := CacheEntry{
+		StoredAt: time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC),
+		ETag:     `"abc"`,
+		Result:   RepoResult{FullName: "owner/repo", Archived: true, PushedAt: "2024-01-01T00:00:00Z"},
+	}
+
+	writer, err := NewHTTPCache(srv.Client(), srv.URL+"/arc/", "secret")
+	require.NoError(t, err)
+
+	_, found := writer.Get(context.Background(), "owner/repo")
+	require.False(t, found)
+
+	writer.Set(context.Background(), "owner/repo", want)
+	require.Contains(t, store.entries, "/arc/owner/repo")
+	require.Equal(t, "Bearer secret", store.auth)
+
+	// A cache on another runner reads the entry from the store.
+	reader, err := NewHTTPCache(srv.Client(), srv.URL+"/arc", "")
+	require.NoError(t, err)
+
+	got, found := reader.Get(context.Background(), "owner/repo")
+	require.True(t, found)
+	require.Equal(t, want, got)
+}
+
+func TestHTTPCache_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewHTTPCache(srv.Client(), srv.URL, "")
+	require.NoError(t, err)
+
+	c.Set(context.Background(), "owner/repo", CacheEntry{Result: RepoResult{Archived: true}})
+
+	_, found := c.Get(context.Background(), "owner/repo")
+	require.False(t, found)
+}
+
+func TestHTTPCache_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	hung := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-hung:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(hung) })
+
+	c, err := NewHTTPCache(srv.Client(), srv.URL, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, found := c.Get(ctx, "owner/repo")
+	require.False(t, found)
+	require.Error(t, ctx.Err(), "the lookup returned once its context was done")
+}
+
+func TestNewHTTPCache_Timeout(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewHTTPCache(http.DefaultClient, "https://cache.example.com", "")
+	require.NoError(t, err)
+	require.Equal(t, config.DefaultReadTimeout, c.client.Timeout)
+	require.Zero(t, http.DefaultClient.Timeout, "the client passed in is not modified")
+
+	c, err = NewHTTPCache(&http.Client{Timeout: time.Second}, "https://cache.example.com", "")
+	require.NoError(t, err)
+	require.Equal(t, time.Second, c.client.Timeout)
+}
+
+func TestNewHTTPCache_InvalidURL(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewHTTPCache(http.DefaultClient, "redis://localhost:6379", "")
+	require.Error(t, err)
+}
+
+func TestGetRepoResult_SharedCache(t *testing.T) {
+	t.Parallel()
+
+	shared := NewMemoryCache()
+	shared.Set(context.Background(), "owner/repo", CacheEntry{StoredAt: time.Now(), Result: RepoResult{Archived: true}})
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(string, any) error {
+			t.Fatal("unexpected request")
+
+			return nil
+		},
+	})
+	c.cache = shared
+
+	got, err := c.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.True(t, got.Archived)
+}
+
+func TestGetRepoResult_Revalidate(t *testing.T) {
+	t.Parallel()
+
+	var sent []string
+
+	c := NewWithClient(&mockRESTClient{
+		doFunc: func(ctx context.Context, method, path string, v any) error {
+			require.Equal(t, http.MethodGet, method)
+			require.Equal(t, "repos/owner/repo", path)
+
+			cond, ok := ctx.Value(conditionalKey{}).(*conditional)
+			require.True(t, ok)
+
+			sent = append(sent, cond.ifNoneMatch)
+
+			if cond.ifNoneMatch == `"v1"` {
+				return &api.HTTPError{StatusCode: http.StatusNotModified}
+			}
+
+			cond.etag = `"v1"`
+			v.(*RepoResult).Archived = true
+
+			return nil
+		},
+	})
+
+	expired := time.Now().Add(-2 * DefaultCacheTTL)
+
+	// An expired entry without an entity tag is fetched again.
+	c.cache.Set(context.Background(), "owner/repo", CacheEntry{StoredAt: expired, Result: RepoResult{Archived: false}})
+
+	got, err := c.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.True(t, got.Archived)
+
+	entry, found := c.cache.Get(context.Background(), "owner/repo")
+	require.True(t, found)
+	require.Equal(t, `"v1"`, entry.ETag)
+
+	// A fresh entry is used without a request.
+	_, err = c.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, sent)
+
+	// An expired entry with an entity tag is revalidated, and kept when
+	// it is unchanged.
+	entry.StoredAt = expired
+	c.cache.Set(context.Background(), "owner/repo", entry)
+
+	got, err = c.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.True(t, got.Archived)
+	require.Equal(t, []string{"", `"v1"`}, sent)
+
+	entry, found = c.cache.Get(context.Background(), "owner/repo")
+	require.True(t, found)
+	require.WithinDuration(t, time.Now(), entry.StoredAt, time.Minute)
+}
+
+func TestConditionalTransport(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ifNoneMatchHeader) == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set(etagHeader, `"v1"`)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+
+	hc := &http.Client{Transport: conditionalTransport{next: http.DefaultTransport}}
+
+	do := func(cond *conditional) int {
+		req, err := http.NewRequestWithContext(withConditional(context.Background(), cond), http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		return resp.StatusCode
+	}
+
+	var cond conditional
+	require.Equal(t, http.StatusOK, do(&cond))
+	require.Equal(t, `"v1"`, cond.etag)
+
+	cond.ifNoneMatch = cond.etag
+	require.Equal(t, http.StatusNotModified, do(&cond))
+}
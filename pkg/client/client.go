@@ -1,36 +1,175 @@
 // Package client provides a GitHub API client with transparent caching for repository metadata.
 // It allows efficient retrieval of repository information such as archived status and last push date,
-// reducing redundant API calls by using an in-memory cache.
+// reducing redundant API calls by using an in-memory or shared cache.
 package client
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
-	"github.com/patrickmn/go-cache"
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
+	"github.com/wayneashleyberry/gh-arc/pkg/config"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
 )
 
+// ErrRepoNotFound is returned when a repository no longer exists or is not
+// visible to the authenticated user.
+var ErrRepoNotFound = errors.New("repository not found")
+
+// Provider reports whether a repository is alive. Client answers using the
+// GitHub API; other implementations may answer the same question without it.
+type Provider interface {
+	GetRepoResult(ctx context.Context, repo string) (RepoResult, error)
+}
+
+// Fallback is a Provider that asks a secondary provider when the primary one
+// fails for any reason other than the repository not existing.
+type Fallback struct {
+	Primary   Provider
+	Secondary Provider
+}
+
+// GetRepoResult returns the primary provider's result, or the secondary's
+// result marked as degraded if the primary lookup failed for any reason other
+// than ctx being done.
+func (f Fallback) GetRepoResult(ctx context.Context, repo string) (RepoResult, error) {
+	result, err := f.Primary.GetRepoResult(ctx, repo)
+	if err == nil || errors.Is(err, ErrRepoNotFound) || ctx.Err() != nil {
+		return result, err
+	}
+
+	result, fallbackErr := f.Secondary.GetRepoResult(ctx, repo)
+	if fallbackErr != nil {
+		return RepoResult{}, fmt.Errorf("%w; git fallback: %w", err, fallbackErr)
+	}
+
+	result.Degraded = true
+
+	return result, nil
+}
+
 // CachedGitHubClient wraps the GitHub API client and transparently caches repo
 // results.
 // restClient defines the minimal interface needed for CachedGitHubClient.
 type restClient interface {
-	Get(path string, resp any) error
+	DoWithContext(ctx context.Context, method, path string, body io.Reader, resp any) error
+}
+
+// graphQLClient defines the minimal GraphQL interface needed by Client.
+type graphQLClient interface {
+	DoWithContext(ctx context.Context, query string, variables map[string]any, response any) error
 }
 
 // Client provides methods to interact with the GitHub API and transparently cache repository metadata.
 // It is safe for concurrent use by multiple goroutines.
 type Client struct {
-	client restClient
-	cache  *cache.Cache
+	client  restClient
+	graphQL graphQLClient
+	cache   Cache
+	// cacheTTL is how long cached metadata is used before it is
+	// revalidated.
+	cacheTTL time.Duration
+	// retries and retryDelay configure retrying transient errors.
+	retries    int
+	retryDelay time.Duration
+	// sleep waits between retries, returning early with an error when ctx
+	// is done.
+	sleep func(ctx context.Context, d time.Duration) error
+	// audit records cache hits and misses, if set.
+	audit *audit.Log
 }
 
 // RepoResult contains metadata about a GitHub repository, including its
-// archived status and last push date.
+// archived status, last push date, canonical name and fork parent.
 type RepoResult struct {
-	Archived bool   `json:"archived"`
-	PushedAt string `json:"pushed_at"`
+	Archived    bool   `json:"archived"`
+	PushedAt    string `json:"pushed_at"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	// Homepage is the website of the repository, which archived
+	// repositories often point at their replacement.
+	Homepage string `json:"homepage"`
+	// Topics are the topics the repository is labelled with.
+	Topics []string `json:"topics"`
+	// MirrorURL is the repository GitHub mirrors this one from, if it is
+	// a mirror.
+	MirrorURL string `json:"mirror_url"`
+	Fork      bool   `json:"fork"`
+	// DefaultBranch is the branch checked out by a clone, e.g. "main".
+	DefaultBranch string `json:"default_branch"`
+	// OpenIssues counts open issues and pull requests.
+	OpenIssues int `json:"open_issues_count"`
+	// LatestRelease is the latest published release. It is not part of the
+	// repository response and is only set when looked up separately.
+	LatestRelease *Release `json:"latest_release,omitempty"`
+	// License is the license GitHub detected in the repository, or nil if
+	// it has none.
+	License *License `json:"license"`
+	// Parent is the repository this one was forked from, if it is a fork.
+	Parent *RepoResult `json:"parent,omitempty"`
+	// Degraded is set when the result came from a provider that cannot
+	// report every field, such as git probing, which never knows whether a
+	// repository is archived.
+	Degraded bool `json:"-"`
+}
+
+// License is a license detected by GitHub.
+type License struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// SPDXID is the SPDX identifier, e.g. "MIT", or "NOASSERTION" for a
+	// license GitHub could not identify.
+	SPDXID string `json:"spdx_id"`
+}
+
+// CorrelationIDHeader is the request header used to propagate a correlation ID.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// Options configures how the client identifies itself to the GitHub API.
+type Options struct {
+	// Host is the GitHub host to query, such as a GitHub Enterprise Server
+	// hostname. Empty means the default host of the gh CLI.
+	Host string
+	// UserAgent is appended to the default User-Agent, e.g. "acme-ci/1.0".
+	UserAgent string
+	// CorrelationID is sent in the CorrelationIDHeader on every request so
+	// proxies and support requests can attribute traffic to a run.
+	CorrelationID string
+	// Timeout configures the network timeouts of API requests. Zero values
+	// keep the defaults of the underlying client.
+	Timeout config.Timeout
+	// Retries is how often a lookup is retried after a server or connection
+	// error. Zero disables retries; rate limited requests are always retried.
+	Retries int
+	// RetryDelay is the wait before the first retry, which doubles with
+	// every further retry. Zero means DefaultRetryDelay.
+	RetryDelay time.Duration
+	// Audit records whether each lookup was answered from the cache. Nil
+	// disables recording.
+	Audit *audit.Log
+	// Cache stores repository metadata. Nil means an in-memory cache
+	// private to the client.
+	Cache Cache
+	// CacheTTL is how long cached metadata is used before it is
+	// revalidated with a conditional request. Zero means DefaultCacheTTL.
+	CacheTTL time.Duration
+}
+
+// NewCorrelationID returns a random identifier suitable for Options.CorrelationID.
+func NewCorrelationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
 }
 
 // New creates a new CachedGitHubClient with a default REST
@@ -39,46 +178,253 @@ type RepoResult struct {
 // cannot be created.
 // New creates a new CachedGitHubClient with a default REST client and an in-memory cache.
 func New() (*Client, error) {
-	client, err := api.DefaultRESTClient()
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates a new CachedGitHubClient that identifies itself
+// according to opts.
+func NewWithOptions(opts Options) (*Client, error) {
+	clientOpts := clientOptions(opts)
+
+	client, err := api.NewRESTClient(clientOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitHub API client: %w", err)
 	}
 
-	c := cache.New(1*time.Hour, 2*time.Hour)
+	graphQL, err := api.NewGraphQLClient(clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub GraphQL client: %w", err)
+	}
+
+	var c Cache = NewMemoryCache()
+	if opts.Cache != nil {
+		c = opts.Cache
+	}
+
+	cacheTTL := opts.CacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+
+	retryDelay := opts.RetryDelay
+	if retryDelay == 0 {
+		retryDelay = DefaultRetryDelay
+	}
 
-	return &Client{client: client, cache: c}, nil
+	return &Client{client: client, graphQL: graphQL, cache: c, cacheTTL: cacheTTL, retries: opts.Retries, retryDelay: retryDelay, sleep: sleepContext, audit: opts.Audit}, nil
+}
+
+// clientOptions returns the options of the gh API clients for opts.
+func clientOptions(opts Options) api.ClientOptions {
+	headers := map[string]string{}
+
+	if opts.UserAgent != "" {
+		headers["User-Agent"] = fmt.Sprintf("gh-arc/%s %s", version.Get().Version, opts.UserAgent)
+	}
+
+	if opts.CorrelationID != "" {
+		headers[CorrelationIDHeader] = opts.CorrelationID
+	}
+
+	transport := opts.Timeout.Transport()
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return api.ClientOptions{
+		Host:      opts.Host,
+		Headers:   headers,
+		Timeout:   opts.Timeout.Read,
+		Transport: rateLimitTransport{next: conditionalTransport{next: transport}},
+	}
 }
 
 // NewWithClient allows injecting a custom REST client (for testing).
 func NewWithClient(client restClient) *Client {
-	c := cache.New(1*time.Hour, 2*time.Hour)
+	return &Client{client: client, cache: NewMemoryCache(), cacheTTL: DefaultCacheTTL, retryDelay: DefaultRetryDelay, sleep: sleepContext}
+}
 
-	return &Client{client: client, cache: c}
+// NewWithClients is like NewWithClient and also injects a GraphQL client.
+func NewWithClients(client restClient, graphQL graphQLClient) *Client {
+	c := NewWithClient(client)
+	c.graphQL = graphQL
+
+	return c
 }
 
 // GetRepoResult returns the archived status and last push date for a GitHub
-// repository. It transparently caches results to avoid redundant API calls. The
-// repo argument should be in the form "owner/repo".
-func (c *Client) GetRepoResult(repo string) (RepoResult, error) {
-	if cached, found := c.cache.Get(repo); found {
-		return cached.(RepoResult), nil
+// repository. It transparently caches results to avoid redundant API calls,
+// revalidates expired results with a conditional request, and backs off and
+// retries when rate limited or after transient errors. The repo argument
+// should be in the form "owner/repo".
+//
+// GitHub redirects renamed and transferred repositories, so the FullName of
+// the result is their current name. Results are cached under both names,
+// case-insensitively like GitHub matches them, so the old and new name of a
+// repository are only looked up once.
+func (c *Client) GetRepoResult(ctx context.Context, repo string) (RepoResult, error) {
+	cached, found := c.cache.Get(ctx, cacheKey(repo))
+	if found && time.Since(cached.StoredAt) < c.cacheTTL {
+		c.audit.Record(audit.CacheHit, repo, "")
+
+		return cached.Result, nil
 	}
 
+	c.audit.Record(audit.CacheMiss, repo, "")
+
 	ownerRepo := strings.Split(repo, "/")
 	if len(ownerRepo) != 2 {
 		return RepoResult{}, fmt.Errorf("invalid repo: %s", repo)
 	}
 
-	var result RepoResult
+	var (
+		result RepoResult
+		cond   conditional
+	)
 
-	path := fmt.Sprintf("repos/%s/%s", ownerRepo[0], ownerRepo[1])
+	if found {
+		cond.ifNoneMatch = cached.ETag
+	}
 
-	err := c.client.Get(path, &result)
+	err := c.getConditional(ctx, fmt.Sprintf("repos/%s/%s", ownerRepo[0], ownerRepo[1]), &cond, &result)
 	if err != nil {
+		if found && isNotModified(err) {
+			c.audit.Record(audit.CacheRevalidated, repo, "")
+
+			cached.StoredAt = time.Now()
+			c.cache.Set(ctx, cacheKey(repo), cached)
+
+			return cached.Result, nil
+		}
+
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return RepoResult{}, fmt.Errorf("%w: %s", ErrRepoNotFound, repo)
+		}
+
 		return RepoResult{}, fmt.Errorf("failed to fetch repo %s: %w", repo, err)
 	}
 
-	c.cache.Set(repo, result, cache.DefaultExpiration)
+	entry := CacheEntry{StoredAt: time.Now(), ETag: cond.etag, Result: result}
+	c.cache.Set(ctx, cacheKey(repo), entry)
+
+	if result.FullName != "" && cacheKey(result.FullName) != cacheKey(repo) {
+		c.cache.Set(ctx, cacheKey(result.FullName), entry)
+	}
 
 	return result, nil
 }
+
+// cacheKey returns the key repo is cached under. Repository names are
+// case-insensitive.
+func cacheKey(repo string) string {
+	return strings.ToLower(repo)
+}
+
+// getConditional is like get, but sends the entity tag in cond and records
+// the entity tag of the response in it.
+func (c *Client) getConditional(ctx context.Context, path string, cond *conditional, resp any) error {
+	return c.retry(ctx, path, func() error {
+		return c.client.DoWithContext(withConditional(ctx, cond), http.MethodGet, path, nil, resp)
+	})
+}
+
+// get fetches path into resp, backing off and retrying when rate limited or
+// after transient errors.
+func (c *Client) get(ctx context.Context, path string, resp any) error {
+	return c.retry(ctx, path, func() error {
+		return c.client.DoWithContext(ctx, http.MethodGet, path, nil, resp)
+	})
+}
+
+// retry calls request until it succeeds, backing off when rate limited or
+// after transient errors, and gives up without waiting for the next attempt
+// once ctx is done. name identifies the request in logs.
+func (c *Client) retry(ctx context.Context, name string, request func() error) error {
+	err := request()
+
+	for attempt := 0; err != nil && ctx.Err() == nil; attempt++ {
+		delay, ok := c.backoff(err, attempt, time.Now())
+		if !ok {
+			break
+		}
+
+		slog.DebugContext(ctx, fmt.Sprintf("error fetching %s, retrying in %s: %v", name, delay.Round(time.Millisecond), err))
+
+		if err := c.sleep(ctx, delay); err != nil {
+			return err
+		}
+
+		err = request()
+	}
+
+	return err
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// RateLimit is the state of the core REST API rate limit.
+type RateLimit struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+	// Reset is when the limit resets, in Unix seconds.
+	Reset int64 `json:"reset"`
+}
+
+// RateLimit returns the current core rate limit. Checking it does not count
+// against the limit.
+func (c *Client) RateLimit(ctx context.Context) (RateLimit, error) {
+	var resp struct {
+		Resources struct {
+			Core RateLimit `json:"core"`
+		} `json:"resources"`
+	}
+
+	if err := c.client.DoWithContext(ctx, http.MethodGet, "rate_limit", nil, &resp); err != nil {
+		return RateLimit{}, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+
+	return resp.Resources.Core, nil
+}
+
+// RepoFromURL returns the "owner/repo" part of a GitHub repository URL, such as
+// the repository field of a package registry entry. Schemes, "git+" prefixes,
+// ".git" suffixes and paths below the repository are ignored.
+func RepoFromURL(rawURL string) (string, bool) {
+	u := strings.TrimSpace(rawURL)
+	u = strings.TrimPrefix(u, "git+")
+
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	}
+
+	u = strings.TrimPrefix(u, "git@")
+	u = strings.TrimPrefix(u, "www.")
+
+	if !strings.HasPrefix(u, "github.com/") && !strings.HasPrefix(u, "github.com:") {
+		return "", false
+	}
+
+	u = u[len("github.com/"):]
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+
+	parts := strings.Split(u, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+
+	return parts[0] + "/" + strings.TrimSuffix(parts[1], ".git"), true
+}
@@ -1,21 +1,40 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
 	"testing"
+	"time"
 
-	"github.com/patrickmn/go-cache"
+	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/stretchr/testify/require"
 )
 
-// mockRESTClient implements the minimal interface needed for testing
-// Only Get is used in CachedGitHubClient
-
+// mockRESTClient implements the minimal interface needed for testing.
 type mockRESTClient struct {
-	getFunc func(string, any) error
+	getFunc   func(string, any) error
+	postFunc  func(string, io.Reader, any) error
+	patchFunc func(string, io.Reader, any) error
+	// doFunc answers every request. Nil means requests are answered by
+	// getFunc, postFunc or patchFunc according to their method.
+	doFunc func(context.Context, string, string, any) error
 }
 
-func (m *mockRESTClient) Get(path string, v any) error {
+func (m *mockRESTClient) DoWithContext(ctx context.Context, method, path string, body io.Reader, v any) error {
+	if m.doFunc != nil {
+		return m.doFunc(ctx, method, path, v)
+	}
+
+	switch method {
+	case http.MethodPost:
+		return m.postFunc(path, body, v)
+	case http.MethodPatch:
+		return m.patchFunc(path, body, v)
+	}
+
 	return m.getFunc(path, v)
 }
 
@@ -28,15 +47,36 @@ func TestNew(t *testing.T) {
 	require.NotNil(t, c.cache)
 }
 
+func TestClientOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := clientOptions(Options{Host: "github.example.com", UserAgent: "acme-ci/1.0", CorrelationID: "abc123"})
+	require.Equal(t, "github.example.com", opts.Host)
+	require.Regexp(t, `^gh-arc/\S+ acme-ci/1\.0$`, opts.Headers["User-Agent"])
+	require.Equal(t, "abc123", opts.Headers[CorrelationIDHeader])
+	require.NotNil(t, opts.Transport)
+
+	opts = clientOptions(Options{})
+	require.Empty(t, opts.Headers)
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	t.Parallel()
+
+	id := NewCorrelationID()
+	require.Len(t, id, 32)
+	require.NotEqual(t, id, NewCorrelationID())
+}
+
 func TestGetRepoResult_CacheHit(t *testing.T) {
 	t.Parallel()
 
 	c := NewWithClient(&mockRESTClient{})
 	repo := "owner/repo"
 	want := RepoResult{Archived: true, PushedAt: "2024-01-01T00:00:00Z"}
-	c.cache.Set(repo, want, cache.DefaultExpiration)
+	c.cache.Set(context.Background(), repo, CacheEntry{StoredAt: time.Now(), Result: want})
 
-	got, err := c.GetRepoResult(repo)
+	got, err := c.GetRepoResult(context.Background(), repo)
 	require.NoError(t, err)
 	require.Equal(t, want, got)
 }
@@ -46,7 +86,7 @@ func TestGetRepoResult_InvalidRepo(t *testing.T) {
 
 	c := NewWithClient(&mockRESTClient{})
 
-	_, err := c.GetRepoResult("invalidrepo")
+	_, err := c.GetRepoResult(context.Background(), "invalidrepo")
 	require.Error(t, err)
 }
 
@@ -59,11 +99,25 @@ func TestGetRepoResult_APIFailure(t *testing.T) {
 		},
 	})
 
-	_, err := c.GetRepoResult("owner/repo")
+	_, err := c.GetRepoResult(context.Background(), "owner/repo")
 	require.Error(t, err)
 	require.Equal(t, "failed to fetch repo owner/repo: api error", err.Error())
 }
 
+func TestGetRepoResult_NotFound(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(_ string, _ any) error {
+			return &api.HTTPError{StatusCode: http.StatusNotFound}
+		},
+	})
+
+	_, err := c.GetRepoResult(context.Background(), "owner/repo")
+	require.ErrorIs(t, err, ErrRepoNotFound)
+	require.Equal(t, "repository not found: owner/repo", err.Error())
+}
+
 func TestGetRepoResult_APISuccess(t *testing.T) {
 	t.Parallel()
 
@@ -81,14 +135,129 @@ func TestGetRepoResult_APISuccess(t *testing.T) {
 	})
 	repo := "owner/repo"
 
-	got, err := c.GetRepoResult(repo)
+	got, err := c.GetRepoResult(context.Background(), repo)
 	require.NoError(t, err)
 
 	require.False(t, got.Archived)
 	require.Equal(t, "2025-07-18T12:00:00Z", got.PushedAt)
 
 	// Should be cached now
-	cached, found := c.cache.Get(repo)
+	cached, found := c.cache.Get(context.Background(), repo)
 	require.True(t, found)
-	require.Equal(t, cached, got)
+	require.Equal(t, cached.Result, got)
+}
+
+func TestGetRepoResult_Renamed(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(path string, v any) error {
+			calls++
+
+			require.Equal(t, "repos/Old-Owner/repo", path)
+
+			r, ok := v.(*RepoResult)
+			if !ok {
+				return errors.New("wrong type")
+			}
+
+			r.FullName = "new-owner/repo"
+
+			return nil
+		},
+	})
+
+	got, err := c.GetRepoResult(context.Background(), "Old-Owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, "new-owner/repo", got.FullName)
+
+	for _, repo := range []string{"old-owner/repo", "new-owner/repo", "New-Owner/Repo"} {
+		got, err = c.GetRepoResult(context.Background(), repo)
+		require.NoError(t, err)
+		require.Equal(t, "new-owner/repo", got.FullName)
+	}
+
+	require.Equal(t, 1, calls)
+}
+
+func TestFallback(t *testing.T) {
+	t.Parallel()
+
+	failing := &mockRESTClient{
+		getFunc: func(_ string, _ any) error {
+			return errors.New("api error")
+		},
+	}
+	missing := &mockRESTClient{
+		getFunc: func(_ string, _ any) error {
+			return &api.HTTPError{StatusCode: http.StatusNotFound}
+		},
+	}
+	healthy := &mockRESTClient{
+		getFunc: func(_ string, v any) error {
+			r, ok := v.(*RepoResult)
+			if !ok {
+				return errors.New("wrong type")
+			}
+			r.PushedAt = "2025-07-18T12:00:00Z"
+
+			return nil
+		},
+	}
+
+	got, err := Fallback{NewWithClient(failing), NewWithClient(healthy)}.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.True(t, got.Degraded)
+	require.Equal(t, "2025-07-18T12:00:00Z", got.PushedAt)
+
+	_, err = Fallback{NewWithClient(missing), NewWithClient(healthy)}.GetRepoResult(context.Background(), "owner/repo")
+	require.ErrorIs(t, err, ErrRepoNotFound)
+
+	_, err = Fallback{NewWithClient(failing), NewWithClient(failing)}.GetRepoResult(context.Background(), "owner/repo")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "\n", "the error is used in finding reasons")
+	require.Contains(t, err.Error(), "; git fallback: ")
+}
+
+func TestRepoFromURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url  string
+		want string
+		ok   bool
+	}{
+		{"https://github.com/serde-rs/serde", "serde-rs/serde", true},
+		{"https://github.com/tokio-rs/tokio/tree/master/tokio", "tokio-rs/tokio", true},
+		{"git+https://github.com/owner/repo.git?branch=main#abc123", "owner/repo", true},
+		{"git@github.com:owner/repo.git", "owner/repo", true},
+		{"https://www.github.com/owner/repo/", "owner/repo", true},
+		{"https://gitlab.com/owner/repo", "", false},
+		{"https://github.com/", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := RepoFromURL(tt.url)
+		require.Equal(t, tt.ok, ok, tt.url)
+		require.Equal(t, tt.want, got, tt.url)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(path string, v any) error {
+			require.Equal(t, "rate_limit", path)
+
+			return json.Unmarshal([]byte(`{"resources":{"core":{"limit":5000,"remaining":4990,"reset":1752840000}}}`), v)
+		},
+	})
+
+	got, err := c.RateLimit(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, RateLimit{Limit: 5000, Remaining: 4990, Reset: 1752840000}, got)
 }
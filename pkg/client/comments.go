@@ -0,0 +1,69 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Comment is a comment on an issue or pull request.
+type Comment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// IssueComments returns the comments on issue or pull request number of
+// repo, oldest first.
+func (c *Client) IssueComments(ctx context.Context, repo string, number int) ([]Comment, error) {
+	var comments []Comment
+
+	for page := 1; ; page++ {
+		var batch []Comment
+
+		if err := c.get(ctx, fmt.Sprintf("repos/%s/issues/%d/comments?per_page=%d&page=%d", repo, number, issuesPageSize, page), &batch); err != nil {
+			return nil, fmt.Errorf("failed to list comments on %s#%d: %w", repo, number, err)
+		}
+
+		comments = append(comments, batch...)
+
+		if len(batch) < issuesPageSize {
+			return comments, nil
+		}
+	}
+}
+
+// CreateComment comments body on issue or pull request number of repo. Like
+// CreateIssue, it is not retried.
+func (c *Client) CreateComment(ctx context.Context, repo string, number int, body string) (Comment, error) {
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to encode comment: %w", err)
+	}
+
+	var created Comment
+
+	if err := c.client.DoWithContext(ctx, http.MethodPost, fmt.Sprintf("repos/%s/issues/%d/comments", repo, number), bytes.NewReader(data), &created); err != nil {
+		return Comment{}, fmt.Errorf("failed to comment on %s#%d: %w", repo, number, err)
+	}
+
+	return created, nil
+}
+
+// UpdateComment replaces the body of comment id in repo.
+func (c *Client) UpdateComment(ctx context.Context, repo string, id int64, body string) (Comment, error) {
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to encode comment: %w", err)
+	}
+
+	var updated Comment
+
+	if err := c.client.DoWithContext(ctx, http.MethodPatch, fmt.Sprintf("repos/%s/issues/comments/%d", repo, id), bytes.NewReader(data), &updated); err != nil {
+		return Comment{}, fmt.Errorf("failed to update comment %d in %s: %w", id, repo, err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Comments(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(path string, v any) error {
+			require.Equal(t, "repos/owner/repo/issues/5/comments?per_page=100&page=1", path)
+
+			return json.Unmarshal([]byte(`[{"id": 1, "body": "hello"}]`), v)
+		},
+		postFunc: func(path string, body io.Reader, v any) error {
+			require.Equal(t, "repos/owner/repo/issues/5/comments", path)
+
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"body": "new"}`, string(data))
+
+			return json.Unmarshal([]byte(`{"id": 2, "body": "new"}`), v)
+		},
+		patchFunc: func(path string, body io.Reader, v any) error {
+			require.Equal(t, "repos/owner/repo/issues/comments/1", path)
+
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"body": "edited"}`, string(data))
+
+			return json.Unmarshal([]byte(`{"id": 1, "body": "edited"}`), v)
+		},
+	})
+
+	comments, err := c.IssueComments(context.Background(), "owner/repo", 5)
+	require.NoError(t, err)
+	require.Equal(t, []Comment{{ID: 1, Body: "hello"}}, comments)
+
+	created, err := c.CreateComment(context.Background(), "owner/repo", 5, "new")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), created.ID)
+
+	updated, err := c.UpdateComment(context.Background(), "owner/repo", 1, "edited")
+	require.NoError(t, err)
+	require.Equal(t, "edited", updated.Body)
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Conditional request headers.
+const (
+	etagHeader        = "ETag"
+	ifNoneMatchHeader = "If-None-Match"
+)
+
+// conditionalKey is the context key of a *conditional.
+type conditionalKey struct{}
+
+// conditional carries the entity tag of a cached response into a request,
+// and the entity tag of the response back out, since the REST client does
+// not expose either.
+type conditional struct {
+	// ifNoneMatch is sent with If-None-Match, if set.
+	ifNoneMatch string
+	// etag is the ETag of the response.
+	etag string
+}
+
+// withConditional returns ctx carrying cond to conditionalTransport.
+func withConditional(ctx context.Context, cond *conditional) context.Context {
+	return context.WithValue(ctx, conditionalKey{}, cond)
+}
+
+// conditionalTransport makes requests whose context carries a conditional
+// conditional on its entity tag, and records the entity tag of their
+// responses.
+type conditionalTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cond, ok := req.Context().Value(conditionalKey{}).(*conditional)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	if cond.ifNoneMatch != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(ifNoneMatchHeader, cond.ifNoneMatch)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	cond.etag = resp.Header.Get(etagHeader)
+
+	return resp, nil
+}
+
+// isNotModified reports whether err is a 304 response to a conditional
+// request, meaning the cached response is still current.
+func isNotModified(err error) bool {
+	var httpErr *api.HTTPError
+
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotModified
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// issuesPageSize is the number of issues requested per page, the most the
+// API allows.
+const issuesPageSize = 100
+
+// Issue is a GitHub issue.
+type Issue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	// PullRequest is set when the issue is a pull request, which the issues
+	// API also lists.
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+// NewIssue is an issue to be opened.
+type NewIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// OpenIssues returns the open issues of repo with the given label. Pull
+// requests are left out.
+func (c *Client) OpenIssues(ctx context.Context, repo, label string) ([]Issue, error) {
+	var issues []Issue
+
+	for page := 1; ; page++ {
+		var batch []Issue
+
+		path := fmt.Sprintf("repos/%s/issues?state=open&labels=%s&per_page=%d&page=%d", repo, url.QueryEscape(label), issuesPageSize, page)
+		if err := c.get(ctx, path, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list issues of %s: %w", repo, err)
+		}
+
+		for _, issue := range batch {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+
+		if len(batch) < issuesPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// CreateIssue opens an issue in repo. It is not retried, since a request
+// that failed after reaching GitHub may still have opened the issue.
+func (c *Client) CreateIssue(ctx context.Context, repo string, issue NewIssue) (Issue, error) {
+	body, err := json.Marshal(issue)
+	if err != nil {
+		return Issue{}, fmt.Errorf("failed to encode issue: %w", err)
+	}
+
+	var created Issue
+
+	if err := c.client.DoWithContext(ctx, http.MethodPost, fmt.Sprintf("repos/%s/issues", repo), bytes.NewReader(body), &created); err != nil {
+		return Issue{}, fmt.Errorf("failed to create issue in %s: %w", repo, err)
+	}
+
+	return created, nil
+}
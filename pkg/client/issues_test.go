@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_OpenIssues(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{getFunc: func(path string, v any) error {
+		require.Equal(t, "repos/owner/repo/issues?state=open&labels=gh-arc&per_page=100&page=1", path)
+
+		return json.Unmarshal([]byte(`[
+			{"number": 1, "title": "archived", "body": "b"},
+			{"number": 2, "title": "pr", "pull_request": {}}
+		]`), v)
+	}})
+
+	issues, err := c.OpenIssues(context.Background(), "owner/repo", "gh-arc")
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, 1, issues[0].Number)
+}
+
+func TestClient_CreateIssue(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{postFunc: func(path string, body io.Reader, v any) error {
+		require.Equal(t, "repos/owner/repo/issues", path)
+
+		var issue NewIssue
+		require.NoError(t, json.NewDecoder(body).Decode(&issue))
+		require.Equal(t, NewIssue{Title: "t", Body: "b", Labels: []string{"gh-arc"}}, issue)
+
+		return json.Unmarshal([]byte(`{"number": 7, "html_url": "https://github.com/owner/repo/issues/7"}`), v)
+	}})
+
+	issue, err := c.CreateIssue(context.Background(), "owner/repo", NewIssue{Title: "t", Body: "b", Labels: []string{"gh-arc"}})
+	require.NoError(t, err)
+	require.Equal(t, 7, issue.Number)
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// orgPageSize is the number of repositories requested per page, the most the
+// API allows.
+const orgPageSize = 100
+
+// OrgRepo is a repository of an organization.
+type OrgRepo struct {
+	FullName      string `json:"full_name"`
+	Language      string `json:"language"`
+	Archived      bool   `json:"archived"`
+	Fork          bool   `json:"fork"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// OrgRepos returns every repository of org visible to the authenticated
+// user.
+func (c *Client) OrgRepos(ctx context.Context, org string) ([]OrgRepo, error) {
+	var repos []OrgRepo
+
+	for page := 1; ; page++ {
+		var batch []OrgRepo
+
+		if err := c.get(ctx, fmt.Sprintf("orgs/%s/repos?per_page=%d&page=%d", url.PathEscape(org), orgPageSize, page), &batch); err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", org, err)
+		}
+
+		repos = append(repos, batch...)
+
+		if len(batch) < orgPageSize {
+			return repos, nil
+		}
+	}
+}
+
+// Files returns the paths of every file in repo at ref. Very large
+// repositories are truncated by the API, in which case only some paths are
+// returned.
+func (c *Client) Files(ctx context.Context, repo, ref string) ([]string, error) {
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"tree"`
+	}
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/git/trees/%s?recursive=1", repo, url.PathEscape(ref)), &tree); err != nil {
+		return nil, fmt.Errorf("failed to list files of %s: %w", repo, err)
+	}
+
+	paths := make([]string, 0, len(tree.Tree))
+
+	for _, entry := range tree.Tree {
+		if entry.Type == "blob" {
+			paths = append(paths, entry.Path)
+		}
+	}
+
+	return paths, nil
+}
+
+// FileContents returns the contents of the file at path in repo at ref.
+func (c *Client) FileContents(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	var file contents
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/contents/%s?ref=%s", repo, path, url.QueryEscape(ref)), &file); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s of %s: %w", path, repo, err)
+	}
+
+	data, err := file.decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s of %s: %w", path, repo, err)
+	}
+
+	return data, nil
+}
+
+// Readme returns the contents of the README of repo on its default branch.
+func (c *Client) Readme(ctx context.Context, repo string) ([]byte, error) {
+	var file contents
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/readme", repo), &file); err != nil {
+		return nil, fmt.Errorf("failed to fetch README of %s: %w", repo, err)
+	}
+
+	data, err := file.decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch README of %s: %w", repo, err)
+	}
+
+	return data, nil
+}
+
+// contents is a file returned by the repository contents API.
+type contents struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// decode returns the decoded content of the file.
+func (f contents) decode() ([]byte, error) {
+	if f.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported encoding %q", f.Encoding)
+	}
+
+	// The content is wrapped at 60 characters.
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(f.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_OrgRepos(t *testing.T) {
+	t.Parallel()
+
+	page := make([]string, orgPageSize)
+	for i := range page {
+		page[i] = fmt.Sprintf(`{"full_name":"acme/r%d","language":"Go"}`, i)
+	}
+
+	c := NewWithClient(jsonRESTClient(t, map[string]string{
+		"orgs/acme/repos?per_page=100&page=1": "[" + strings.Join(page, ",") + "]",
+		"orgs/acme/repos?per_page=100&page=2": `[{"full_name":"acme/last","language":"Go","archived":true,"default_branch":"main"}]`,
+	}))
+
+	repos, err := c.OrgRepos(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Len(t, repos, orgPageSize+1)
+	require.Equal(t, OrgRepo{FullName: "acme/last", Language: "Go", Archived: true, DefaultBranch: "main"}, repos[orgPageSize])
+}
+
+func TestClient_FilesAndContents(t *testing.T) {
+	t.Parallel()
+
+	content := base64.StdEncoding.EncodeToString([]byte("module acme/api\n"))
+
+	c := NewWithClient(jsonRESTClient(t, map[string]string{
+		"repos/acme/api/git/trees/main?recursive=1":   `{"tree":[{"path":"cmd","type":"tree"},{"path":"cmd/go.mod","type":"blob"}]}`,
+		"repos/acme/api/contents/cmd/go.mod?ref=main": `{"content":"` + content[:8] + `\n` + content[8:] + `","encoding":"base64"}`,
+		"repos/acme/api/readme":                       `{"content":"` + base64.StdEncoding.EncodeToString([]byte("# api\n")) + `","encoding":"base64"}`,
+		"repos/acme/raw/readme":                       `{"content":"# raw","encoding":"none"}`,
+	}))
+
+	paths, err := c.Files(context.Background(), "acme/api", "main")
+	require.NoError(t, err)
+	require.Equal(t, []string{"cmd/go.mod"}, paths)
+
+	data, err := c.FileContents(context.Background(), "acme/api", "cmd/go.mod", "main")
+	require.NoError(t, err)
+	require.Equal(t, "module acme/api\n", string(data))
+
+	data, err = c.Readme(context.Background(), "acme/api")
+	require.NoError(t, err)
+	require.Equal(t, "# api\n", string(data))
+
+	_, err = c.Readme(context.Background(), "acme/raw")
+	require.Error(t, err)
+}
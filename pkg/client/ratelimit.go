@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Rate limit response headers sent by the GitHub API.
+const (
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
+)
+
+const (
+	// maxRateLimitRetries is how often a rate limited request is retried.
+	maxRateLimitRetries = 3
+	// rateLimitBaseDelay is the first backoff when the API does not say how
+	// long to wait. It doubles with every retry.
+	rateLimitBaseDelay = 2 * time.Second
+	// maxRateLimitWait is the longest single wait. Limits that reset later
+	// than this fail instead of stalling the run.
+	maxRateLimitWait = time.Minute
+)
+
+// rateLimitTransport logs the remaining rate limit of every API response at
+// debug level.
+type rateLimitTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if remaining := resp.Header.Get(rateLimitRemainingHeader); remaining != "" {
+		reset := resp.Header.Get(rateLimitResetHeader)
+		if sec, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			reset = time.Unix(sec, 0).Format(time.Kitchen)
+		}
+
+		slog.DebugContext(req.Context(), fmt.Sprintf("rate limit: %s/%s remaining, resets %s", remaining, resp.Header.Get(rateLimitLimitHeader), reset))
+	}
+
+	return resp, nil
+}
+
+// isRateLimited reports whether err is a 429 or a 403 caused by a primary or
+// secondary rate limit, rather than by missing permissions.
+func isRateLimited(err error) (*api.HTTPError, bool) {
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) {
+		return nil, false
+	}
+
+	switch httpErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return httpErr, true
+	case http.StatusForbidden:
+		limited := httpErr.Headers.Get(retryAfterHeader) != "" ||
+			httpErr.Headers.Get(rateLimitRemainingHeader) == "0" ||
+			strings.Contains(strings.ToLower(httpErr.Message), "rate limit")
+
+		return httpErr, limited
+	default:
+		return nil, false
+	}
+}
+
+// rateLimitDelay returns how long to wait before retry attempt, counting from
+// zero, of a rate limited request. It prefers the Retry-After header, then
+// the reset time of an exhausted limit, and otherwise backs off
+// exponentially. Jitter of up to half the delay is added so concurrent
+// requests do not retry in lockstep. It returns false when the request is
+// not rate limited or the wait, before jitter, exceeds maxRateLimitWait.
+func rateLimitDelay(err error, attempt int, now time.Time) (time.Duration, bool) {
+	httpErr, ok := isRateLimited(err)
+	if !ok {
+		return 0, false
+	}
+
+	delay := rateLimitBaseDelay << attempt
+
+	if sec, err := strconv.Atoi(httpErr.Headers.Get(retryAfterHeader)); err == nil {
+		delay = time.Duration(sec) * time.Second
+	} else if httpErr.Headers.Get(rateLimitRemainingHeader) == "0" {
+		if reset, err := strconv.ParseInt(httpErr.Headers.Get(rateLimitResetHeader), 10, 64); err == nil {
+			delay = time.Unix(reset, 0).Sub(now)
+		}
+	}
+
+	delay = max(delay, 0)
+	if delay > maxRateLimitWait {
+		return 0, false
+	}
+
+	return delay + rand.N(delay/2+1), true // #nosec G404
+}
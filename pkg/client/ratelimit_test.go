@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+// headers returns the given key and value pairs as response headers.
+func headers(kv ...string) http.Header {
+	h := http.Header{}
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+
+	return h
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		err      error
+		attempt  int
+		min, max time.Duration
+		ok       bool
+	}{
+		{"other error", errors.New("boom"), 0, 0, 0, false},
+		{"not found", &api.HTTPError{StatusCode: http.StatusNotFound}, 0, 0, 0, false},
+		{"forbidden", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not accessible"}, 0, 0, 0, false},
+		{"too many requests", &api.HTTPError{StatusCode: http.StatusTooManyRequests}, 0, 2 * time.Second, 3 * time.Second, true},
+		{"backoff doubles", &api.HTTPError{StatusCode: http.StatusTooManyRequests}, 2, 8 * time.Second, 12 * time.Second, true},
+		{"secondary rate limit", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "You have exceeded a secondary rate limit"}, 0, 2 * time.Second, 3 * time.Second, true},
+		{"retry after", &api.HTTPError{StatusCode: http.StatusForbidden, Headers: headers(retryAfterHeader, "10")}, 0, 10 * time.Second, 15 * time.Second, true},
+		{"reset soon", &api.HTTPError{StatusCode: http.StatusForbidden, Headers: headers(
+			rateLimitRemainingHeader, "0",
+			rateLimitResetHeader, strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+		)}, 0, 20 * time.Second, 30 * time.Second, true},
+		{"reset too late", &api.HTTPError{StatusCode: http.StatusForbidden, Headers: headers(
+			rateLimitRemainingHeader, "0",
+			rateLimitResetHeader, strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+		)}, 0, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			delay, ok := rateLimitDelay(tt.err, tt.attempt, now)
+			require.Equal(t, tt.ok, ok)
+			require.GreaterOrEqual(t, delay, tt.min)
+			require.LessOrEqual(t, delay, tt.max)
+		})
+	}
+}
+
+func TestGetRepoResult_RateLimitRetry(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(_ string, v any) error {
+			calls++
+			if calls < 3 {
+				return &api.HTTPError{StatusCode: http.StatusTooManyRequests}
+			}
+
+			r, ok := v.(*RepoResult)
+			require.True(t, ok)
+
+			r.FullName = "owner/repo"
+
+			return nil
+		},
+	})
+
+	var slept []time.Duration
+
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+
+		return nil
+	}
+
+	got, err := c.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, "owner/repo", got.FullName)
+	require.Equal(t, 3, calls)
+	require.Len(t, slept, 2)
+}
+
+func TestGetRepoResult_RateLimitExhausted(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(_ string, _ any) error {
+			calls++
+
+			return &api.HTTPError{StatusCode: http.StatusTooManyRequests}
+		},
+	})
+	c.sleep = func(context.Context, time.Duration) error { return nil }
+
+	_, err := c.GetRepoResult(context.Background(), "owner/repo")
+	require.Error(t, err)
+	require.Equal(t, maxRateLimitRetries+1, calls)
+}
+
+func TestGetRepoResult_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+
+	c := NewWithClient(&mockRESTClient{
+		getFunc: func(_ string, _ any) error {
+			calls++
+
+			return &api.HTTPError{StatusCode: http.StatusTooManyRequests}
+		},
+	})
+	c.sleep = func(ctx context.Context, _ time.Duration) error {
+		cancel()
+
+		return ctx.Err()
+	}
+
+	_, err := c.GetRepoResult(ctx, "owner/repo")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+
+	// A lookup started after cancellation is not retried.
+	_, err = c.GetRepoResult(ctx, "owner/repo")
+	require.Error(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestGetRepoResult_TransientRetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		retries int
+		calls   int
+	}{
+		{"server error", &api.HTTPError{StatusCode: http.StatusBadGateway}, 2, 3},
+		{"connection error", &url.Error{Op: "Get", URL: "https://api.github.com", Err: errors.New("connection reset")}, 2, 3},
+		{"retries disabled", &api.HTTPError{StatusCode: http.StatusBadGateway}, 0, 1},
+		{"client error", &api.HTTPError{StatusCode: http.StatusUnprocessableEntity}, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+
+			c := NewWithClient(&mockRESTClient{
+				getFunc: func(_ string, _ any) error {
+					calls++
+
+					return tt.err
+				},
+			})
+			c.retries = tt.retries
+			c.sleep = func(context.Context, time.Duration) error { return nil }
+
+			_, err := c.GetRepoResult(context.Background(), "owner/repo")
+			require.Error(t, err)
+			require.Equal(t, tt.calls, calls)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Defaults for Options.Retries and Options.RetryDelay.
+const (
+	DefaultRetries    = 2
+	DefaultRetryDelay = time.Second
+)
+
+// isTransient reports whether err is a server error or a failure to connect
+// or read a response, which may succeed when retried.
+func isTransient(err error) bool {
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var (
+		urlErr *url.Error
+		netErr net.Error
+	)
+
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff returns how long to wait before retry attempt, counting from zero,
+// of a failed request, or false if it should not be retried. Rate limited
+// requests are retried according to rateLimitDelay, and transient errors up
+// to c.retries times with exponential backoff and jitter.
+func (c *Client) backoff(err error, attempt int, now time.Time) (time.Duration, bool) {
+	if _, limited := isRateLimited(err); limited {
+		if attempt >= maxRateLimitRetries {
+			return 0, false
+		}
+
+		return rateLimitDelay(err, attempt, now)
+	}
+
+	if attempt >= c.retries || !isTransient(err) {
+		return 0, false
+	}
+
+	delay := c.retryDelay << attempt
+
+	return delay + rand.N(delay/2+1), true // #nosec G404
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// TagExists reports whether repo has a tag named tag, such as "v1.2.3" or
+// "sub/v1.2.3". A missing repository is reported as an error.
+func (c *Client) TagExists(ctx context.Context, repo, tag string) (bool, error) {
+	var ref struct {
+		Ref string `json:"ref"`
+	}
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/git/ref/tags/%s", repo, tag), &ref); err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to fetch tag %s of %s: %w", tag, repo, err)
+	}
+
+	return true, nil
+}
+
+// Tags returns the names of every tag in repo, such as "v1.2.3" or
+// "sub/v1.2.3".
+func (c *Client) Tags(ctx context.Context, repo string) ([]string, error) {
+	var refs []struct {
+		Ref string `json:"ref"`
+	}
+
+	if err := c.get(ctx, fmt.Sprintf("repos/%s/git/matching-refs/tags", repo), &refs); err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", repo, err)
+	}
+
+	tags := make([]string, 0, len(refs))
+
+	for _, ref := range refs {
+		tags = append(tags, strings.TrimPrefix(ref.Ref, "refs/tags/"))
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_TagExists(t *testing.T) {
+	t.Parallel()
+
+	c := NewWithClient(&mockRESTClient{getFunc: func(path string, _ any) error {
+		switch path {
+		case "repos/owner/repo/git/ref/tags/v1.2.3", "repos/owner/repo/git/ref/tags/sub/v1.0.0":
+			return nil
+		case "repos/owner/repo/git/ref/tags/v9.9.9":
+			return &api.HTTPError{StatusCode: http.StatusNotFound}
+		}
+
+		return &api.HTTPError{StatusCode: http.StatusInternalServerError}
+	}})
+
+	for tag, want := range map[string]bool{"v1.2.3": true, "sub/v1.0.0": true, "v9.9.9": false} {
+		ok, err := c.TagExists(context.Background(), "owner/repo", tag)
+		require.NoError(t, err)
+		require.Equal(t, want, ok, tag)
+	}
+
+	_, err := c.TagExists(context.Background(), "other/repo", "v1.0.0")
+	require.Error(t, err)
+}
@@ -0,0 +1,172 @@
+// Package codeowners attributes files to the users and teams listed for them
+// in a repository's CODEOWNERS file, so findings can be routed to whoever
+// owns the manifest they were found in. See
+// https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+)
+
+// Locations are where GitHub looks for a CODEOWNERS file relative to the root
+// of a repository, in the order it looks.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// rule is a line of a CODEOWNERS file.
+type rule struct {
+	// pattern is matched against slash-separated paths relative to the
+	// root with files.MatchSegments.
+	pattern string
+	// dirOnly rules, written with a trailing "/", match only the contents
+	// of a directory.
+	dirOnly bool
+	// contents is set when a match of pattern may be a directory whose
+	// contents are owned too.
+	contents bool
+	owners   []string
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Root is the directory the patterns are relative to.
+	Root string
+	// Path is the file the rules were read from, empty if none was found.
+	Path  string
+	rules []rule
+}
+
+// Parse parses a CODEOWNERS file of the repository at root. Blank lines and
+// comments are skipped. A pattern without a "/" matches at any depth, a
+// trailing "/" matches only directories, and a pattern matching a directory
+// matches everything beneath it, except that "dir/*" matches only the files
+// directly in dir. A pattern without owners leaves its files unowned.
+func Parse(root string, data []byte) File {
+	f := File{Root: root}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, dirOnly := strings.CutSuffix(fields[0], "/")
+
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+
+		pattern = strings.TrimPrefix(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+
+		f.rules = append(f.rules, rule{
+			pattern:  pattern,
+			dirOnly:  dirOnly,
+			contents: !strings.Contains(path.Base(pattern), "*"),
+			owners:   fields[1:],
+		})
+	}
+
+	return f
+}
+
+// Load reads the CODEOWNERS file of the repository containing dir, whose root
+// is the closest directory with a .git entry, or dir itself outside of a
+// repository. A repository without a CODEOWNERS file has no owners.
+func Load(ctx context.Context, dir string) (File, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	root := abs
+
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			root = d
+
+			break
+		}
+
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	for _, location := range Locations {
+		name := filepath.Join(root, filepath.FromSlash(location))
+
+		data, err := os.ReadFile(name) // #nosec G304
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return File{}, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		slog.DebugContext(ctx, fmt.Sprintf("read code owners from %s", name))
+
+		f := Parse(root, data)
+		f.Path = name
+
+		return f, nil
+	}
+
+	slog.DebugContext(ctx, fmt.Sprintf("no CODEOWNERS file found in %s", root))
+
+	return File{Root: root}, nil
+}
+
+// Owners returns the owners of the file name, a path relative to the current
+// directory or an absolute one, as listed by the last rule matching it. Files
+// outside of Root and files no rule matches have no owners.
+func (f File) Owners(name string) []string {
+	if len(f.rules) == 0 {
+		return nil
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return nil
+	}
+
+	rel, err := filepath.Rel(f.Root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		r := f.rules[i]
+
+		if (!r.dirOnly && files.MatchSegments(r.pattern, rel)) ||
+			((r.dirOnly || r.contents) && files.MatchSegments(r.pattern+"/*/**", rel)) {
+			if len(r.owners) == 0 {
+				return nil
+			}
+
+			return r.owners
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package codeowners
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	f := Parse(root, []byte(`# Default owners
+*                    @acme/platform
+*.md                 @acme/docs
+
+/services/payments/  @acme/payments @alice
+services/search      @acme/search # inline comment
+/tools/*             @acme/tooling
+vendor/
+legacy/**/go.mod     @acme/legacy
+`))
+
+	tests := []struct {
+		name   string
+		owners []string
+	}{
+		{"go.mod", []string{"@acme/platform"}},
+		{"README.md", []string{"@acme/docs"}},
+		{"services/payments/go.mod", []string{"@acme/payments", "@alice"}},
+		{"services/payments/cmd/go.mod", []string{"@acme/payments", "@alice"}},
+		{"services/search/go.mod", []string{"@acme/search"}},
+		{"tools/go.mod", []string{"@acme/tooling"}},
+		{"tools/lint/go.mod", []string{"@acme/platform"}},
+		{"vendor/go.mod", nil},
+		{"legacy/a/b/go.mod", []string{"@acme/legacy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.owners, f.Owners(filepath.Join(root, filepath.FromSlash(tt.name))))
+		})
+	}
+
+	require.Nil(t, f.Owners(filepath.Join(filepath.Dir(root), "go.mod")))
+	require.Nil(t, File{}.Owners("go.mod"))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "services", "api")
+
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o750))
+
+	f, err := Load(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, root, f.Root)
+	require.Empty(t, f.Path)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("/services/ @acme/services\n"), 0o600))
+
+	f, err = Load(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, ".github", "CODEOWNERS"), f.Path)
+	require.Equal(t, []string{"@acme/services"}, f.Owners(filepath.Join(dir, "go.mod")))
+}
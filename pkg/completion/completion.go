@@ -0,0 +1,294 @@
+// Package completion generates shell completion scripts. The scripts ask the
+// program itself for candidates, so they always cover its current commands,
+// flags and flag values, such as the ecosystems and output formats it
+// supports.
+package completion
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Flag is appended by the scripts to the words on the command line to ask
+// for candidates, after the word being completed, which may be empty.
+const Flag = "--generate-bash-completion"
+
+// Supported shells.
+const (
+	ShellBash       = "bash"
+	ShellZsh        = "zsh"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+)
+
+// Shells lists every supported shell.
+var Shells = []string{ShellBash, ShellZsh, ShellFish, ShellPowerShell}
+
+// Values returns the candidates for the value of flag, given by its name,
+// of cmd, or for the arguments of cmd if flag is empty. Nil lets the shell
+// complete file names.
+type Values func(cmd *cli.Command, flag string) []string
+
+// Install makes app and all of its commands complete with Complete.
+func Install(app *cli.App, values Values) {
+	app.EnableBashCompletion = true
+	app.BashComplete = complete(values)
+
+	var walk func(commands []*cli.Command)
+
+	walk = func(commands []*cli.Command) {
+		for _, cmd := range commands {
+			cmd.BashComplete = complete(values)
+			walk(cmd.Subcommands)
+		}
+	}
+
+	walk(app.Commands)
+}
+
+// complete returns a completion function that reads the words being
+// completed from os.Args, which is how the cli package passes them on.
+func complete(values Values) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		var lineage []*cli.Command
+
+		for _, ctx := range c.Lineage() {
+			if ctx.Command != nil {
+				lineage = append(lineage, ctx.Command)
+			}
+		}
+
+		args := os.Args[1:]
+		if n := len(args); n > 0 && args[n-1] == Flag {
+			args = args[:n-1]
+		}
+
+		Complete(c.App.Writer, args, lineage, values)
+	}
+}
+
+// Complete writes one candidate per line for the last of args, the words
+// typed after the program name, where lineage is the command the words
+// select followed by its parents. It offers the values of a flag after the
+// flag, the flags of the command for words starting with a dash, and its
+// subcommands or arguments otherwise.
+func Complete(w io.Writer, args []string, lineage []*cli.Command, values Values) {
+	if len(lineage) == 0 {
+		return
+	}
+
+	cmd := lineage[0]
+
+	var cur, prev string
+
+	if n := len(args); n > 0 {
+		cur = args[n-1]
+
+		if n > 1 {
+			prev = args[n-2]
+		}
+
+		// Bash splits --flag=value into three words.
+		switch {
+		case cur == "=" && n > 1:
+			cur, prev = "", args[n-2]
+		case prev == "=" && n > 2:
+			prev = args[n-3]
+		}
+	}
+
+	if name, value, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(name, "-") {
+		if f := findFlag(lineage, name); f != nil && takesValue(f) {
+			for _, v := range values(cmd, f.Names()[0]) {
+				if strings.HasPrefix(v, value) {
+					fmt.Fprintln(w, name+"="+v)
+				}
+			}
+		}
+
+		return
+	}
+
+	if strings.HasPrefix(prev, "-") && !strings.Contains(prev, "=") {
+		if f := findFlag(lineage, prev); f != nil && takesValue(f) {
+			writeLines(w, values(cmd, f.Names()[0]))
+
+			return
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		writeLines(w, flagNames(cmd))
+
+		return
+	}
+
+	// The word is the name of the command it selected, so it is already
+	// complete.
+	if cur != "" && cmd.HasName(cur) && len(lineage) > 1 {
+		fmt.Fprintln(w, cur)
+
+		return
+	}
+
+	var names []string
+
+	for _, sub := range cmd.Subcommands {
+		if !sub.Hidden {
+			names = append(names, sub.Names()...)
+		}
+	}
+
+	// The cli package adds a help command to commands without subcommands
+	// of their own, which take arguments instead.
+	if !slices.ContainsFunc(cmd.Subcommands, func(sub *cli.Command) bool { return !sub.Hidden && !sub.HasName("help") }) {
+		names = values(cmd, "")
+	}
+
+	writeLines(w, names)
+}
+
+// writeLines writes one candidate per line.
+func writeLines(w io.Writer, candidates []string) {
+	for _, c := range candidates {
+		fmt.Fprintln(w, c)
+	}
+}
+
+// findFlag returns the flag named by arg, such as --format or -o, of the
+// first command in lineage that has it.
+func findFlag(lineage []*cli.Command, arg string) cli.Flag {
+	name := strings.TrimLeft(arg, "-")
+
+	for _, cmd := range lineage {
+		for _, f := range cmd.Flags {
+			if slices.Contains(f.Names(), name) {
+				return f
+			}
+		}
+	}
+
+	return nil
+}
+
+// takesValue reports whether f is followed by a value, unlike boolean flags.
+func takesValue(f cli.Flag) bool {
+	v, ok := f.(cli.DocGenerationFlag)
+
+	return ok && v.TakesValue()
+}
+
+// flagNames returns the names of the flags of cmd with their dashes, and
+// the help flag.
+func flagNames(cmd *cli.Command) []string {
+	var names []string
+
+	flags := cmd.Flags
+	if !slices.Contains(flags, cli.HelpFlag) {
+		flags = append(slices.Clone(flags), cli.HelpFlag)
+	}
+
+	for _, f := range flags {
+		if v, ok := f.(cli.VisibleFlag); ok && !v.IsVisible() {
+			continue
+		}
+
+		for _, name := range f.Names() {
+			if len(name) == 1 {
+				names = append(names, "-"+name)
+			} else {
+				names = append(names, "--"+name)
+			}
+		}
+	}
+
+	return names
+}
+
+// identifier matches characters that may not appear in shell function
+// names.
+var identifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Script writes the completion script of shell for the program prog, which
+// must be on the PATH under that name.
+func Script(w io.Writer, shell, prog string) error {
+	fn := "_" + identifier.ReplaceAllString(prog, "_") + "_completion"
+
+	var script string
+
+	switch shell {
+	case ShellBash:
+		script = bashScript
+	case ShellZsh:
+		script = zshScript
+	case ShellFish:
+		script = fishScript
+	case ShellPowerShell:
+		script = powerShellScript
+	default:
+		return fmt.Errorf("unsupported shell %q, expected one of: %s", shell, strings.Join(Shells, ", "))
+	}
+
+	_, err := io.WriteString(w, strings.NewReplacer("{{prog}}", prog, "{{fn}}", fn, "{{flag}}", Flag).Replace(script))
+
+	return err
+}
+
+const bashScript = `# bash completion for {{prog}}, load with:
+#   source <({{prog}} completion bash)
+{{fn}}() {
+  local cur="${COMP_WORDS[COMP_CWORD]}"
+  [[ "$cur" == "=" ]] && cur=""
+  local IFS=$'\n'
+  COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" "${COMP_WORDS[@]:1:COMP_CWORD}" {{flag}} 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F {{fn}} {{prog}}
+`
+
+const zshScript = `#compdef {{prog}}
+# zsh completion for {{prog}}, load with:
+#   source <({{prog}} completion zsh)
+{{fn}}() {
+  local -a candidates
+  candidates=("${(@f)$("${words[1]}" "${(@)words[2,CURRENT]}" {{flag}} 2>/dev/null)}")
+  if [[ -n "${candidates[1]}" ]]; then
+    compadd -a candidates
+  else
+    _files
+  fi
+}
+compdef {{fn}} {{prog}}
+`
+
+const fishScript = `# fish completion for {{prog}}, load with:
+#   {{prog}} completion fish | source
+function {{fn}}
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l candidates ({{prog}} $words (commandline -ct | string collect -a) {{flag}} 2>/dev/null)
+    if test (count $candidates) -eq 0
+        __fish_complete_path (commandline -ct)
+        return
+    end
+    printf '%s\n' $candidates
+end
+complete -c {{prog}} -f -a '({{fn}})'
+`
+
+const powerShellScript = `# PowerShell completion for {{prog}}, load with:
+#   {{prog}} completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName '{{prog}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') { $words += '' }
+    & '{{prog}}' @words '{{flag}}' 2>$null | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
@@ -0,0 +1,89 @@
+package completion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestComplete(t *testing.T) {
+	t.Parallel()
+
+	sub := &cli.Command{
+		Name: "gomod",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format"},
+			&cli.BoolFlag{Name: "indirect"},
+			&cli.StringFlag{Name: "secret", Hidden: true},
+		},
+		Subcommands: []*cli.Command{{Name: "help", Aliases: []string{"h"}}},
+	}
+	root := &cli.Command{
+		Name: "arc",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "config"},
+			&cli.StringFlag{Name: "output", Aliases: []string{"o"}},
+		},
+		Subcommands: []*cli.Command{sub, {Name: "hidden", Hidden: true}, {Name: "version"}},
+	}
+
+	values := func(cmd *cli.Command, flag string) []string {
+		switch flag {
+		case "format":
+			return []string{"text", "json"}
+		case "":
+			if cmd.Name == "gomod" {
+				return []string{"github.com/owner/repo"}
+			}
+		}
+
+		return nil
+	}
+
+	complete := func(lineage []*cli.Command, args ...string) []string {
+		var buf bytes.Buffer
+
+		Complete(&buf, args, lineage, values)
+
+		return strings.Fields(buf.String())
+	}
+
+	gomod := []*cli.Command{sub, root}
+
+	require.Equal(t, []string{"gomod", "version"}, complete([]*cli.Command{root}, ""))
+	require.Equal(t, []string{"gomod", "version"}, complete([]*cli.Command{root}, "go"))
+	require.Equal(t, []string{"--config", "--output", "-o", "--help", "-h"}, complete([]*cli.Command{root}, "--"))
+	require.Empty(t, complete([]*cli.Command{root}, "--config", ""))
+	require.Equal(t, []string{"gomod"}, complete(gomod, "gomod"))
+	require.Equal(t, []string{"github.com/owner/repo"}, complete(gomod, "gomod", ""))
+	require.Equal(t, []string{"text", "json"}, complete(gomod, "gomod", "--format", ""))
+	require.Equal(t, []string{"text", "json"}, complete(gomod, "gomod", "--format", "=", "j"))
+	require.Equal(t, []string{"text", "json"}, complete(gomod, "gomod", "--format", "="))
+	require.Equal(t, []string{"--format=json"}, complete(gomod, "gomod", "--format=j"))
+	require.Equal(t, []string{"github.com/owner/repo"}, complete(gomod, "gomod", "--indirect", ""))
+	require.Empty(t, complete(gomod, "gomod", "--output", ""))
+	require.Equal(t, []string{"--format", "--indirect", "--help", "-h"}, complete(gomod, "gomod", "--in"))
+}
+
+func TestScript(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range Shells {
+		var buf bytes.Buffer
+
+		require.NoError(t, Script(&buf, shell, "gh-arc"))
+		require.Contains(t, buf.String(), "gh-arc")
+		require.Contains(t, buf.String(), Flag)
+		require.NotContains(t, buf.String(), "{{")
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Script(&buf, ShellBash, "gh-arc"))
+	require.Contains(t, buf.String(), "complete -o default -F _gh_arc_completion gh-arc\n")
+
+	require.EqualError(t, Script(&buf, "tcsh", "arc"), `unsupported shell "tcsh", expected one of: bash, zsh, fish, powershell`)
+}
@@ -0,0 +1,192 @@
+// Package composer provides commands for scanning PHP package dependencies
+// declared in composer.json and composer.lock files and reporting archived
+// GitHub repositories.
+package composer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/packagist"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+// Manifest is the file that declares the packages of a PHP project.
+const Manifest = "composer.json"
+
+// LockFile is the file that records the resolved packages of a PHP project.
+const LockFile = "composer.lock"
+
+var (
+	// requireStart matches the start of the require and require-dev
+	// objects of a composer.json file.
+	requireStart = regexp.MustCompile(`"require(-dev)?"\s*:\s*\{`)
+	// requirement matches a package and its version constraint. Platform
+	// requirements such as "php" and "ext-json" have no vendor and do not
+	// match.
+	requirement = regexp.MustCompile(`"([^"/\s]+/[^"\s]+)"\s*:\s*"([^"]*)"`)
+)
+
+// parseManifest returns the packages required by a composer.json file,
+// including those only required for development.
+func parseManifest(name string, data []byte) []resolve.Dependency {
+	var (
+		deps      []resolve.Dependency
+		inRequire bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+
+		if !inRequire {
+			loc := requireStart.FindStringIndex(line)
+			if loc == nil {
+				continue
+			}
+
+			inRequire = true
+			line = line[loc[1]:]
+		}
+
+		body, _, closed := strings.Cut(line, "}")
+
+		for _, m := range requirement.FindAllStringSubmatch(body, -1) {
+			deps = append(deps, resolve.Dependency{Name: m[1], Version: m[2], File: name, Line: lineNo})
+		}
+
+		inRequire = !closed
+	}
+
+	return deps
+}
+
+// lockedPackage is a package recorded in a composer.lock file.
+type lockedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  struct {
+		URL string `json:"url"`
+	} `json:"source"`
+}
+
+// parseLock returns the packages recorded in a composer.lock file, with the
+// repository they were installed from. Packages are reported at the line of
+// their name.
+func parseLock(name string, data []byte) ([]resolve.Dependency, error) {
+	var lock struct {
+		Packages    []lockedPackage `json:"packages"`
+		PackagesDev []lockedPackage `json:"packages-dev"`
+	}
+
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	var deps []resolve.Dependency
+
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		line := 0
+
+		if i := bytes.Index(data, []byte(`"name": "`+pkg.Name+`"`)); i >= 0 {
+			line = bytes.Count(data[:i], []byte("\n")) + 1
+		}
+
+		deps = append(deps, resolve.Dependency{
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			File:     name,
+			Line:     line,
+			Indirect: true,
+			URL:      pkg.Source.URL,
+		})
+	}
+
+	return deps, nil
+}
+
+// Discover parses the given composer.json and composer.lock files. Packages
+// required by a composer.json are direct dependencies and take their version
+// and repository from a lock file when one records them; packages only found
+// in a composer.lock are indirect.
+func Discover(ctx context.Context, manifests, lockfiles []string) []resolve.Dependency {
+	var direct, locked []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		direct = append(direct, parseManifest(name, data)...)
+	}
+
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		deps, err := parseLock(name, data)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		locked = append(locked, deps...)
+	}
+
+	urls := map[string]string{}
+	for _, dep := range locked {
+		urls[packagist.Normalize(dep.Name)] = dep.URL
+	}
+
+	for i, dep := range direct {
+		direct[i].URL = urls[packagist.Normalize(dep.Name)]
+	}
+
+	return resolve.Merge(direct, locked, packagist.Normalize)
+}
+
+// installed reports whether name is inside a vendor directory, where composer
+// installs packages along with their own composer.json files.
+func installed(name string) bool {
+	return slices.Contains(strings.Split(filepath.ToSlash(filepath.Dir(name)), "/"), "vendor")
+}
+
+// Repos returns the repositories of the packages required in opts.Scope,
+// taken from composer.lock files or looked up on Packagist. Installed
+// packages in vendor directories are not scanned.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	manifests, err := files.RecursiveFind(ctx, opts.Scope, Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", Manifest, err)
+	}
+
+	lockfiles, err := files.RecursiveFind(ctx, opts.Scope, LockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", LockFile, err)
+	}
+
+	manifests = slices.DeleteFunc(manifests, installed)
+	lockfiles = slices.DeleteFunc(lockfiles, installed)
+
+	return resolve.Repos(ctx, packagist.NewWithHTTPClient(opts.Timeouts.HTTPClient(packagist.DefaultBaseURL), packagist.DefaultBaseURL), Discover(ctx, manifests, lockfiles), opts)
+}
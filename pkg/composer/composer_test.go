@@ -0,0 +1,91 @@
+package composer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+    "name": "acme/app",
+    "require": {
+        "php": ">=8.1",
+        "ext-json": "*",
+        "symfony/console": "^6.4",
+        "Monolog/Monolog": "^3.0"
+    },
+    "require-dev": {"phpunit/phpunit": "^10.5"},
+    "config": {
+        "platform": {"php": "8.1"}
+    }
+}
+`
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "symfony/console", Version: "^6.4", File: "composer.json", Line: 6},
+		{Name: "Monolog/Monolog", Version: "^3.0", File: "composer.json", Line: 7},
+		{Name: "phpunit/phpunit", Version: "^10.5", File: "composer.json", Line: 9},
+	}, parseManifest("composer.json", []byte(data)))
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	manifest := filepath.Join(dir, "composer.json")
+	lock := filepath.Join(dir, "composer.lock")
+
+	require.NoError(t, os.WriteFile(manifest, []byte(`{
+    "require": {
+        "monolog/monolog": "^3.0"
+    }
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(lock, []byte(`{
+    "packages": [
+        {
+            "name": "monolog/monolog",
+            "version": "3.5.0",
+            "source": {
+                "type": "git",
+                "url": "https://github.com/Seldaek/monolog.git"
+            },
+            "authors": [
+                {
+                    "name": "Jordi Boggiano"
+                }
+            ]
+        },
+        {
+            "name": "psr/log",
+            "version": "3.0.0",
+            "source": {
+                "type": "git",
+                "url": "https://github.com/php-fig/log.git"
+            }
+        }
+    ],
+    "packages-dev": []
+}
+`), 0o600))
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "monolog/monolog", Version: "3.5.0", File: manifest, Line: 3, URL: "https://github.com/Seldaek/monolog.git"},
+		{Name: "psr/log", Version: "3.0.0", File: lock, Line: 17, Indirect: true, URL: "https://github.com/php-fig/log.git"},
+	}, Discover(context.Background(), []string{manifest}, []string{lock}))
+}
+
+func TestInstalled(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, installed(filepath.Join("app", "vendor", "monolog", "monolog", "composer.json")))
+	require.False(t, installed(filepath.Join("app", "composer.json")))
+}
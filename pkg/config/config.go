@@ -0,0 +1,154 @@
+// Package config reads the optional gh-arc configuration file.
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the configuration file read from the current directory when
+// no other path is given.
+const DefaultPath = ".gh-arc.yml"
+
+// DefaultHost is the Timeouts key that applies to hosts without an entry of
+// their own.
+const DefaultHost = "*"
+
+// DefaultReadTimeout is used by registry clients, such as deps.dev and
+// crates.io, when no read timeout is configured.
+const DefaultReadTimeout = 10 * time.Second
+
+// Config is the contents of a configuration file, for example:
+//
+//	timeouts:
+//	  "*":
+//	    read: 30s
+//	  ghe.example.com:
+//	    connect: 10s
+//	    read: 2m
+//	telemetry:
+//	  endpoint: https://telemetry.example.com/gh-arc
+//	go_version:
+//	  min: "1.22"
+//	  max: "1.24"
+//	licenses:
+//	  disallowed: [AGPL-3.0-only, GPL-3.0-only]
+type Config struct {
+	// Timeouts configures network timeouts per host.
+	Timeouts Timeouts `yaml:"timeouts"`
+	// Telemetry configures where opted-in usage counters are sent.
+	Telemetry Telemetry `yaml:"telemetry"`
+	// GoVersion is the range of Go releases go.mod files may require, used
+	// by "arc gomod --check-go-version".
+	GoVersion GoVersion `yaml:"go_version"`
+	// Licenses configures "--licenses".
+	Licenses Licenses `yaml:"licenses"`
+}
+
+// Licenses configures which dependency licenses fail a run.
+type Licenses struct {
+	// Disallowed lists SPDX identifiers or patterns such as "AGPL-*".
+	Disallowed []string `yaml:"disallowed"`
+}
+
+// GoVersion is a range of Go releases, such as "1.22" to "1.24". Empty
+// bounds are not checked.
+type GoVersion struct {
+	Min string `yaml:"min"`
+	Max string `yaml:"max"`
+}
+
+// Telemetry configures usage reporting, which is only active once enabled
+// with "arc telemetry enable".
+type Telemetry struct {
+	// Endpoint receives the aggregate counters as a JSON POST request. When
+	// empty, counters are only kept locally.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Timeout configures the network timeouts for a host. Zero values are unset.
+type Timeout struct {
+	// Connect limits establishing a connection, including the TLS handshake.
+	Connect time.Duration `yaml:"connect"`
+	// Read limits waiting for a complete response once a request is sent.
+	Read time.Duration `yaml:"read"`
+}
+
+// Timeouts maps host names, such as "github.com", to their timeouts.
+type Timeouts map[string]Timeout
+
+// Load reads the configuration file at path. The error wraps fs.ErrNotExist
+// if the file does not exist.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// For returns the timeouts for host. Fields the host does not set are taken
+// from the DefaultHost entry.
+func (t Timeouts) For(host string) Timeout {
+	timeout := t[host]
+	fallback := t[DefaultHost]
+
+	if timeout.Connect == 0 {
+		timeout.Connect = fallback.Connect
+	}
+
+	if timeout.Read == 0 {
+		timeout.Read = fallback.Read
+	}
+
+	return timeout
+}
+
+// HTTPClient returns an HTTP client for requests to baseURL, using
+// DefaultReadTimeout unless a read timeout is configured for its host.
+func (t Timeouts) HTTPClient(baseURL string) *http.Client {
+	var host string
+
+	if u, err := url.Parse(baseURL); err == nil {
+		host = u.Hostname()
+	}
+
+	timeout := t.For(host)
+	if timeout.Read == 0 {
+		timeout.Read = DefaultReadTimeout
+	}
+
+	return &http.Client{Timeout: timeout.Read, Transport: timeout.Transport()}
+}
+
+// Transport returns an HTTP transport that applies the connect timeout, or
+// nil to use the default transport when none is set.
+func (t Timeout) Transport() http.RoundTripper {
+	if t.Connect == 0 {
+		return nil
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+
+	transport = transport.Clone()
+	transport.DialContext = (&net.Dialer{Timeout: t.Connect, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = t.Connect
+
+	return transport
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), DefaultPath)
+
+	require.NoError(t, os.WriteFile(path, []byte(`timeouts:
+  "*":
+    read: 30s
+  ghe.example.com:
+    connect: 10s
+    read: 2m
+telemetry:
+  endpoint: https://telemetry.example.com
+go_version:
+  min: "1.22"
+licenses:
+  disallowed: [AGPL-3.0-only]
+`), 0o600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, Timeouts{
+		DefaultHost:       {Read: 30 * time.Second},
+		"ghe.example.com": {Connect: 10 * time.Second, Read: 2 * time.Minute},
+	}, cfg.Timeouts)
+	require.Equal(t, "https://telemetry.example.com", cfg.Telemetry.Endpoint)
+	require.Equal(t, GoVersion{Min: "1.22"}, cfg.GoVersion)
+	require.Equal(t, []string{"AGPL-3.0-only"}, cfg.Licenses.Disallowed)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yml"))
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestTimeouts_For(t *testing.T) {
+	t.Parallel()
+
+	timeouts := Timeouts{
+		DefaultHost:       {Connect: 5 * time.Second, Read: 30 * time.Second},
+		"ghe.example.com": {Read: 2 * time.Minute},
+	}
+
+	require.Equal(t, Timeout{Connect: 5 * time.Second, Read: 2 * time.Minute}, timeouts.For("ghe.example.com"))
+	require.Equal(t, Timeout{Connect: 5 * time.Second, Read: 30 * time.Second}, timeouts.For("github.com"))
+	require.Equal(t, Timeout{}, Timeouts(nil).For("github.com"))
+}
+
+func TestTimeouts_HTTPClient(t *testing.T) {
+	t.Parallel()
+
+	timeouts := Timeouts{"crates.io": {Connect: time.Second, Read: time.Minute}}
+
+	c := timeouts.HTTPClient("https://crates.io")
+	require.Equal(t, time.Minute, c.Timeout)
+
+	transport, ok := c.Transport.(*http.Transport)
+	require.True(t, ok)
+	require.Equal(t, time.Second, transport.TLSHandshakeTimeout)
+
+	c = timeouts.HTTPClient("https://api.deps.dev")
+	require.Equal(t, DefaultReadTimeout, c.Timeout)
+	require.Nil(t, c.Transport)
+}
@@ -0,0 +1,102 @@
+// Package cratesio provides a minimal client for the crates.io API, used to
+// resolve Rust crates to their source repositories.
+package cratesio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+// DefaultBaseURL is the crates.io API endpoint.
+const DefaultBaseURL = "https://crates.io"
+
+// batchSize is the number of crates looked up per request, which is also the
+// largest page size crates.io allows.
+const batchSize = 100
+
+// Client queries the crates.io API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a Client for the public crates.io API.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 10 * time.Second}, DefaultBaseURL)
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client and endpoint (for testing).
+func NewWithHTTPClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{httpClient: httpClient, baseURL: baseURL}
+}
+
+// Repositories returns the repository URL of each named crate. Crates that do
+// not exist or do not declare a repository are omitted.
+func (c *Client) Repositories(ctx context.Context, names []string) (map[string]string, error) {
+	repos := map[string]string{}
+
+	for start := 0; start < len(names); start += batchSize {
+		batch := names[start:min(start+batchSize, len(names))]
+
+		if err := c.repositories(ctx, batch, repos); err != nil {
+			return nil, err
+		}
+	}
+
+	return repos, nil
+}
+
+// repositories looks up a single batch of crates and adds their repository
+// URLs to repos.
+func (c *Client) repositories(ctx context.Context, names []string, repos map[string]string) error {
+	query := url.Values{"per_page": {fmt.Sprint(batchSize)}}
+	for _, name := range names {
+		query.Add("ids[]", name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/crates?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// crates.io rejects requests without a User-Agent identifying the client.
+	req.Header.Set("User-Agent", "gh-arc/"+version.Get().Version+" (https://github.com/wayneashleyberry/gh-arc)")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch crates: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch crates: %s", resp.Status)
+	}
+
+	var body struct {
+		Crates []struct {
+			Name       string `json:"name"`
+			Repository string `json:"repository"`
+		} `json:"crates"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode crates: %w", err)
+	}
+
+	for _, crate := range body.Crates {
+		if crate.Repository != "" {
+			repos[crate.Name] = crate.Repository
+		}
+	}
+
+	return nil
+}
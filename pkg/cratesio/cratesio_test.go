@@ -0,0 +1,68 @@
+package cratesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/crates", r.URL.Path)
+		require.Equal(t, []string{"serde", "left-pad"}, r.URL.Query()["ids[]"])
+		require.NotEmpty(t, r.Header.Get("User-Agent"))
+
+		_, _ = w.Write([]byte(`{"crates":[{"name":"serde","repository":"https://github.com/serde-rs/serde"},{"name":"left-pad","repository":null}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	got, err := c.Repositories(context.Background(), []string{"serde", "left-pad"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"serde": "https://github.com/serde-rs/serde"}, got)
+}
+
+func TestRepositories_Batches(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		_, _ = w.Write([]byte(`{"crates":[]}`))
+	}))
+	defer srv.Close()
+
+	names := make([]string, batchSize+1)
+	for i := range names {
+		names[i] = fmt.Sprintf("crate-%d", i)
+	}
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	_, err := c.Repositories(context.Background(), names)
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+}
+
+func TestRepositories_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	_, err := c.Repositories(context.Background(), []string{"serde"})
+	require.Error(t, err)
+}
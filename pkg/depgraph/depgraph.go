@@ -0,0 +1,177 @@
+// Package depgraph renders module requirement graphs as Graphviz DOT and
+// Mermaid diagrams, with archived and stale modules colored, so the risk in
+// a dependency tree can be embedded in architecture documents.
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Output formats.
+const (
+	// FormatDOT is a Graphviz digraph.
+	FormatDOT = "dot"
+	// FormatMermaid is a Mermaid flowchart, which GitHub renders in
+	// Markdown files.
+	FormatMermaid = "mermaid"
+)
+
+// Formats lists every supported output format.
+var Formats = []string{FormatDOT, FormatMermaid}
+
+// Node colors.
+const (
+	// colorArchived fills archived and missing modules.
+	colorArchived = "#f8b4b4"
+	// colorStale fills stale and deprecated modules.
+	colorStale = "#fde68a"
+)
+
+// Node is a module of the graph.
+type Node struct {
+	// ID is the module path and version, or just the path for main
+	// modules, as `go mod graph` prints it.
+	ID string
+	// Status is the most severe status of the module's repository. Zero
+	// means the repository is healthy or was not checked.
+	Status status.Status
+}
+
+// Edge is a requirement of one module on another.
+type Edge struct {
+	From string
+	To   string
+}
+
+// Graph is a module requirement graph.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// color returns the fill color of a node with status st, or "" if it is not
+// highlighted.
+func color(st status.Status) string {
+	switch st {
+	case status.Archived, status.Missing, status.UpstreamArchived:
+		return colorArchived
+	case status.Stale, status.Deprecated:
+		return colorStale
+	}
+
+	return ""
+}
+
+// Write renders g in format, one of Formats.
+func Write(w io.Writer, format string, g Graph) error {
+	switch format {
+	case FormatDOT:
+		return DOT(w, g)
+	case FormatMermaid:
+		return Mermaid(w, g)
+	}
+
+	return fmt.Errorf("unsupported graph format %q, expected one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// DOT writes g as a Graphviz digraph. Highlighted nodes are filled and
+// labelled with their status.
+func DOT(w io.Writer, g Graph) error {
+	var b strings.Builder
+
+	b.WriteString("digraph modules {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, n := range g.Nodes {
+		fill := color(n.Status)
+		if fill == "" {
+			continue
+		}
+
+		fmt.Fprintf(&b, "  %s [label=%s, style=filled, fillcolor=%s];\n", strconv.Quote(n.ID), strconv.Quote(n.ID+"\n"+n.Status.String()), strconv.Quote(fill))
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// Mermaid writes g as a Mermaid flowchart. Module paths are not valid
+// Mermaid identifiers, so nodes are numbered in the order of g.Nodes and
+// labelled with their ID.
+func Mermaid(w io.Writer, g Graph) error {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	classes := map[string][]string{}
+
+	for i, n := range g.Nodes {
+		id := "n" + strconv.Itoa(i)
+		ids[n.ID] = id
+
+		label := n.ID
+		if color(n.Status) != "" {
+			label += "<br/>" + n.Status.String()
+
+			classes[className(n.Status)] = append(classes[className(n.Status)], id)
+		}
+
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, strings.ReplaceAll(label, `"`, "#quot;"))
+	}
+
+	for _, e := range g.Edges {
+		from, ok := ids[e.From]
+		if !ok {
+			continue
+		}
+
+		to, ok := ids[e.To]
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(&b, "  %s --> %s\n", from, to)
+	}
+
+	for _, class := range []string{"archived", "stale"} {
+		members := classes[class]
+		if len(members) == 0 {
+			continue
+		}
+
+		fill := colorArchived
+		if class == "stale" {
+			fill = colorStale
+		}
+
+		fmt.Fprintf(&b, "  classDef %s fill:%s\n", class, fill)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(members, ","), class)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// className returns the Mermaid class of highlighted nodes with status st.
+func className(st status.Status) string {
+	if color(st) == colorArchived {
+		return "archived"
+	}
+
+	return "stale"
+}
@@ -0,0 +1,68 @@
+package depgraph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+var testGraph = Graph{
+	Nodes: []Node{
+		{ID: "example.com/app"},
+		{ID: "github.com/a/b@v1.0.0", Status: status.Stale},
+		{ID: "github.com/foo/bar@v1.1.0", Status: status.Archived},
+		{ID: "github.com/ok/mod@v0.1.0", Status: status.Moved},
+	},
+	Edges: []Edge{
+		{"example.com/app", "github.com/a/b@v1.0.0"},
+		{"example.com/app", "github.com/ok/mod@v0.1.0"},
+		{"github.com/a/b@v1.0.0", "github.com/foo/bar@v1.1.0"},
+	},
+}
+
+func TestDOT(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatDOT, testGraph))
+	require.Equal(t, `digraph modules {
+  rankdir=LR;
+  node [shape=box];
+  "github.com/a/b@v1.0.0" [label="github.com/a/b@v1.0.0\nstale", style=filled, fillcolor="#fde68a"];
+  "github.com/foo/bar@v1.1.0" [label="github.com/foo/bar@v1.1.0\narchived", style=filled, fillcolor="#f8b4b4"];
+  "example.com/app" -> "github.com/a/b@v1.0.0";
+  "example.com/app" -> "github.com/ok/mod@v0.1.0";
+  "github.com/a/b@v1.0.0" -> "github.com/foo/bar@v1.1.0";
+}
+`, buf.String())
+}
+
+func TestMermaid(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatMermaid, testGraph))
+	require.Equal(t, `flowchart LR
+  n0["example.com/app"]
+  n1["github.com/a/b@v1.0.0<br/>stale"]
+  n2["github.com/foo/bar@v1.1.0<br/>archived"]
+  n3["github.com/ok/mod@v0.1.0"]
+  n0 --> n1
+  n0 --> n3
+  n1 --> n2
+  classDef archived fill:#f8b4b4
+  class n2 archived
+  classDef stale fill:#fde68a
+  class n1 stale
+`, buf.String())
+}
+
+func TestWrite_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	require.EqualError(t, Write(&bytes.Buffer{}, "svg", testGraph), `unsupported graph format "svg", expected one of: dot, mermaid`)
+}
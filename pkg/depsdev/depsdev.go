@@ -0,0 +1,84 @@
+// Package depsdev provides a minimal client for the deps.dev API, used to
+// give users a starting point when migrating away from archived modules.
+package depsdev
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultBaseURL is the deps.dev API endpoint.
+const DefaultBaseURL = "https://api.deps.dev"
+
+// Dependents summarises how many packages depend on a module version.
+type Dependents struct {
+	DependentCount         int `json:"dependentCount"`
+	DirectDependentCount   int `json:"directDependentCount"`
+	IndirectDependentCount int `json:"indirectDependentCount"`
+}
+
+// Client queries the deps.dev API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a Client for the public deps.dev API.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 10 * time.Second}, DefaultBaseURL)
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client and endpoint (for testing).
+func NewWithHTTPClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{httpClient: httpClient, baseURL: baseURL}
+}
+
+// GoDependents returns the number of dependents of a Go module version.
+func (c *Client) GoDependents(ctx context.Context, modPath, version string) (Dependents, error) {
+	endpoint := fmt.Sprintf("%s/v3alpha/systems/go/packages/%s/versions/%s:dependents", c.baseURL, url.PathEscape(modPath), url.PathEscape(version))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Dependents{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Dependents{}, fmt.Errorf("failed to fetch dependents of %s@%s: %w", modPath, version, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return Dependents{}, fmt.Errorf("failed to fetch dependents of %s@%s: %s", modPath, version, resp.Status)
+	}
+
+	var dependents Dependents
+
+	if err := json.NewDecoder(resp.Body).Decode(&dependents); err != nil {
+		return Dependents{}, fmt.Errorf("failed to decode dependents of %s@%s: %w", modPath, version, err)
+	}
+
+	return dependents, nil
+}
+
+// PackageURL returns the deps.dev page for a Go module.
+func PackageURL(modPath string) string {
+	return "https://deps.dev/go/" + url.PathEscape(modPath)
+}
+
+// ImportedByURL returns the pkg.go.dev page listing importers of a Go module.
+func ImportedByURL(modPath string) string {
+	return "https://pkg.go.dev/" + modPath + "?tab=importedby"
+}
+
+// SearchURL returns a pkg.go.dev search for modules similar to modPath.
+func SearchURL(modPath string) string {
+	return "https://pkg.go.dev/search?q=" + url.QueryEscape(modPath)
+}
@@ -0,0 +1,39 @@
+package depsdev
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoDependents(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v3alpha/systems/go/packages/github.com%2Ffoo%2Fbar/versions/v1.2.3:dependents", r.URL.EscapedPath())
+
+		_, _ = w.Write([]byte(`{"dependentCount":12,"directDependentCount":5,"indirectDependentCount":7}`))
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	got, err := c.GoDependents(context.Background(), "github.com/foo/bar", "v1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, Dependents{12, 5, 7}, got)
+}
+
+func TestGoDependents_NotFound(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	_, err := c.GoDependents(context.Background(), "github.com/foo/bar", "v1.2.3")
+	require.Error(t, err)
+}
@@ -0,0 +1,163 @@
+// Package diff compares the findings of two scans, such as the scans of two
+// releases or of a pull request and its base, to report what was introduced
+// and what was resolved in between.
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/baseline"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gitprobe"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// Result lists the findings that differ between two scans. Findings are
+// matched like baseline entries, ignoring line numbers.
+type Result struct {
+	// Added are findings of the second scan missing from the first.
+	Added []finding.Finding `json:"added"`
+	// Resolved are findings of the first scan missing from the second.
+	Resolved []finding.Finding `json:"resolved"`
+}
+
+// Compare returns the findings added and resolved between before and after.
+func Compare(before, after finding.Report) Result {
+	result := Result{Added: []finding.Finding{}, Resolved: []finding.Finding{}}
+
+	known := baseline.New(before)
+	for _, f := range after.Findings {
+		if !known.Contains(f) {
+			result.Added = append(result.Added, f)
+		}
+	}
+
+	remaining := baseline.New(after)
+	for _, f := range before.Findings {
+		if !remaining.Contains(f) {
+			result.Resolved = append(result.Resolved, f)
+		}
+	}
+
+	finding.Sort(result.Added)
+	finding.Sort(result.Resolved)
+
+	return result
+}
+
+// Report returns the added findings as a report, which is what a policy
+// decides on when gating changes on regressions only.
+func (r Result) Report() finding.Report {
+	return finding.Report{Findings: r.Added}
+}
+
+// Load reads a report written with --format json.
+func Load(path string) (finding.Report, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return finding.Report{}, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report finding.Report
+
+	if err := json.Unmarshal(data, &report); err != nil {
+		return finding.Report{}, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+
+	return report, nil
+}
+
+// Scan scans the given repositories with opts.
+type Scan func(ctx context.Context, opts gomod.Options) (finding.Report, error)
+
+// ScanRef scans ref of the git repository in the current directory, by
+// checking it out into a temporary worktree. Paths of opts.Scope and of the
+// findings are relative to the worktree, so they compare equal to those of a
+// scan of the current directory.
+func ScanRef(ctx context.Context, ref string, opts gomod.Options, scan Scan) (finding.Report, error) {
+	dir, cleanup, err := gitprobe.Worktree(ctx, "", ref)
+	if err != nil {
+		return finding.Report{}, err
+	}
+	defer cleanup()
+
+	opts.Scope = scopeIn(dir, opts.Scope)
+	opts.SelfCheck = nil
+
+	report, err := scan(ctx, opts)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	for i, f := range report.Findings {
+		if rel, err := filepath.Rel(dir, filepath.FromSlash(f.File)); err == nil {
+			report.Findings[i].File = files.FormatPath(rel, opts.PathStyle)
+		}
+	}
+
+	return report, nil
+}
+
+// scopeIn returns scope with its paths and anchored exclude patterns moved
+// beneath dir.
+func scopeIn(dir string, scope files.Scope) files.Scope {
+	paths := scope.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	moved := files.Scope{NoIgnore: scope.NoIgnore}
+
+	for _, p := range paths {
+		moved.Paths = append(moved.Paths, filepath.Join(dir, p))
+	}
+
+	for _, pattern := range scope.Exclude {
+		if strings.Contains(pattern, "/") {
+			pattern = filepath.ToSlash(filepath.Join(dir, pattern))
+		}
+
+		moved.Exclude = append(moved.Exclude, pattern)
+	}
+
+	return moved
+}
+
+// Text writes added findings prefixed with "+" and resolved ones prefixed with
+// "-", followed by the number of each.
+func Text(w io.Writer, r Result) error {
+	for _, f := range r.Added {
+		fmt.Fprintf(w, "+ %s: %s (%s)\n", f.File, f.URL(), f.Reason)
+	}
+
+	for _, f := range r.Resolved {
+		fmt.Fprintf(w, "- %s: %s (%s)\n", f.File, f.URL(), f.Reason)
+	}
+
+	if len(r.Added)+len(r.Resolved) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d new, %d resolved\n", len(r.Added), len(r.Resolved))
+
+	return nil
+}
+
+// JSON writes the result as an indented JSON document.
+func JSON(w io.Writer, r Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode diff: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	kept := finding.Finding{Repo: "owner/kept", File: "go.mod", Line: 3, Status: status.Archived, Reason: "repository archived"}
+	resolved := finding.Finding{Repo: "owner/resolved", File: "go.mod", Line: 4, Status: status.Archived, Reason: "repository archived"}
+	added := finding.Finding{Repo: "owner/added", File: "go.mod", Line: 5, Status: status.Missing, Reason: "repository missing"}
+
+	moved := kept
+	moved.Line = 8
+
+	result := Compare(
+		finding.Report{Findings: []finding.Finding{kept, resolved}},
+		finding.Report{Findings: []finding.Finding{moved, added}},
+	)
+	require.Equal(t, Result{Added: []finding.Finding{added}, Resolved: []finding.Finding{resolved}}, result)
+	require.Equal(t, status.Counts{status.Missing: 1}, result.Report().Counts())
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Text(&buf, result))
+	require.Equal(t, "+ go.mod: https://github.com/owner/added (repository missing)\n"+
+		"- go.mod: https://github.com/owner/resolved (repository archived)\n"+
+		"\n1 new, 1 resolved\n", buf.String())
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Tool: &version.Info{Version: "v1.2.3"}, Findings: []finding.Finding{
+		{Repo: "owner/repo", Module: "github.com/owner/repo", File: "go.mod", Line: 3, Status: status.Archived, Archived: true, Reason: "repository archived"},
+	}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, render.JSON(&buf, report))
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, report, loaded)
+}
+
+func TestScopeIn(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("tmp", "worktree")
+
+	scope := scopeIn(dir, files.Scope{Exclude: []string{"vendor/**", "testdata"}, NoIgnore: true})
+	require.Equal(t, files.Scope{
+		Paths:    []string{dir},
+		Exclude:  []string{"tmp/worktree/vendor/**", "testdata"},
+		NoIgnore: true,
+	}, scope)
+	require.True(t, scope.Excluded(filepath.Join(dir, "vendor", "go.mod")))
+
+	scope = scopeIn(dir, files.Scope{Paths: []string{"svc"}})
+	require.Equal(t, []string{filepath.Join(dir, "svc")}, scope.Paths)
+}
@@ -0,0 +1,221 @@
+// Package docker provides commands for scanning Dockerfiles for base images
+// and Go tools that are built from archived GitHub repositories.
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// Kinds of references found in Dockerfiles.
+const (
+	// KindImage is a base image named by a FROM instruction.
+	KindImage = "image"
+	// KindTool is a Go program installed by a RUN instruction.
+	KindTool = "tool"
+)
+
+// Reference is a base image or tool in a Dockerfile whose source repository
+// is on GitHub.
+type Reference struct {
+	// Kind is KindImage or KindTool.
+	Kind string
+	// Name is the image name or Go package path.
+	Name string
+	// Version is the image tag or module version, if any.
+	Version string
+	// Repo is the GitHub repository in the form "owner/repo".
+	Repo string
+	// File is the Dockerfile the reference was found in.
+	File string
+	// Line is the line of the reference in File.
+	Line int
+	// Column is the 1-based column of the reference on Line.
+	Column int
+}
+
+// goTool matches the github.com package path and optional version of Go
+// programs installed with go install, go get or go run.
+var goTool = regexp.MustCompile(`github\.com/[^\s@"';&|]+(@[^\s"';&|]+)?`)
+
+// goCommand matches the go commands that fetch programs.
+var goCommand = regexp.MustCompile(`\bgo\s+(install|get|run)\b`)
+
+// IsDockerfile reports whether a file name is a Dockerfile, such as
+// Dockerfile, Dockerfile.dev or build.Dockerfile.
+func IsDockerfile(name string) bool {
+	return name == "Dockerfile" || strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile")
+}
+
+// imageRepo returns the GitHub repository an image is published from.
+// Only images on the GitHub Container Registry can be mapped reliably, as
+// they are named after the owner and repository that publish them.
+func imageRepo(image string) (string, bool) {
+	path, ok := strings.CutPrefix(image, "ghcr.io/")
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	return parts[0] + "/" + parts[1], true
+}
+
+// splitImage splits an image reference into its name and tag or digest.
+func splitImage(image string) (string, string) {
+	if name, digest, ok := strings.Cut(image, "@"); ok {
+		return name, digest
+	}
+
+	// A colon after the last slash separates the tag; one before it
+	// belongs to a registry port.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+
+	return image, ""
+}
+
+// fromImage returns the image and its column in a FROM instruction, skipping
+// flags such as --platform.
+func fromImage(line string) (string, int) {
+	start := len(line) - len(strings.TrimLeft(line, " \t")) + len("FROM")
+
+	for _, field := range strings.Fields(line[start:]) {
+		if !strings.HasPrefix(field, "--") {
+			return field, start + strings.Index(line[start:], field) + 1
+		}
+	}
+
+	return "", 0
+}
+
+// parse returns the references in a Dockerfile.
+func parse(name string, data []byte) []Reference {
+	var (
+		refs   []Reference
+		stages = map[string]bool{}
+		// goRun is set while reading a RUN instruction, including its
+		// continuation lines, once it has run a go command.
+		inRun, goRun, continued bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !continued {
+			fields := strings.Fields(trimmed)
+			instruction := strings.ToUpper(fields[0])
+			inRun = instruction == "RUN"
+			goRun = false
+
+			if instruction == "FROM" {
+				image, column := fromImage(line)
+
+				// FROM image AS stage
+				if len(fields) >= 2 && strings.EqualFold(fields[len(fields)-2], "AS") {
+					stages[strings.ToLower(fields[len(fields)-1])] = true
+				}
+
+				imageName, tag := splitImage(image)
+				if repo, ok := imageRepo(imageName); ok && !stages[strings.ToLower(image)] {
+					refs = append(refs, Reference{KindImage, imageName, tag, repo, name, lineNo, column})
+				}
+			}
+		}
+
+		continued = strings.HasSuffix(trimmed, `\`)
+
+		if !inRun {
+			continue
+		}
+
+		start := 0
+
+		if !goRun {
+			loc := goCommand.FindStringIndex(line)
+			if loc == nil {
+				continue
+			}
+
+			goRun = true
+			start = loc[1]
+		}
+
+		for _, loc := range goTool.FindAllStringIndex(line[start:], -1) {
+			loc[0] += start
+			loc[1] += start
+
+			// Skip URLs such as https://github.com/owner/repo/releases.
+			if loc[0] > 0 && line[loc[0]-1] == '/' {
+				continue
+			}
+
+			pkg, version, _ := strings.Cut(line[loc[0]:loc[1]], "@")
+
+			repo, ok := client.RepoFromURL(pkg)
+			if !ok {
+				continue
+			}
+
+			refs = append(refs, Reference{KindTool, pkg, version, repo, name, lineNo, loc[0] + 1})
+		}
+	}
+
+	return refs
+}
+
+// Discover parses the given Dockerfiles.
+func Discover(ctx context.Context, dockerfiles []string) []Reference {
+	var refs []Reference
+
+	for _, name := range dockerfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		refs = append(refs, parse(name, data)...)
+	}
+
+	return refs
+}
+
+// Repos returns the repositories of the base images and Go tools referenced
+// by Dockerfiles in opts.Scope.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	dockerfiles, err := files.RecursiveMatch(ctx, opts.Scope, IsDockerfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Dockerfiles: %w", err)
+	}
+
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, ref := range Discover(ctx, dockerfiles) {
+		repos[ref.Repo] = append(repos[ref.Repo], gomod.NewRepoInfo(ref.File, ref.Line, ref.Column, ref.Name, ref.Version, false))
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	data := `# syntax=docker/dockerfile:1
+FROM --platform=$BUILDPLATFORM golang:1.24 AS build
+RUN go install github.com/golangci/golangci-lint/cmd/golangci-lint@v1.59.0
+RUN go install \
+    github.com/owner/tool@latest && \
+    curl -sL https://github.com/other/repo/releases/download/v1/x.tgz | tar xz
+RUN git clone https://github.com/ignored/repo
+
+FROM ghcr.io/owner/image:1.2 AS runtime
+FROM build
+FROM ghcr.io/owner/base@sha256:abc
+`
+
+	require.Equal(t, []Reference{
+		{KindTool, "github.com/golangci/golangci-lint/cmd/golangci-lint", "v1.59.0", "golangci/golangci-lint", "Dockerfile", 3, 16},
+		{KindTool, "github.com/owner/tool", "latest", "owner/tool", "Dockerfile", 5, 5},
+		{KindImage, "ghcr.io/owner/image", "1.2", "owner/image", "Dockerfile", 9, 6},
+		{KindImage, "ghcr.io/owner/base", "sha256:abc", "owner/base", "Dockerfile", 11, 6},
+	}, parse("Dockerfile", []byte(data)))
+}
+
+func TestIsDockerfile(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsDockerfile("Dockerfile"))
+	require.True(t, IsDockerfile("Dockerfile.dev"))
+	require.True(t, IsDockerfile("build.Dockerfile"))
+	require.False(t, IsDockerfile("Dockerfile-notes.md"))
+}
+
+func TestSplitImage(t *testing.T) {
+	t.Parallel()
+
+	name, tag := splitImage("localhost:5000/image:1.0")
+	require.Equal(t, "localhost:5000/image", name)
+	require.Equal(t, "1.0", tag)
+
+	name, tag = splitImage("localhost:5000/image")
+	require.Equal(t, "localhost:5000/image", name)
+	require.Empty(t, tag)
+}
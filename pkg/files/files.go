@@ -3,35 +3,308 @@ package files
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
 )
 
-// RecursiveFind searches recursively from the current directory for files with the
-// given name. It returns a slice of matching file paths or an error if
+// skipDirs are directory names that never contain manifests worth scanning
+// and are pruned from the walk.
+var skipDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	".terraform":   true,
+	"node_modules": true,
+}
+
+// RecursiveFind searches recursively through scope for files with the given
+// name. It returns a sorted slice of matching file paths or an error if
 // directory traversal fails. Logging is performed for each found file using
 // slog with the provided context.
-func RecursiveFind(ctx context.Context, name string) ([]string, error) {
-	var files []string
+func RecursiveFind(ctx context.Context, scope Scope, name string) ([]string, error) {
+	return RecursiveMatch(ctx, scope, func(base string) bool {
+		return base == name
+	})
+}
+
+// RecursiveMatch searches recursively through scope for files whose base name
+// satisfies match, such as Dockerfile and Dockerfile.dev, and returns their
+// sorted paths.
+func RecursiveMatch(ctx context.Context, scope Scope, match func(name string) bool) ([]string, error) {
+	if err := scope.Validate(); err != nil {
+		return nil, err
+	}
+
+	var found []string
 
-	err := filepath.WalkDir(".", func(path string, d os.DirEntry, err error) error {
+	for _, root := range scope.roots() {
+		files, err := walk(ctx, root, match, scope, 4*runtime.GOMAXPROCS(0))
 		if err != nil {
-			return fmt.Errorf("error accessing path %s: %w", path, err)
+			return nil, err
+		}
+
+		found = append(found, files...)
+	}
+
+	found = dedupe(ctx, found)
+
+	for _, path := range found {
+		audit.Record(ctx, audit.FileFound, path, "")
+	}
+
+	return found, nil
+}
+
+// walk reads directories beneath root concurrently, using at most workers
+// goroutines in addition to the caller, and returns the sorted paths of files
+// whose name satisfies match. Directories in skipDirs, paths excluded by scope
+// and, unless scope.NoIgnore is set, paths listed in IgnoreFiles are not
+// descended into, and paths that refer to the same file are only returned
+// once.
+func walk(ctx context.Context, root string, match func(name string) bool, scope Scope, workers int) ([]string, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		files    []string
+		firstErr error
+	)
+
+	sem := make(chan struct{}, workers)
+
+	var visit func(dir string, rules []ignoreRule)
+
+	visit = func(dir string, rules []ignoreRule) {
+		if !scope.NoIgnore {
+			// Clip so directories visited concurrently never share the
+			// backing array of their parent's rules.
+			rules = append(slices.Clip(rules), readIgnoreRules(ctx, dir)...)
 		}
 
-		if !d.IsDir() && d.Name() == name {
-			files = append(files, path)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error accessing path %s: %w", dir, err)
+			}
+			mu.Unlock()
 
-			slog.DebugContext(ctx, "found "+name+" file", slog.String("path", path))
+			return
 		}
 
-		return nil
-	})
-	if err != nil {
-		return files, fmt.Errorf("error walking directories: %w", err)
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+
+			if scope.Excluded(path) {
+				slog.DebugContext(ctx, "excluded "+path)
+				audit.Record(ctx, audit.FileSkipped, path, "matches an --exclude pattern")
+
+				continue
+			}
+
+			if ignored(rules, path, entry.IsDir()) {
+				slog.DebugContext(ctx, "ignored "+path)
+				audit.Record(ctx, audit.FileSkipped, path, "listed in .gitignore or .arcignore-paths")
+
+				continue
+			}
+
+			if !entry.IsDir() {
+				if match(entry.Name()) {
+					mu.Lock()
+					files = append(files, path)
+					mu.Unlock()
+
+					slog.DebugContext(ctx, "found "+entry.Name()+" file", slog.String("path", path))
+				}
+
+				continue
+			}
+
+			if skipDirs[entry.Name()] {
+				audit.Record(ctx, audit.FileSkipped, path, entry.Name()+" directories are never searched")
+
+				continue
+			}
+
+			// Hand the directory to another goroutine while there is
+			// capacity, and read it on this one otherwise so the walk
+			// never blocks waiting for a free worker.
+			select {
+			case sem <- struct{}{}:
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					visit(path, rules)
+				}()
+			default:
+				visit(path, rules)
+			}
+		}
+	}
+
+	visit(root, nil)
+	wg.Wait()
+
+	files = dedupe(ctx, files)
+
+	if firstErr != nil {
+		return files, fmt.Errorf("error walking directories: %w", firstErr)
 	}
 
 	return files, nil
 }
+
+// dedupe sorts paths and removes those that refer to the same file as another
+// path, by resolved absolute path or by inode, such as symlinked manifests in
+// bazel or build output directories and hard links. Regular files are kept in
+// preference to symlinks. Paths that cannot be resolved are always kept.
+func dedupe(ctx context.Context, paths []string) []string {
+	slices.Sort(paths)
+
+	// Visit regular files before symlinks so the symlinks are dropped.
+	ordered := make([]string, 0, len(paths))
+	links := []string{}
+
+	for _, path := range paths {
+		if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			links = append(links, path)
+
+			continue
+		}
+
+		ordered = append(ordered, path)
+	}
+
+	var (
+		kept     []string
+		resolved = map[string]bool{}
+		bySize   = map[int64][]fs.FileInfo{}
+	)
+
+	for _, path := range append(ordered, links...) {
+		target, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			target, err = filepath.Abs(target)
+		}
+
+		info, statErr := os.Stat(path)
+		if err != nil || statErr != nil {
+			kept = append(kept, path)
+
+			continue
+		}
+
+		duplicate := resolved[target] || slices.ContainsFunc(bySize[info.Size()], func(other fs.FileInfo) bool {
+			return os.SameFile(info, other)
+		})
+		if duplicate {
+			slog.DebugContext(ctx, "skipping duplicate file", slog.String("path", path), slog.String("resolved", target))
+			audit.Record(ctx, audit.FileSkipped, path, "same file as another manifest")
+
+			continue
+		}
+
+		resolved[target] = true
+		bySize[info.Size()] = append(bySize[info.Size()], info)
+		kept = append(kept, path)
+	}
+
+	slices.Sort(kept)
+
+	return kept
+}
+
+// Supported path styles for reports.
+const (
+	// PathStyleNative prints paths with the operating system's separator.
+	PathStyleNative = "native"
+	// PathStyleUnix prints paths with forward slashes on every platform.
+	PathStyleUnix = "unix"
+)
+
+// PathStyles lists every supported path style.
+var PathStyles = []string{PathStyleNative, PathStyleUnix}
+
+// FormatPath returns path in the given style. Unknown styles leave the path
+// unchanged.
+func FormatPath(path, style string) string {
+	if style == PathStyleUnix {
+		return filepath.ToSlash(path)
+	}
+
+	return path
+}
+
+// CacheDir returns the directory used for gh-arc's persistent state, such as
+// %LOCALAPPDATA%\gh-arc on Windows or $XDG_CACHE_HOME/gh-arc on Linux. The
+// directory is not created.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, "gh-arc"), nil
+}
+
+// CacheEntries returns the files and directories in the cache directory dir,
+// except those named in keep, such as state that must survive a clean. A
+// missing directory has no entries.
+func CacheEntries(dir string, keep ...string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var paths []string
+
+	for _, e := range entries {
+		if !slices.Contains(keep, e.Name()) {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	return paths, nil
+}
+
+// Clean removes the given paths, which may be files or directories, and
+// writes a line for each one that existed. With dryRun set nothing is removed
+// and the lines describe what would be.
+func Clean(w io.Writer, paths []string, dryRun bool) error {
+	for _, path := range paths {
+		if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(w, "would remove %s\n", path)
+
+			continue
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+
+		fmt.Fprintf(w, "removed %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPath(t *testing.T) {
+	t.Parallel()
+
+	native := filepath.Join("a", "b", "go.mod")
+
+	require.Equal(t, "a/b/go.mod", FormatPath(native, PathStyleUnix))
+	require.Equal(t, native, FormatPath(native, PathStyleNative))
+}
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	t.Setenv("HOME", "/tmp/home")
+
+	dir, err := CacheDir()
+	require.NoError(t, err)
+	require.Equal(t, "gh-arc", filepath.Base(dir))
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	for _, path := range []string{
+		"go.mod",
+		"a/go.mod",
+		"a/b/c/go.mod",
+		"a/b/other.txt",
+		"d/go.mod",
+		".git/go.mod",
+		"d/node_modules/pkg/go.mod",
+		"e/.terraform/modules/vpc/go.mod",
+	} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	want := []string{
+		filepath.Join(root, "a", "b", "c", "go.mod"),
+		filepath.Join(root, "a", "go.mod"),
+		filepath.Join(root, "d", "go.mod"),
+		filepath.Join(root, "go.mod"),
+	}
+
+	for _, workers := range []int{0, 1, 8} {
+		got, err := walk(context.Background(), root, func(name string) bool {
+			return name == "go.mod"
+		}, Scope{}, workers)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
+
+func TestWalk_Duplicates(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	for _, path := range []string{"a/go.mod", "b/go.mod"} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("module example.com/"+path), 0o600))
+	}
+
+	// A symlink sorting before its target and a hard link are both the
+	// same file as a/go.mod.
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "0-bazel-out"), 0o750))
+
+	if err := os.Symlink(filepath.Join(root, "a", "go.mod"), filepath.Join(root, "0-bazel-out", "go.mod")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "c"), 0o750))
+	require.NoError(t, os.Link(filepath.Join(root, "a", "go.mod"), filepath.Join(root, "c", "go.mod")))
+
+	got, err := walk(context.Background(), root, func(name string) bool {
+		return name == "go.mod"
+	}, Scope{}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(root, "a", "go.mod"),
+		filepath.Join(root, "b", "go.mod"),
+	}, got)
+}
+
+func TestWalk_Exclude(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	for _, path := range []string{"go.mod", "vendor/a/go.mod", "a/testdata/go.mod", "a/go.mod"} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	scope := Scope{Exclude: []string{"**/vendor/**", "testdata"}}
+
+	got, err := walk(context.Background(), root, func(name string) bool {
+		return name == "go.mod"
+	}, scope, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(root, "a", "go.mod"),
+		filepath.Join(root, "go.mod"),
+	}, got)
+}
+
+func TestCacheEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "repos"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "telemetry.json"), nil, 0o600))
+
+	got, err := CacheEntries(dir, "telemetry.json")
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "repos")}, got)
+
+	got, err = CacheEntries(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestClean(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache := filepath.Join(dir, "cache")
+	missing := filepath.Join(dir, "missing")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(cache, "repos"), 0o750))
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Clean(&buf, []string{cache, missing}, true))
+	require.Equal(t, "would remove "+cache+"\n", buf.String())
+	require.DirExists(t, cache)
+
+	buf.Reset()
+
+	require.NoError(t, Clean(&buf, []string{cache, missing}, false))
+	require.Equal(t, "removed "+cache+"\n", buf.String())
+	require.NoDirExists(t, cache)
+
+	baseline := filepath.Join(dir, "arc-baseline.json")
+	report := filepath.Join(dir, "report.json")
+
+	for _, path := range []string{baseline, report} {
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+	}
+
+	buf.Reset()
+
+	require.NoError(t, Clean(&buf, []string{baseline, report}, false))
+	require.Equal(t, "removed "+baseline+"\nremoved "+report+"\n", buf.String())
+	require.NoFileExists(t, baseline)
+	require.NoFileExists(t, report)
+}
@@ -0,0 +1,127 @@
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFiles are read in every directory walked and list paths beneath that
+// directory to skip, in .gitignore syntax. .arcignore-paths is for paths that
+// are committed, such as vendored copies, but should not be scanned.
+var IgnoreFiles = []string{".gitignore", ".arcignore-paths"}
+
+// ignoreRule is a pattern read from one of IgnoreFiles.
+type ignoreRule struct {
+	// dir is the directory of the file the rule was read from.
+	dir      string
+	segments []string
+	// anchored rules match paths relative to dir, others match base names
+	// at any depth.
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+// parseIgnore parses the rules of an ignore file in dir. Blank lines and
+// comments are skipped, a leading "!" re-includes a path, a trailing "/"
+// matches only directories and a pattern containing any other "/" is
+// relative to dir.
+func parseIgnore(dir string, data []byte) []ignoreRule {
+	var rules []ignoreRule
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{dir: dir}
+
+		if rest, ok := strings.CutPrefix(line, "!"); ok {
+			rule.negate = true
+			line = rest
+		}
+
+		line = strings.TrimPrefix(line, `\`)
+
+		if rest, ok := strings.CutSuffix(line, "/"); ok {
+			rule.dirOnly = true
+			line = rest
+		}
+
+		rule.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		if line == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(line, "/")
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// readIgnoreRules returns the rules of the IgnoreFiles in dir.
+func readIgnoreRules(ctx context.Context, dir string) []ignoreRule {
+	var rules []ignoreRule
+
+	for _, name := range IgnoreFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name)) // #nosec G304
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", filepath.Join(dir, name), err))
+			}
+
+			continue
+		}
+
+		rules = append(rules, parseIgnore(dir, data)...)
+	}
+
+	return rules
+}
+
+// ignored reports whether the last rule matching name excludes it.
+func ignored(rules []ignoreRule, name string, isDir bool) bool {
+	result := false
+
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		rel, err := filepath.Rel(rule.dir, name)
+		if err != nil {
+			continue
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		var match bool
+
+		if rule.anchored {
+			match = matchSegments(rule.segments, strings.Split(rel, "/"))
+		} else {
+			match, _ = path.Match(rule.segments[0], path.Base(rel))
+		}
+
+		if match {
+			result = !rule.negate
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+package files
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnored(t *testing.T) {
+	t.Parallel()
+
+	rules := parseIgnore("repo", []byte(`# build output
+/dist
+build/
+*.gen.mod
+third_party/**
+!third_party/keep
+\#hash
+`))
+
+	for name, want := range map[string]bool{
+		"repo/dist":             true,
+		"repo/a/dist":           false,
+		"repo/build":            true,
+		"repo/a/build":          true,
+		"repo/x.gen.mod":        true,
+		"repo/a/x.gen.mod":      true,
+		"repo/third_party/a":    true,
+		"repo/third_party/keep": false,
+		"repo/a/third_party/b":  false,
+		"repo/#hash":            true,
+		"repo/go.mod":           false,
+		"other/dist":            false,
+	} {
+		require.Equal(t, want, ignored(rules, filepath.FromSlash(name), true), name)
+	}
+
+	require.False(t, ignored(rules, filepath.Join("repo", "a", "build"), false))
+}
+
+func TestWalk_Ignore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	for _, path := range []string{"go.mod", "dist/go.mod", "a/go.mod", "a/gen/go.mod", "vendor/x/go.mod"} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("dist/\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".arcignore-paths"), []byte("/vendor\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", ".gitignore"), []byte("/gen\n"), 0o600))
+
+	match := func(name string) bool {
+		return name == "go.mod"
+	}
+
+	got, err := walk(context.Background(), root, match, Scope{}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(root, "a", "go.mod"),
+		filepath.Join(root, "go.mod"),
+	}, got)
+
+	got, err = walk(context.Background(), root, match, Scope{NoIgnore: true}, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 5)
+}
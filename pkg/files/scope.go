@@ -0,0 +1,101 @@
+package files
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Scope limits which parts of the tree are searched for manifests.
+type Scope struct {
+	// Paths are the directories searched. Empty means the current
+	// directory.
+	Paths []string
+	// Exclude are glob patterns of files and directories to skip, matched
+	// against slash-separated paths as they are reported, e.g. "vendor/**"
+	// or "**/testdata". "**" matches any number of directories, and a
+	// pattern without a slash matches a name at any depth.
+	Exclude []string
+	// NoIgnore disables reading IgnoreFiles.
+	NoIgnore bool
+}
+
+// Validate reports whether every exclude pattern is well formed.
+func (s Scope) Validate() error {
+	for _, pattern := range s.Exclude {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// roots returns the directories to search.
+func (s Scope) roots() []string {
+	if len(s.Paths) == 0 {
+		return []string{"."}
+	}
+
+	return s.Paths
+}
+
+// Excluded reports whether a file or directory path matches an exclude
+// pattern.
+func (s Scope) Excluded(name string) bool {
+	name = filepath.ToSlash(filepath.Clean(name))
+
+	for _, pattern := range s.Exclude {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(name)); ok {
+				return true
+			}
+
+			continue
+		}
+
+		if matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchSegments reports whether the slash-separated path name matches
+// pattern, in which "**" matches any number of directories and other
+// segments are matched with path.Match.
+func MatchSegments(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
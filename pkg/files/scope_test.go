@@ -0,0 +1,40 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_Excluded(t *testing.T) {
+	t.Parallel()
+
+	scope := Scope{Exclude: []string{"vendor/**", "**/testdata", "*.lock", "tools/go.mod"}}
+
+	for name, want := range map[string]bool{
+		"vendor":            true,
+		"vendor/a/go.mod":   true,
+		"./vendor/go.mod":   true,
+		"a/vendor/go.mod":   false,
+		"testdata":          true,
+		"a/b/testdata":      true,
+		"a/testdata/go.mod": false,
+		"Cargo.lock":        true,
+		"a/b/poetry.lock":   true,
+		"tools/go.mod":      true,
+		"a/tools/go.mod":    false,
+		"go.mod":            false,
+		"a/go.mod":          false,
+	} {
+		require.Equal(t, want, scope.Excluded(name), name)
+	}
+
+	require.False(t, Scope{}.Excluded("vendor/go.mod"))
+}
+
+func TestScope_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Scope{Exclude: []string{"vendor/**", "**/testdata", "*.lock"}}.Validate())
+	require.ErrorContains(t, Scope{Exclude: []string{"a/[b"}}.Validate(), `invalid exclude pattern "a/[b"`)
+}
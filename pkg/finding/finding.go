@@ -0,0 +1,446 @@
+// Package finding defines the data model produced by scans: one Finding per
+// unhealthy dependency reference, collected in a Report. Scans return findings
+// instead of printing them so that every output format, and programs
+// embedding the scan, work from the same data.
+package finding
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/advisory"
+	"github.com/wayneashleyberry/gh-arc/pkg/automation"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/depsdev"
+	"github.com/wayneashleyberry/gh-arc/pkg/forks"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+// Finding is a dependency whose upstream repository is archived, missing or
+// otherwise unhealthy, at the location where it is required.
+type Finding struct {
+	// Module is the module path, e.g. "github.com/owner/repo/v2".
+	Module string `json:"module,omitempty"`
+	// Version is the required version of the module.
+	Version string `json:"version,omitempty"`
+	// Latest is the latest tag of an outdated module.
+	Latest string `json:"latest,omitempty"`
+	// Repo is the GitHub repository in the form "owner/repo".
+	Repo string `json:"repo"`
+	// Canonical is the current name of Repo, in the form "owner/repo", if
+	// it has been renamed or transferred since it was referenced.
+	Canonical string `json:"canonical,omitempty"`
+	// File is the manifest or binary that requires the module.
+	File string `json:"file"`
+	// CodeOwners are the users and teams the repository's CODEOWNERS file
+	// lists for File, if requested.
+	CodeOwners []string `json:"code_owners,omitempty"`
+	// Automation is whether Dependabot or Renovate keeps the dependency up
+	// to date, if requested and the repository configures either.
+	Automation automation.Coverage `json:"automation,omitempty"`
+	// Line is the line of the requirement in File, or zero if unknown.
+	Line int `json:"line,omitempty"`
+	// Column is the 1-based column of the requirement on Line, or zero if
+	// unknown.
+	Column int `json:"column,omitempty"`
+	// Indirect is set for indirect dependencies.
+	Indirect bool `json:"indirect"`
+	// Tool is set for dependencies providing a tool used to build or
+	// develop the module, declared with a tool directive in go.mod or a
+	// blank import in tools.go, rather than code linked into it.
+	Tool bool `json:"tool,omitempty"`
+	// Status classifies the finding.
+	Status status.Status `json:"status"`
+	// Archived is set when the repository is archived.
+	Archived bool `json:"archived"`
+	// PushedAt is the time of the last push to the repository.
+	PushedAt string `json:"pushed_at,omitempty"`
+	// Reason explains the finding in a few words.
+	Reason string `json:"reason"`
+	// Informational is set for findings that never fail a run, such as
+	// those in repositories of ignored owners.
+	Informational bool `json:"informational,omitempty"`
+	// Baselined is set for findings acknowledged in a baseline file, which
+	// never fail a run either.
+	Baselined bool `json:"baselined,omitempty"`
+	// Mirror is set when the repository is a read-only mirror, whose
+	// findings are informational unless its upstream was checked instead.
+	Mirror bool `json:"mirror,omitempty"`
+	// Upstream is the URL of the repository a mirror is mirrored from, if
+	// it is known.
+	Upstream string `json:"upstream,omitempty"`
+	// Successor is the repository an archived repository names as its
+	// replacement, in the form "owner/repo", if it names one.
+	Successor string `json:"successor,omitempty"`
+	// Alternatives points at places to look for a replacement, if requested.
+	Alternatives *Alternatives `json:"alternatives,omitempty"`
+	// Advisories are unpatched security advisories affecting Version of an
+	// archived dependency, if requested.
+	Advisories []advisory.Advisory `json:"advisories,omitempty"`
+	// Details is context about the repository that helps prioritize
+	// replacements, if requested.
+	Details *Details `json:"details,omitempty"`
+	// Metadata is the repository metadata the finding was derived from.
+	Metadata client.RepoResult `json:"-"`
+}
+
+// Severity ranks how urgently a finding needs attention.
+type Severity string
+
+// Supported severities, from most to least urgent.
+const (
+	// SeverityError is an archived or missing direct dependency, or an
+	// archived dependency with unpatched advisories.
+	SeverityError Severity = "error"
+	// SeverityWarning is any other unhealthy dependency, such as an indirect
+	// or stale one.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is a finding that does not need action, such as a moved
+	// or unknown repository or an informational finding.
+	SeverityInfo Severity = "info"
+)
+
+// Alternatives points users at places to look for a maintained replacement.
+type Alternatives struct {
+	// Dependents is the number of dependents on deps.dev, if known.
+	Dependents *depsdev.Dependents `json:"dependents,omitempty"`
+	DepsDevURL string              `json:"deps_dev_url"`
+	ImportedBy string              `json:"imported_by_url"`
+	SearchURL  string              `json:"search_url"`
+	// Forks are forks of the archived repository ranked as candidate
+	// replacements, best first.
+	Forks []forks.Candidate `json:"forks,omitempty"`
+}
+
+// Details describes how alive a repository still is beyond its last push.
+type Details struct {
+	// OpenIssues counts open issues and pull requests.
+	OpenIssues    int    `json:"open_issues"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// LatestRelease is the tag of the latest release, if there is one.
+	LatestRelease   string `json:"latest_release,omitempty"`
+	LatestReleaseAt string `json:"latest_release_at,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Homepage        string `json:"homepage,omitempty"`
+	// Topics are the topics the repository is labelled with.
+	Topics []string `json:"topics,omitempty"`
+}
+
+// Severity classifies the finding: archived and missing direct dependencies
+// and archived dependencies with unpatched advisories are errors, moved, unknown, informational and baselined findings are info,
+// and everything else is a warning.
+func (f Finding) Severity() Severity {
+	if f.Informational || f.Baselined {
+		return SeverityInfo
+	}
+
+	switch f.Status {
+	case status.Moved, status.Unknown:
+		return SeverityInfo
+	case status.Archived, status.Missing:
+		if f.Indirect && len(f.Advisories) == 0 {
+			return SeverityWarning
+		}
+
+		return SeverityError
+	case status.Deprecated, status.UpstreamArchived, status.Stale, status.UnsupportedGo,
+		status.NoLicense, status.DisallowedLicense, status.Copyleft, status.MissingVersion, status.Outdated:
+		return SeverityWarning
+	}
+
+	return SeverityWarning
+}
+
+// Unattended reports whether the finding is an archived dependency that the
+// repository's update automation has been told to ignore, which nobody is
+// likely to be prompted to replace.
+func (f Finding) Unattended() bool {
+	return f.Archived && f.Automation == automation.Excluded
+}
+
+// OutdatedPath reports whether the finding's repository has been renamed or
+// transferred and its module path, or action reference, still names it by
+// its old name.
+func (f Finding) OutdatedPath() bool {
+	if f.Canonical == "" {
+		return false
+	}
+
+	module, repo := strings.ToLower(f.Module), strings.ToLower(f.Repo)
+
+	for _, prefix := range []string{"github.com/" + repo, repo} {
+		if module == prefix || strings.HasPrefix(module, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// URL returns the GitHub URL of the finding's repository.
+func (f Finding) URL() string {
+	return "https://github.com/" + f.Repo
+}
+
+// Report is the outcome of a scan.
+type Report struct {
+	// Tool is the build of arc that produced the report, so consumers can
+	// handle changes to its schema and reproduce results. It is set when
+	// the report is written.
+	Tool     *version.Info `json:"tool,omitempty"`
+	Findings []Finding     `json:"findings"`
+	// Unchecked is the number of repositories skipped because the API call
+	// budget was exhausted, making the report partial.
+	Unchecked int `json:"unchecked,omitempty"`
+	// MaxAPICalls is the API call budget the scan ran with.
+	MaxAPICalls int `json:"max_api_calls,omitempty"`
+	// Degraded is the number of repositories checked with git instead of
+	// the API, which cannot tell whether they are archived, making the
+	// report partial.
+	Degraded int `json:"degraded,omitempty"`
+	// Unreadable is the number of repositories whose go.mod files could not
+	// all be read, such as repositories of an organization, making the
+	// report partial.
+	Unreadable int `json:"unreadable,omitempty"`
+}
+
+// Counts returns the number of findings per status.
+func (r Report) Counts() status.Counts {
+	counts := status.Counts{}
+	for _, f := range r.Findings {
+		counts[f.Status]++
+	}
+
+	return counts
+}
+
+// DirectCounts returns the number of findings per status in direct
+// dependencies.
+func (r Report) DirectCounts() status.Counts {
+	counts := status.Counts{}
+
+	for _, f := range r.Findings {
+		if !f.Indirect {
+			counts[f.Status]++
+		}
+	}
+
+	return counts
+}
+
+// Enforced returns the report without informational and baselined findings,
+// which is what a policy decides on.
+func (r Report) Enforced() Report {
+	r.Findings = slices.DeleteFunc(slices.Clone(r.Findings), func(f Finding) bool {
+		return f.Informational || f.Baselined
+	})
+
+	return r
+}
+
+// Summary describes the report in one line, e.g. "3 archived, 2 stale, 1
+// unknown across 4 modules", or "no findings".
+func (r Report) Summary() string {
+	counts := r.Counts()
+	if counts.Total() == 0 {
+		return "no findings"
+	}
+
+	modules := map[string]bool{}
+	for _, f := range r.Findings {
+		modules[cmp.Or(f.Module, f.Repo)] = true
+	}
+
+	noun := "modules"
+	if len(modules) == 1 {
+		noun = "module"
+	}
+
+	return fmt.Sprintf("%s across %d %s", counts, len(modules), noun)
+}
+
+// PartialNote explains why the report is partial, or returns an empty string.
+func (r Report) PartialNote() string {
+	var reasons []string
+
+	if r.Unchecked > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d repositories not checked, API call budget of %d exhausted", r.Unchecked, r.MaxAPICalls))
+	}
+
+	if r.Degraded > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d repositories checked with git, which cannot tell whether they are archived", r.Degraded))
+	}
+
+	if r.Unreadable > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d repositories could not be read", r.Unreadable))
+	}
+
+	if len(reasons) == 0 {
+		return ""
+	}
+
+	return "partial report: " + strings.Join(reasons, "; ")
+}
+
+// Orders findings can be sorted in.
+const (
+	// OrderFile sorts by file and position in it, then by repository.
+	OrderFile = "file"
+	// OrderModule sorts by module, then by file.
+	OrderModule = "module"
+	// OrderPushed sorts the least recently pushed repositories first, and
+	// those without a push date last.
+	OrderPushed = "pushed"
+	// OrderSeverity sorts the most severe findings first, then by status
+	// from most to least severe, then by file.
+	OrderSeverity = "severity"
+)
+
+// Orders lists every order findings can be sorted in.
+var Orders = []string{OrderFile, OrderModule, OrderPushed, OrderSeverity}
+
+// severityRank ranks severities from most to least urgent.
+var severityRank = map[Severity]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// Sort orders findings by file, line, column, repository and status.
+func Sort(findings []Finding) {
+	slices.SortFunc(findings, compareFile)
+}
+
+// SortBy orders findings in order, one of Orders. Ties, and unknown orders,
+// fall back to the order of Sort.
+func SortBy(findings []Finding, order string) {
+	switch order {
+	case OrderModule:
+		slices.SortFunc(findings, func(a, b Finding) int {
+			return cmp.Or(strings.Compare(cmp.Or(a.Module, a.Repo), cmp.Or(b.Module, b.Repo)), compareFile(a, b))
+		})
+	case OrderPushed:
+		slices.SortFunc(findings, func(a, b Finding) int {
+			at, aOK := pushedAt(a)
+			bt, bOK := pushedAt(b)
+
+			switch {
+			case !aOK && bOK:
+				return 1
+			case aOK && !bOK:
+				return -1
+			}
+
+			return cmp.Or(at.Compare(bt), compareFile(a, b))
+		})
+	case OrderSeverity:
+		slices.SortFunc(findings, func(a, b Finding) int {
+			return cmp.Or(
+				cmp.Compare(severityRank[a.Severity()], severityRank[b.Severity()]),
+				cmp.Compare(slices.Index(status.All, a.Status), slices.Index(status.All, b.Status)),
+				compareFile(a, b),
+			)
+		})
+	default:
+		Sort(findings)
+	}
+}
+
+// pushedAt parses the push date of the finding's repository, which the git
+// provider reports in the committer's time zone.
+func pushedAt(f Finding) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, f.PushedAt)
+
+	return t, err == nil
+}
+
+// compareFile compares findings by file, line, column, repository and status.
+func compareFile(a, b Finding) int {
+	return cmp.Or(
+		strings.Compare(a.File, b.File),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Column, b.Column),
+		strings.Compare(a.Repo, b.Repo),
+		cmp.Compare(a.Status, b.Status),
+	)
+}
+
+// Groupings findings can be grouped by.
+const (
+	// GroupFile groups findings by the file they were found in.
+	GroupFile = "file"
+	// GroupRepo groups findings by repository.
+	GroupRepo = "repo"
+	// GroupOwner groups findings by the user or organization owning their
+	// repository, which shows when much of one vendor's stack is affected.
+	GroupOwner = "owner"
+	// GroupSeverity groups findings by severity.
+	GroupSeverity = "severity"
+	// GroupTeam groups findings by the code owners of their file, so each
+	// team sees the findings it is responsible for.
+	GroupTeam = "team"
+)
+
+// Groupings lists everything findings can be grouped by.
+var Groupings = []string{GroupFile, GroupRepo, GroupOwner, GroupSeverity, GroupTeam}
+
+// Group is findings sharing a key.
+type Group struct {
+	Key      string
+	Findings []Finding
+}
+
+// GroupBy splits findings into groups by, one of Groupings, keeping their
+// order within each group. Severity groups are ordered from most to least
+// urgent, and other groups by key.
+func GroupBy(findings []Finding, by string) []Group {
+	var groups []Group
+
+	for _, f := range findings {
+		key := groupKey(f, by)
+
+		i := slices.IndexFunc(groups, func(g Group) bool { return g.Key == key })
+		if i < 0 {
+			groups = append(groups, Group{Key: key})
+			i = len(groups) - 1
+		}
+
+		groups[i].Findings = append(groups[i].Findings, f)
+	}
+
+	slices.SortFunc(groups, func(a, b Group) int {
+		if by == GroupSeverity {
+			return cmp.Compare(severityRank[Severity(a.Key)], severityRank[Severity(b.Key)])
+		}
+
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return groups
+}
+
+// groupKey returns the key of the group f belongs to.
+func groupKey(f Finding, by string) string {
+	switch by {
+	case GroupRepo:
+		return f.Repo
+	case GroupOwner:
+		owner, _, _ := strings.Cut(f.Repo, "/")
+
+		return owner
+	case GroupSeverity:
+		return string(f.Severity())
+	case GroupTeam:
+		if len(f.CodeOwners) == 0 {
+			return "(no code owners)"
+		}
+
+		return strings.Join(f.CodeOwners, " ")
+	}
+
+	if f.File == "" {
+		return "(no file)"
+	}
+
+	return f.File
+}
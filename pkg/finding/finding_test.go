@@ -0,0 +1,176 @@
+package finding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/automation"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestReport_Counts(t *testing.T) {
+	t.Parallel()
+
+	r := Report{Findings: []Finding{
+		{Repo: "a/a", Status: status.Archived},
+		{Repo: "b/b", Status: status.Archived, Indirect: true},
+		{Repo: "c/c", Status: status.Missing},
+	}}
+
+	require.Equal(t, status.Counts{status.Archived: 2, status.Missing: 1}, r.Counts())
+	require.Equal(t, status.Counts{status.Archived: 1, status.Missing: 1}, r.DirectCounts())
+	require.Empty(t, r.PartialNote())
+
+	r.Unchecked = 3
+	r.MaxAPICalls = 10
+	require.Equal(t, "partial report: 3 repositories not checked, API call budget of 10 exhausted", r.PartialNote())
+
+	r.Degraded = 2
+	require.Equal(t, "partial report: 3 repositories not checked, API call budget of 10 exhausted; 2 repositories checked with git, which cannot tell whether they are archived", r.PartialNote())
+
+	require.Equal(t, "partial report: 1 repositories could not be read", Report{Unreadable: 1}.PartialNote())
+}
+
+func TestReport_Summary(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "no findings", Report{}.Summary())
+
+	report := Report{Findings: []Finding{
+		{Module: "github.com/a/a", Repo: "a/a", Status: status.Archived},
+		{Module: "github.com/a/a", Repo: "a/a", File: "tools/go.mod", Status: status.Archived},
+		{Module: "github.com/b/b", Repo: "b/b", Status: status.Stale},
+		{Repo: "c/c", Status: status.Unknown},
+	}}
+	require.Equal(t, "2 archived, 1 stale, 1 unknown across 3 modules", report.Summary())
+
+	report = Report{Findings: []Finding{{Module: "github.com/a/a", Repo: "a/a", Status: status.Missing}}}
+	require.Equal(t, "1 missing across 1 module", report.Summary())
+}
+
+func TestReport_Enforced(t *testing.T) {
+	t.Parallel()
+
+	r := Report{Findings: []Finding{
+		{Repo: "a/a", Status: status.Archived},
+		{Repo: "b/b", Status: status.Archived, Informational: true},
+		{Repo: "c/c", Status: status.Archived, Baselined: true},
+	}}
+
+	require.Equal(t, status.Counts{status.Archived: 1}, r.Enforced().Counts())
+	require.Len(t, r.Findings, 3)
+}
+
+func TestFinding_Severity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		finding Finding
+		want    Severity
+	}{
+		{Finding{Status: status.Archived}, SeverityError},
+		{Finding{Status: status.Missing}, SeverityError},
+		{Finding{Status: status.Archived, Indirect: true}, SeverityWarning},
+		{Finding{Status: status.Stale}, SeverityWarning},
+		{Finding{Status: status.Deprecated}, SeverityWarning},
+		{Finding{Status: status.Unknown}, SeverityInfo},
+		{Finding{Status: status.Moved}, SeverityInfo},
+		{Finding{Status: status.Archived, Informational: true}, SeverityInfo},
+		{Finding{Status: status.Archived, Baselined: true}, SeverityInfo},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.finding.Severity(), "%+v", tt.finding)
+	}
+}
+
+func TestFinding_Unattended(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Finding{Archived: true, Automation: automation.Excluded}.Unattended())
+	require.False(t, Finding{Archived: true, Automation: automation.Covered}.Unattended())
+	require.False(t, Finding{Archived: true}.Unattended())
+	require.False(t, Finding{Status: status.Stale, Automation: automation.Excluded}.Unattended())
+}
+
+func TestFinding_OutdatedPath(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Finding{Module: "github.com/Old/Repo/v2", Repo: "old/repo", Canonical: "new/repo"}.OutdatedPath())
+	require.True(t, Finding{Module: "old/repo", Repo: "old/repo", Canonical: "new/repo"}.OutdatedPath())
+	require.False(t, Finding{Module: "github.com/old/repository", Repo: "old/repo", Canonical: "new/repo"}.OutdatedPath())
+	require.False(t, Finding{Module: "gopkg.in/repo.v1", Repo: "old/repo", Canonical: "new/repo"}.OutdatedPath())
+	require.False(t, Finding{Module: "github.com/old/repo", Repo: "old/repo"}.OutdatedPath())
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	findings := []Finding{
+		{File: "b/go.mod", Line: 1, Repo: "x/x"},
+		{File: "a/go.mod", Line: 9, Repo: "y/y"},
+		{File: "a/go.mod", Line: 3, Repo: "z/z"},
+	}
+
+	Sort(findings)
+
+	require.Equal(t, []string{"z/z", "y/y", "x/x"}, []string{findings[0].Repo, findings[1].Repo, findings[2].Repo})
+}
+
+func TestSortBy(t *testing.T) {
+	t.Parallel()
+
+	findings := []Finding{
+		{File: "a/go.mod", Line: 1, Module: "github.com/c/c", Repo: "c/c", Status: status.Stale, PushedAt: "2021-01-01T00:00:00Z"},
+		{File: "a/go.mod", Line: 2, Module: "github.com/a/a", Repo: "a/a", Status: status.Moved},
+		{File: "b/go.mod", Line: 1, Module: "github.com/b/b", Repo: "b/b", Status: status.Archived, PushedAt: "2020-06-01T02:00:00+02:00"},
+		{File: "b/go.mod", Line: 2, Module: "github.com/d/d", Repo: "d/d", Status: status.Missing},
+	}
+
+	repos := func() []string {
+		var repos []string
+		for _, f := range findings {
+			repos = append(repos, f.Repo)
+		}
+
+		return repos
+	}
+
+	SortBy(findings, OrderModule)
+	require.Equal(t, []string{"a/a", "b/b", "c/c", "d/d"}, repos())
+
+	SortBy(findings, OrderPushed)
+	require.Equal(t, []string{"b/b", "c/c", "a/a", "d/d"}, repos())
+
+	SortBy(findings, OrderSeverity)
+	require.Equal(t, []string{"d/d", "b/b", "c/c", "a/a"}, repos())
+
+	SortBy(findings, OrderFile)
+	require.Equal(t, []string{"c/c", "a/a", "b/b", "d/d"}, repos())
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	findings := []Finding{
+		{File: "b/go.mod", Repo: "vendor/a", Status: status.Stale},
+		{File: "a/go.mod", Repo: "acme/b", Status: status.Missing, CodeOwners: []string{"@acme/a", "@alice"}},
+		{Repo: "vendor/c", Status: status.Archived},
+	}
+
+	keys := func(groups []Group) []string {
+		var keys []string
+		for _, g := range groups {
+			keys = append(keys, fmt.Sprintf("%s:%d", g.Key, len(g.Findings)))
+		}
+
+		return keys
+	}
+
+	require.Equal(t, []string{"(no file):1", "a/go.mod:1", "b/go.mod:1"}, keys(GroupBy(findings, GroupFile)))
+	require.Equal(t, []string{"acme/b:1", "vendor/a:1", "vendor/c:1"}, keys(GroupBy(findings, GroupRepo)))
+	require.Equal(t, []string{"acme:1", "vendor:2"}, keys(GroupBy(findings, GroupOwner)))
+	require.Equal(t, []string{"error:2", "warning:1"}, keys(GroupBy(findings, GroupSeverity)))
+	require.Equal(t, []string{"(no code owners):2", "@acme/a @alice:1"}, keys(GroupBy(findings, GroupTeam)))
+}
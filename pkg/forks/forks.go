@@ -0,0 +1,213 @@
+// Package forks ranks the forks of an archived repository as candidate
+// replacements. Each candidate is scored on recent commit activity, how
+// quickly pull requests are closed, its release history and its stars, and
+// the score is broken down so users can see why one fork ranks above another.
+package forks
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// Scoring windows and limits.
+const (
+	// activityWindow is how far back commits are counted.
+	activityWindow = 90 * 24 * time.Hour
+	// releaseWindow is how far back releases are counted.
+	releaseWindow = 365 * 24 * time.Hour
+	// pullRequestSample is the number of recently closed pull requests used
+	// to judge responsiveness.
+	pullRequestSample = 30
+)
+
+// Maximum points per component, adding up to a score out of 10.
+const (
+	maxActivity       = 4
+	maxResponsiveness = 3
+	maxReleases       = 2
+	maxPopularity     = 1
+)
+
+// API is the part of the GitHub API needed to rank forks. client.Client
+// implements it.
+type API interface {
+	Forks(ctx context.Context, repo string, limit int) ([]client.Fork, error)
+	CommitsSince(ctx context.Context, repo string, since time.Time) (int, error)
+	ClosedPullRequests(ctx context.Context, repo string, limit int) ([]client.PullRequest, error)
+	Releases(ctx context.Context, repo string, limit int) ([]client.Release, error)
+}
+
+// Component is one part of a candidate's score.
+type Component struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	Max    float64 `json:"max"`
+	// Detail explains the points, e.g. "12 commits in 90 days".
+	Detail string `json:"detail"`
+}
+
+// Candidate is a fork with its score out of 10.
+type Candidate struct {
+	Repo      string      `json:"repo"`
+	Stars     int         `json:"stars"`
+	Score     float64     `json:"score"`
+	Breakdown []Component `json:"breakdown"`
+}
+
+// Rank scores up to limit of the most starred forks of repo that are not
+// archived themselves, and returns them from highest to lowest score. Parts
+// of a candidate's history that cannot be fetched score zero.
+func Rank(ctx context.Context, api API, repo string, limit int, now time.Time) ([]Candidate, error) {
+	forks, err := api.Forks(ctx, repo, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+
+	for _, fork := range forks {
+		if fork.Archived {
+			continue
+		}
+
+		c := Candidate{Repo: fork.FullName, Stars: fork.Stars}
+		c.Breakdown = []Component{
+			activity(ctx, api, fork.FullName, now),
+			responsiveness(ctx, api, fork.FullName),
+			releases(ctx, api, fork.FullName, now),
+			popularity(fork.Stars),
+		}
+
+		for _, component := range c.Breakdown {
+			c.Score += component.Points
+		}
+
+		c.Score = round(c.Score)
+		candidates = append(candidates, c)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b Candidate) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(b.Stars, a.Stars))
+	})
+
+	return candidates, nil
+}
+
+// activity scores commits in the activityWindow, full points from 30.
+func activity(ctx context.Context, api API, repo string, now time.Time) Component {
+	c := Component{Name: "activity", Max: maxActivity}
+
+	n, err := api.CommitsSince(ctx, repo, now.Add(-activityWindow))
+	if err != nil {
+		slog.Debug(fmt.Sprintf("error counting commits of %s: %v", repo, err))
+
+		c.Detail = "commits unavailable"
+
+		return c
+	}
+
+	c.Points = round(maxActivity * math.Min(float64(n)/30, 1))
+	c.Detail = fmt.Sprintf("%d commits in 90 days", n)
+
+	return c
+}
+
+// responsiveness scores the median time to close recent pull requests: full
+// points within a week, less within a month or a quarter.
+func responsiveness(ctx context.Context, api API, repo string) Component {
+	c := Component{Name: "responsiveness", Max: maxResponsiveness}
+
+	pulls, err := api.ClosedPullRequests(ctx, repo, pullRequestSample)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("error listing pull requests of %s: %v", repo, err))
+
+		c.Detail = "pull requests unavailable"
+
+		return c
+	}
+
+	var durations []time.Duration
+
+	for _, pr := range pulls {
+		created, err1 := time.Parse(time.RFC3339, pr.CreatedAt)
+		closed, err2 := time.Parse(time.RFC3339, pr.ClosedAt)
+
+		if err1 == nil && err2 == nil {
+			durations = append(durations, closed.Sub(created))
+		}
+	}
+
+	if len(durations) == 0 {
+		c.Detail = "no closed pull requests"
+
+		return c
+	}
+
+	slices.Sort(durations)
+	median := durations[len(durations)/2]
+	days := median.Hours() / 24
+
+	switch {
+	case days <= 7:
+		c.Points = maxResponsiveness
+	case days <= 30:
+		c.Points = 2
+	case days <= 90:
+		c.Points = 1
+	}
+
+	c.Detail = fmt.Sprintf("median %.0f days to close %d pull requests", math.Ceil(days), len(durations))
+
+	return c
+}
+
+// releases scores releases published in the releaseWindow, full points from
+// four.
+func releases(ctx context.Context, api API, repo string, now time.Time) Component {
+	c := Component{Name: "releases", Max: maxReleases}
+
+	list, err := api.Releases(ctx, repo, 30)
+	if err != nil {
+		slog.Debug(fmt.Sprintf("error listing releases of %s: %v", repo, err))
+
+		c.Detail = "releases unavailable"
+
+		return c
+	}
+
+	n := 0
+
+	for _, r := range list {
+		published, err := time.Parse(time.RFC3339, r.PublishedAt)
+		if err == nil && !r.Draft && now.Sub(published) <= releaseWindow {
+			n++
+		}
+	}
+
+	c.Points = round(maxReleases * math.Min(float64(n)/4, 1))
+	c.Detail = fmt.Sprintf("%d releases in the last year", n)
+
+	return c
+}
+
+// popularity scores stars on a logarithmic scale, full points from 1000.
+func popularity(stars int) Component {
+	return Component{
+		Name:   "popularity",
+		Points: round(maxPopularity * math.Min(math.Log10(float64(stars)+1)/3, 1)),
+		Max:    maxPopularity,
+		Detail: fmt.Sprintf("%d stars", stars),
+	}
+}
+
+// round rounds to one decimal place.
+func round(f float64) float64 {
+	return math.Round(f*10) / 10
+}
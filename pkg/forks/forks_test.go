@@ -0,0 +1,91 @@
+package forks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// fakeAPI answers from fixed data. Repositories without commits fail to
+// list them.
+type fakeAPI struct {
+	forks    []client.Fork
+	commits  map[string]int
+	pulls    map[string][]client.PullRequest
+	releases map[string][]client.Release
+}
+
+func (f fakeAPI) Forks(context.Context, string, int) ([]client.Fork, error) {
+	return f.forks, nil
+}
+
+func (f fakeAPI) CommitsSince(_ context.Context, repo string, _ time.Time) (int, error) {
+	n, ok := f.commits[repo]
+	if !ok {
+		return 0, errors.New("not found")
+	}
+
+	return n, nil
+}
+
+func (f fakeAPI) ClosedPullRequests(_ context.Context, repo string, _ int) ([]client.PullRequest, error) {
+	return f.pulls[repo], nil
+}
+
+func (f fakeAPI) Releases(_ context.Context, repo string, _ int) ([]client.Release, error) {
+	return f.releases[repo], nil
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	api := fakeAPI{
+		forks: []client.Fork{
+			{FullName: "popular/repo", Stars: 999},
+			{FullName: "active/repo", Stars: 9},
+			{FullName: "archived/repo", Stars: 50, Archived: true},
+		},
+		commits: map[string]int{"active/repo": 45},
+		pulls: map[string][]client.PullRequest{
+			"active/repo": {
+				{CreatedAt: "2025-06-01T00:00:00Z", ClosedAt: "2025-06-02T00:00:00Z"},
+				{CreatedAt: "2025-06-01T00:00:00Z", ClosedAt: "2025-06-04T00:00:00Z"},
+				{CreatedAt: "2025-05-01T00:00:00Z", ClosedAt: "2025-06-20T00:00:00Z"},
+			},
+		},
+		releases: map[string][]client.Release{
+			"active/repo": {
+				{PublishedAt: "2025-06-01T00:00:00Z"},
+				{PublishedAt: "2025-05-01T00:00:00Z", Draft: true},
+				{PublishedAt: "2023-01-01T00:00:00Z"},
+			},
+		},
+	}
+
+	candidates, err := Rank(context.Background(), api, "old/repo", 5, now)
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+
+	require.Equal(t, Candidate{
+		Repo:  "active/repo",
+		Stars: 9,
+		Score: 7.8,
+		Breakdown: []Component{
+			{Name: "activity", Points: 4, Max: 4, Detail: "45 commits in 90 days"},
+			{Name: "responsiveness", Points: 3, Max: 3, Detail: "median 3 days to close 3 pull requests"},
+			{Name: "releases", Points: 0.5, Max: 2, Detail: "1 releases in the last year"},
+			{Name: "popularity", Points: 0.3, Max: 1, Detail: "9 stars"},
+		},
+	}, candidates[0])
+
+	require.Equal(t, "popular/repo", candidates[1].Repo)
+	require.Equal(t, 1.0, candidates[1].Score)
+	require.Equal(t, "commits unavailable", candidates[1].Breakdown[0].Detail)
+	require.Equal(t, "no closed pull requests", candidates[1].Breakdown[1].Detail)
+}
@@ -0,0 +1,163 @@
+// Package freshness buckets dependencies by how long ago their repositories
+// were last pushed to, for rendering heatmaps in dashboards. It gives a
+// richer picture of ecosystem health than whether a repository is archived.
+package freshness
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// day is the unit ages are measured in.
+const day = 24 * time.Hour
+
+// Bucket is a range of ages since the last push.
+type Bucket struct {
+	Label string
+	// MaxAge is the exclusive upper bound of the range. Zero means the range
+	// is unbounded.
+	MaxAge time.Duration
+}
+
+// Buckets lists the age ranges from most to least recently pushed.
+var Buckets = []Bucket{
+	{"<30d", 30 * day},
+	{"30-90d", 90 * day},
+	{"90-180d", 180 * day},
+	{"180d-1y", 365 * day},
+	{"1-2y", 730 * day},
+	{">2y", 0},
+}
+
+// Unknown is the bucket of dependencies whose repository could not be looked
+// up or has no push date.
+const Unknown = "unknown"
+
+// Labels returns the labels of Buckets followed by Unknown, the columns of a
+// heatmap.
+func Labels() []string {
+	labels := make([]string, 0, len(Buckets)+1)
+
+	for _, b := range Buckets {
+		labels = append(labels, b.Label)
+	}
+
+	return append(labels, Unknown)
+}
+
+// Classify returns the age in days of a push at pushedAt, an RFC 3339
+// timestamp, and the label of its bucket. Unparsable timestamps are Unknown.
+func Classify(pushedAt string, now time.Time) (int, string) {
+	t, err := time.Parse(time.RFC3339, pushedAt)
+	if err != nil {
+		return 0, Unknown
+	}
+
+	age := max(now.Sub(t), 0)
+
+	for _, b := range Buckets {
+		if b.MaxAge == 0 || age < b.MaxAge {
+			return int(age / day), b.Label
+		}
+	}
+
+	return int(age / day), Unknown
+}
+
+// Dependency is a dependency at the location where it is required.
+type Dependency struct {
+	Repo     string `json:"repo"`
+	Module   string `json:"module,omitempty"`
+	Version  string `json:"version,omitempty"`
+	File     string `json:"file"`
+	Archived bool   `json:"archived"`
+	PushedAt string `json:"pushed_at,omitempty"`
+	// AgeDays is the number of days since the last push. It is zero for
+	// dependencies in the Unknown bucket.
+	AgeDays int    `json:"age_days"`
+	Bucket  string `json:"bucket"`
+}
+
+// Row counts the dependencies of a file per bucket, in the order of Labels.
+type Row struct {
+	File   string `json:"file"`
+	Counts []int  `json:"counts"`
+}
+
+// Heatmap is the freshness of every dependency, and the number of
+// dependencies per file and bucket.
+type Heatmap struct {
+	Buckets      []string     `json:"buckets"`
+	Dependencies []Dependency `json:"dependencies"`
+	Files        []Row        `json:"files"`
+}
+
+// New sorts the dependencies by file and repository and tallies them per
+// file and bucket.
+func New(deps []Dependency) Heatmap {
+	deps = slices.Clone(deps)
+	slices.SortFunc(deps, func(a, b Dependency) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Repo, b.Repo), cmp.Compare(a.Module, b.Module))
+	})
+
+	labels := Labels()
+	h := Heatmap{Buckets: labels, Dependencies: deps, Files: []Row{}}
+
+	if h.Dependencies == nil {
+		h.Dependencies = []Dependency{}
+	}
+
+	for _, dep := range deps {
+		if len(h.Files) == 0 || h.Files[len(h.Files)-1].File != dep.File {
+			h.Files = append(h.Files, Row{File: dep.File, Counts: make([]int, len(labels))})
+		}
+
+		if i := slices.Index(labels, dep.Bucket); i >= 0 {
+			h.Files[len(h.Files)-1].Counts[i]++
+		}
+	}
+
+	return h
+}
+
+// JSON writes the heatmap as an indented JSON document.
+func JSON(w io.Writer, h Heatmap) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(h); err != nil {
+		return fmt.Errorf("failed to encode heatmap: %w", err)
+	}
+
+	return nil
+}
+
+// CSV writes one row per dependency with a header row.
+func CSV(w io.Writer, h Heatmap) error {
+	cw := csv.NewWriter(w)
+
+	_ = cw.Write([]string{"repo", "module", "version", "file", "archived", "pushed_at", "age_days", "bucket"})
+
+	for _, dep := range h.Dependencies {
+		age := ""
+		if dep.Bucket != Unknown {
+			age = strconv.Itoa(dep.AgeDays)
+		}
+
+		_ = cw.Write([]string{dep.Repo, dep.Module, dep.Version, dep.File, strconv.FormatBool(dep.Archived), dep.PushedAt, age, dep.Bucket})
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write heatmap: %w", err)
+	}
+
+	return nil
+}
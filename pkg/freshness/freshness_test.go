@@ -0,0 +1,69 @@
+package freshness
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pushedAt string
+		age      int
+		bucket   string
+	}{
+		{"2025-06-30T00:00:00Z", 1, "<30d"},
+		{"2025-06-01T00:00:00Z", 30, "30-90d"},
+		{"2025-01-01T00:00:00Z", 181, "180d-1y"},
+		{"2023-07-01T00:00:00Z", 731, ">2y"},
+		{"2025-08-01T00:00:00Z", 0, "<30d"},
+		{"", 0, Unknown},
+	}
+
+	for _, tt := range tests {
+		age, bucket := Classify(tt.pushedAt, now)
+		require.Equal(t, tt.age, age, tt.pushedAt)
+		require.Equal(t, tt.bucket, bucket, tt.pushedAt)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	h := New([]Dependency{
+		{Repo: "b/b", File: "go.mod", Bucket: ">2y"},
+		{Repo: "a/a", File: "go.mod", Bucket: "<30d"},
+		{Repo: "c/c", File: "Cargo.toml", Bucket: Unknown},
+		{Repo: "d/d", File: "go.mod", Bucket: ">2y"},
+	})
+
+	require.Equal(t, []string{"<30d", "30-90d", "90-180d", "180d-1y", "1-2y", ">2y", "unknown"}, h.Buckets)
+	require.Equal(t, []Row{
+		{File: "Cargo.toml", Counts: []int{0, 0, 0, 0, 0, 0, 1}},
+		{File: "go.mod", Counts: []int{1, 0, 0, 0, 0, 2, 0}},
+	}, h.Files)
+	require.Equal(t, "a/a", h.Dependencies[1].Repo)
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	h := New([]Dependency{
+		{Repo: "owner/repo", Module: "github.com/owner/repo", Version: "v1.0.0", File: "go.mod", PushedAt: "2025-06-01T00:00:00Z", AgeDays: 30, Bucket: "30-90d"},
+		{Repo: "owner/gone", File: "go.mod", Bucket: Unknown},
+	})
+
+	var buf bytes.Buffer
+
+	require.NoError(t, CSV(&buf, h))
+	require.Equal(t, `repo,module,version,file,archived,pushed_at,age_days,bucket
+owner/gone,,,go.mod,false,,,unknown
+owner/repo,github.com/owner/repo,v1.0.0,go.mod,false,2025-06-01T00:00:00Z,30,30-90d
+`, buf.String())
+}
@@ -0,0 +1,259 @@
+// Package gem provides commands for scanning Ruby gem dependencies declared in
+// Gemfile and Gemfile.lock files and reporting archived GitHub repositories.
+package gem
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+	"github.com/wayneashleyberry/gh-arc/pkg/rubygems"
+)
+
+// Manifest is the file that declares the gems of a Ruby project.
+const Manifest = "Gemfile"
+
+// LockFile is the file that records the resolved gems of a Ruby project.
+const LockFile = "Gemfile.lock"
+
+var (
+	// gemLine matches a gem declaration and captures its name and
+	// arguments.
+	gemLine = regexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["']\s*(.*?)\)?$`)
+	// blockLine matches the source, git and github declarations that
+	// open a block of gems, and captures the declaration and its argument.
+	blockLine = regexp.MustCompile(`^(source|git|github)\s*\(?\s*["']([^"']+)["']`)
+	// option matches a keyword argument of a gem declaration, in either
+	// the `key: "value"` or the `:key => "value"` form.
+	option = regexp.MustCompile(`^:?(\w+)(?::|\s*=>)\s*["']([^"']*)["']$`)
+	// quoted matches a single or double quoted string.
+	quoted = regexp.MustCompile(`^"[^"]*"$|^'[^']*'$`)
+	// blockStart matches lines opening a do...end block.
+	blockStart = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?$`)
+	// lockSpec matches a gem recorded under the specs of a Gemfile.lock
+	// source, and captures its name and version.
+	lockSpec = regexp.MustCompile(`^    ([^\s(]+) \(([^)]+)\)$`)
+)
+
+// block is a do...end block of a Gemfile.
+type block struct {
+	// private is set for source blocks of sources other than RubyGems.org.
+	private bool
+	// url is the repository of git and github blocks.
+	url string
+}
+
+// parseGemfile returns the gems declared in a Gemfile. Gems from local paths,
+// and gems from sources other than RubyGems.org that are not fetched from
+// git, are omitted.
+func parseGemfile(name string, data []byte) []resolve.Dependency {
+	var (
+		deps   []resolve.Dependency
+		blocks []block
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " #")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "end" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+
+			continue
+		}
+
+		if blockStart.MatchString(line) {
+			var b block
+
+			if m := blockLine.FindStringSubmatch(line); m != nil {
+				switch m[1] {
+				case "source":
+					b.private = !rubygems.IsSource(m[2])
+				case "git":
+					b.url = m[2]
+				case "github":
+					b.url = "https://github.com/" + m[2]
+				}
+			}
+
+			blocks = append(blocks, b)
+
+			continue
+		}
+
+		m := gemLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		dep := resolve.Dependency{Name: m[1], File: name, Line: lineNo}
+
+		var (
+			versions                []string
+			options, local, private bool
+		)
+
+		for _, b := range blocks {
+			private = private || b.private
+			dep.URL = cmp.Or(b.url, dep.URL)
+		}
+
+		// Versions are the quoted arguments before the first keyword
+		// argument.
+
+		for _, arg := range strings.Split(m[2], ",") {
+			arg = strings.TrimSpace(arg)
+
+			if arg == "" {
+				continue
+			}
+
+			if quoted.MatchString(arg) {
+				if !options {
+					versions = append(versions, arg[1:len(arg)-1])
+				}
+
+				continue
+			}
+
+			options = true
+
+			opt := option.FindStringSubmatch(arg)
+			if opt == nil {
+				continue
+			}
+
+			switch opt[1] {
+			case "git":
+				dep.URL = opt[2]
+			case "github":
+				dep.URL = "https://github.com/" + opt[2]
+			case "path":
+				local = true
+			case "source":
+				private = !rubygems.IsSource(opt[2])
+			}
+		}
+
+		dep.Version = strings.Join(versions, ", ")
+
+		if dep.URL == "" && (local || private) {
+			continue
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
+
+// parseLock returns the gems recorded in a Gemfile.lock file that come from
+// RubyGems.org or git. Gems from local paths and other gem servers are
+// omitted.
+func parseLock(name string, data []byte) []resolve.Dependency {
+	var (
+		deps            []resolve.Dependency
+		section, remote string
+		inSpecs         bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+
+		if line != "" && !strings.HasPrefix(line, " ") {
+			section, remote, inSpecs = line, "", false
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "  remote: "):
+			remote = strings.TrimPrefix(line, "  remote: ")
+		case line == "  specs:":
+			inSpecs = true
+		case inSpecs:
+			m := lockSpec.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+
+			dep := resolve.Dependency{Name: m[1], Version: m[2], File: name, Line: lineNo, Indirect: true}
+
+			switch {
+			case section == "GEM" && rubygems.IsSource(remote):
+				deps = append(deps, dep)
+			case section == "GIT":
+				dep.URL = remote
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	return deps
+}
+
+// Discover parses the given Gemfile and Gemfile.lock files. Gems declared in a
+// Gemfile are direct dependencies and take their version from a lock file
+// when one records them; gems only found in a Gemfile.lock are indirect.
+func Discover(ctx context.Context, manifests, lockfiles []string) []resolve.Dependency {
+	var direct, locked []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		direct = append(direct, parseGemfile(name, data)...)
+	}
+
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		locked = append(locked, parseLock(name, data)...)
+	}
+
+	return resolve.Merge(direct, locked, func(name string) string {
+		return name
+	})
+}
+
+// Repos returns the repositories of the gems required in opts.Scope, looking
+// them up on RubyGems.org.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	manifests, err := files.RecursiveFind(ctx, opts.Scope, Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", Manifest, err)
+	}
+
+	lockfiles, err := files.RecursiveFind(ctx, opts.Scope, LockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", LockFile, err)
+	}
+
+	return resolve.Repos(ctx, rubygems.NewWithHTTPClient(opts.Timeouts.HTTPClient(rubygems.DefaultBaseURL), rubygems.DefaultBaseURL), Discover(ctx, manifests, lockfiles), opts)
+}
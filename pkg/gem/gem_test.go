@@ -0,0 +1,91 @@
+package gem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+func TestParseGemfile(t *testing.T) {
+	t.Parallel()
+
+	data := `source "https://rubygems.org"
+
+gemspec
+
+gem "rails", "~> 7.1", ">= 7.1.2"
+gem 'puma' # web server
+gem "bootsnap", require: false
+gem "devise", git: "https://github.com/heartcombo/devise.git", branch: "main"
+gem "kaminari", github: "kaminari/kaminari"
+gem "local", path: "../local"
+gem "internal", source: "https://gems.example.com"
+gem "tzinfo-data", platforms: [:mri, :windows]
+
+group :development, :test do
+  gem "rspec-rails", "~> 6.0"
+end
+
+source "https://gems.example.com" do
+  gem "private"
+end
+
+git "https://github.com/rails/rails.git" do
+  gem "activesupport"
+end
+`
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "rails", Version: "~> 7.1, >= 7.1.2", File: "Gemfile", Line: 5},
+		{Name: "puma", File: "Gemfile", Line: 6},
+		{Name: "bootsnap", File: "Gemfile", Line: 7},
+		{Name: "devise", File: "Gemfile", Line: 8, URL: "https://github.com/heartcombo/devise.git"},
+		{Name: "kaminari", File: "Gemfile", Line: 9, URL: "https://github.com/kaminari/kaminari"},
+		{Name: "tzinfo-data", File: "Gemfile", Line: 12},
+		{Name: "rspec-rails", Version: "~> 6.0", File: "Gemfile", Line: 15},
+		{Name: "activesupport", File: "Gemfile", Line: 23, URL: "https://github.com/rails/rails.git"},
+	}, parseGemfile("Gemfile", []byte(data)))
+}
+
+func TestParseLock(t *testing.T) {
+	t.Parallel()
+
+	data := `GIT
+  remote: https://github.com/heartcombo/devise.git
+  revision: 0123456789abcdef
+  branch: main
+  specs:
+    devise (4.9.3)
+      bcrypt (~> 3.0)
+
+PATH
+  remote: ../local
+  specs:
+    local (0.1.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    bcrypt (3.1.20)
+    nokogiri (1.16.0-x86_64-linux)
+      racc (~> 1.4)
+
+GEM
+  remote: https://gems.example.com/
+  specs:
+    internal (1.0.0)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  devise!
+`
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "devise", Version: "4.9.3", File: "Gemfile.lock", Line: 6, Indirect: true, URL: "https://github.com/heartcombo/devise.git"},
+		{Name: "bcrypt", Version: "3.1.20", File: "Gemfile.lock", Line: 17, Indirect: true},
+		{Name: "nokogiri", Version: "1.16.0-x86_64-linux", File: "Gemfile.lock", Line: 18, Indirect: true},
+	}, parseLock("Gemfile.lock", []byte(data)))
+}
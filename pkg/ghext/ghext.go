@@ -0,0 +1,84 @@
+// Package ghext adapts the tool to how it was installed: as the `gh arc`
+// extension of the GitHub CLI, or as a standalone `arc` binary. Either way
+// it honors the GitHub CLI's configuration, so both behave the same.
+package ghext
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// Supported git protocols, as configured with `gh config set git_protocol`.
+const (
+	ProtocolHTTPS = "https"
+	ProtocolSSH   = "ssh"
+)
+
+// executableName is the name the GitHub CLI runs extensions by.
+const executableName = "gh-arc"
+
+// Extension reports whether the tool runs as a GitHub CLI extension, which
+// gh runs from an executable named after the extension.
+func Extension() bool {
+	return isExtension(os.Args[0])
+}
+
+func isExtension(arg0 string) bool {
+	return strings.TrimSuffix(filepath.Base(arg0), ".exe") == executableName
+}
+
+// CommandName returns how users invoke the tool, for help text and hints.
+func CommandName() string {
+	if Extension() {
+		return "gh arc"
+	}
+
+	return "arc"
+}
+
+// AuthHint explains how to authenticate with the GitHub API.
+func AuthHint() string {
+	if Extension() {
+		return "run `gh auth login`, or set GH_TOKEN"
+	}
+
+	return "set GH_TOKEN, or install the GitHub CLI and run `gh auth login`"
+}
+
+// GitProtocol returns the git protocol configured for host in the GitHub CLI,
+// falling back to the global setting and to ProtocolHTTPS.
+func GitProtocol(host string) string {
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return ProtocolHTTPS
+	}
+
+	return gitProtocol(cfg, host)
+}
+
+func gitProtocol(cfg *config.Config, host string) string {
+	for _, keys := range [][]string{{"hosts", host, "git_protocol"}, {"git_protocol"}} {
+		if protocol, err := cfg.Get(keys); err == nil && protocol != "" {
+			if protocol == ProtocolSSH {
+				return ProtocolSSH
+			}
+
+			return ProtocolHTTPS
+		}
+	}
+
+	return ProtocolHTTPS
+}
+
+// CloneBaseURL returns the prefix that turns "owner/repo" into a clone URL of
+// a repository on host, using the configured git protocol.
+func CloneBaseURL(host string) string {
+	if GitProtocol(host) == ProtocolSSH {
+		return "git@" + host + ":"
+	}
+
+	return "https://" + host + "/"
+}
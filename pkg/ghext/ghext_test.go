@@ -0,0 +1,31 @@
+package ghext
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExtension(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isExtension("/home/me/.local/share/gh/extensions/gh-arc/gh-arc"))
+	require.True(t, isExtension("gh-arc.exe"))
+	require.False(t, isExtension("/usr/local/bin/arc"))
+}
+
+func TestGitProtocol(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, ProtocolHTTPS, gitProtocol(config.ReadFromString(""), "github.com"))
+	require.Equal(t, ProtocolSSH, gitProtocol(config.ReadFromString("git_protocol: ssh\n"), "github.com"))
+
+	cfg := config.ReadFromString(`git_protocol: ssh
+hosts:
+  github.com:
+    git_protocol: https
+`)
+	require.Equal(t, ProtocolHTTPS, gitProtocol(cfg, "github.com"))
+	require.Equal(t, ProtocolSSH, gitProtocol(cfg, "git.example.com"))
+}
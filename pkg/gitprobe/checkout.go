@@ -0,0 +1,75 @@
+package gitprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// ErrNoCheckout is returned by LocalCheckout when dir is not a git checkout
+// with an origin remote on GitHub.
+var ErrNoCheckout = errors.New("not a checkout of a github repository")
+
+// Checkout describes the local git checkout a scan runs in.
+type Checkout struct {
+	// Repo is the "owner/repo" of the origin remote.
+	Repo string
+	// DefaultBranch is the branch origin/HEAD points to as of the last
+	// clone or `git remote set-head`. Empty if the checkout does not know,
+	// as in most CI checkouts.
+	DefaultBranch string
+}
+
+// LocalCheckout reads the origin remote of the git checkout containing dir.
+func LocalCheckout(ctx context.Context, dir string) (Checkout, error) {
+	url, err := git(ctx, dir, "remote", "get-url", "origin")
+	if err != nil {
+		return Checkout{}, errors.Join(ErrNoCheckout, err)
+	}
+
+	repo, ok := client.RepoFromURL(url)
+	if !ok {
+		return Checkout{}, ErrNoCheckout
+	}
+
+	checkout := Checkout{Repo: repo}
+
+	head, err := git(ctx, dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+	if err == nil {
+		checkout.DefaultBranch = strings.TrimPrefix(head, "origin/")
+	}
+
+	return checkout, nil
+}
+
+// Worktree checks out ref of the git repository containing dir into a
+// temporary directory and returns its path, along with a function that
+// removes it again.
+func Worktree(ctx context.Context, dir, ref string) (string, func(), error) {
+	tmp, err := os.MkdirTemp("", "gh-arc-worktree-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	if _, err := git(ctx, dir, "worktree", "add", "--quiet", "--detach", tmp, ref); err != nil {
+		_ = os.RemoveAll(tmp)
+
+		return "", nil, fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+
+	cleanup := func() {
+		// Not ctx, so the worktree is removed even if the scan was canceled.
+		if _, err := git(context.Background(), dir, "worktree", "remove", "--force", tmp); err != nil {
+			slog.Debug(fmt.Sprintf("failed to remove worktree %s: %v", tmp, err))
+
+			_ = os.RemoveAll(tmp)
+		}
+	}
+
+	return tmp, cleanup, nil
+}
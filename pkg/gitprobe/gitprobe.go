@@ -0,0 +1,140 @@
+// Package gitprobe answers repository liveness questions using the git
+// command-line tool instead of a hosting provider's API. It can tell whether a
+// repository still exists and when its default branch was last committed to,
+// but it cannot tell whether a repository is archived.
+package gitprobe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// DefaultBaseURL is the prefix used to turn "owner/repo" into a clone URL.
+const DefaultBaseURL = "https://github.com/"
+
+// notFoundMessages are fragments of git's stderr output that indicate the
+// remote repository does not exist. Hosts usually ask for credentials rather
+// than admitting a private or deleted repository is missing, so a refused
+// credential prompt is treated the same way.
+var notFoundMessages = []string{
+	"not found",
+	"does not appear to be a git repository",
+	"could not read username",
+	"does not exist",
+}
+
+// Prober implements client.Provider by shelling out to git.
+type Prober struct {
+	baseURL string
+	// Timeout limits how long probing a single repository may take. Zero
+	// means no limit.
+	Timeout time.Duration
+}
+
+// New creates a Prober that resolves repositories relative to baseURL, for
+// example "https://github.com/", "https://git.example.com/" or the scp-like
+// "git@github.com:".
+func New(baseURL string) *Prober {
+	if !strings.HasSuffix(baseURL, "/") && !strings.HasSuffix(baseURL, ":") {
+		baseURL += "/"
+	}
+
+	return &Prober{baseURL: baseURL}
+}
+
+// GetRepoResult checks that the repository exists with `git ls-remote` and
+// reads the date of the latest commit on its default branch from a shallow
+// clone. The Archived field is always false, so results are marked degraded.
+func (p *Prober) GetRepoResult(ctx context.Context, repo string) (client.RepoResult, error) {
+	url := p.baseURL + repo
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	_, err := git(ctx, "", "ls-remote", "--exit-code", url, "HEAD")
+	if err != nil {
+		return client.RepoResult{}, classifyError(repo, err)
+	}
+
+	dir, err := os.MkdirTemp("", "gh-arc-")
+	if err != nil {
+		return client.RepoResult{}, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	_, err = git(ctx, "", "clone", "--quiet", "--bare", "--depth", "1", "--filter=blob:none", url, dir)
+	if err != nil {
+		return client.RepoResult{}, classifyError(repo, err)
+	}
+
+	date, err := git(ctx, dir, "log", "-1", "--format=%cI")
+	if err != nil {
+		return client.RepoResult{}, fmt.Errorf("failed to read last commit of %s: %w", repo, err)
+	}
+
+	return client.RepoResult{PushedAt: date, FullName: repo, Degraded: true}, nil
+}
+
+// gitError carries git's stderr so callers can inspect why a command failed.
+type gitError struct {
+	args   []string
+	stderr string
+	err    error
+}
+
+func (e *gitError) Error() string {
+	return fmt.Sprintf("git %s: %v: %s", e.args[0], e.err, e.stderr)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204
+
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", &gitError{args: args, stderr: strings.TrimSpace(stderr.String()), err: err}
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func classifyError(repo string, err error) error {
+	var gerr *gitError
+	if errors.As(err, &gerr) {
+		msg := strings.ToLower(gerr.stderr)
+
+		for _, fragment := range notFoundMessages {
+			if strings.Contains(msg, fragment) {
+				return fmt.Errorf("%w: %s", client.ErrRepoNotFound, repo)
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to probe repo %s: %w", repo, err)
+}
@@ -0,0 +1,90 @@
+package gitprobe
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+func initRepo(t *testing.T, dir string) {
+	t.Helper()
+
+	for _, args := range [][]string{
+		{"init", "--quiet", dir},
+		{"-C", dir, "-c", "user.name=arc", "-c", "user.email=arc@example.com", "commit", "--quiet", "--allow-empty", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestProber_GetRepoResult(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	initRepo(t, filepath.Join(base, "owner", "repo"))
+
+	p := New("file://" + base)
+
+	got, err := p.GetRepoResult(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.False(t, got.Archived)
+	require.Equal(t, "owner/repo", got.FullName)
+
+	_, err = time.Parse(time.RFC3339, got.PushedAt)
+	require.NoError(t, err)
+}
+
+func TestProber_GetRepoResult_Missing(t *testing.T) {
+	t.Parallel()
+
+	p := New("file://" + t.TempDir())
+
+	_, err := p.GetRepoResult(context.Background(), "owner/missing")
+	require.ErrorIs(t, err, client.ErrRepoNotFound)
+}
+
+func TestLocalCheckout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	initRepo(t, dir)
+
+	_, err := LocalCheckout(context.Background(), dir)
+	require.ErrorIs(t, err, ErrNoCheckout)
+
+	for _, args := range [][]string{
+		{"-C", dir, "remote", "add", "origin", "git@github.com:owner/repo.git"},
+		{"-C", dir, "update-ref", "refs/remotes/origin/master", "HEAD"},
+		{"-C", dir, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/master"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	got, err := LocalCheckout(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, Checkout{Repo: "owner/repo", DefaultBranch: "master"}, got)
+}
+
+func TestWorktree(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	initRepo(t, dir)
+
+	worktree, cleanup, err := Worktree(context.Background(), dir, "HEAD")
+	require.NoError(t, err)
+	require.DirExists(t, worktree)
+
+	cleanup()
+	require.NoDirExists(t, worktree)
+
+	_, _, err = Worktree(context.Background(), dir, "no-such-ref")
+	require.ErrorContains(t, err, "failed to check out no-such-ref")
+}
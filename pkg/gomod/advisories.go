@@ -0,0 +1,74 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/advisory"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// advisoryEcosystem returns the GitHub Advisory Database ecosystem of a
+// dependency required in file, or "" if it has none. Go modules are
+// recognised by their path starting with a domain name, so modules compiled
+// into binaries or installed in Dockerfiles are included.
+func advisoryEcosystem(file, module string) string {
+	switch filepath.Base(file) {
+	case "Cargo.toml", "Cargo.lock":
+		return client.EcosystemRust
+	case "requirements.txt", "pyproject.toml", "poetry.lock":
+		return client.EcosystemPip
+	case "action.yml", "action.yaml":
+		return client.EcosystemActions
+	}
+
+	if strings.Contains(filepath.ToSlash(file), ".github/workflows/") {
+		return client.EcosystemActions
+	}
+
+	if first, _, _ := strings.Cut(module, "/"); strings.Contains(first, ".") {
+		return client.EcosystemGo
+	}
+
+	return ""
+}
+
+// advisoryLookup returns the advisories affecting the pinned version of a
+// dependency, querying each package once.
+type advisoryLookup struct {
+	ctx   context.Context
+	api   advisory.API
+	vulns map[string][]client.Vulnerability
+}
+
+func newAdvisoryLookup(ctx context.Context, api advisory.API) *advisoryLookup {
+	return &advisoryLookup{ctx: ctx, api: api, vulns: map[string][]client.Vulnerability{}}
+}
+
+// affecting returns the advisories affecting info's version. Lookup errors
+// are logged and treated as no advisories.
+func (l *advisoryLookup) affecting(info RepoInfo) []advisory.Advisory {
+	ecosystem := advisoryEcosystem(info.goModPath, info.modPath)
+	if ecosystem == "" || info.modPath == "" || info.version == "" {
+		return nil
+	}
+
+	key := ecosystem + " " + info.modPath
+
+	vulns, ok := l.vulns[key]
+	if !ok {
+		var err error
+
+		vulns, err = l.api.Vulnerabilities(l.ctx, ecosystem, info.modPath)
+		if err != nil {
+			slog.DebugContext(l.ctx, fmt.Sprintf("error looking up advisories of %s: %v", info.modPath, err))
+		}
+
+		l.vulns[key] = vulns
+	}
+
+	return advisory.Affecting(vulns, info.version)
+}
@@ -0,0 +1,48 @@
+package gomod
+
+import (
+	"context"
+	"debug/buildinfo"
+	"fmt"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// DiscoverBinaryDependencies reads the module build information embedded in
+// the provided Go binaries and returns a map of GitHub repositories to their
+// info. Binaries do not record whether a dependency is direct, so every
+// dependency is treated as direct. Replacements are resolved to the module
+// that was actually compiled in.
+func DiscoverBinaryDependencies(paths []string) (map[string][]RepoInfo, error) {
+	repos := map[string][]RepoInfo{}
+
+	for _, path := range paths {
+		bi, err := buildinfo.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read build info from %s: %w", path, err)
+		}
+
+		for _, dep := range bi.Deps {
+			mod := dep
+			if dep.Replace != nil {
+				mod = dep.Replace
+			}
+
+			repo, ok := gitHubRepo(mod.Path)
+			if !ok {
+				continue
+			}
+
+			repos[repo] = append(repos[repo], RepoInfo{false, path, 0, 0, mod.Path, mod.Version, false})
+		}
+	}
+
+	return repos, nil
+}
+
+// ListArchivedInBinaries lists archived, missing and otherwise unhealthy
+// repositories of the modules compiled into the given Go binaries, printing
+// findings to stdout.
+func ListArchivedInBinaries(ctx context.Context, paths []string, opts Options) (finding.Report, error) {
+	return NewScanner(opts, opts.Output).ScanBinaries(ctx, paths)
+}
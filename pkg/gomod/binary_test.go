@@ -0,0 +1,32 @@
+package gomod
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverBinaryDependencies(t *testing.T) {
+	t.Parallel()
+
+	// The test binary embeds its own module dependencies.
+	self, err := os.Executable()
+	require.NoError(t, err)
+
+	repos, err := DiscoverBinaryDependencies([]string{self})
+	require.NoError(t, err)
+
+	infos, ok := repos["stretchr/testify"]
+	require.True(t, ok, "expected stretchr/testify in repos")
+	require.Equal(t, self, infos[0].goModPath)
+}
+
+func TestDiscoverBinaryDependencies_NotABinary(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, t.TempDir(), "go.mod", "module example.com/foo\n")
+
+	_, err := DiscoverBinaryDependencies([]string{path})
+	require.Error(t, err)
+}
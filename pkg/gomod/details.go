@@ -0,0 +1,69 @@
+package gomod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/successor"
+)
+
+// repoDetails looks up the latest release of repo, records it in result and
+// returns the details of the repository. A release that cannot be looked up
+// is left out.
+func repoDetails(ctx context.Context, api ReleaseAPI, repo string, result *client.RepoResult) *finding.Details {
+	release, err := api.LatestRelease(ctx, repo)
+
+	switch {
+	case err == nil:
+		result.LatestRelease = &release
+	case !errors.Is(err, client.ErrNoRelease):
+		slog.DebugContext(ctx, fmt.Sprintf("error fetching latest release of %s: %v", repo, err))
+	}
+
+	details := &finding.Details{
+		OpenIssues:    result.OpenIssues,
+		DefaultBranch: result.DefaultBranch,
+		Description:   result.Description,
+		Homepage:      result.Homepage,
+		Topics:        result.Topics,
+	}
+
+	if result.LatestRelease != nil {
+		details.LatestRelease = result.LatestRelease.TagName
+		details.LatestReleaseAt = result.LatestRelease.PublishedAt
+	}
+
+	return details
+}
+
+// repoSuccessor returns the repository an archived repo names as its
+// replacement in its description or homepage, or, if api is not nil, in its
+// README. An empty string means it names none.
+func repoSuccessor(ctx context.Context, api ReadmeAPI, repo string, result client.RepoResult) string {
+	if found, ok := successor.Find(repo, result.Description); ok {
+		return found
+	}
+
+	if found, ok := successor.FromHomepage(repo, result.Homepage); ok {
+		return found
+	}
+
+	if api == nil {
+		return ""
+	}
+
+	readme, err := api.Readme(ctx, repo)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("error fetching README of %s: %v", repo, err))
+
+		return ""
+	}
+
+	found, _ := successor.Find(repo, string(readme))
+
+	return found
+}
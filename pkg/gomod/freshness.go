@@ -0,0 +1,88 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/freshness"
+	"github.com/wayneashleyberry/gh-arc/pkg/output"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+)
+
+// Freshness looks up every given repository, healthy or not, and buckets its
+// dependencies by the age of the last push. Repositories that cannot be
+// looked up are in the freshness.Unknown bucket.
+func (s *Scanner) Freshness(ctx context.Context, repos map[string][]RepoInfo) (freshness.Heatmap, error) {
+	opts := s.Options
+
+	ordered := prioritize(repos, opts.Indirect)
+	if len(ordered) == 0 {
+		return freshness.New(nil), nil
+	}
+
+	if opts.MaxAPICalls > 0 && len(ordered) > opts.MaxAPICalls {
+		slog.DebugContext(ctx, fmt.Sprintf("api call budget of %d exhausted, skipping %d repositories", opts.MaxAPICalls, len(ordered)-opts.MaxAPICalls))
+
+		ordered = ordered[:opts.MaxAPICalls]
+	}
+
+	provider := s.Provider
+	if provider == nil {
+		var err error
+
+		provider, err = NewProvider(ctx, opts)
+		if err != nil {
+			return freshness.Heatmap{}, err
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	now := time.Now()
+	collected := output.Start[freshness.Dependency](progress.New(opts.Progress, len(ordered)))
+
+	for _, repo := range ordered {
+		wg.Add(1)
+
+		go func(repo string, infos []RepoInfo) {
+			defer wg.Done()
+
+			result, err := provider.GetRepoResult(ctx, repo)
+
+			collected.Done(repo)
+
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching repo %s: %v", repo, err))
+			}
+
+			age, bucket := freshness.Classify(result.PushedAt, now)
+
+			for _, info := range infos {
+				if !opts.Indirect && info.indirect {
+					continue
+				}
+
+				collected.Add(freshness.Dependency{
+					Repo:     repo,
+					Module:   info.modPath,
+					Version:  info.version,
+					File:     files.FormatPath(info.goModPath, opts.PathStyle),
+					Archived: result.Archived,
+					PushedAt: result.PushedAt,
+					AgeDays:  age,
+					Bucket:   bucket,
+				})
+			}
+		}(repo, repos[repo])
+	}
+
+	wg.Wait()
+
+	deps := collected.Close()
+
+	return freshness.New(deps), nil
+}
@@ -0,0 +1,177 @@
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// listedModule is a module printed by `go list -m -json all`.
+type listedModule struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Replace  *listedModule
+}
+
+// goListModules lists the build list of the module in dir with the go
+// command, with the versions minimal version selection picked and replace
+// and exclude directives applied. It never changes go.mod. With offline set
+// it never downloads anything either, and fails if the module cache is
+// missing a module of the build list or the toolchain go.mod asks for.
+func goListModules(ctx context.Context, dir string, offline bool) ([]listedModule, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-json", "all")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly")
+
+	if offline {
+		cmd.Env = append(cmd.Env, "GOTOOLCHAIN=local", "GOPROXY=off")
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to run go list in %s: %w: %s", dir, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return nil, fmt.Errorf("failed to run go list in %s: %w", dir, err)
+	}
+
+	var mods []listedModule
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+
+	for {
+		var mod listedModule
+
+		err := dec.Decode(&mod)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode go list output: %w", err)
+		}
+
+		mods = append(mods, mod)
+	}
+
+	return mods, nil
+}
+
+// goListPackageModules returns the paths of the modules providing the
+// packages of the module in dir and everything they and their tests import,
+// as `go list all` loads them. Like goListModules with offline set, it never
+// changes go.mod or downloads anything.
+func goListPackageModules(ctx context.Context, dir string) (map[string]bool, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-f", "{{with .Module}}{{.Path}}{{end}}", "all")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly", "GOTOOLCHAIN=local", "GOPROXY=off")
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to run go list in %s: %w: %s", dir, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return nil, fmt.Errorf("failed to run go list in %s: %w", dir, err)
+	}
+
+	paths := map[string]bool{}
+
+	for _, path := range strings.Fields(string(out)) {
+		paths[path] = true
+	}
+
+	return paths, nil
+}
+
+// discoverWithGoList returns the GitHub dependencies of the given go.mod
+// files as the go command resolves them, see goListModules. Modules are
+// reported at their requirement in go.mod, or at go.mod without a line when
+// it does not require them, and only modules go.mod requires without an
+// indirect comment are direct. Modules replaced by local directories are
+// skipped.
+func discoverWithGoList(ctx context.Context, goModFileNames []string) (map[string][]RepoInfo, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return nil, fmt.Errorf("resolving modules with go list requires the go command: %w", err)
+	}
+
+	repos := map[string][]RepoInfo{}
+
+	for _, name := range goModFileNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		mf, err := modfile.Parse(name, data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		mods, err := goListModules(ctx, filepath.Dir(name), false)
+		if err != nil {
+			return nil, err
+		}
+
+		slog.DebugContext(ctx, fmt.Sprintf("listed %d modules of %s with go list", len(mods), name))
+
+		addListed(repos, name, mf, mods)
+		markTools(repos, name, toolPackages(mf))
+	}
+
+	return repos, nil
+}
+
+// addListed adds the GitHub modules of mods, listed by go for the go.mod file
+// name with contents mf, to repos.
+func addListed(repos map[string][]RepoInfo, name string, mf *modfile.File, mods []listedModule) {
+	required := map[string]*modfile.Require{}
+	for _, req := range mf.Require {
+		required[req.Mod.Path] = req
+	}
+
+	for _, mod := range mods {
+		if mod.Main {
+			continue
+		}
+
+		info := RepoInfo{indirect: true, goModPath: name}
+
+		if req, ok := required[mod.Path]; ok {
+			info.indirect = req.Indirect
+			info.line = req.Syntax.Start.Line
+			info.column = req.Syntax.Start.LineRune
+		}
+
+		if mod.Replace != nil {
+			if mod.Replace.Version == "" {
+				continue
+			}
+
+			mod = *mod.Replace
+		}
+
+		repo, ok := gitHubRepo(mod.Path)
+		if !ok {
+			continue
+		}
+
+		info.modPath, info.version = mod.Path, mod.Version
+		repos[repo] = append(repos[repo], info)
+	}
+}
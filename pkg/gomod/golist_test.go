@@ -0,0 +1,82 @@
+package gomod
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestAddListed(t *testing.T) {
+	t.Parallel()
+
+	mf, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+go 1.22
+
+require (
+	github.com/foo/bar v1.0.0
+	github.com/old/lib v1.0.0
+	github.com/local/mod v0.0.0
+	github.com/other/mod v0.1.0 // indirect
+)
+`), nil)
+	require.NoError(t, err)
+
+	repos := map[string][]RepoInfo{}
+
+	addListed(repos, "go.mod", mf, []listedModule{
+		{Path: "example.com/app", Main: true},
+		{Path: "github.com/foo/bar", Version: "v1.2.0"},
+		{Path: "github.com/old/lib", Version: "v1.0.0", Replace: &listedModule{Path: "github.com/new/lib", Version: "v1.1.0"}},
+		{Path: "github.com/local/mod", Version: "v0.0.0", Replace: &listedModule{Path: "../mod"}},
+		{Path: "github.com/other/mod", Version: "v0.1.0", Indirect: true},
+		{Path: "github.com/deep/dep", Version: "v0.3.0"},
+		{Path: "golang.org/x/mod", Version: "v0.17.0"},
+	})
+
+	require.Equal(t, map[string][]RepoInfo{
+		"foo/bar":   {{false, "go.mod", 6, 2, "github.com/foo/bar", "v1.2.0", false}},
+		"new/lib":   {{false, "go.mod", 7, 2, "github.com/new/lib", "v1.1.0", false}},
+		"other/mod": {{true, "go.mod", 9, 2, "github.com/other/mod", "v0.1.0", false}},
+		"deep/dep":  {{true, "go.mod", 0, 0, "github.com/deep/dep", "v0.3.0", false}},
+	}, repos)
+}
+
+func TestDiscoverWithGoList(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "mod"), 0o750))
+	writeTempFile(t, filepath.Join(dir, "mod"), "go.mod", "module github.com/local/mod\n\ngo 1.22\n")
+
+	goMod := writeTempFile(t, dir, "go.mod", `module example.com/app
+
+go 1.22
+
+require github.com/local/mod v0.0.0
+
+replace github.com/local/mod => ./mod
+`)
+
+	repos, err := discoverWithGoList(context.Background(), []string{goMod})
+	require.NoError(t, err)
+	require.Empty(t, repos)
+}
+
+func TestGoListPackageModules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTempFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTempFile(t, dir, "main.go", "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n")
+
+	paths, err := goListPackageModules(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"example.com/app": true}, paths)
+}
@@ -7,43 +7,159 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/depsdev"
 	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
-// archivedPrinter encapsulates printing and counting archived repos.
-type archivedPrinter struct {
-	count int64
-	mu    sync.Mutex
+// Supported repository metadata providers.
+const (
+	// ProviderAuto uses the GitHub API when credentials are available and
+	// falls back to git otherwise, or when an individual lookup fails.
+	ProviderAuto = "auto"
+	// ProviderGitHub uses the GitHub API.
+	ProviderGitHub = "github"
+	// ProviderGit probes repositories with the git command-line tool. It
+	// cannot detect archived repositories.
+	ProviderGit = "git"
+)
+
+// Providers lists every supported provider.
+var Providers = []string{ProviderAuto, ProviderGitHub, ProviderGit}
+
+// suggestAlternatives returns a function describing where to look for a
+// replacement of an archived module: its number of dependents on deps.dev and
+// links to its importers and similar modules on pkg.go.dev.
+func suggestAlternatives(ctx context.Context, dd *depsdev.Client) func(RepoInfo) *finding.Alternatives {
+	return func(info RepoInfo) *finding.Alternatives {
+		if info.modPath == "" {
+			return nil
+		}
+
+		alt := &finding.Alternatives{
+			DepsDevURL: depsdev.PackageURL(info.modPath),
+			ImportedBy: depsdev.ImportedByURL(info.modPath),
+			SearchURL:  depsdev.SearchURL(info.modPath),
+		}
+
+		if info.version != "" {
+			dependents, err := dd.GoDependents(ctx, info.modPath, info.version)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching dependents of %s: %v", info.modPath, err))
+			} else {
+				alt.Dependents = &dependents
+			}
+		}
+
+		return alt
+	}
 }
 
-func (ap *archivedPrinter) Print(goModPath, repo, pushedAt string, indirect bool) {
-	if indirect {
-		fmt.Printf("%s: https://github.com/%s (last push: %s) // indirect\n", goModPath, repo, pushedAt)
-	} else {
-		fmt.Printf("%s: https://github.com/%s (last push: %s)\n", goModPath, repo, pushedAt)
+// reason explains a finding in a few words.
+func reason(st status.Status, result client.RepoResult, staleAfter time.Duration) string {
+	switch st {
+	case status.Missing:
+		return "repository missing"
+	case status.Archived:
+		return "repository archived"
+	case status.Deprecated:
+		return "repository description marks it as deprecated"
+	case status.UpstreamArchived:
+		return "fork of archived " + result.Parent.FullName
+	case status.Stale:
+		return "no push for longer than " + staleAfter.String()
+	case status.Moved:
+		return "moved to " + result.FullName
+	case status.Unknown:
+		return "could not be checked"
+	case status.UnsupportedGo:
+		return "unsupported go version"
+	case status.NoLicense:
+		return "no license detected"
+	case status.DisallowedLicense:
+		return "license " + result.License.SPDXID + " is disallowed"
+	case status.Copyleft:
+		return "copyleft license " + result.License.SPDXID
+	case status.MissingVersion:
+		return "required version has no tag"
+	case status.Outdated:
+		return "required version is major versions behind"
 	}
 
-	ap.mu.Lock()
-	ap.count++
-	ap.mu.Unlock()
+	return st.String()
 }
 
-func (ap *archivedPrinter) Count() int {
-	ap.mu.Lock()
-	defer ap.mu.Unlock()
+// classify returns the most severe status that applies to a repository, or
+// false if the repository is healthy.
+func classify(repo string, result client.RepoResult, staleAfter time.Duration, now time.Time) (status.Status, bool) {
+	if result.Archived {
+		return status.Archived, true
+	}
+
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(result.Description)), "deprecated") {
+		return status.Deprecated, true
+	}
+
+	if result.Fork && result.Parent != nil && result.Parent.Archived {
+		return status.UpstreamArchived, true
+	}
+
+	if staleAfter > 0 {
+		pushedAt, err := time.Parse(time.RFC3339, result.PushedAt)
+		if err == nil && now.Sub(pushedAt) > staleAfter {
+			return status.Stale, true
+		}
+	}
+
+	if result.FullName != "" && !strings.EqualFold(result.FullName, repo) {
+		return status.Moved, true
+	}
 
-	return int(ap.count)
+	return 0, false
 }
 
 // RepoInfo holds information about a discovered repository in a go.mod file.
 type RepoInfo struct {
 	indirect  bool
 	goModPath string
+	line      int
+	column    int
+	modPath   string
+	version   string
+	// tool is set for modules providing a tool, named by a tool directive
+	// or imported by a tools.go file.
+	tool bool
+}
+
+// NewRepoInfo describes a dependency on module at version, required at line
+// and column of file. It allows other ecosystems to reuse Scanner.Check, in
+// which case module is the package name in that ecosystem.
+func NewRepoInfo(file string, line, column int, module, version string, indirect bool) RepoInfo {
+	return RepoInfo{indirect, file, line, column, module, version, false}
+}
+
+// gitHubRepo returns the "owner/repo" part of a github.com module path.
+func gitHubRepo(modPath string) (string, bool) {
+	if !strings.HasPrefix(modPath, "github.com/") {
+		return "", false
+	}
+
+	parts := strings.Split(modPath, "/")
+	if len(parts) < 3 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/%s", parts[1], parts[2]), true
 }
 
 // DiscoverGitHubDependencies parses the provided go.mod files and returns a map of GitHub repositories to their info.
@@ -58,132 +174,155 @@ func DiscoverGitHubDependencies(ctx context.Context, goModFileNames []string) ma
 			continue
 		}
 
-		mf, err := modfile.Parse(name, data, nil)
-		if err != nil {
-			slog.DebugContext(ctx, fmt.Sprintf("failed to parse %s: %v", name, err))
-
-			continue
+		if err := ParseGoMod(name, data, repos); err != nil {
+			slog.DebugContext(ctx, err.Error())
 		}
+	}
 
-		addDep := func(modPath string, indirect bool) {
-			if !strings.HasPrefix(modPath, "github.com/") {
-				return
-			}
+	return repos
+}
 
-			parts := strings.Split(modPath, "/")
-			if len(parts) < 3 {
-				return
-			}
+// ParseGoMod adds the GitHub dependencies of the go.mod file name, with the
+// given contents, to repos. It lets go.mod files be read from elsewhere than
+// the local disk, such as the GitHub contents API.
+func ParseGoMod(name string, data []byte, repos map[string][]RepoInfo) error {
+	return parseGoMod(name, data, gitHubRepo, repos)
+}
 
-			repo := fmt.Sprintf("%s/%s", parts[1], parts[2])
-			repos[repo] = append(repos[repo], RepoInfo{indirect, name})
-		}
+// parseGoMod adds the dependencies of a go.mod file to repos, keyed by key,
+// which reports false for dependencies to leave out. Replaced requirements
+// are resolved to the module that is actually built, like vendor/modules.txt
+// records them: a requirement replaced by a local directory, such as another
+// module of a monorepo, is skipped, and one replaced by a fork is reported as
+// the fork at the position of the requirement, so an archived module
+// replaced by a maintained fork is not reported.
+func parseGoMod(name string, data []byte, key func(modPath string) (string, bool), repos map[string][]RepoInfo) error {
+	mf, err := modfile.Parse(name, data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
 
-		for _, req := range mf.Require {
-			addDep(req.Mod.Path, req.Indirect)
+	added := map[string]bool{}
+
+	addDep := func(modPath, version string, indirect bool, pos modfile.Position) {
+		repo, ok := key(modPath)
+		if !ok {
+			return
 		}
 
-		for _, rep := range mf.Replace {
-			if !strings.HasPrefix(rep.New.Path, "github.com/") {
-				continue
-			}
+		added[repo] = true
+		repos[repo] = append(repos[repo], RepoInfo{indirect, name, pos.Line, pos.LineRune, modPath, version, false})
+	}
+
+	required := map[string]bool{}
 
-			parts := strings.Split(rep.New.Path, "/")
-			if len(parts) < 3 {
+	for _, req := range mf.Require {
+		mod := req.Mod
+		required[mod.Path] = true
+
+		if rep := replacement(mf.Replace, mod); rep != nil {
+			if modfile.IsDirectoryPath(rep.New.Path) {
 				continue
 			}
 
-			repo := fmt.Sprintf("%s/%s", parts[1], parts[2])
-			found := false
+			mod = rep.New
+		}
 
-			for _, info := range repos[repo] {
-				if info.goModPath == name {
-					found = true
+		addDep(mod.Path, mod.Version, req.Indirect, req.Syntax.Start)
+	}
 
-					break
-				}
-			}
+	// Replacements of modules this file does not require may still apply to
+	// modules required transitively.
+	for _, rep := range mf.Replace {
+		if required[rep.Old.Path] || modfile.IsDirectoryPath(rep.New.Path) {
+			continue
+		}
 
-			if !found {
-				repos[repo] = append(repos[repo], RepoInfo{false, name})
-			}
+		if repo, ok := key(rep.New.Path); ok && added[repo] {
+			continue
 		}
+
+		addDep(rep.New.Path, rep.New.Version, false, rep.Syntax.Start)
 	}
 
-	return repos
-}
+	markTools(repos, name, toolPackages(mf))
 
-// ListArchived lists archived Go modules, optionally including
-// indirect ones. Returns the count of archived repos found.
-func ListArchived(ctx context.Context, checkIndirect bool) (int, error) {
-	goModFileNames, err := files.RecursiveFind(ctx, "go.mod")
-	if err != nil {
-		return 0, fmt.Errorf("failed to find go.mod files: %w", err)
-	}
+	return nil
+}
 
-	repos := DiscoverGitHubDependencies(ctx, goModFileNames)
+// replacement returns the directive replacing mod, preferring one for its
+// exact version over one for every version like the go command does, or nil
+// if mod is not replaced.
+func replacement(replaces []*modfile.Replace, mod module.Version) *modfile.Replace {
+	var match *modfile.Replace
 
-	if len(repos) == 0 {
-		slog.DebugContext(ctx, "no github.com modules found in any go.mod file")
+	for _, rep := range replaces {
+		if rep.Old.Path != mod.Path {
+			continue
+		}
 
-		return 0, nil
+		switch rep.Old.Version {
+		case mod.Version:
+			return rep
+		case "":
+			match = rep
+		}
 	}
 
-	client, err := client.New()
+	return match
+}
+
+// ModulePaths returns the sorted, de-duplicated module paths required by every
+// go.mod file found in scope. It is intended for shell completion of commands
+// that take module arguments.
+func ModulePaths(ctx context.Context, scope files.Scope) ([]string, error) {
+	goModFileNames, err := files.RecursiveFind(ctx, scope, "go.mod")
 	if err != nil {
-		return 0, fmt.Errorf("failed to create github api client: %w", err)
+		return nil, fmt.Errorf("failed to find go.mod files: %w", err)
 	}
 
-	var wg sync.WaitGroup
-
-	ap := &archivedPrinter{}
+	seen := map[string]bool{}
 
-	for repo, infos := range repos {
-		// Skip this repository if the user does not want to include indirect
-		// dependencies and all references to this repository are indirect. This
-		// ensures that only directly required repositories are processed unless
-		// indirects are explicitly requested.
-		if !checkIndirect {
-			onlyIndirect := true
+	for _, name := range goModFileNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
 
-			for _, info := range infos {
-				if !info.indirect {
-					onlyIndirect = false
+			continue
+		}
 
-					break
-				}
-			}
+		mf, err := modfile.Parse(name, data, nil)
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("failed to parse %s: %v", name, err))
 
-			if onlyIndirect {
-				continue
-			}
+			continue
 		}
 
-		wg.Add(1)
+		for _, req := range mf.Require {
+			seen[req.Mod.Path] = true
+		}
+	}
 
-		go func(repo string, infos []RepoInfo) {
-			defer wg.Done()
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		paths = append(paths, path)
+	}
 
-			result, err := client.GetRepoResult(repo)
-			if err != nil {
-				slog.DebugContext(ctx, fmt.Sprintf("error fetching repo %s: %v", repo, err))
+	slices.Sort(paths)
 
-				return
-			}
-
-			if result.Archived {
-				for _, info := range infos {
-					if !checkIndirect && info.indirect {
-						continue
-					}
+	return paths, nil
+}
 
-					ap.Print(info.goModPath, repo, result.PushedAt, info.indirect)
-				}
+// Rebase renames the files of repos beneath dir, replacing dir with prefix,
+// e.g. to name manifests fetched into a temporary directory after the
+// repository they came from.
+func Rebase(repos map[string][]RepoInfo, dir, prefix string) {
+	for _, infos := range repos {
+		for i := range infos {
+			rel, err := filepath.Rel(dir, infos[i].goModPath)
+			if err == nil && filepath.IsLocal(rel) {
+				infos[i].goModPath = path.Join(prefix, filepath.ToSlash(rel))
 			}
-		}(repo, infos)
+		}
 	}
-
-	wg.Wait()
-
-	return ap.Count(), nil
 }
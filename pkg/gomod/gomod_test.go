@@ -1,60 +1,62 @@
 package gomod
 
 import (
-	"bytes"
 	"context"
-	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
 )
 
-func captureStdout(t *testing.T, f func()) string {
-	t.Helper()
-
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	f()
-
-	_ = w.Close()
-
-	os.Stdout = old
-
-	var buf bytes.Buffer
-
-	_, _ = io.Copy(&buf, r)
-
-	return buf.String()
-}
-
-func TestArchivedPrinter_Print_Direct(t *testing.T) {
+func TestClassify(t *testing.T) {
 	t.Parallel()
 
-	ap := &archivedPrinter{}
-	out := captureStdout(t, func() {
-		ap.Print("foo/go.mod", "owner/repo", "2025-07-18T12:00:00Z", false)
-	})
+	now := time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC)
+	staleAfter := 365 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		result client.RepoResult
+		want   status.Status
+		found  bool
+	}{
+		{"healthy", client.RepoResult{FullName: "owner/repo", PushedAt: "2025-07-01T00:00:00Z"}, 0, false},
+		{"archived", client.RepoResult{Archived: true, FullName: "owner/repo"}, status.Archived, true},
+		{"deprecated", client.RepoResult{Description: "DEPRECATED: use other/repo"}, status.Deprecated, true},
+		{"stale", client.RepoResult{FullName: "owner/repo", PushedAt: "2023-01-01T00:00:00Z"}, status.Stale, true},
+		{"moved", client.RepoResult{FullName: "new-owner/repo", PushedAt: "2025-07-01T00:00:00Z"}, status.Moved, true},
+		{"fork of archived", client.RepoResult{FullName: "owner/repo", Fork: true, Parent: &client.RepoResult{FullName: "upstream/repo", Archived: true}}, status.UpstreamArchived, true},
+		{"fork of healthy", client.RepoResult{FullName: "owner/repo", PushedAt: "2025-07-01T00:00:00Z", Fork: true, Parent: &client.RepoResult{FullName: "upstream/repo"}}, 0, false},
+		{"case only", client.RepoResult{FullName: "Owner/Repo", PushedAt: "2025-07-01T00:00:00Z"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-	expected := "foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n"
-	require.Equal(t, expected, out)
-	require.Equal(t, 1, ap.Count())
+			got, found := classify("owner/repo", tt.result, staleAfter, now)
+			require.Equal(t, tt.found, found)
+			require.Equal(t, tt.want, got)
+		})
+	}
 }
 
-func TestArchivedPrinter_Print_Indirect(t *testing.T) {
+func TestPrioritize(t *testing.T) {
 	t.Parallel()
 
-	ap := &archivedPrinter{}
-	out := captureStdout(t, func() {
-		ap.Print("bar/go.mod", "owner/repo", "2025-07-18T12:00:00Z", true)
-	})
+	repos := map[string][]RepoInfo{
+		"z/direct":   {{indirect: false}},
+		"a/indirect": {{indirect: true}},
+		"b/mixed":    {{indirect: true}, {indirect: false}},
+	}
 
-	expected := "bar/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z) // indirect\n"
-	require.Equal(t, expected, out)
-	require.Equal(t, 1, ap.Count())
+	require.Equal(t, []string{"b/mixed", "z/direct"}, prioritize(repos, false))
+	require.Equal(t, []string{"b/mixed", "z/direct", "a/indirect"}, prioritize(repos, true))
 }
 
 func writeTempFile(t *testing.T, dir, name, content string) string {
@@ -110,6 +112,9 @@ require (
 	for _, info := range infos {
 		if info.goModPath == goModPath && !info.indirect {
 			foundDirect = true
+
+			require.Equal(t, 4, info.line, "expected require line for wayneashleyberry/gh-arc")
+			require.Equal(t, 2, info.column, "expected require column for wayneashleyberry/gh-arc")
 		}
 	}
 
@@ -148,3 +153,51 @@ require (
 		require.Contains(t, repo, "/", "unexpected repo key: %s", repo)
 	}
 }
+
+func TestDiscoverGitHubDependencies_Replace(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, t.TempDir(), "go.mod", `module example.com/app
+
+go 1.22
+
+require (
+	github.com/archived/lib v1.0.0
+	github.com/acme/internal v0.0.0
+	github.com/pinned/mod v1.2.0
+	github.com/other/mod v0.3.0 // indirect
+)
+
+replace (
+	github.com/archived/lib => github.com/maintained/lib v1.1.0
+	github.com/acme/internal => ../internal
+	github.com/pinned/mod v1.0.0 => github.com/never/used v1.0.0
+	github.com/transitive/dep => github.com/transitive/fork v0.1.0
+	github.com/local/only => ./local
+)
+`)
+
+	repos := DiscoverGitHubDependencies(context.Background(), []string{path})
+
+	require.Equal(t, map[string][]RepoInfo{
+		"maintained/lib":  {{false, path, 6, 2, "github.com/maintained/lib", "v1.1.0", false}},
+		"pinned/mod":      {{false, path, 8, 2, "github.com/pinned/mod", "v1.2.0", false}},
+		"other/mod":       {{true, path, 9, 2, "github.com/other/mod", "v0.3.0", false}},
+		"transitive/fork": {{false, path, 16, 2, "github.com/transitive/fork", "v0.1.0", false}},
+	}, repos)
+}
+
+func TestModulePaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTempFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/foo/bar v1.0.0\n\tgolang.org/x/mod v0.17.0 // indirect\n)\n")
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "tools"), 0o750))
+	writeTempFile(t, filepath.Join(dir, "tools"), "go.mod", "module example.com/app/tools\n\ngo 1.22\n\nrequire (\n\tgithub.com/foo/bar v1.1.0\n\tgithub.com/acme/lint v0.3.0\n)\n")
+
+	paths, err := ModulePaths(context.Background(), files.Scope{Paths: []string{dir}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/acme/lint", "github.com/foo/bar", "golang.org/x/mod"}, paths)
+}
@@ -0,0 +1,173 @@
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// parseGoSum returns the highest version of each module whose contents are
+// recorded in a go.sum file, with the line it was recorded on. Modules of
+// which only the go.mod file was needed are not part of the build and are
+// left out.
+func parseGoSum(data []byte) map[string]RepoInfo {
+	mods := map[string]RepoInfo{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+
+		path, version := fields[0], fields[1]
+
+		if prev, ok := mods[path]; ok && semver.Compare(prev.version, version) >= 0 {
+			continue
+		}
+
+		mods[path] = RepoInfo{indirect: true, line: line, column: 1, modPath: path, version: version}
+	}
+
+	return mods
+}
+
+// discoverWithGoSum returns the dependencies declared in the given go.mod
+// files, like DiscoverGitHubDependencies, together with every other module
+// in their build graph. The build graph is listed with go list when a Go
+// toolchain is available and can list it without network access, and read
+// from the go.sum file next to each go.mod otherwise, see buildGraph. Modules
+// that only appear in the build graph are indirect, and are reported at their
+// line in go.sum, or at the go.mod file without a line when listed by go.
+func discoverWithGoSum(ctx context.Context, goModFileNames []string) (map[string][]RepoInfo, error) {
+	type declaredModule struct {
+		goModPath, modPath string
+	}
+
+	repos := DiscoverGitHubDependencies(ctx, goModFileNames)
+
+	declared := map[declaredModule]bool{}
+
+	for _, infos := range repos {
+		for _, info := range infos {
+			declared[declaredModule{info.goModPath, info.modPath}] = true
+		}
+	}
+
+	_, lookErr := exec.LookPath("go")
+
+	for _, name := range goModFileNames {
+		graph, err := buildGraph(ctx, name, lookErr == nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range graph {
+			if declared[declaredModule{name, info.modPath}] {
+				continue
+			}
+
+			repo, ok := gitHubRepo(info.modPath)
+			if !ok {
+				continue
+			}
+
+			repos[repo] = append(repos[repo], info)
+		}
+	}
+
+	return repos, nil
+}
+
+// buildGraph returns the modules in the build graph of the go.mod file name,
+// listed with the go command if useGo is set and it succeeds, and read from
+// go.sum otherwise. A module without a go.sum file has no dependencies.
+//
+// Since Go 1.17 the module graph is pruned, but still holds modules that are
+// only needed to verify it and provide no package to the build. Go.sum only
+// records the go.mod file of such modules, and go list leaves them out of
+// the packages it loads, so both ways skip them.
+func buildGraph(ctx context.Context, name string, useGo bool) ([]RepoInfo, error) {
+	dir := filepath.Dir(name)
+
+	if useGo {
+		mods, err := goListModules(ctx, dir, true)
+		if err == nil {
+			slog.DebugContext(ctx, fmt.Sprintf("listed %d modules of %s with go list", len(mods), name))
+
+			needed, err := goListPackageModules(ctx, dir)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("keeping modules that provide no packages: %v", err))
+			}
+
+			return listedGraph(name, mods, needed), nil
+		}
+
+		slog.DebugContext(ctx, fmt.Sprintf("falling back to go.sum: %v", err))
+	}
+
+	goSum := filepath.Join(dir, "go.sum")
+
+	data, err := os.ReadFile(goSum) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", goSum, err)
+	}
+
+	var infos []RepoInfo
+
+	for _, info := range parseGoSum(data) {
+		info.goModPath = goSum
+		infos = append(infos, info)
+	}
+
+	slices.SortFunc(infos, func(a, b RepoInfo) int {
+		return cmp.Compare(a.line, b.line)
+	})
+
+	return infos, nil
+}
+
+// listedGraph returns the modules mods listed by go for the go.mod file name,
+// leaving out the main module, local replacements and, unless needed is nil,
+// modules not in needed.
+func listedGraph(name string, mods []listedModule, needed map[string]bool) []RepoInfo {
+	var infos []RepoInfo
+
+	for _, mod := range mods {
+		if mod.Main || (needed != nil && !needed[mod.Path]) {
+			continue
+		}
+
+		if mod.Replace != nil {
+			if mod.Replace.Version == "" {
+				continue
+			}
+
+			mod = *mod.Replace
+		}
+
+		infos = append(infos, RepoInfo{indirect: true, goModPath: name, modPath: mod.Path, version: mod.Version})
+	}
+
+	return infos
+}
@@ -0,0 +1,73 @@
+package gomod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoSum(t *testing.T) {
+	t.Parallel()
+
+	mods := parseGoSum([]byte(`github.com/foo/bar v1.0.0 h1:abc=
+github.com/foo/bar v1.0.0/go.mod h1:abc=
+github.com/deep/dep v0.2.0 h1:def=
+github.com/deep/dep v0.10.0 h1:ghi=
+github.com/deep/dep v0.3.0 h1:jkl=
+github.com/pruned/mod v1.0.0/go.mod h1:mno=
+malformed line
+`))
+
+	require.Equal(t, map[string]RepoInfo{
+		"github.com/foo/bar":  {true, "", 1, 1, "github.com/foo/bar", "v1.0.0", false},
+		"github.com/deep/dep": {true, "", 4, 1, "github.com/deep/dep", "v0.10.0", false},
+	}, mods)
+}
+
+func TestBuildGraph_GoSum(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	goMod := writeTempFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/foo/bar v1.0.0\n")
+
+	graph, err := buildGraph(context.Background(), goMod, false)
+	require.NoError(t, err)
+	require.Empty(t, graph)
+
+	goSum := writeTempFile(t, dir, "go.sum", "github.com/foo/bar v1.0.0 h1:abc=\ngithub.com/deep/dep v0.1.0 h1:def=\ngolang.org/x/mod v0.17.0 h1:ghi=\n")
+
+	graph, err = buildGraph(context.Background(), goMod, false)
+	require.NoError(t, err)
+	require.Equal(t, []RepoInfo{
+		{true, goSum, 1, 1, "github.com/foo/bar", "v1.0.0", false},
+		{true, goSum, 2, 1, "github.com/deep/dep", "v0.1.0", false},
+		{true, goSum, 3, 1, "golang.org/x/mod", "v0.17.0", false},
+	}, graph)
+}
+
+func TestListedGraph(t *testing.T) {
+	t.Parallel()
+
+	mods := []listedModule{
+		{Path: "example.com/app", Main: true},
+		{Path: "github.com/foo/bar", Version: "v1.2.0"},
+		{Path: "github.com/old/lib", Version: "v1.0.0", Replace: &listedModule{Path: "github.com/new/lib", Version: "v1.1.0"}},
+		{Path: "github.com/local/mod", Version: "v0.0.0", Replace: &listedModule{Path: "../mod"}},
+		{Path: "github.com/graph/only", Version: "v0.3.0"},
+	}
+
+	require.Equal(t, []RepoInfo{
+		{true, "go.mod", 0, 0, "github.com/foo/bar", "v1.2.0", false},
+		{true, "go.mod", 0, 0, "github.com/new/lib", "v1.1.0", false},
+		{true, "go.mod", 0, 0, "github.com/graph/only", "v0.3.0", false},
+	}, listedGraph("go.mod", mods, nil))
+
+	// Modules that provide no package to the build are pruned.
+	needed := map[string]bool{"example.com/app": true, "github.com/foo/bar": true, "github.com/old/lib": true}
+
+	require.Equal(t, []RepoInfo{
+		{true, "go.mod", 0, 0, "github.com/foo/bar", "v1.2.0", false},
+		{true, "go.mod", 0, 0, "github.com/new/lib", "v1.1.0", false},
+	}, listedGraph("go.mod", mods, needed))
+}
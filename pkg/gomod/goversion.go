@@ -0,0 +1,119 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"go/version"
+	"log/slog"
+	"os"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+	"golang.org/x/mod/modfile"
+)
+
+// goRepo is the repository findings about the go directive are attributed
+// to.
+const goRepo = "golang/go"
+
+// GoVersionPolicy is the range of Go releases go.mod files may require in
+// their go directive, such as "1.22" to "1.24". Empty bounds are not checked.
+type GoVersionPolicy struct {
+	Min string
+	Max string
+}
+
+// Enabled reports whether any bound is set.
+func (p GoVersionPolicy) Enabled() bool {
+	return p.Min != "" || p.Max != ""
+}
+
+// Validate reports whether the bounds are valid Go versions and in order.
+func (p GoVersionPolicy) Validate() error {
+	for _, v := range []string{p.Min, p.Max} {
+		if v != "" && !version.IsValid("go"+v) {
+			return fmt.Errorf("invalid go version %q, expected a release such as 1.22 or 1.22.3", v)
+		}
+	}
+
+	if p.Min != "" && p.Max != "" && version.Compare("go"+p.Min, "go"+p.Max) > 0 {
+		return fmt.Errorf("minimum go version %s is newer than maximum %s", p.Min, p.Max)
+	}
+
+	return nil
+}
+
+// violation explains why goVersion is outside the policy, or returns false
+// if it is supported. A maximum of "1.24" allows every 1.24.x release.
+func (p GoVersionPolicy) violation(goVersion string) (string, bool) {
+	v := "go" + goVersion
+
+	if p.Min != "" && version.Compare(v, "go"+p.Min) < 0 {
+		return fmt.Sprintf("go %s is older than the minimum supported %s", goVersion, p.Min), true
+	}
+
+	if p.Max != "" {
+		upper := "go" + p.Max
+
+		// Compare language versions only when the maximum is one, so
+		// patch releases are allowed.
+		if version.Lang(upper) == upper {
+			v = version.Lang(v)
+		}
+
+		if version.Compare(v, upper) > 0 {
+			return fmt.Sprintf("go %s is newer than the maximum supported %s", goVersion, p.Max), true
+		}
+	}
+
+	return "", false
+}
+
+// CheckGoVersions returns a finding for every go.mod file whose go directive
+// is outside the policy. Files without a go directive are not reported, as
+// they default to the oldest Go release.
+func (p GoVersionPolicy) CheckGoVersions(ctx context.Context, goModFileNames []string) ([]finding.Finding, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var findings []finding.Finding
+
+	for _, name := range goModFileNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		mf, err := modfile.ParseLax(name, data, nil)
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("failed to parse %s: %v", name, err))
+
+			continue
+		}
+
+		if mf.Go == nil {
+			continue
+		}
+
+		reason, ok := p.violation(mf.Go.Version)
+		if !ok {
+			continue
+		}
+
+		findings = append(findings, finding.Finding{
+			Module:  "go",
+			Version: mf.Go.Version,
+			Repo:    goRepo,
+			File:    name,
+			Line:    mf.Go.Syntax.Start.Line,
+			Column:  mf.Go.Syntax.Start.LineRune,
+			Status:  status.UnsupportedGo,
+			Reason:  reason,
+		})
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,74 @@
+package gomod
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestGoVersionPolicy_violation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policy    GoVersionPolicy
+		goVersion string
+		expected  string
+	}{
+		{GoVersionPolicy{Min: "1.22"}, "1.22", ""},
+		{GoVersionPolicy{Min: "1.22"}, "1.22.0", ""},
+		{GoVersionPolicy{Min: "1.22"}, "1.21.13", "go 1.21.13 is older than the minimum supported 1.22"},
+		{GoVersionPolicy{Min: "1.22.3"}, "1.22.1", "go 1.22.1 is older than the minimum supported 1.22.3"},
+		{GoVersionPolicy{Max: "1.24"}, "1.24.5", ""},
+		{GoVersionPolicy{Max: "1.24"}, "1.25", "go 1.25 is newer than the maximum supported 1.24"},
+		{GoVersionPolicy{Max: "1.24.2"}, "1.24.5", "go 1.24.5 is newer than the maximum supported 1.24.2"},
+		{GoVersionPolicy{Min: "1.22", Max: "1.24"}, "1.23", ""},
+	}
+
+	for _, tt := range tests {
+		reason, ok := tt.policy.violation(tt.goVersion)
+		require.Equal(t, tt.expected, reason, tt.goVersion)
+		require.Equal(t, tt.expected != "", ok, tt.goVersion)
+	}
+}
+
+func TestGoVersionPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, GoVersionPolicy{Min: "1.22", Max: "1.24.1"}.Validate())
+	require.ErrorContains(t, GoVersionPolicy{Min: "go1.22"}.Validate(), `invalid go version "go1.22"`)
+	require.ErrorContains(t, GoVersionPolicy{Min: "1.24", Max: "1.22"}.Validate(), "minimum go version 1.24 is newer than maximum 1.22")
+}
+
+func TestScanner_Scan_GoVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	old := writeTempFile(t, dir, "go.mod", "module example.com/old\n\ngo 1.20\n")
+
+	writeTempFile(t, t.TempDir(), "go.mod", "module example.com/current\n\ngo 1.23\n")
+
+	s := NewScanner(Options{
+		Format:    render.FormatText,
+		Scope:     files.Scope{Paths: []string{dir}},
+		GoVersion: GoVersionPolicy{Min: "1.22"},
+	}, io.Discard)
+
+	result, err := s.Scan(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []finding.Finding{{
+		Module:  "go",
+		Version: "1.20",
+		Repo:    "golang/go",
+		File:    old,
+		Line:    3,
+		Column:  1,
+		Status:  status.UnsupportedGo,
+		Reason:  "go 1.20 is older than the minimum supported 1.22",
+	}}, result.Findings)
+}
@@ -0,0 +1,132 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/depgraph"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Graph returns the module requirement graph of every go.mod file in
+// s.Scope, as `go mod graph` lists it, with each github.com module carrying
+// the most severe status of its repository. Every module of the graph is
+// looked up, direct and indirect, subject to MaxAPICalls. It requires the go
+// command.
+func (s *Scanner) Graph(ctx context.Context) (depgraph.Graph, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return depgraph.Graph{}, fmt.Errorf("listing the module graph requires the go command: %w", err)
+	}
+
+	goModFileNames, err := files.RecursiveFind(ctx, s.Options.Scope, "go.mod")
+	if err != nil {
+		return depgraph.Graph{}, fmt.Errorf("failed to find go.mod files: %w", err)
+	}
+
+	var g depgraph.Graph
+
+	seen := map[string]bool{}
+	edges := map[depgraph.Edge]bool{}
+	repos := map[string][]RepoInfo{}
+
+	addNode := func(id, goModPath string) {
+		if seen[id] {
+			return
+		}
+
+		seen[id] = true
+		g.Nodes = append(g.Nodes, depgraph.Node{ID: id})
+
+		path, version, ok := strings.Cut(id, "@")
+		if !ok {
+			return
+		}
+
+		if repo, ok := gitHubRepo(path); ok {
+			repos[repo] = append(repos[repo], RepoInfo{indirect: true, goModPath: goModPath, modPath: path, version: version})
+		}
+	}
+
+	for _, name := range goModFileNames {
+		graph, err := goModGraph(ctx, filepath.Dir(name))
+		if err != nil {
+			return depgraph.Graph{}, err
+		}
+
+		addGraph(graph, name, addNode, func(e depgraph.Edge) {
+			if !edges[e] {
+				edges[e] = true
+				g.Edges = append(g.Edges, e)
+			}
+		})
+	}
+
+	if len(repos) == 0 {
+		return g, nil
+	}
+
+	scanner := *s
+	scanner.Out = io.Discard
+	scanner.Format = render.FormatText
+	scanner.Indirect = true
+	scanner.SelfCheck = nil
+
+	report, err := scanner.check(ctx, repos, nil)
+	if err != nil {
+		return depgraph.Graph{}, err
+	}
+
+	statuses := map[string]status.Status{}
+
+	for _, f := range report.Findings {
+		id := f.Module + "@" + f.Version
+		if st, ok := statuses[id]; !ok || slices.Index(status.All, f.Status) < slices.Index(status.All, st) {
+			statuses[id] = f.Status
+		}
+	}
+
+	for i := range g.Nodes {
+		g.Nodes[i].Status = statuses[g.Nodes[i].ID]
+	}
+
+	return g, nil
+}
+
+// addGraph passes the modules and requirements of graph, listed for the
+// go.mod file name, to addNode and addEdge in a stable order: each module
+// before the modules it requires, and main modules first.
+func addGraph(graph modGraph, name string, addNode func(id, goModPath string), addEdge func(depgraph.Edge)) {
+	from := make([]string, 0, len(graph))
+	for id := range graph {
+		from = append(from, id)
+	}
+
+	slices.SortFunc(from, func(a, b string) int {
+		// Main modules are printed without a version.
+		if am, bm := !strings.Contains(a, "@"), !strings.Contains(b, "@"); am != bm {
+			if am {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	for _, id := range from {
+		addNode(id, name)
+
+		for _, to := range graph[id] {
+			addNode(to, name)
+			addEdge(depgraph.Edge{From: id, To: to})
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package gomod
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/depgraph"
+)
+
+func TestAddGraph(t *testing.T) {
+	t.Parallel()
+
+	graph, err := parseModGraph(strings.NewReader(testModGraph))
+	require.NoError(t, err)
+
+	var (
+		nodes []string
+		edges []depgraph.Edge
+	)
+
+	seen := map[string]bool{}
+
+	addGraph(graph, "go.mod", func(id, goModPath string) {
+		require.Equal(t, "go.mod", goModPath)
+
+		if !seen[id] {
+			seen[id] = true
+			nodes = append(nodes, id)
+		}
+	}, func(e depgraph.Edge) {
+		edges = append(edges, e)
+	})
+
+	require.Equal(t, []string{
+		"example.com/app",
+		"github.com/a/b@v1.0.0",
+		"github.com/c/d@v0.2.0",
+		"github.com/foo/bar@v1.1.0",
+		"github.com/x/y@v1.0.0",
+		"github.com/foo/bar@v1.0.0",
+	}, nodes)
+	require.Len(t, edges, 7)
+	require.Equal(t, depgraph.Edge{From: "example.com/app", To: "github.com/a/b@v1.0.0"}, edges[0])
+}
@@ -0,0 +1,69 @@
+package gomod
+
+import (
+	"path"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// noAssertion is the SPDX identifier GitHub reports for a license it found
+// but could not identify.
+const noAssertion = "NOASSERTION"
+
+// copyleftLicenses are patterns of SPDX identifiers of copyleft licenses,
+// which are warned about unless disallowed outright.
+var copyleftLicenses = []string{"agpl-*", "gpl-*", "lgpl-*", "mpl-*", "epl-*", "eupl-*", "osl-*", "cc-by-sa-*"}
+
+// LicensePolicy configures the license checks of Check.
+type LicensePolicy struct {
+	// Enabled reports dependencies without a license, with a disallowed
+	// license or with a copyleft license.
+	Enabled bool
+	// Disallowed lists SPDX identifiers, or patterns such as "AGPL-*", of
+	// licenses that fail the run. Matching is case-insensitive.
+	Disallowed []string
+}
+
+// classify returns the license status of a repository, or false if its
+// license is acceptable. Results of providers that cannot see licenses, such
+// as git probing, are never reported.
+func (p LicensePolicy) classify(result client.RepoResult) (status.Status, bool) {
+	if !p.Enabled || result.Degraded {
+		return 0, false
+	}
+
+	if result.License == nil || result.License.SPDXID == "" {
+		return status.NoLicense, true
+	}
+
+	id := result.License.SPDXID
+	if id == noAssertion {
+		return 0, false
+	}
+
+	if matchLicense(p.Disallowed, id) {
+		return status.DisallowedLicense, true
+	}
+
+	if matchLicense(copyleftLicenses, id) {
+		return status.Copyleft, true
+	}
+
+	return 0, false
+}
+
+// matchLicense reports whether the SPDX identifier id matches any of
+// patterns, ignoring case.
+func matchLicense(patterns []string, id string) bool {
+	id = strings.ToLower(id)
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), id); ok {
+			return true
+		}
+	}
+
+	return false
+}
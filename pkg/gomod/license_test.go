@@ -0,0 +1,39 @@
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestLicensePolicy_classify(t *testing.T) {
+	t.Parallel()
+
+	policy := LicensePolicy{Enabled: true, Disallowed: []string{"agpl-*", "SSPL-1.0"}}
+
+	tests := []struct {
+		name     string
+		result   client.RepoResult
+		expected status.Status
+		found    bool
+	}{
+		{"mit", client.RepoResult{License: &client.License{SPDXID: "MIT"}}, 0, false},
+		{"none", client.RepoResult{}, status.NoLicense, true},
+		{"unidentified", client.RepoResult{License: &client.License{SPDXID: "NOASSERTION"}}, 0, false},
+		{"disallowed pattern", client.RepoResult{License: &client.License{SPDXID: "AGPL-3.0-only"}}, status.DisallowedLicense, true},
+		{"disallowed", client.RepoResult{License: &client.License{SPDXID: "SSPL-1.0"}}, status.DisallowedLicense, true},
+		{"copyleft", client.RepoResult{License: &client.License{SPDXID: "GPL-2.0-or-later"}}, status.Copyleft, true},
+		{"degraded", client.RepoResult{Degraded: true}, 0, false},
+	}
+
+	for _, tt := range tests {
+		st, found := policy.classify(tt.result)
+		require.Equal(t, tt.expected, st, tt.name)
+		require.Equal(t, tt.found, found, tt.name)
+	}
+
+	_, found := LicensePolicy{}.classify(client.RepoResult{})
+	require.False(t, found)
+}
@@ -0,0 +1,99 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/modpath"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// TagAPI looks up the tags of a repository. client.Client implements it.
+type TagAPI interface {
+	TagExists(ctx context.Context, repo, tag string) (bool, error)
+	Tags(ctx context.Context, repo string) ([]string, error)
+}
+
+// CheckModule checks the repository of the module at path, which may be a
+// vanity import path, before it is added as a dependency. If version is set
+// and is not a pseudo-version, it also reports when the tag the version was
+// released from no longer exists. Findings have no file.
+func (s *Scanner) CheckModule(ctx context.Context, path, version string) (finding.Report, error) {
+	if err := s.validate(); err != nil {
+		return finding.Report{}, err
+	}
+
+	resolver := modpath.Resolver{HTTPClient: s.Timeouts.HTTPClient("https://" + path)}
+
+	repo, err := resolver.Resolve(ctx, path)
+	if err != nil {
+		return finding.Report{}, fmt.Errorf("failed to resolve repository of %s: %w", path, err)
+	}
+
+	slog.DebugContext(ctx, fmt.Sprintf("module %s is hosted at github.com/%s", path, repo.Name))
+
+	scanner := *s
+
+	if scanner.Provider == nil {
+		scanner.Provider, err = NewProvider(ctx, s.Options)
+		if err != nil {
+			return finding.Report{}, err
+		}
+	}
+
+	repos := map[string][]RepoInfo{repo.Name: {NewRepoInfo("", 0, 0, path, version, false)}}
+
+	var extra []finding.Finding
+
+	// The repository is looked up first so a missing one is not also
+	// reported as missing the tag. Providers cache the result for check.
+	tag, tagged := modpath.Tag(path, repo.Root, version)
+	if _, err := scanner.Provider.GetRepoResult(ctx, repo.Name); tagged && err == nil {
+		tags, err := scanner.tagAPI(ctx)
+		if err != nil {
+			return finding.Report{}, err
+		}
+
+		f, missing := missingTag(ctx, tags, path, version, repo.Name, tag)
+
+		if missing {
+			extra = append(extra, f)
+		}
+	}
+
+	return scanner.check(ctx, repos, extra)
+}
+
+// tagAPI returns s.Tags, or a GitHub client if it is nil.
+func (s *Scanner) tagAPI(ctx context.Context) (TagAPI, error) {
+	if s.Tags != nil {
+		return s.Tags, nil
+	}
+
+	return NewGitHubClient(ctx, s.Options)
+}
+
+// missingTag returns a finding if repo has no tag named tag. Lookup errors
+// are logged and treated as the tag existing.
+func missingTag(ctx context.Context, tags TagAPI, path, version, repo, tag string) (finding.Finding, bool) {
+	exists, err := tags.TagExists(ctx, repo, tag)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("error looking up tag %s of %s: %v", tag, repo, err))
+
+		return finding.Finding{}, false
+	}
+
+	if exists {
+		return finding.Finding{}, false
+	}
+
+	return finding.Finding{
+		Module:  path,
+		Version: version,
+		Repo:    repo,
+		Status:  status.MissingVersion,
+		Reason:  fmt.Sprintf("tag %s no longer exists", tag),
+	}, true
+}
@@ -0,0 +1,152 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/modpath"
+	"github.com/wayneashleyberry/gh-arc/pkg/output"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+	"golang.org/x/mod/semver"
+)
+
+// major returns the major version of a semantic version, such as 2 for
+// "v2.1.0" or "v2.0.0+incompatible", or false if it is not valid.
+func major(version string) (int, bool) {
+	m := semver.Major(strings.TrimSuffix(version, "+incompatible"))
+	if m == "" {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(m[1:])
+
+	return n, err == nil
+}
+
+// latestTag returns the highest release version among the tags that start
+// with prefix, without the prefix. Pre-releases are ignored.
+func latestTag(tags []string, prefix string) (string, bool) {
+	latest := ""
+
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok || !semver.IsValid(version) || semver.Prerelease(version) != "" {
+			continue
+		}
+
+		if latest == "" || semver.Compare(version, latest) > 0 {
+			latest = version
+		}
+	}
+
+	return latest, latest != ""
+}
+
+// outdated returns a finding for every dependency in deps whose required
+// version is at least s.OutdatedMajors major versions behind the latest tag
+// of its module.
+func (s *Scanner) outdated(ctx context.Context, deps map[string][]RepoInfo) ([]finding.Finding, error) {
+	wanted := map[string][]RepoInfo{}
+	prefixes := map[string]string{}
+
+	for repo, infos := range deps {
+		for _, info := range infos {
+			if !s.Indirect && info.indirect {
+				continue
+			}
+
+			if _, ok := major(info.version); !ok {
+				continue
+			}
+
+			if _, ok := prefixes[info.modPath]; !ok {
+				prefixes[info.modPath] = modpath.TagPrefix(info.modPath, s.moduleRoot(ctx, info.modPath))
+			}
+
+			wanted[repo] = append(wanted[repo], info)
+		}
+	}
+
+	if len(wanted) == 0 {
+		return nil, nil
+	}
+
+	tags, err := s.tagAPI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, tagConcurrency)
+	)
+
+	collected := output.Start[finding.Finding](progress.New(s.Progress, len(wanted)))
+
+	for repo, infos := range wanted {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			names, err := tags.Tags(ctx, repo)
+
+			collected.Done(repo)
+
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error listing tags of %s: %v", repo, err))
+
+				return
+			}
+
+			for _, info := range infos {
+				if f, ok := s.outdatedFinding(repo, info, names, prefixes[info.modPath]); ok {
+					collected.Add(f)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return collected.Close(), nil
+}
+
+// outdatedFinding returns a finding if info requires a version at least
+// s.OutdatedMajors major versions behind the latest of tags.
+func (s *Scanner) outdatedFinding(repo string, info RepoInfo, tags []string, prefix string) (finding.Finding, bool) {
+	latest, ok := latestTag(tags, prefix)
+	if !ok {
+		return finding.Finding{}, false
+	}
+
+	required, _ := major(info.version)
+	newest, _ := major(latest)
+
+	behind := newest - required
+	if behind < s.OutdatedMajors {
+		return finding.Finding{}, false
+	}
+
+	return finding.Finding{
+		Module:   info.modPath,
+		Version:  info.version,
+		Latest:   latest,
+		Repo:     repo,
+		File:     info.goModPath,
+		Line:     info.line,
+		Column:   info.column,
+		Indirect: info.indirect,
+		Status:   status.Outdated,
+		Reason:   fmt.Sprintf("requires %s, latest %s is %d major versions ahead", info.version, latest, behind),
+	}, true
+}
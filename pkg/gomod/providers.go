@@ -0,0 +1,142 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// ProviderStatus describes a repository metadata provider for a host.
+type ProviderStatus struct {
+	// Provider is one of Providers, other than ProviderAuto.
+	Provider string `json:"provider"`
+	// Host is the host the provider looks repositories up on.
+	Host string `json:"host"`
+	// Available is set when the provider can be used.
+	Available bool `json:"available"`
+	// Auth describes the credentials used, such as "token (GH_TOKEN)".
+	Auth string `json:"auth"`
+	// RateLimit is the current API rate limit, if the provider has one.
+	RateLimit *client.RateLimit `json:"rate_limit,omitempty"`
+	// Modules lists the module path patterns checked with the provider.
+	Modules []string `json:"modules"`
+	// Detail explains why a provider is unavailable, or which git is used.
+	Detail string `json:"detail,omitempty"`
+}
+
+// gitHubHosts returns github.com followed by the other hosts the gh CLI is
+// authenticated with, such as GitHub Enterprise Server instances.
+func gitHubHosts() []string {
+	hosts := []string{gitHubHost}
+
+	for _, host := range auth.KnownHosts() {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// modulePatterns returns the module path patterns checked against host. Only
+// github.com modules are checked so far.
+func modulePatterns(host string) []string {
+	if host == gitHubHost {
+		return []string{"github.com/*"}
+	}
+
+	return []string{}
+}
+
+// ProviderStatuses describes the GitHub API provider for every known host
+// and the git provider, including authentication and rate limit state.
+func ProviderStatuses(ctx context.Context, opts Options) []ProviderStatus {
+	var statuses []ProviderStatus
+
+	for _, host := range gitHubHosts() {
+		st := ProviderStatus{Provider: ProviderGitHub, Host: host, Auth: "none", Modules: modulePatterns(host)}
+
+		if token, source := auth.TokenForHost(host); token != "" {
+			st.Auth = "token (" + source + ")"
+		}
+
+		clientOpts := opts.Client
+		clientOpts.Host = host
+		clientOpts.Timeout = opts.Timeouts.For(host)
+
+		c, err := client.NewWithOptions(clientOpts)
+		if err != nil {
+			st.Detail = err.Error()
+			statuses = append(statuses, st)
+
+			continue
+		}
+
+		rl, err := c.RateLimit(ctx)
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("error fetching rate limit for %s: %v", host, err))
+
+			st.Detail = err.Error()
+		} else {
+			st.Available = true
+			st.RateLimit = &rl
+		}
+
+		statuses = append(statuses, st)
+	}
+
+	git := ProviderStatus{Provider: ProviderGit, Host: gitHubHost, Auth: "git credentials", Modules: modulePatterns(gitHubHost)}
+
+	out, err := exec.CommandContext(ctx, "git", "version").Output()
+	if err != nil {
+		git.Detail = "git not found: " + err.Error()
+	} else {
+		git.Available = true
+		git.Detail = strings.TrimSpace(string(out))
+	}
+
+	return append(statuses, git)
+}
+
+// ListProviders prints the status of every provider as a table.
+func ListProviders(ctx context.Context, w io.Writer, opts Options) ([]ProviderStatus, error) {
+	statuses := ProviderStatuses(ctx, opts)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "PROVIDER\tHOST\tSTATUS\tAUTH\tRATE LIMIT\tMODULES\tDETAIL")
+
+	for _, st := range statuses {
+		state := "unavailable"
+		if st.Available {
+			state = "available"
+		}
+
+		rateLimit := "-"
+		if rl := st.RateLimit; rl != nil {
+			rateLimit = fmt.Sprintf("%d/%d, resets %s", rl.Remaining, rl.Limit, time.Unix(rl.Reset, 0).Format(time.Kitchen))
+		}
+
+		modules := "-"
+		if len(st.Modules) > 0 {
+			modules = strings.Join(st.Modules, ",")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", st.Provider, st.Host, state, st.Auth, rateLimit, modules, st.Detail)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write providers: %w", err)
+	}
+
+	return statuses, nil
+}
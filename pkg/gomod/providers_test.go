@@ -0,0 +1,23 @@
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModulePatterns(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"github.com/*"}, modulePatterns("github.com"))
+	require.Empty(t, modulePatterns("ghe.example.com"))
+}
+
+func TestGitHubHosts(t *testing.T) {
+	t.Setenv("GH_HOST", "ghe.example.com")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "x")
+
+	hosts := gitHubHosts()
+	require.Equal(t, "github.com", hosts[0])
+	require.Contains(t, hosts, "ghe.example.com")
+}
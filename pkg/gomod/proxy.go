@@ -0,0 +1,89 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/goproxy"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// ProxyAPI resolves module versions to the GitHub repositories they were
+// fetched from. It is satisfied by *goproxy.Client.
+type ProxyAPI interface {
+	Repositories(ctx context.Context, mods []module.Version) map[module.Version]string
+}
+
+var _ ProxyAPI = (*goproxy.Client)(nil)
+
+// modulePath keys dependencies by their module path, leaving out replacements
+// by local directories.
+func modulePath(modPath string) (string, bool) {
+	return modPath, !modfile.IsDirectoryPath(modPath)
+}
+
+// DiscoverWithProxy parses the provided go.mod files and returns a map of
+// GitHub repositories to their info, like DiscoverGitHubDependencies, but
+// finds the repository of every dependency through the origin recorded by the
+// module proxy. This attributes vanity import paths, and modules whose
+// repository has been renamed, to the repository they are really developed
+// in. Dependencies the proxy cannot resolve fall back to their module path.
+func DiscoverWithProxy(ctx context.Context, proxy ProxyAPI, goModFileNames []string) map[string][]RepoInfo {
+	mods := map[string][]RepoInfo{}
+
+	for _, name := range goModFileNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		if err := parseGoMod(name, data, modulePath, mods); err != nil {
+			slog.DebugContext(ctx, err.Error())
+		}
+	}
+
+	var versions []module.Version
+
+	seen := map[module.Version]bool{}
+
+	for _, infos := range mods {
+		for _, info := range infos {
+			mv := module.Version{Path: info.modPath, Version: info.version}
+			if !seen[mv] {
+				seen[mv] = true
+				versions = append(versions, mv)
+			}
+		}
+	}
+
+	resolved := proxy.Repositories(ctx, versions)
+	repos := map[string][]RepoInfo{}
+
+	for modPath, infos := range mods {
+		for _, info := range infos {
+			pathRepo, isGitHub := gitHubRepo(modPath)
+
+			repo, ok := resolved[module.Version{Path: info.modPath, Version: info.version}]
+			if !ok {
+				repo, ok = pathRepo, isGitHub
+			}
+
+			if !ok {
+				continue
+			}
+
+			if isGitHub && repo != pathRepo {
+				slog.DebugContext(ctx, fmt.Sprintf("module proxy resolved %s to %s rather than %s", modPath, repo, pathRepo))
+			}
+
+			repos[repo] = append(repos[repo], info)
+		}
+	}
+
+	return repos
+}
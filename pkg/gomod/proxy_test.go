@@ -0,0 +1,56 @@
+package gomod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/module"
+)
+
+// mockProxy resolves module versions from a fixed map.
+type mockProxy map[module.Version]string
+
+func (m mockProxy) Repositories(_ context.Context, mods []module.Version) map[module.Version]string {
+	repos := map[module.Version]string{}
+
+	for _, mod := range mods {
+		if repo, ok := m[mod]; ok {
+			repos[mod] = repo
+		}
+	}
+
+	return repos
+}
+
+func TestDiscoverWithProxy(t *testing.T) {
+	t.Parallel()
+
+	goMod := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/foo/bar v1.2.3
+	github.com/old/name v0.1.0
+	go.example.org/vanity v1.0.0
+	gitlab.com/owner/lib v0.2.0
+	github.com/local/mod v1.0.0
+)
+
+replace github.com/local/mod => ../mod
+`
+	path := writeTempFile(t, t.TempDir(), "go.mod", goMod)
+
+	repos := DiscoverWithProxy(context.Background(), mockProxy{
+		{Path: "github.com/old/name", Version: "v0.1.0"}:   "new/name",
+		{Path: "go.example.org/vanity", Version: "v1.0.0"}: "example/vanity",
+	}, []string{path})
+
+	require.Len(t, repos, 3)
+	require.Equal(t, []RepoInfo{{false, path, 6, 2, "github.com/foo/bar", "v1.2.3", false}}, repos["foo/bar"])
+	require.Equal(t, []RepoInfo{{false, path, 7, 2, "github.com/old/name", "v0.1.0", false}}, repos["new/name"])
+	require.Equal(t, []RepoInfo{{false, path, 8, 2, "go.example.org/vanity", "v1.0.0", false}}, repos["example/vanity"])
+	require.NotContains(t, repos, "local/mod")
+	require.NotContains(t, repos, "old/name")
+}
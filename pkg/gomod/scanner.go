@@ -0,0 +1,908 @@
+package gomod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/advisory"
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
+	"github.com/wayneashleyberry/gh-arc/pkg/automation"
+	"github.com/wayneashleyberry/gh-arc/pkg/baseline"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/codeowners"
+	"github.com/wayneashleyberry/gh-arc/pkg/config"
+	"github.com/wayneashleyberry/gh-arc/pkg/depsdev"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/forks"
+	"github.com/wayneashleyberry/gh-arc/pkg/ghext"
+	"github.com/wayneashleyberry/gh-arc/pkg/gitprobe"
+	"github.com/wayneashleyberry/gh-arc/pkg/goproxy"
+	"github.com/wayneashleyberry/gh-arc/pkg/mirror"
+	"github.com/wayneashleyberry/gh-arc/pkg/output"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Options configures a Scanner.
+type Options struct {
+	// Indirect includes indirect dependencies.
+	Indirect bool
+	// Format is one of render.Formats.
+	Format string
+	// StaleAfter reports repositories that have not been pushed to for
+	// longer than this duration. Zero disables stale detection.
+	StaleAfter time.Duration
+	// Vendor reads vendor/modules.txt instead of go.mod for modules that
+	// vendor their dependencies.
+	Vendor bool
+	// ModuleProxy resolves the repository of every go.mod dependency
+	// through the origin recorded by the module proxies in $GOPROXY rather
+	// than from its module path. It does not apply to vendored modules.
+	ModuleProxy bool
+	// FromGoSum adds every module in the build graph of each go.mod file,
+	// listed with `go list -m all` or read from go.sum, to the modules it
+	// declares. Modules it does not declare are indirect.
+	FromGoSum bool
+	// GoList resolves the dependencies of each go.mod file with
+	// `go list -m all` instead of reading go.mod, so findings name the
+	// versions that are actually built. It requires the go command.
+	GoList bool
+	// ToolsOnly keeps only tool dependencies, declared with tool
+	// directives in go.mod or imported by tools.go files.
+	ToolsOnly bool
+	// OutdatedMajors reports dependencies whose required version is at
+	// least this many major versions behind the latest tag of their
+	// module. Zero disables the check.
+	OutdatedMajors int
+	// Provider is one of Providers. An empty value means ProviderAuto.
+	Provider string
+	// Client configures how the GitHub API client identifies itself.
+	Client client.Options
+	// SuggestAlternatives prints deps.dev and pkg.go.dev pointers for
+	// archived modules in text output.
+	SuggestAlternatives bool
+	// SuggestForks ranks up to this many forks of each archived repository
+	// as candidate replacements. Zero disables ranking.
+	SuggestForks int
+	// Advisories looks up unpatched security advisories affecting the
+	// required version of archived dependencies.
+	Advisories bool
+	// ResolveMirrors checks the upstream of read-only mirrors hosted on
+	// GitHub instead of the mirror. Stale and unlicensed findings of other
+	// mirrors are informational either way.
+	ResolveMirrors bool
+	// Details adds open issues, the latest release and the default branch
+	// of each unhealthy repository to its findings.
+	Details bool
+	// Order is one of finding.Orders and controls the order findings are
+	// returned and rendered in. An empty value means finding.OrderFile.
+	Order string
+	// GroupBy is one of finding.Groupings and groups text and table
+	// output. An empty value disables grouping.
+	GroupBy string
+	// CodeOwners attributes findings to the owners CODEOWNERS lists for
+	// their file, read from the repository containing the current
+	// directory.
+	CodeOwners bool
+	// Automation notes on findings whether Dependabot or Renovate, as
+	// configured in the repository containing the current directory,
+	// keeps their dependency up to date.
+	Automation bool
+	// PathStyle is one of files.PathStyles and controls how go.mod paths
+	// are printed. An empty value means files.PathStyleNative.
+	PathStyle string
+	// MaxAPICalls caps the number of repositories looked up. Repositories
+	// required directly are looked up first. Zero means no limit.
+	MaxAPICalls int
+	// Timeouts configures network timeouts per host.
+	Timeouts config.Timeouts
+	// Scope limits where manifests are searched for.
+	Scope files.Scope
+	// IgnoreArchivedOwners lists repository owners, such as organisations that
+	// archive repositories and republish them elsewhere, whose findings are
+	// reported as informational. Owners are matched case-insensitively.
+	IgnoreArchivedOwners []string
+	// Baseline lists acknowledged findings, which are reported as baselined
+	// and never fail a run.
+	Baseline baseline.Baseline
+	// Color highlights text output by severity, see render.Options.
+	Color bool
+	// Width is the maximum line width of table output, see render.Options.
+	Width int
+	// Output receives rendered findings, such as a file given with
+	// --output. Nil means os.Stdout.
+	Output io.Writer
+	// Progress receives a status line while repositories are looked up,
+	// see progress.Interactive. Nil disables it.
+	Progress io.Writer
+	// SelfCheck receives warnings about the repository being scanned,
+	// detected from the git checkout of the current directory, if it is
+	// archived or its default branch changed since it was cloned. Nil
+	// disables the check.
+	SelfCheck io.Writer
+	// GoVersion reports go.mod files whose go directive is outside the
+	// supported range. It only applies to Scan.
+	GoVersion GoVersionPolicy
+	// Licenses reports dependencies whose license is missing, disallowed or
+	// copyleft, in addition to their repository's health.
+	Licenses LicensePolicy
+}
+
+func (opts Options) validate() error {
+	if err := render.Validate(opts.Format); err != nil {
+		return err
+	}
+
+	if opts.Order != "" && !slices.Contains(finding.Orders, opts.Order) {
+		return fmt.Errorf("unsupported sort order %q, expected one of: %s", opts.Order, strings.Join(finding.Orders, ", "))
+	}
+
+	if opts.GroupBy != "" && !slices.Contains(finding.Groupings, opts.GroupBy) {
+		return fmt.Errorf("unsupported grouping %q, expected one of: %s", opts.GroupBy, strings.Join(finding.Groupings, ", "))
+	}
+
+	if opts.FromGoSum && (opts.Vendor || opts.ModuleProxy) {
+		return errors.New("reading the build graph from go.sum cannot be combined with vendored modules or the module proxy")
+	}
+
+	if opts.GoList && (opts.Vendor || opts.ModuleProxy || opts.FromGoSum) {
+		return errors.New("resolving modules with go list cannot be combined with vendored modules, the module proxy or go.sum")
+	}
+
+	if opts.PathStyle != "" && !slices.Contains(files.PathStyles, opts.PathStyle) {
+		return fmt.Errorf("unsupported path style %q, expected one of: %s", opts.PathStyle, strings.Join(files.PathStyles, ", "))
+	}
+
+	if err := opts.GoVersion.Validate(); err != nil {
+		return err
+	}
+
+	if opts.SuggestForks < 0 {
+		return fmt.Errorf("number of forks to suggest must not be negative: %d", opts.SuggestForks)
+	}
+
+	return nil
+}
+
+// gitHubHost is the host whose timeouts apply to GitHub lookups.
+const gitHubHost = "github.com"
+
+// mirrorMisreported lists the statuses a mirror can report although the
+// project it mirrors is healthy: mirrors are pushed to by a bot, and often
+// without the license file. They are informational on mirrors whose upstream
+// was not checked. An archived or missing mirror no longer receives updates
+// either way, so those findings are enforced.
+var mirrorMisreported = []status.Status{status.Stale, status.NoLicense}
+
+// NewProvider returns the repository metadata provider named by
+// opts.Provider. ProviderAuto warns when it falls back to git because no
+// GitHub API client can be created, while ProviderGitHub fails.
+func NewProvider(ctx context.Context, opts Options) (client.Provider, error) {
+	clientOpts := gitHubClientOptions(ctx, opts)
+
+	prober := gitprobe.New(ghext.CloneBaseURL(gitHubHost))
+	prober.Timeout = opts.Timeouts.For(gitHubHost).Read
+
+	switch opts.Provider {
+	case ProviderGit:
+		return prober, nil
+	case ProviderGitHub:
+		return NewGitHubClient(ctx, opts)
+	case "", ProviderAuto:
+		c, err := client.NewWithOptions(clientOpts)
+		if err != nil {
+			// The git prober cannot see whether a repository is archived,
+			// so the switch must not go unnoticed.
+			slog.WarnContext(ctx, fmt.Sprintf("github api unavailable, checking repositories with git, which cannot detect archived repositories; %s, or use --provider github to fail instead: %v", ghext.AuthHint(), err))
+
+			return prober, nil
+		}
+
+		return client.Fallback{Primary: c, Secondary: prober}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported provider %q, expected one of: %s", opts.Provider, strings.Join(Providers, ", "))
+}
+
+// NewGitHubClient returns a GitHub API client configured by opts, for lookups
+// that only the API can answer.
+func NewGitHubClient(ctx context.Context, opts Options) (*client.Client, error) {
+	c, err := client.NewWithOptions(gitHubClientOptions(ctx, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github api client, %s: %w", ghext.AuthHint(), err)
+	}
+
+	return c, nil
+}
+
+// gitHubClientOptions returns the options of GitHub API clients.
+func gitHubClientOptions(ctx context.Context, opts Options) client.Options {
+	clientOpts := opts.Client
+	clientOpts.Timeout = opts.Timeouts.For(gitHubHost)
+	clientOpts.Audit = audit.FromContext(ctx)
+
+	return clientOpts
+}
+
+// Scanner checks Go module dependencies for archived and otherwise unhealthy
+// upstream repositories, returns the findings and renders them to Out. It is the entry point for
+// embedding the scan in other Go programs.
+type Scanner struct {
+	Options
+
+	// Out receives rendered findings. Nil means Options.Output, and
+	// io.Discard disables rendering for callers that only want the
+	// returned report.
+	Out io.Writer
+	// Provider looks up repositories. Nil selects one according to
+	// Options.Provider.
+	Provider client.Provider
+	// Forks ranks forks when Options.SuggestForks is set. Nil means the
+	// GitHub API.
+	Forks forks.API
+	// Advisories looks up security advisories when Options.Advisories is
+	// set. Nil means the GitHub API.
+	Advisories advisory.API
+	// Releases looks up latest releases when Options.Details is set. Nil
+	// means the GitHub API.
+	Releases ReleaseAPI
+	// Readmes looks up READMEs of archived repositories for a successor
+	// when Options.Details is set. Nil means the GitHub API.
+	Readmes ReadmeAPI
+	// Tags looks up the tags of required versions. Nil means the GitHub
+	// API.
+	Tags TagAPI
+	// Checked, if set, is called with every repository as soon as it has
+	// been looked up, healthy or not, for callers that show results while
+	// the scan runs. It is called concurrently.
+	Checked func(CheckedRepo)
+	// Unreadable is the number of repositories whose go.mod files could
+	// not all be read before the scan, such as repositories of an
+	// organization, reported as making the report partial.
+	Unreadable int
+}
+
+// CheckedRepo is a repository looked up by a scan, passed to
+// Scanner.Checked.
+type CheckedRepo struct {
+	Repo string
+	// Modules are the module paths resolving to Repo.
+	Modules []string
+	// Requirements are the places Repo is required.
+	Requirements []Requirement
+	// Result is the repository's metadata, which is empty if Err is set.
+	Result client.RepoResult
+	Err    error
+	// Findings are the findings reported for Repo, none if it is healthy.
+	Findings []finding.Finding
+}
+
+// checkedRepo describes repo, required as infos, for Scanner.Checked.
+func checkedRepo(repo string, infos []RepoInfo, result client.RepoResult, err error, findings []finding.Finding) CheckedRepo {
+	checked := CheckedRepo{Repo: repo, Result: result, Err: err, Findings: findings}
+
+	for _, info := range infos {
+		if !slices.Contains(checked.Modules, info.modPath) {
+			checked.Modules = append(checked.Modules, info.modPath)
+		}
+
+		checked.Requirements = append(checked.Requirements, Requirement{info.goModPath, info.line, info.version})
+	}
+
+	return checked
+}
+
+// ReleaseAPI looks up the latest release of a repository. client.Client
+// implements it.
+type ReleaseAPI interface {
+	LatestRelease(ctx context.Context, repo string) (client.Release, error)
+}
+
+// ReadmeAPI looks up the README of a repository. client.Client implements
+// it.
+type ReadmeAPI interface {
+	Readme(ctx context.Context, repo string) ([]byte, error)
+}
+
+// NewScanner creates a Scanner that writes findings to out.
+func NewScanner(opts Options, out io.Writer) *Scanner {
+	return &Scanner{Options: opts, Out: out}
+}
+
+// Repos returns the repositories of the dependencies of every go.mod file in
+// opts.Scope, reading vendor/modules.txt instead where opts.Vendor is set and
+// resolving them through the module proxy where opts.ModuleProxy is.
+func Repos(ctx context.Context, opts Options) (map[string][]RepoInfo, error) {
+	goModFileNames, err := files.RecursiveFind(ctx, opts.Scope, "go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find go.mod files: %w", err)
+	}
+
+	return repos(ctx, opts, goModFileNames)
+}
+
+// repos returns the repositories of the dependencies of the given go.mod
+// files, see Repos. Dependencies imported by tools.go files are marked as
+// tool dependencies, and with opts.ToolsOnly all other dependencies are
+// dropped.
+func repos(ctx context.Context, opts Options, goModFileNames []string) (map[string][]RepoInfo, error) {
+	deps, err := discover(ctx, opts, goModFileNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := markToolsFiles(ctx, opts.Scope, goModFileNames, deps); err != nil {
+		return nil, err
+	}
+
+	if opts.ToolsOnly {
+		for repo, infos := range deps {
+			infos = slices.DeleteFunc(infos, func(info RepoInfo) bool { return !info.tool })
+			if len(infos) == 0 {
+				delete(deps, repo)
+
+				continue
+			}
+
+			deps[repo] = infos
+		}
+	}
+
+	return deps, nil
+}
+
+// discover returns the repositories of the dependencies of the given go.mod
+// files with the discovery mode opts selects.
+func discover(ctx context.Context, opts Options, goModFileNames []string) (map[string][]RepoInfo, error) {
+	if opts.GoList {
+		return discoverWithGoList(ctx, goModFileNames)
+	}
+
+	if opts.FromGoSum {
+		return discoverWithGoSum(ctx, goModFileNames)
+	}
+
+	if opts.Vendor {
+		return discoverWithVendor(ctx, goModFileNames)
+	}
+
+	if opts.ModuleProxy {
+		return DiscoverWithProxy(ctx, goproxy.FromEnv(opts.Timeouts.HTTPClient(goproxy.DefaultURL)), goModFileNames), nil
+	}
+
+	return DiscoverGitHubDependencies(ctx, goModFileNames), nil
+}
+
+// Scan checks the dependencies of every go.mod file beneath the current
+// directory.
+func (s *Scanner) Scan(ctx context.Context) (finding.Report, error) {
+	if err := s.validate(); err != nil {
+		return finding.Report{}, err
+	}
+
+	goModFileNames, err := files.RecursiveFind(ctx, s.Options.Scope, "go.mod")
+	if err != nil {
+		return finding.Report{}, fmt.Errorf("failed to find go.mod files: %w", err)
+	}
+
+	deps, err := repos(ctx, s.Options, goModFileNames)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	var goVersions []finding.Finding
+
+	if s.Options.GoVersion.Enabled() {
+		goVersions, err = s.Options.GoVersion.CheckGoVersions(ctx, goModFileNames)
+		if err != nil {
+			return finding.Report{}, err
+		}
+	}
+
+	if s.Options.OutdatedMajors > 0 {
+		outdated, err := s.outdated(ctx, deps)
+		if err != nil {
+			return finding.Report{}, err
+		}
+
+		goVersions = append(goVersions, outdated...)
+	}
+
+	return s.check(ctx, deps, goVersions)
+}
+
+// ScanBinaries checks the modules compiled into the given Go binaries.
+func (s *Scanner) ScanBinaries(ctx context.Context, paths []string) (finding.Report, error) {
+	if err := s.validate(); err != nil {
+		return finding.Report{}, err
+	}
+
+	repos, err := DiscoverBinaryDependencies(paths)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return s.Check(ctx, repos)
+}
+
+// ListArchived lists archived, missing and otherwise unhealthy Go module
+// repositories according to opts, printing findings to stdout.
+func ListArchived(ctx context.Context, opts Options) (finding.Report, error) {
+	return NewScanner(opts, opts.Output).Scan(ctx)
+}
+
+// prioritize returns the repositories to look up, those required directly
+// first and each group sorted by name. Repositories that are only required
+// indirectly are omitted unless includeIndirect is set.
+func prioritize(repos map[string][]RepoInfo, includeIndirect bool) []string {
+	var direct, indirect []string
+
+	for repo, infos := range repos {
+		onlyIndirect := true
+
+		for _, info := range infos {
+			if !info.indirect {
+				onlyIndirect = false
+
+				break
+			}
+		}
+
+		if onlyIndirect {
+			indirect = append(indirect, repo)
+		} else {
+			direct = append(direct, repo)
+		}
+	}
+
+	slices.Sort(direct)
+
+	// Skip repositories whose every reference is indirect if the user does
+	// not want to include indirect dependencies. This ensures that only
+	// directly required repositories are processed unless indirects are
+	// explicitly requested.
+	if !includeIndirect {
+		return direct
+	}
+
+	slices.Sort(indirect)
+
+	return append(direct, indirect...)
+}
+
+// Check looks up the given repositories, as returned by one of the Discover
+// functions, and renders the resulting report to Out.
+func (s *Scanner) Check(ctx context.Context, repos map[string][]RepoInfo) (finding.Report, error) {
+	return s.check(ctx, repos, nil)
+}
+
+// check is like Check, and also reports the given findings that need no
+// lookup, such as unsupported go versions.
+func (s *Scanner) check(ctx context.Context, repos map[string][]RepoInfo, extra []finding.Finding) (finding.Report, error) {
+	opts := s.Options
+	if err := opts.validate(); err != nil {
+		return finding.Report{}, err
+	}
+
+	out := s.Out
+	if out == nil {
+		out = opts.Output
+	}
+
+	if out == nil {
+		out = os.Stdout
+	}
+
+	report := finding.Report{Findings: []finding.Finding{}, MaxAPICalls: opts.MaxAPICalls, Unreadable: s.Unreadable}
+
+	if len(repos) == 0 && len(extra) == 0 {
+		slog.DebugContext(ctx, "no github.com modules found")
+
+		// An empty report is still rendered, so machine-readable formats
+		// always produce a document to parse.
+		if err := render.RenderWith(out, opts.Format, report, render.Options{Color: opts.Color, Width: opts.Width, Order: opts.Order, GroupBy: opts.GroupBy}); err != nil {
+			return finding.Report{}, fmt.Errorf("failed to render findings: %w", err)
+		}
+
+		return report, nil
+	}
+
+	provider := s.Provider
+	if provider == nil && len(repos) > 0 {
+		var err error
+
+		provider, err = NewProvider(ctx, opts)
+		if err != nil {
+			return finding.Report{}, err
+		}
+	}
+
+	if opts.SelfCheck != nil && provider != nil {
+		checkSelf(ctx, opts.SelfCheck, provider, ".")
+	}
+
+	for _, infos := range repos {
+		for i := range infos {
+			infos[i].goModPath = files.FormatPath(infos[i].goModPath, opts.PathStyle)
+		}
+	}
+
+	var suggest func(RepoInfo) *finding.Alternatives
+	if opts.SuggestAlternatives {
+		suggest = suggestAlternatives(ctx, depsdev.NewWithHTTPClient(opts.Timeouts.HTTPClient(depsdev.DefaultBaseURL), depsdev.DefaultBaseURL))
+	}
+
+	now := time.Now()
+
+	forksAPI, advisoryAPI, releaseAPI, readmeAPI := s.Forks, s.Advisories, s.Releases, s.Readmes
+
+	if (opts.SuggestForks > 0 && forksAPI == nil) || (opts.Advisories && advisoryAPI == nil) || (opts.Details && (releaseAPI == nil || readmeAPI == nil)) {
+		c, err := NewGitHubClient(ctx, opts)
+		if err != nil {
+			return finding.Report{}, err
+		}
+
+		if forksAPI == nil {
+			forksAPI = c
+		}
+
+		if advisoryAPI == nil {
+			advisoryAPI = c
+		}
+
+		if releaseAPI == nil {
+			releaseAPI = c
+		}
+
+		if readmeAPI == nil {
+			readmeAPI = c
+		}
+	}
+
+	var rankForks func(repo string) []forks.Candidate
+
+	if opts.SuggestForks > 0 {
+		api := forksAPI
+
+		rankForks = func(repo string) []forks.Candidate {
+			candidates, err := forks.Rank(ctx, api, repo, opts.SuggestForks, now)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error ranking forks of %s: %v", repo, err))
+			}
+
+			return candidates
+		}
+	}
+
+	ordered := prioritize(repos, opts.Indirect)
+
+	if len(ordered) < len(repos) {
+		for repo := range repos {
+			if !slices.Contains(ordered, repo) {
+				audit.Record(ctx, audit.RepoSkipped, repo, "only required indirectly and --indirect is not set")
+			}
+		}
+	}
+
+	if opts.MaxAPICalls > 0 && len(ordered) > opts.MaxAPICalls {
+		report.Unchecked = len(ordered) - opts.MaxAPICalls
+
+		for _, repo := range ordered[opts.MaxAPICalls:] {
+			audit.Record(ctx, audit.RepoSkipped, repo, fmt.Sprintf("api call budget of %d exhausted", opts.MaxAPICalls))
+		}
+
+		ordered = ordered[:opts.MaxAPICalls]
+
+		slog.DebugContext(ctx, fmt.Sprintf("api call budget of %d exhausted, skipping %d repositories", opts.MaxAPICalls, report.Unchecked))
+	}
+
+	var (
+		wg sync.WaitGroup
+		// degraded counts the repositories only git could check.
+		degraded atomic.Int64
+	)
+
+	collected := output.Start[finding.Finding](progress.New(opts.Progress, len(ordered)))
+
+	for _, repo := range ordered {
+		infos := repos[repo]
+
+		wg.Add(1)
+
+		go func(repo string, infos []RepoInfo) {
+			defer wg.Done()
+
+			// statuses holds the health of the repository, if it is
+			// unhealthy, and its license status, if that is reported.
+			var statuses []status.Status
+
+			result, err := provider.GetRepoResult(ctx, repo)
+
+			collected.Done(repo)
+
+			if err == nil && result.Degraded {
+				degraded.Add(1)
+			}
+
+			var repoFindings []finding.Finding
+
+			if s.Checked != nil {
+				defer func() { s.Checked(checkedRepo(repo, infos, result, err, repoFindings)) }()
+			}
+
+			// checked is the repository result describes, which is the
+			// upstream of a mirror resolved with opts.ResolveMirrors.
+			checked := repo
+
+			upstream, isMirror := "", false
+
+			if err == nil {
+				upstream, isMirror = mirror.Detect(repo, result)
+			}
+
+			if upstreamRepo, ok := mirror.GitHubUpstream(repo, upstream); ok && opts.ResolveMirrors {
+				upstreamResult, upstreamErr := provider.GetRepoResult(ctx, upstreamRepo)
+				if upstreamErr == nil {
+					checked, result = upstreamRepo, upstreamResult
+				} else {
+					slog.DebugContext(ctx, fmt.Sprintf("error fetching upstream %s of mirror %s: %v", upstreamRepo, repo, upstreamErr))
+				}
+			}
+
+			// Unless its upstream was checked instead, the activity and
+			// license of a mirror say little about the project it mirrors.
+			unresolvedMirror := isMirror && checked == repo
+
+			switch {
+			case errors.Is(err, client.ErrRepoNotFound):
+				statuses = append(statuses, status.Missing)
+			case err != nil:
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching repo %s: %v", repo, err))
+
+				statuses = append(statuses, status.Unknown)
+			default:
+				if st, found := classify(checked, result, opts.StaleAfter, now); found {
+					statuses = append(statuses, st)
+				}
+
+				if st, found := opts.Licenses.classify(result); found {
+					statuses = append(statuses, st)
+				}
+			}
+
+			switch {
+			case len(statuses) == 0:
+				audit.Record(ctx, audit.RepoChecked, repo, "healthy")
+
+				return
+			case err != nil:
+				audit.Record(ctx, audit.RepoChecked, repo, fmt.Sprintf("%s: %v", statuses[0], err))
+			default:
+				audit.Record(ctx, audit.RepoChecked, repo, statuses[0].String())
+			}
+
+			var details *finding.Details
+
+			if opts.Details && err == nil && !result.Degraded {
+				details = repoDetails(ctx, releaseAPI, repo, &result)
+			}
+
+			var successor string
+
+			if err == nil && slices.Contains(statuses, status.Archived) {
+				var readmes ReadmeAPI
+
+				if opts.Details && !result.Degraded {
+					readmes = readmeAPI
+				}
+
+				successor = repoSuccessor(ctx, readmes, repo, result)
+			}
+
+			var candidates []forks.Candidate
+
+			if rankForks != nil && slices.Contains(statuses, status.Archived) {
+				candidates = rankForks(repo)
+			}
+
+			var advisories *advisoryLookup
+
+			if opts.Advisories && slices.Contains(statuses, status.Archived) {
+				advisories = newAdvisoryLookup(ctx, advisoryAPI)
+			}
+
+			for _, info := range infos {
+				if !opts.Indirect && info.indirect {
+					continue
+				}
+
+				for _, st := range statuses {
+					f := finding.Finding{
+						Module:   info.modPath,
+						Version:  info.version,
+						Repo:     repo,
+						File:     info.goModPath,
+						Line:     info.line,
+						Column:   info.column,
+						Indirect: info.indirect,
+						Tool:     info.tool,
+						Status:   st,
+						Archived: result.Archived,
+						PushedAt: result.PushedAt,
+						Reason:   reason(st, result, opts.StaleAfter),
+						Metadata: result,
+					}
+
+					if st == status.Unknown {
+						f.Reason += ": " + err.Error()
+					}
+
+					if result.FullName != "" && !result.Degraded && !strings.EqualFold(result.FullName, checked) {
+						f.Canonical = result.FullName
+					}
+
+					if st == status.Moved && f.OutdatedPath() {
+						f.Reason += ", module path is out of date"
+					}
+
+					if isMirror {
+						f.Mirror = true
+						f.Upstream = upstream
+					}
+
+					ignoredOwner := false
+
+					if owner, _, _ := strings.Cut(repo, "/"); slices.ContainsFunc(opts.IgnoreArchivedOwners, func(ignored string) bool {
+						return strings.EqualFold(ignored, owner)
+					}) {
+						ignoredOwner = true
+					}
+
+					misreported := unresolvedMirror && slices.Contains(mirrorMisreported, st)
+
+					f.Informational = ignoredOwner || misreported
+					f.Baselined = opts.Baseline.Contains(f)
+
+					switch {
+					case ignoredOwner:
+						audit.Record(ctx, audit.FindingExempt, repo+" in "+f.File, "owner is listed in --ignore-archived-owners")
+					case misreported:
+						audit.Record(ctx, audit.FindingExempt, repo+" in "+f.File, "repository is a read-only mirror")
+					case f.Baselined:
+						audit.Record(ctx, audit.FindingExempt, repo+" in "+f.File, "listed in the baseline")
+					}
+
+					if st == status.Archived {
+						f.Successor = successor
+					}
+
+					if st == status.Archived && suggest != nil {
+						f.Alternatives = suggest(info)
+					}
+
+					if st == status.Archived && len(candidates) > 0 {
+						if f.Alternatives == nil {
+							f.Alternatives = &finding.Alternatives{}
+						}
+
+						f.Alternatives.Forks = candidates
+					}
+
+					f.Details = details
+
+					if st == status.Archived && advisories != nil {
+						f.Advisories = advisories.affecting(info)
+					}
+
+					collected.Add(f)
+
+					repoFindings = append(repoFindings, f)
+				}
+			}
+		}(repo, infos)
+	}
+
+	wg.Wait()
+
+	findings := collected.Close()
+
+	// Lookups cancelled with ctx would otherwise be reported as unknown.
+	if ctx.Err() != nil {
+		return finding.Report{}, fmt.Errorf("scan interrupted: %w", context.Cause(ctx))
+	}
+
+	report.Findings = append(report.Findings, findings...)
+	report.Degraded = int(degraded.Load())
+
+	for _, f := range extra {
+		f.File = files.FormatPath(f.File, opts.PathStyle)
+		f.Baselined = opts.Baseline.Contains(f)
+
+		if f.Baselined {
+			audit.Record(ctx, audit.FindingExempt, f.Repo+" in "+f.File, "listed in the baseline")
+		}
+
+		report.Findings = append(report.Findings, f)
+	}
+
+	if opts.CodeOwners {
+		if err := attributeCodeOwners(ctx, report.Findings); err != nil {
+			return finding.Report{}, err
+		}
+	}
+
+	if opts.Automation {
+		if err := attributeAutomation(ctx, report.Findings); err != nil {
+			return finding.Report{}, err
+		}
+	}
+
+	finding.SortBy(report.Findings, opts.Order)
+
+	if err := render.RenderWith(out, opts.Format, report, render.Options{Color: opts.Color, Width: opts.Width, Order: opts.Order, GroupBy: opts.GroupBy}); err != nil {
+		return finding.Report{}, fmt.Errorf("failed to render findings: %w", err)
+	}
+
+	return report, nil
+}
+
+// attributeCodeOwners sets the code owners of findings in files of the
+// repository containing the current directory. Findings without a file, or
+// named after a file that does not exist locally such as those of remote
+// repositories, are left unowned.
+func attributeCodeOwners(ctx context.Context, findings []finding.Finding) error {
+	owners, err := codeowners.Load(ctx, ".")
+	if err != nil {
+		return fmt.Errorf("failed to load code owners: %w", err)
+	}
+
+	for i, f := range findings {
+		if f.File == "" {
+			continue
+		}
+
+		if _, err := os.Stat(f.File); err != nil {
+			continue
+		}
+
+		findings[i].CodeOwners = owners.Owners(f.File)
+	}
+
+	return nil
+}
+
+// attributeAutomation notes whether update automation covers the dependency
+// of each finding in a file of the repository containing the current
+// directory. Findings without a file, or named after a file that does not
+// exist locally, are left unannotated.
+func attributeAutomation(ctx context.Context, findings []finding.Finding) error {
+	config, err := automation.Load(ctx, ".")
+	if err != nil {
+		return fmt.Errorf("failed to load update automation: %w", err)
+	}
+
+	for i, f := range findings {
+		if f.File == "" {
+			continue
+		}
+
+		if _, err := os.Stat(f.File); err != nil {
+			continue
+		}
+
+		findings[i].Automation = config.Coverage(f.File, f.Module)
+	}
+
+	return nil
+}
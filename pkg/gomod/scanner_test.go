@@ -0,0 +1,698 @@
+package gomod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
+	"github.com/wayneashleyberry/gh-arc/pkg/baseline"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/freshness"
+	"github.com/wayneashleyberry/gh-arc/pkg/policy"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// mockProvider answers lookups from a fixed map of results.
+type mockProvider map[string]client.RepoResult
+
+func (m mockProvider) GetRepoResult(_ context.Context, repo string) (client.RepoResult, error) {
+	result, ok := m[repo]
+	if !ok {
+		return client.RepoResult{}, errors.New("unexpected repo " + repo)
+	}
+
+	return result, nil
+}
+
+func TestScanner_Check(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/archived": {{false, "go.mod", 4, 2, "github.com/owner/archived", "v1.0.0", false}},
+		"owner/healthy":  {{false, "go.mod", 5, 2, "github.com/owner/healthy", "v1.0.0", false}},
+		"owner/indirect": {{true, "go.mod", 6, 2, "github.com/owner/indirect", "v1.0.0", false}},
+		"owner/broken":   {{false, "go.mod", 7, 2, "github.com/owner/broken", "v1.0.0", false}},
+	}
+
+	var buf bytes.Buffer
+
+	s := NewScanner(Options{Format: render.FormatText}, &buf)
+	s.Provider = mockProvider{
+		"owner/archived": {Archived: true, FullName: "owner/archived", PushedAt: "2020-01-01T00:00:00Z"},
+		"owner/healthy":  {FullName: "owner/healthy", PushedAt: "2025-01-01T00:00:00Z"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Equal(t, status.Counts{status.Archived: 1, status.Unknown: 1}, result.Counts())
+	require.Equal(t, status.Counts{status.Archived: 1, status.Unknown: 1}, result.DirectCounts())
+	require.Len(t, result.Findings, 2)
+	require.Equal(t, "github.com/owner/archived", result.Findings[0].Module)
+	require.Equal(t, 4, result.Findings[0].Line)
+	require.Equal(t, 2, result.Findings[0].Column)
+	require.Equal(t, "repository archived", result.Findings[0].Reason)
+	require.Equal(t, status.Unknown, result.Findings[1].Status)
+	require.Equal(t, "could not be checked: unexpected repo owner/broken", result.Findings[1].Reason)
+	require.Equal(t, "go.mod: https://github.com/owner/archived (last push: 2020-01-01T00:00:00Z)\n"+
+		"go.mod: https://github.com/owner/broken (could not be checked: unexpected repo owner/broken)\n\n1 archived, 1 unknown\n", buf.String())
+}
+
+// countingREST answers every repository request of a GitHub API client with
+// an archived repository, counting the requests.
+type countingREST struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (c *countingREST) DoWithContext(_ context.Context, _, path string, _ io.Reader, resp any) error {
+	c.mu.Lock()
+	c.requests++
+	c.mu.Unlock()
+
+	*resp.(*client.RepoResult) = client.RepoResult{FullName: strings.TrimPrefix(path, "repos/"), Archived: true}
+
+	return nil
+}
+
+func TestScanner_Scan_SharedProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTempFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/owner/archived v1.0.0\n")
+
+	rest := &countingREST{}
+	provider := client.NewWithClient(rest)
+
+	scan := func() finding.Report {
+		s := NewScanner(Options{Format: render.FormatJSON, Scope: files.Scope{Paths: []string{dir}}}, io.Discard)
+		s.Provider = provider
+
+		report, err := s.Scan(context.Background())
+		require.NoError(t, err)
+
+		return report
+	}
+
+	require.Equal(t, status.Counts{status.Archived: 1}, scan().Counts())
+	require.Equal(t, 1, rest.requests)
+
+	// Scanning again, as watch mode does, is answered from the cache.
+	require.Equal(t, status.Counts{status.Archived: 1}, scan().Counts())
+	require.Equal(t, 1, rest.requests)
+}
+
+func TestScanner_Check_Checked(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/archived": {
+			{false, "go.mod", 4, 2, "github.com/owner/archived", "v1.0.0", false},
+			{false, "sub/go.mod", 5, 2, "github.com/owner/archived", "v1.1.0", false},
+		},
+		"owner/healthy": {{false, "go.mod", 5, 2, "github.com/owner/healthy/v2", "v2.0.0", false}},
+	}
+
+	var (
+		mu      sync.Mutex
+		checked = map[string]CheckedRepo{}
+	)
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/archived": {Archived: true, FullName: "owner/archived"},
+		"owner/healthy":  {FullName: "owner/healthy"},
+	}
+	s.Checked = func(c CheckedRepo) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		checked[c.Repo] = c
+	}
+
+	_, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, checked, 2)
+
+	require.Equal(t, []string{"github.com/owner/archived"}, checked["owner/archived"].Modules)
+	require.Equal(t, []Requirement{{"go.mod", 4, "v1.0.0"}, {"sub/go.mod", 5, "v1.1.0"}}, checked["owner/archived"].Requirements)
+	require.Len(t, checked["owner/archived"].Findings, 2)
+	require.True(t, checked["owner/archived"].Result.Archived)
+
+	require.Equal(t, []string{"github.com/owner/healthy/v2"}, checked["owner/healthy"].Modules)
+	require.Empty(t, checked["owner/healthy"].Findings)
+	require.NoError(t, checked["owner/healthy"].Err)
+}
+
+func TestScanner_Check_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("interrupted"))
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{}
+
+	_, err := s.Check(ctx, map[string][]RepoInfo{
+		"owner/repo": {{false, "go.mod", 4, 2, "github.com/owner/repo", "v1.0.0", false}},
+	})
+	require.EqualError(t, err, "scan interrupted: interrupted")
+}
+
+func TestScanner_Check_Output(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	s := NewScanner(Options{Format: render.FormatJSON, Output: &buf}, nil)
+
+	result, err := s.Check(context.Background(), map[string][]RepoInfo{})
+	require.NoError(t, err)
+	require.Empty(t, result.Findings)
+
+	var report finding.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report), "an empty report is still rendered")
+	require.Empty(t, report.Findings)
+}
+
+func TestScanner_Check_Degraded(t *testing.T) {
+	t.Parallel()
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/git": {FullName: "owner/git", PushedAt: time.Now().Format(time.RFC3339), Degraded: true},
+		"owner/api": {FullName: "owner/api", PushedAt: time.Now().Format(time.RFC3339)},
+	}
+
+	result, err := s.Check(context.Background(), map[string][]RepoInfo{
+		"owner/git": {{false, "go.mod", 4, 2, "github.com/owner/git", "v1.0.0", false}},
+		"owner/api": {{false, "go.mod", 5, 2, "github.com/owner/api", "v1.0.0", false}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Findings)
+	require.Equal(t, 1, result.Degraded)
+	require.Contains(t, result.PartialNote(), "1 repositories checked with git")
+}
+
+func TestScanner_Check_IgnoreArchivedOwners(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"Reorg/archived": {{false, "go.mod", 4, 2, "github.com/Reorg/archived", "v1.0.0", false}},
+		"owner/archived": {{false, "go.mod", 5, 2, "github.com/owner/archived", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText, IgnoreArchivedOwners: []string{"reorg"}}, io.Discard)
+	s.Provider = mockProvider{
+		"Reorg/archived": {Archived: true, FullName: "Reorg/archived"},
+		"owner/archived": {Archived: true, FullName: "owner/archived"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+	require.True(t, result.Findings[0].Informational)
+	require.False(t, result.Findings[1].Informational)
+	require.Equal(t, status.Counts{status.Archived: 1}, result.Enforced().Counts())
+}
+
+func TestScanner_Check_Baseline(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/known": {{false, "go.mod", 4, 2, "github.com/owner/known", "v1.0.0", false}},
+		"owner/new":   {{false, "go.mod", 5, 2, "github.com/owner/new", "v1.0.0", false}},
+	}
+
+	b := baseline.Baseline{Findings: []baseline.Entry{
+		{Repo: "owner/known", Module: "github.com/owner/known", File: "go.mod", Status: status.Archived},
+	}}
+
+	s := NewScanner(Options{Format: render.FormatText, Baseline: b}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/known": {Archived: true, FullName: "owner/known"},
+		"owner/new":   {Archived: true, FullName: "owner/new"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+	require.True(t, result.Findings[0].Baselined)
+	require.False(t, result.Findings[1].Baselined)
+	require.Equal(t, status.Counts{status.Archived: 1}, result.Enforced().Counts())
+}
+
+func TestScanner_Check_Audit(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/archived": {{false, "go.mod", 4, 2, "github.com/owner/archived", "v1.0.0", false}},
+		"owner/healthy":  {{false, "go.mod", 5, 2, "github.com/owner/healthy", "v1.0.0", false}},
+		"owner/indirect": {{true, "go.mod", 6, 2, "github.com/owner/indirect", "v1.0.0", false}},
+	}
+
+	var buf bytes.Buffer
+
+	ctx := audit.WithLog(context.Background(), audit.New(&buf))
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/archived": {Archived: true, FullName: "owner/archived"},
+		"owner/healthy":  {FullName: "owner/healthy", PushedAt: time.Now().Format(time.RFC3339)},
+	}
+
+	_, err := s.Check(ctx, repos)
+	require.NoError(t, err)
+
+	var events []string
+
+	for line := range strings.Lines(buf.String()) {
+		var e audit.Event
+
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+
+		events = append(events, e.Kind+" "+e.Subject+": "+e.Reason)
+	}
+
+	require.ElementsMatch(t, []string{
+		"repo-skipped owner/indirect: only required indirectly and --indirect is not set",
+		"repo-checked owner/archived: archived",
+		"repo-checked owner/healthy: healthy",
+	}, events)
+}
+
+func TestScanner_Freshness(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/fresh":    {{false, "go.mod", 4, 2, "github.com/owner/fresh", "v1.0.0", false}},
+		"owner/gone":     {{false, "go.mod", 5, 2, "github.com/owner/gone", "v1.0.0", false}},
+		"owner/indirect": {{true, "go.mod", 6, 2, "github.com/owner/indirect", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/fresh":    {FullName: "owner/fresh", PushedAt: time.Now().Add(-48 * time.Hour).Format(time.RFC3339)},
+		"owner/indirect": {FullName: "owner/indirect", PushedAt: time.Now().Format(time.RFC3339)},
+	}
+
+	h, err := s.Freshness(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, h.Dependencies, 2)
+	require.Equal(t, "<30d", h.Dependencies[0].Bucket)
+	require.Equal(t, 2, h.Dependencies[0].AgeDays)
+	require.Equal(t, freshness.Unknown, h.Dependencies[1].Bucket)
+	require.Equal(t, []freshness.Row{{File: "go.mod", Counts: []int{1, 0, 0, 0, 0, 0, 1}}}, h.Files)
+}
+
+func TestScanner_Check_Licenses(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/archived": {{false, "go.mod", 4, 2, "github.com/owner/archived", "v1.0.0", false}},
+		"owner/mit":      {{false, "go.mod", 5, 2, "github.com/owner/mit", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText, Licenses: LicensePolicy{Enabled: true, Disallowed: []string{"GPL-3.0-only"}}}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/archived": {Archived: true, FullName: "owner/archived", License: &client.License{SPDXID: "GPL-3.0-only"}},
+		"owner/mit":      {FullName: "owner/mit", License: &client.License{SPDXID: "MIT"}},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+	require.Equal(t, status.Counts{status.Archived: 1, status.DisallowedLicense: 1}, result.Counts())
+	require.Equal(t, "license GPL-3.0-only is disallowed", result.Findings[1].Reason)
+}
+
+// staticForks is a forks.API with a single fork without any history.
+type staticForks struct{}
+
+func (staticForks) Forks(context.Context, string, int) ([]client.Fork, error) {
+	return []client.Fork{{FullName: "fork/repo", Stars: 3}}, nil
+}
+
+func (staticForks) CommitsSince(context.Context, string, time.Time) (int, error) { return 0, nil }
+
+func (staticForks) ClosedPullRequests(context.Context, string, int) ([]client.PullRequest, error) {
+	return nil, nil
+}
+
+func (staticForks) Releases(context.Context, string, int) ([]client.Release, error) { return nil, nil }
+
+func TestScanner_Check_SuggestForks(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/archived": {{false, "go.mod", 4, 2, "github.com/owner/archived", "v1.0.0", false}},
+		"owner/stale":    {{false, "go.mod", 5, 2, "github.com/owner/stale", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText, SuggestForks: 3, StaleAfter: time.Hour}, io.Discard)
+	s.Forks = staticForks{}
+	s.Provider = mockProvider{
+		"owner/archived": {Archived: true, FullName: "owner/archived"},
+		"owner/stale":    {FullName: "owner/stale", PushedAt: "2020-01-01T00:00:00Z"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+	require.Len(t, result.Findings[0].Alternatives.Forks, 1)
+	require.Equal(t, "fork/repo", result.Findings[0].Alternatives.Forks[0].Repo)
+	require.Nil(t, result.Findings[1].Alternatives)
+}
+
+// staticAdvisories is an advisory.API with one advisory affecting versions
+// before v1.2.0 of every package.
+type staticAdvisories struct{}
+
+func (staticAdvisories) Vulnerabilities(context.Context, string, string) ([]client.Vulnerability, error) {
+	var v client.Vulnerability
+
+	v.Advisory.GHSAID = "GHSA-aaaa-bbbb-cccc"
+	v.Advisory.Severity = "HIGH"
+	v.VulnerableVersionRange = "< 1.2.0"
+
+	return []client.Vulnerability{v}, nil
+}
+
+func TestScanner_Check_Advisories(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/archived": {
+			{true, "go.mod", 4, 2, "github.com/owner/archived", "v1.0.0", false},
+			{true, "other/go.mod", 4, 2, "github.com/owner/archived", "v1.2.0", false},
+		},
+		"owner/stale": {{false, "tools/go.mod", 5, 2, "github.com/owner/stale", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText, Indirect: true, Advisories: true, StaleAfter: time.Hour}, io.Discard)
+	s.Advisories = staticAdvisories{}
+	s.Provider = mockProvider{
+		"owner/archived": {Archived: true, FullName: "owner/archived"},
+		"owner/stale":    {FullName: "owner/stale", PushedAt: "2020-01-01T00:00:00Z"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 3)
+
+	vulnerable := result.Findings[0]
+	require.Equal(t, "go.mod", vulnerable.File)
+	require.Len(t, vulnerable.Advisories, 1)
+	require.Equal(t, "high", vulnerable.Advisories[0].Severity)
+	require.Equal(t, finding.SeverityError, vulnerable.Severity(), "indirect findings with advisories are errors")
+
+	patched := result.Findings[1]
+	require.Equal(t, "other/go.mod", patched.File)
+	require.Empty(t, patched.Advisories)
+	require.Equal(t, finding.SeverityWarning, patched.Severity())
+
+	require.Equal(t, status.Stale, result.Findings[2].Status)
+	require.Empty(t, result.Findings[2].Advisories)
+}
+
+// staticReleases is a ReleaseAPI where only owner/released has a release.
+type staticReleases struct{}
+
+func (staticReleases) LatestRelease(_ context.Context, repo string) (client.Release, error) {
+	if repo != "owner/released" {
+		return client.Release{}, client.ErrNoRelease
+	}
+
+	return client.Release{TagName: "v1.2.3", PublishedAt: "2024-01-02T03:04:05Z"}, nil
+}
+
+func TestScanner_Check_Details(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/released":   {{false, "go.mod", 4, 2, "github.com/owner/released", "v1.0.0", false}},
+		"owner/unreleased": {{false, "go.mod", 5, 2, "github.com/owner/unreleased", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText, Details: true}, io.Discard)
+	s.Releases = staticReleases{}
+	s.Readmes = staticReadmes{}
+	s.Provider = mockProvider{
+		"owner/released":   {Archived: true, FullName: "owner/released", OpenIssues: 12, DefaultBranch: "main", Description: "Released things", Topics: []string{"go", "cli"}},
+		"owner/unreleased": {Archived: true, FullName: "owner/unreleased", DefaultBranch: "master"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+	require.Equal(t, &finding.Details{OpenIssues: 12, DefaultBranch: "main", LatestRelease: "v1.2.3", LatestReleaseAt: "2024-01-02T03:04:05Z", Description: "Released things", Topics: []string{"go", "cli"}}, result.Findings[0].Details)
+	require.Equal(t, "v1.2.3", result.Findings[0].Metadata.LatestRelease.TagName)
+	require.Empty(t, result.Findings[0].Successor)
+	require.Equal(t, &finding.Details{DefaultBranch: "master"}, result.Findings[1].Details)
+	require.Equal(t, "owner/next", result.Findings[1].Successor, "read from the README with --details")
+}
+
+// staticReadmes is a ReadmeAPI where only owner/unreleased names a
+// successor.
+type staticReadmes struct{}
+
+func (staticReadmes) Readme(_ context.Context, repo string) ([]byte, error) {
+	if repo != "owner/unreleased" {
+		return nil, client.ErrRepoNotFound
+	}
+
+	return []byte("# unreleased\n\nThis project has moved to https://github.com/owner/next.\n"), nil
+}
+
+func TestScanner_Check_Successor(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"owner/described": {{false, "go.mod", 4, 2, "github.com/owner/described", "v1.0.0", false}},
+		"owner/homepage":  {{false, "go.mod", 5, 2, "github.com/owner/homepage", "v1.0.0", false}},
+		"owner/readme":    {{false, "go.mod", 6, 2, "github.com/owner/readme", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{
+		"owner/described": {Archived: true, FullName: "owner/described", Description: "Deprecated: superseded by owner/better"},
+		"owner/homepage":  {Archived: true, FullName: "owner/homepage", Homepage: "https://github.com/owner/home"},
+		"owner/readme":    {Archived: true, FullName: "owner/readme"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 3)
+	require.Equal(t, "owner/better", result.Findings[0].Successor)
+	require.Equal(t, "owner/home", result.Findings[1].Successor)
+	require.Empty(t, result.Findings[2].Successor, "READMEs are only read with --details")
+}
+
+func TestScanner_Check_Renamed(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"old/archived": {{false, "go.mod", 4, 2, "github.com/old/archived", "v1.0.0", false}},
+		"old/moved":    {{false, "go.mod", 5, 2, "github.com/old/moved", "v1.0.0", false}},
+		"Old/Cased":    {{false, "go.mod", 6, 2, "github.com/Old/Cased", "v1.0.0", false}},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{
+		"old/archived": {Archived: true, FullName: "new/archived"},
+		"old/moved":    {FullName: "new/moved"},
+		"Old/Cased":    {Archived: true, FullName: "old/cased"},
+	}
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 3)
+
+	require.Equal(t, status.Archived, result.Findings[0].Status)
+	require.Equal(t, "new/archived", result.Findings[0].Canonical)
+
+	require.Equal(t, status.Moved, result.Findings[1].Status)
+	require.Equal(t, "new/moved", result.Findings[1].Canonical)
+	require.Equal(t, "moved to new/moved, module path is out of date", result.Findings[1].Reason)
+
+	require.Empty(t, result.Findings[2].Canonical, "names only differing in case are the same repository")
+}
+
+func TestScanner_Check_Mirrors(t *testing.T) {
+	t.Parallel()
+
+	repos := map[string][]RepoInfo{
+		"golang/old":    {{false, "go.mod", 4, 2, "github.com/golang/old", "v1.0.0", false}},
+		"acme/mirrored": {{false, "go.mod", 5, 2, "github.com/acme/mirrored", "v1.0.0", false}},
+	}
+
+	provider := mockProvider{
+		"golang/old":    {FullName: "golang/old", Description: "[mirror] Old things", PushedAt: "2020-01-01T00:00:00Z"},
+		"acme/mirrored": {FullName: "acme/mirrored", Description: "Read-only mirror of https://github.com/upstream/lib", PushedAt: "2020-01-01T00:00:00Z"},
+		"upstream/lib":  {FullName: "upstream/lib", PushedAt: time.Now().Format(time.RFC3339)},
+	}
+
+	s := NewScanner(Options{Format: render.FormatText, StaleAfter: 24 * time.Hour}, io.Discard)
+	s.Provider = provider
+
+	result, err := s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+
+	for _, f := range result.Findings {
+		require.Equal(t, status.Stale, f.Status, f.Repo)
+		require.True(t, f.Mirror, f.Repo)
+		require.True(t, f.Informational, f.Repo)
+	}
+
+	require.Equal(t, "https://go.googlesource.com/old", result.Findings[0].Upstream)
+	require.Equal(t, "https://github.com/upstream/lib", result.Findings[1].Upstream)
+
+	s = NewScanner(Options{Format: render.FormatText, StaleAfter: 24 * time.Hour, ResolveMirrors: true}, io.Discard)
+	s.Provider = provider
+
+	result, err = s.Check(context.Background(), repos)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1, "the healthy upstream is checked instead of the stale mirror")
+	require.Equal(t, "golang/old", result.Findings[0].Repo)
+	require.True(t, result.Findings[0].Informational, "upstreams outside GitHub cannot be checked")
+}
+
+func TestScanner_Check_ArchivedMirror(t *testing.T) {
+	t.Parallel()
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Provider = mockProvider{
+		"golang/old": {Archived: true, FullName: "golang/old", Description: "[mirror] Old things"},
+	}
+
+	result, err := s.Check(context.Background(), map[string][]RepoInfo{
+		"golang/old": {{false, "go.mod", 4, 2, "github.com/golang/old", "v1.0.0", false}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	require.True(t, result.Findings[0].Mirror)
+	require.False(t, result.Findings[0].Informational, "an archived mirror receives no updates either")
+
+	enforced := result.Enforced()
+	require.True(t, policy.Policy{}.Failed(enforced.Counts(), enforced.DirectCounts()))
+}
+
+// staticTags is a TagAPI where only v1.0.0 is tagged.
+type staticTags struct{}
+
+func (staticTags) TagExists(_ context.Context, _, tag string) (bool, error) {
+	return tag == "v1.0.0", nil
+}
+
+func (staticTags) Tags(context.Context, string) ([]string, error) {
+	return []string{"v1.0.0"}, nil
+}
+
+func TestScanner_CheckModule(t *testing.T) {
+	t.Parallel()
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Tags = staticTags{}
+	s.Provider = mockProvider{
+		"owner/repo":   {FullName: "owner/repo", PushedAt: time.Now().Format(time.RFC3339)},
+		"go-yaml/yaml": {Archived: true, FullName: "go-yaml/yaml"},
+	}
+
+	result, err := s.CheckModule(context.Background(), "github.com/owner/repo", "v1.0.0")
+	require.NoError(t, err)
+	require.Empty(t, result.Findings)
+
+	result, err = s.CheckModule(context.Background(), "github.com/owner/repo", "v1.0.1")
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	require.Equal(t, status.MissingVersion, result.Findings[0].Status)
+	require.Equal(t, "tag v1.0.1 no longer exists", result.Findings[0].Reason)
+
+	result, err = s.CheckModule(context.Background(), "github.com/owner/repo", "v0.0.0-20240101000000-abcdefabcdef")
+	require.NoError(t, err)
+	require.Empty(t, result.Findings, "pseudo-versions are not tagged")
+
+	result, err = s.CheckModule(context.Background(), "gopkg.in/yaml.v3", "")
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	require.Equal(t, "go-yaml/yaml", result.Findings[0].Repo)
+	require.Equal(t, status.Archived, result.Findings[0].Status)
+}
+
+func TestScanner_verify(t *testing.T) {
+	t.Parallel()
+
+	s := NewScanner(Options{Format: render.FormatText}, io.Discard)
+	s.Tags = staticTags{}
+
+	result, err := s.verify(context.Background(), map[string][]RepoInfo{
+		"owner/repo": {
+			{false, "go.mod", 4, 2, "github.com/owner/repo", "v1.0.0", false},
+			{false, "tools/go.mod", 5, 2, "github.com/owner/repo", "v1.0.1", false},
+			{false, "go.mod", 6, 2, "github.com/owner/repo/sub", "v1.0.0", false},
+			{true, "go.mod", 7, 2, "github.com/owner/repo", "v1.0.2", false},
+			{false, "go.mod", 8, 2, "github.com/owner/repo", "v0.0.0-20240101000000-abcdefabcdef", false},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+
+	require.Equal(t, "go.mod", result.Findings[0].File)
+	require.Equal(t, "github.com/owner/repo/sub", result.Findings[0].Module)
+	require.Equal(t, "tag sub/v1.0.0 no longer exists", result.Findings[0].Reason)
+
+	require.Equal(t, "tools/go.mod", result.Findings[1].File)
+	require.Equal(t, 5, result.Findings[1].Line)
+	require.Equal(t, status.MissingVersion, result.Findings[1].Status)
+	require.Equal(t, "tag v1.0.1 no longer exists", result.Findings[1].Reason)
+}
+
+// manyTags is a TagAPI whose repositories have tags up to v4 and a
+// pre-release of v5.
+type manyTags struct {
+	staticTags
+}
+
+func (manyTags) Tags(context.Context, string) ([]string, error) {
+	return []string{"v0.9.0", "v1.0.0", "v2.3.0", "v3.0.0", "v4.1.0", "v5.0.0-rc.1", "sub/v1.0.0", "sub/v1.2.0"}, nil
+}
+
+func TestScanner_outdated(t *testing.T) {
+	t.Parallel()
+
+	s := NewScanner(Options{Format: render.FormatText, OutdatedMajors: 2}, io.Discard)
+	s.Tags = manyTags{}
+
+	findings, err := s.outdated(context.Background(), map[string][]RepoInfo{
+		"owner/repo": {
+			{false, "go.mod", 4, 2, "github.com/owner/repo", "v1.0.0", false},
+			{false, "go.mod", 5, 2, "github.com/owner/repo/v3", "v3.0.0", false},
+			{false, "go.mod", 6, 2, "github.com/owner/repo/sub", "v0.1.0", false},
+			{false, "go.mod", 7, 2, "github.com/owner/repo", "v0.0.0-20200101000000-abcdefabcdef", false},
+			{true, "go.mod", 8, 2, "github.com/owner/repo", "v1.0.0", false},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	finding.Sort(findings)
+
+	require.Equal(t, status.Outdated, findings[0].Status)
+	require.Equal(t, "v1.0.0", findings[0].Version)
+	require.Equal(t, "v4.1.0", findings[0].Latest)
+	require.Equal(t, "requires v1.0.0, latest v4.1.0 is 3 major versions ahead", findings[0].Reason)
+
+	require.Equal(t, 7, findings[1].Line)
+	require.Equal(t, "v4.1.0", findings[1].Latest)
+}
@@ -0,0 +1,52 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/gitprobe"
+)
+
+// checkSelf writes warnings to w if the repository checked out in dir is
+// archived, or if its default branch is no longer the one the checkout was
+// cloned from. Checkouts that cannot be detected or looked up are skipped.
+func checkSelf(ctx context.Context, w io.Writer, provider client.Provider, dir string) {
+	checkout, err := gitprobe.LocalCheckout(ctx, dir)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("skipping self check: %v", err))
+
+		return
+	}
+
+	result, err := provider.GetRepoResult(ctx, checkout.Repo)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("skipping self check of %s: %v", checkout.Repo, err))
+
+		return
+	}
+
+	for _, warning := range selfWarnings(checkout, result) {
+		_, _ = fmt.Fprintln(w, "warning: "+warning)
+	}
+}
+
+// selfWarnings returns what is wrong with the checked out repository.
+func selfWarnings(checkout gitprobe.Checkout, result client.RepoResult) []string {
+	var warnings []string
+
+	if result.Archived {
+		warnings = append(warnings, fmt.Sprintf("%s, the repository being scanned, is archived", checkout.Repo))
+	}
+
+	if checkout.DefaultBranch != "" && result.DefaultBranch != "" && checkout.DefaultBranch != result.DefaultBranch {
+		warnings = append(warnings, fmt.Sprintf(
+			"the default branch of %s is %s, but this checkout still tracks %s; run `git remote set-head origin --auto` to update it",
+			checkout.Repo, result.DefaultBranch, checkout.DefaultBranch,
+		))
+	}
+
+	return warnings
+}
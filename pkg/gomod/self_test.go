@@ -0,0 +1,24 @@
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/gitprobe"
+)
+
+func TestSelfWarnings(t *testing.T) {
+	t.Parallel()
+
+	checkout := gitprobe.Checkout{Repo: "owner/repo", DefaultBranch: "master"}
+
+	require.Empty(t, selfWarnings(checkout, client.RepoResult{DefaultBranch: "master"}))
+	require.Empty(t, selfWarnings(gitprobe.Checkout{Repo: "owner/repo"}, client.RepoResult{DefaultBranch: "main"}))
+	require.Empty(t, selfWarnings(checkout, client.RepoResult{Degraded: true}))
+
+	require.Equal(t, []string{
+		"owner/repo, the repository being scanned, is archived",
+		"the default branch of owner/repo is main, but this checkout still tracks master; run `git remote set-head origin --auto` to update it",
+	}, selfWarnings(checkout, client.RepoResult{Archived: true, DefaultBranch: "main"}))
+}
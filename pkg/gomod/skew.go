@@ -0,0 +1,101 @@
+package gomod
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"golang.org/x/mod/modfile"
+)
+
+// Requirement is a single require directive for a module in a go.mod file.
+type Requirement struct {
+	GoModPath string
+	Line      int
+	Version   string
+}
+
+// Skew is a module that is required at more than one version across go.mod
+// files in the same tree.
+type Skew struct {
+	Path         string
+	Requirements []Requirement
+}
+
+// FindVersionSkew parses the provided go.mod files and returns every module
+// that is required at different versions, sorted by module path.
+func FindVersionSkew(ctx context.Context, goModFileNames []string) []Skew {
+	reqs := map[string][]Requirement{}
+
+	for _, name := range goModFileNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		mf, err := modfile.Parse(name, data, nil)
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("failed to parse %s: %v", name, err))
+
+			continue
+		}
+
+		for _, req := range mf.Require {
+			reqs[req.Mod.Path] = append(reqs[req.Mod.Path], Requirement{name, req.Syntax.Start.Line, req.Mod.Version})
+		}
+	}
+
+	var skews []Skew
+
+	for path, rs := range reqs {
+		versions := map[string]bool{}
+		for _, r := range rs {
+			versions[r.Version] = true
+		}
+
+		if len(versions) < 2 {
+			continue
+		}
+
+		slices.SortFunc(rs, func(a, b Requirement) int {
+			return cmp.Or(strings.Compare(a.Version, b.Version), strings.Compare(a.GoModPath, b.GoModPath))
+		})
+
+		skews = append(skews, Skew{Path: path, Requirements: rs})
+	}
+
+	slices.SortFunc(skews, func(a, b Skew) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return skews
+}
+
+// ListVersionSkew prints every module required at different versions by
+// go.mod files in scope. Returns the number of modules with skewed versions.
+func ListVersionSkew(ctx context.Context, w io.Writer, scope files.Scope) (int, error) {
+	goModFileNames, err := files.RecursiveFind(ctx, scope, "go.mod")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find go.mod files: %w", err)
+	}
+
+	skews := FindVersionSkew(ctx, goModFileNames)
+
+	for _, skew := range skews {
+		fmt.Fprintln(w, skew.Path)
+
+		for _, r := range skew.Requirements {
+			fmt.Fprintf(w, "  %s %s:%d\n", r.Version, r.GoModPath, r.Line)
+		}
+	}
+
+	return len(skews), nil
+}
@@ -0,0 +1,41 @@
+package gomod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindVersionSkew(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	a := writeTempFile(t, dir, "a.mod", `module example.com/a
+
+require (
+	github.com/foo/bar v1.2.3
+	golang.org/x/mod v0.17.0
+)
+`)
+	b := writeTempFile(t, dir, "b.mod", `module example.com/b
+
+require (
+	github.com/foo/bar v1.3.0
+	golang.org/x/mod v0.17.0
+)
+`)
+
+	skews := FindVersionSkew(context.Background(), []string{a, b})
+
+	require.Equal(t, []Skew{
+		{
+			Path: "github.com/foo/bar",
+			Requirements: []Requirement{
+				{a, 4, "v1.2.3"},
+				{b, 4, "v1.3.0"},
+			},
+		},
+	}, skews)
+}
@@ -0,0 +1,163 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"golang.org/x/mod/modfile"
+)
+
+// ToolsFile is the conventional name of a file that pins the versions of
+// tools with blank imports behind the "tools" build constraint, from before
+// go.mod had tool directives.
+const ToolsFile = "tools.go"
+
+// toolPackages returns the packages named by the tool directives of mf,
+// added in Go 1.24.
+func toolPackages(mf *modfile.File) []string {
+	pkgs := make([]string, 0, len(mf.Tool))
+	for _, t := range mf.Tool {
+		pkgs = append(pkgs, t.Path)
+	}
+
+	return pkgs
+}
+
+// markTools marks the dependencies of the go.mod file goModPath that provide
+// the packages pkgs as tool dependencies. A package is provided by the
+// longest module path it is in. Tools are direct dependencies, even where go
+// marks their module as indirect because no package of the module imports
+// it.
+func markTools(repos map[string][]RepoInfo, goModPath string, pkgs []string) {
+	for _, pkg := range pkgs {
+		var provider *RepoInfo
+
+		for _, infos := range repos {
+			for i := range infos {
+				info := &infos[i]
+
+				if info.goModPath != goModPath || (pkg != info.modPath && !strings.HasPrefix(pkg, info.modPath+"/")) {
+					continue
+				}
+
+				if provider == nil || len(info.modPath) > len(provider.modPath) {
+					provider = info
+				}
+			}
+		}
+
+		if provider != nil {
+			provider.tool = true
+			provider.indirect = false
+		}
+	}
+}
+
+// toolsImports returns the blank imports of a tools.go file, or nil if it is
+// not only built with the "tools" build constraint.
+func toolsImports(name string, data []byte) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), name, data, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	tools := false
+
+	for _, group := range f.Comments {
+		if group.Pos() > f.Package {
+			break
+		}
+
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) && !constraint.IsPlusBuild(c.Text) {
+				continue
+			}
+
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				continue
+			}
+
+			tools = expr.Eval(func(tag string) bool { return tag == "tools" }) &&
+				!expr.Eval(func(string) bool { return false })
+		}
+	}
+
+	if !tools {
+		return nil, nil
+	}
+
+	var pkgs []string
+
+	for _, imp := range f.Imports {
+		if imp.Name == nil || imp.Name.Name != "_" {
+			continue
+		}
+
+		pkg, err := strconv.Unquote(imp.Path.Value)
+		if err == nil {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	return pkgs, nil
+}
+
+// markToolsFiles marks the dependencies imported by the tools.go files in
+// scope as tool dependencies of the closest of goModFileNames above them.
+func markToolsFiles(ctx context.Context, scope files.Scope, goModFileNames []string, repos map[string][]RepoInfo) error {
+	toolsFiles, err := files.RecursiveFind(ctx, scope, ToolsFile)
+	if err != nil {
+		return fmt.Errorf("failed to find %s files: %w", ToolsFile, err)
+	}
+
+	for _, name := range toolsFiles {
+		goMod, ok := owningGoMod(name, goModFileNames)
+		if !ok {
+			continue
+		}
+
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		pkgs, err := toolsImports(name, data)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		markTools(repos, goMod, pkgs)
+	}
+
+	return nil
+}
+
+// owningGoMod returns the go.mod file of goModFileNames in the closest
+// directory above name.
+func owningGoMod(name string, goModFileNames []string) (string, bool) {
+	for dir := filepath.Dir(name); ; dir = filepath.Dir(dir) {
+		for _, goMod := range goModFileNames {
+			if filepath.Dir(goMod) == dir {
+				return goMod, true
+			}
+		}
+
+		if parent := filepath.Dir(dir); parent == dir {
+			return "", false
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gomod
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"golang.org/x/mod/modfile"
+)
+
+func TestToolPackages(t *testing.T) {
+	t.Parallel()
+
+	mf, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+go 1.24
+
+tool golang.org/x/tools/cmd/stringer
+
+tool (
+	github.com/owner/gen/cmd/gen
+	github.com/owner/lint
+)
+
+require (
+	github.com/owner/gen v1.0.0
+	github.com/owner/lint v0.1.0 // indirect
+)
+`), nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"golang.org/x/tools/cmd/stringer", "github.com/owner/gen/cmd/gen", "github.com/owner/lint"}, toolPackages(mf))
+	require.Len(t, mf.Require, 2)
+	require.Equal(t, 13, mf.Require[0].Syntax.Start.Line)
+}
+
+func TestDiscoverGitHubDependencies_Tools(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, t.TempDir(), "go.mod", `module example.com/app
+
+go 1.24
+
+tool github.com/owner/repo/sub/cmd/gen
+
+require (
+	github.com/owner/repo v1.0.0
+	github.com/owner/repo/sub v1.0.0
+	github.com/owner/lib v1.0.0
+)
+`)
+
+	repos := DiscoverGitHubDependencies(context.Background(), []string{path})
+
+	require.Equal(t, []RepoInfo{
+		{false, path, 8, 2, "github.com/owner/repo", "v1.0.0", false},
+		{false, path, 9, 2, "github.com/owner/repo/sub", "v1.0.0", true},
+	}, repos["owner/repo"])
+	require.Equal(t, []RepoInfo{{false, path, 10, 2, "github.com/owner/lib", "v1.0.0", false}}, repos["owner/lib"])
+}
+
+func TestToolsImports(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  string
+		pkgs []string
+	}{
+		{"go build", "//go:build tools\n\npackage tools\n\nimport (\n\t_ \"github.com/owner/gen\"\n\t\"fmt\"\n)\n", []string{"github.com/owner/gen"}},
+		{"plus build", "// +build tools\n\npackage tools\n\nimport _ \"github.com/owner/gen/cmd/gen\"\n", []string{"github.com/owner/gen/cmd/gen"}},
+		{"no constraint", "package main\n\nimport _ \"embed\"\n", nil},
+		{"other constraint", "//go:build !tools\n\npackage main\n\nimport _ \"github.com/owner/gen\"\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgs, err := toolsImports("tools.go", []byte(tt.src))
+			require.NoError(t, err)
+			require.Equal(t, tt.pkgs, pkgs)
+		})
+	}
+}
+
+func TestMarkToolsFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "internal", "tools"), 0o750))
+
+	goMod := writeTempFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/owner/gen v1.0.0\n\tgithub.com/owner/lib v1.0.0\n)\n")
+	writeTempFile(t, filepath.Join(dir, "internal", "tools"), "tools.go", "//go:build tools\n\npackage tools\n\nimport _ \"github.com/owner/gen/cmd/gen\"\n")
+
+	repos := DiscoverGitHubDependencies(context.Background(), []string{goMod})
+
+	require.NoError(t, markToolsFiles(context.Background(), files.Scope{Paths: []string{dir}}, []string{goMod}, repos))
+	require.True(t, repos["owner/gen"][0].tool)
+	require.False(t, repos["owner/lib"][0].tool)
+}
+
+func TestDiscoverGitHubDependencies_IndirectTool(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, t.TempDir(), "go.mod", "module example.com/app\n\ngo 1.24\n\ntool github.com/owner/gen/cmd/gen\n\nrequire github.com/owner/gen v1.0.0 // indirect\n")
+
+	repos := DiscoverGitHubDependencies(context.Background(), []string{path})
+
+	require.Equal(t, []RepoInfo{{false, path, 7, 1, "github.com/owner/gen", "v1.0.0", true}}, repos["owner/gen"])
+}
+
+func TestRepos_ToolsOnly(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTempFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.24\n\ntool github.com/owner/gen/cmd/gen\n\nrequire (\n\tgithub.com/owner/gen v1.0.0\n\tgithub.com/owner/lib v1.0.0\n)\n")
+
+	repos, err := Repos(context.Background(), Options{Scope: files.Scope{Paths: []string{dir}}, ToolsOnly: true})
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	require.Contains(t, repos, "owner/gen")
+}
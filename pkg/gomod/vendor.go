@@ -0,0 +1,191 @@
+package gomod
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DiscoverVendoredDependencies parses the provided vendor/modules.txt files and
+// returns a map of GitHub repositories to their info. Modules that the go.mod
+// file next to the vendor directory requires without an indirect comment are
+// direct dependencies, all others are indirect. Since Go 1.17 modules.txt
+// marks every requirement of go.mod "## explicit", indirect ones included, so
+// that marker only decides when go.mod cannot be read. Modules.txt also lists
+// modules of the pruned module graph that provide no vendored packages; these
+// are not needed for the build and are treated as indirect too. Replacements
+// are resolved to the module that is actually vendored.
+func DiscoverVendoredDependencies(ctx context.Context, modulesTxtNames []string) map[string][]RepoInfo {
+	repos := map[string][]RepoInfo{}
+
+	for _, name := range modulesTxtNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		direct := directRequirements(ctx, filepath.Join(filepath.Dir(filepath.Dir(name)), "go.mod"))
+
+		for _, mod := range parseModulesTxt(string(data)) {
+			repo, ok := gitHubRepo(mod.path)
+			if !ok {
+				continue
+			}
+
+			indirect := !mod.explicit
+			if direct != nil {
+				indirect = !direct[mod.reqPath]
+			}
+
+			indirect = indirect || mod.packages == 0
+
+			repos[repo] = append(repos[repo], RepoInfo{indirect, name, mod.line, 0, mod.path, mod.version, false})
+		}
+	}
+
+	return repos
+}
+
+// directRequirements returns the module paths the go.mod file name requires
+// without an indirect comment, or nil if it cannot be read.
+func directRequirements(ctx context.Context, name string) map[string]bool {
+	data, err := os.ReadFile(name) // #nosec G304
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+		return nil
+	}
+
+	mf, err := modfile.Parse(name, data, nil)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("failed to parse %s: %v", name, err))
+
+		return nil
+	}
+
+	direct := map[string]bool{}
+
+	for _, req := range mf.Require {
+		if !req.Indirect {
+			direct[req.Mod.Path] = true
+		}
+	}
+
+	return direct
+}
+
+// vendoredModule is a module entry in vendor/modules.txt.
+type vendoredModule struct {
+	path    string
+	version string
+	// reqPath is the path go.mod requires the module by, which differs from
+	// path when the module is replaced.
+	reqPath  string
+	line     int
+	explicit bool
+	// packages is the number of packages vendored from the module.
+	packages int
+}
+
+// parseModulesTxt extracts module entries from the contents of a
+// vendor/modules.txt file. Lines look like:
+//
+//	# github.com/foo/bar v1.2.3
+//	## explicit; go 1.21
+//	github.com/foo/bar/pkg
+//	# github.com/old/mod v1.0.0 => github.com/new/mod v1.1.0
+//
+// Local filesystem replacements are skipped as there is nothing to check, and
+// so are the packages vendored from them.
+func parseModulesTxt(data string) []vendoredModule {
+	var mods []vendoredModule
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	lineNum := 0
+	// skipped is set while reading the packages of a skipped module.
+	skipped := false
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+
+		if rest, ok := strings.CutPrefix(line, "## "); ok {
+			if len(mods) > 0 && !skipped && strings.HasPrefix(rest, "explicit") {
+				mods[len(mods)-1].explicit = true
+			}
+
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			if line != "" && len(mods) > 0 && !skipped {
+				mods[len(mods)-1].packages++
+			}
+
+			continue
+		}
+
+		skipped = false
+
+		mod := rest
+		if _, replacement, found := strings.Cut(rest, "=> "); found {
+			mod = replacement
+		}
+
+		reqPath, _, _ := strings.Cut(rest, " ")
+		path, version, _ := strings.Cut(mod, " ")
+
+		if strings.HasPrefix(path, ".") || filepath.IsAbs(path) {
+			skipped = true
+
+			continue
+		}
+
+		mods = append(mods, vendoredModule{path: path, version: version, reqPath: reqPath, line: lineNum})
+	}
+
+	return mods
+}
+
+// discoverWithVendor uses vendor/modules.txt for every go.mod that has one next
+// to it, and the go.mod itself otherwise.
+func discoverWithVendor(ctx context.Context, goModFileNames []string) (map[string][]RepoInfo, error) {
+	var goMods, modulesTxts []string
+
+	for _, name := range goModFileNames {
+		modulesTxt := filepath.Join(filepath.Dir(name), "vendor", "modules.txt")
+
+		_, err := os.Stat(modulesTxt)
+
+		switch {
+		case err == nil:
+			slog.DebugContext(ctx, "using vendored modules", slog.String("path", modulesTxt))
+
+			modulesTxts = append(modulesTxts, modulesTxt)
+		case errors.Is(err, fs.ErrNotExist):
+			goMods = append(goMods, name)
+		default:
+			return nil, fmt.Errorf("failed to stat %s: %w", modulesTxt, err)
+		}
+	}
+
+	repos := DiscoverGitHubDependencies(ctx, goMods)
+
+	for repo, infos := range DiscoverVendoredDependencies(ctx, modulesTxts) {
+		repos[repo] = append(repos[repo], infos...)
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,78 @@
+package gomod
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverVendoredDependencies(t *testing.T) {
+	t.Parallel()
+
+	modulesTxt := `# github.com/foo/bar v1.2.3
+## explicit; go 1.21
+github.com/foo/bar
+# github.com/other/repo v0.1.0
+github.com/other/repo/pkg
+# github.com/old/mod v1.0.0 => github.com/new/mod v1.1.0
+## explicit
+github.com/old/mod
+# github.com/local/mod v1.0.0 => ../mod
+## explicit
+# golang.org/x/tools v0.1.0
+# github.com/graph/only v0.2.0
+## explicit; go 1.17
+`
+	path := writeTempFile(t, t.TempDir(), "modules.txt", modulesTxt)
+
+	repos := DiscoverVendoredDependencies(context.Background(), []string{path})
+
+	require.Len(t, repos, 4)
+	require.Equal(t, []RepoInfo{{false, path, 1, 0, "github.com/foo/bar", "v1.2.3", false}}, repos["foo/bar"])
+	require.Equal(t, []RepoInfo{{true, path, 4, 0, "github.com/other/repo", "v0.1.0", false}}, repos["other/repo"])
+	require.Equal(t, []RepoInfo{{false, path, 6, 0, "github.com/new/mod", "v1.1.0", false}}, repos["new/mod"])
+	require.Equal(t, []RepoInfo{{true, path, 12, 0, "github.com/graph/only", "v0.2.0", false}}, repos["graph/only"])
+}
+
+func TestDiscoverVendoredDependencies_GoMod(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTempFile(t, dir, "go.mod", `module example.com/app
+
+go 1.22
+
+require (
+	github.com/foo/bar v1.2.3
+	github.com/old/mod v1.0.0
+	github.com/deep/dep v0.4.0 // indirect
+)
+
+replace github.com/old/mod => github.com/new/mod v1.1.0
+`)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "vendor"), 0o750))
+
+	path := writeTempFile(t, filepath.Join(dir, "vendor"), "modules.txt", `# github.com/deep/dep v0.4.0
+## explicit; go 1.20
+github.com/deep/dep
+# github.com/foo/bar v1.2.3
+## explicit; go 1.21
+github.com/foo/bar
+# github.com/old/mod v1.0.0 => github.com/new/mod v1.1.0
+## explicit
+github.com/old/mod
+`)
+
+	repos := DiscoverVendoredDependencies(context.Background(), []string{path})
+
+	require.Equal(t, map[string][]RepoInfo{
+		"deep/dep": {{true, path, 1, 0, "github.com/deep/dep", "v0.4.0", false}},
+		"foo/bar":  {{false, path, 4, 0, "github.com/foo/bar", "v1.2.3", false}},
+		"new/mod":  {{false, path, 7, 0, "github.com/new/mod", "v1.1.0", false}},
+	}, repos)
+}
@@ -0,0 +1,144 @@
+package gomod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/modpath"
+	"github.com/wayneashleyberry/gh-arc/pkg/output"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+)
+
+// tagConcurrency is the number of repositories whose tags are looked up at
+// the same time.
+const tagConcurrency = 8
+
+// tagRef is a tag of a repository.
+type tagRef struct {
+	repo string
+	tag  string
+}
+
+// Verify reports dependencies of every go.mod file in s.Scope whose required
+// version was released from a tag that has since been deleted from its
+// repository. Pseudo-versions are not tagged and are never reported.
+func (s *Scanner) Verify(ctx context.Context) (finding.Report, error) {
+	if err := s.validate(); err != nil {
+		return finding.Report{}, err
+	}
+
+	goModFileNames, err := files.RecursiveFind(ctx, s.Options.Scope, "go.mod")
+	if err != nil {
+		return finding.Report{}, fmt.Errorf("failed to find go.mod files: %w", err)
+	}
+
+	deps, err := repos(ctx, s.Options, goModFileNames)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return s.verify(ctx, deps)
+}
+
+// verify looks up the tag of every dependency in deps and renders findings
+// for the missing ones.
+func (s *Scanner) verify(ctx context.Context, deps map[string][]RepoInfo) (finding.Report, error) {
+	wanted := map[tagRef][]RepoInfo{}
+	roots := map[string]string{}
+
+	for repo, infos := range deps {
+		for _, info := range infos {
+			if !s.Indirect && info.indirect {
+				continue
+			}
+
+			root, ok := roots[info.modPath]
+			if !ok {
+				root = s.moduleRoot(ctx, info.modPath)
+				roots[info.modPath] = root
+			}
+
+			tag, tagged := modpath.Tag(info.modPath, root, info.version)
+			if !tagged {
+				continue
+			}
+
+			ref := tagRef{repo: repo, tag: tag}
+			wanted[ref] = append(wanted[ref], info)
+		}
+	}
+
+	var extra []finding.Finding
+
+	if len(wanted) > 0 {
+		tags, err := s.tagAPI(ctx)
+		if err != nil {
+			return finding.Report{}, err
+		}
+
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, tagConcurrency)
+		)
+
+		collected := output.Start[finding.Finding](progress.New(s.Progress, len(wanted)))
+
+		for ref, infos := range wanted {
+			wg.Add(1)
+
+			sem <- struct{}{}
+
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				f, missing := missingTag(ctx, tags, infos[0].modPath, infos[0].version, ref.repo, ref.tag)
+
+				collected.Done(ref.repo)
+
+				if !missing {
+					return
+				}
+
+				for _, info := range infos {
+					f.Module = info.modPath
+					f.Version = info.version
+					f.File = info.goModPath
+					f.Line = info.line
+					f.Column = info.column
+					f.Indirect = info.indirect
+
+					collected.Add(f)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		extra = collected.Close()
+	}
+
+	return s.check(ctx, nil, extra)
+}
+
+// moduleRoot returns the path of the repository root of the module at
+// modPath, which prefixes the paths of modules in subdirectories. Vanity
+// import paths, which are only checked with Options.ModuleProxy, are
+// resolved through their go-import meta tag. Modules that cannot be resolved
+// are assumed to be at the root.
+func (s *Scanner) moduleRoot(ctx context.Context, modPath string) string {
+	resolver := modpath.Resolver{HTTPClient: s.Timeouts.HTTPClient("https://" + modPath)}
+
+	repo, err := resolver.Resolve(ctx, modPath)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("could not resolve repository root of %s: %v", modPath, err))
+
+		return modPath
+	}
+
+	return repo.Root
+}
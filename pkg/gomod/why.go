@@ -0,0 +1,288 @@
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+	"golang.org/x/mod/modfile"
+)
+
+// ANSI escape sequences highlighting the module explained by Why.
+const (
+	whyColorReset = "\033[0m"
+	whyColorRed   = "\033[31m"
+)
+
+// modGraph is the module requirement graph printed by `go mod graph`, from
+// each module, as path@version or just the path for the main module, to the
+// modules it requires in the order they were printed.
+type modGraph map[string][]string
+
+// goModGraph runs `go mod graph` for the module in dir. Like goListModules
+// it never changes go.mod.
+func goModGraph(ctx context.Context, dir string) (modGraph, error) {
+	cmd := exec.CommandContext(ctx, "go", "mod", "graph")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=readonly")
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to run go mod graph in %s: %w: %s", dir, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return nil, fmt.Errorf("failed to run go mod graph in %s: %w", dir, err)
+	}
+
+	return parseModGraph(bytes.NewReader(out))
+}
+
+// parseModGraph parses the output of `go mod graph`, one "from to" edge per
+// line.
+func parseModGraph(r io.Reader) (modGraph, error) {
+	graph := modGraph{}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		from, to, ok := strings.Cut(strings.TrimSpace(sc.Text()), " ")
+		if !ok {
+			continue
+		}
+
+		graph[from] = append(graph[from], to)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go mod graph output: %w", err)
+	}
+
+	return graph, nil
+}
+
+// matchesModule reports whether node, a path@version of the module graph,
+// is target, a module path with an optional @version.
+func matchesModule(node, target string) bool {
+	if strings.Contains(target, "@") {
+		return node == target
+	}
+
+	path, _, _ := strings.Cut(node, "@")
+
+	return path == target
+}
+
+// whyChains returns, for every direct requirement of mf in the order go.mod
+// lists them, the shortest chain of requirements in graph from it to target,
+// starting with the direct requirement and ending with target. Direct
+// requirements that do not lead to target are left out.
+func whyChains(graph modGraph, mf *modfile.File, target string) [][]string {
+	var chains [][]string
+
+	for _, req := range mf.Require {
+		if req.Indirect {
+			continue
+		}
+
+		if chain := shortestChain(graph, req.Mod.String(), target); chain != nil {
+			chains = append(chains, chain)
+		}
+	}
+
+	return chains
+}
+
+// shortestChain searches graph breadth first from start for target and
+// returns the path to it, or nil if target cannot be reached.
+func shortestChain(graph modGraph, start, target string) []string {
+	prev := map[string]string{start: ""}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		if matchesModule(node, target) {
+			var chain []string
+			for ; node != ""; node = prev[node] {
+				chain = append([]string{node}, chain...)
+			}
+
+			return chain
+		}
+
+		for _, next := range graph[node] {
+			if _, seen := prev[next]; seen {
+				continue
+			}
+
+			prev[next] = node
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// writeWhyTree prints chains as a tree beneath root, with the last module of
+// every chain followed by label and, with color set, highlighted in red.
+func writeWhyTree(w io.Writer, root string, chains [][]string, label string, color bool) {
+	fmt.Fprintln(w, root)
+
+	for i, chain := range chains {
+		indent := ""
+		last := i == len(chains)-1
+
+		for j, node := range chain {
+			branch := "├── "
+			if j > 0 || last {
+				branch = "└── "
+			}
+
+			if j == len(chain)-1 {
+				if label != "" {
+					node += " (" + label + ")"
+				}
+
+				if color {
+					node = whyColorRed + node + whyColorReset
+				}
+			}
+
+			fmt.Fprintln(w, indent+branch+node)
+
+			if j == 0 && !last {
+				indent += "│   "
+			} else {
+				indent += "    "
+			}
+		}
+	}
+}
+
+// Why prints, for every go.mod file in s.Scope, a tree of the direct
+// requirements that pull in target, a module path with an optional @version,
+// through the shortest chain of requirements `go mod graph` lists. The
+// repository of target is checked so the tree can say why it needs
+// replacing. It returns the number of chains printed, and requires the go
+// command.
+func (s *Scanner) Why(ctx context.Context, w io.Writer, target string) (int, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return 0, fmt.Errorf("explaining requirements requires the go command: %w", err)
+	}
+
+	goModFileNames, err := files.RecursiveFind(ctx, s.Options.Scope, "go.mod")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find go.mod files: %w", err)
+	}
+
+	type tree struct {
+		root   string
+		chains [][]string
+	}
+
+	var trees []tree
+
+	for _, name := range goModFileNames {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		mf, err := modfile.Parse(name, data, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		graph, err := goModGraph(ctx, filepath.Dir(name))
+		if err != nil {
+			return 0, err
+		}
+
+		chains := whyChains(graph, mf, target)
+		if len(chains) == 0 {
+			slog.DebugContext(ctx, fmt.Sprintf("%s does not require %s", name, target))
+
+			continue
+		}
+
+		root := files.FormatPath(name, s.Options.PathStyle)
+		if mf.Module != nil {
+			root += " (" + mf.Module.Mod.Path + ")"
+		}
+
+		trees = append(trees, tree{root, chains})
+	}
+
+	if len(trees) == 0 {
+		return 0, nil
+	}
+
+	label, highlight := s.whyLabel(ctx, target)
+
+	count := 0
+
+	for i, t := range trees {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		writeWhyTree(w, t.root, t.chains, label, highlight && s.Color)
+		count += len(t.chains)
+	}
+
+	return count, nil
+}
+
+// whyLabel checks the repository of target and returns the reason of its
+// most severe finding, and whether that finding is an error, such as an
+// archived repository. Lookup errors are logged and leave the module
+// unlabelled.
+func (s *Scanner) whyLabel(ctx context.Context, target string) (string, bool) {
+	scanner := *s
+	scanner.Out = io.Discard
+	scanner.Format = render.FormatText
+	scanner.Progress = nil
+	scanner.SelfCheck = nil
+
+	path, version, _ := strings.Cut(target, "@")
+
+	report, err := scanner.CheckModule(ctx, path, version)
+	if err != nil {
+		slog.DebugContext(ctx, fmt.Sprintf("error checking %s: %v", target, err))
+
+		return "", false
+	}
+
+	if len(report.Findings) == 0 {
+		return "", false
+	}
+
+	f := report.Findings[0]
+	for _, other := range report.Findings[1:] {
+		if other.Severity() == finding.SeverityError {
+			f = other
+		}
+	}
+
+	if f.Status == status.Unknown {
+		slog.DebugContext(ctx, fmt.Sprintf("could not check %s: %s", target, f.Reason))
+
+		return "", false
+	}
+
+	return f.Reason, f.Severity() == finding.SeverityError
+}
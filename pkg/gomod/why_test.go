@@ -0,0 +1,76 @@
+package gomod
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+const testModGraph = `example.com/app github.com/a/b@v1.0.0
+example.com/app github.com/c/d@v0.2.0
+example.com/app github.com/foo/bar@v1.1.0
+example.com/app github.com/x/y@v1.0.0
+github.com/a/b@v1.0.0 github.com/c/d@v0.2.0
+github.com/c/d@v0.2.0 github.com/foo/bar@v1.1.0
+github.com/x/y@v1.0.0 github.com/foo/bar@v1.0.0
+`
+
+func TestWhyChains(t *testing.T) {
+	t.Parallel()
+
+	graph, err := parseModGraph(strings.NewReader(testModGraph))
+	require.NoError(t, err)
+
+	mf, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+go 1.22
+
+require (
+	github.com/a/b v1.0.0
+	github.com/x/y v1.0.0
+	github.com/unrelated/mod v1.0.0
+	github.com/c/d v0.2.0 // indirect
+	github.com/foo/bar v1.1.0 // indirect
+)
+`), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{
+		{"github.com/a/b@v1.0.0", "github.com/c/d@v0.2.0", "github.com/foo/bar@v1.1.0"},
+		{"github.com/x/y@v1.0.0", "github.com/foo/bar@v1.0.0"},
+	}, whyChains(graph, mf, "github.com/foo/bar"))
+
+	require.Equal(t, [][]string{
+		{"github.com/x/y@v1.0.0", "github.com/foo/bar@v1.0.0"},
+	}, whyChains(graph, mf, "github.com/foo/bar@v1.0.0"))
+
+	require.Empty(t, whyChains(graph, mf, "github.com/other/mod"))
+}
+
+func TestWriteWhyTree(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	writeWhyTree(&buf, "go.mod (example.com/app)", [][]string{
+		{"github.com/a/b@v1.0.0", "github.com/c/d@v0.2.0", "github.com/foo/bar@v1.1.0"},
+		{"github.com/x/y@v1.0.0", "github.com/foo/bar@v1.0.0"},
+	}, "repository archived", false)
+
+	require.Equal(t, `go.mod (example.com/app)
+├── github.com/a/b@v1.0.0
+│   └── github.com/c/d@v0.2.0
+│       └── github.com/foo/bar@v1.1.0 (repository archived)
+└── github.com/x/y@v1.0.0
+    └── github.com/foo/bar@v1.0.0 (repository archived)
+`, buf.String())
+
+	buf.Reset()
+
+	writeWhyTree(&buf, "go.mod", [][]string{{"github.com/foo/bar@v1.0.0"}}, "", true)
+
+	require.Equal(t, "go.mod\n└── \033[31mgithub.com/foo/bar@v1.0.0\033[0m\n", buf.String())
+}
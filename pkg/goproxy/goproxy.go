@@ -0,0 +1,268 @@
+// Package goproxy resolves Go modules through a module proxy, such as
+// proxy.golang.org. The proxy records where each version was fetched from,
+// which finds the repository a module is really developed in even when its
+// path does not say, as for vanity import paths and renamed repositories.
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"golang.org/x/mod/module"
+)
+
+// DefaultURL is the proxy used when GOPROXY is not set.
+const DefaultURL = "https://proxy.golang.org"
+
+// concurrency is the number of modules looked up at the same time. The proxy
+// protocol has no batch endpoint.
+const concurrency = 8
+
+// ErrNotFound is returned when no proxy has the module or version.
+var ErrNotFound = errors.New("module not found in proxy")
+
+// ErrNoOrigin is returned for versions whose origin is unknown or not a
+// GitHub repository.
+var ErrNoOrigin = errors.New("no github origin")
+
+// Info is the metadata of a module version.
+type Info struct {
+	Version string `json:"Version"`
+	Time    string `json:"Time"`
+	// Origin is where the proxy fetched the version from. Proxies only
+	// record it for versions fetched by Go 1.19 or later.
+	Origin *Origin `json:"Origin"`
+}
+
+// Origin is the version control source of a module version.
+type Origin struct {
+	VCS  string `json:"VCS"`
+	URL  string `json:"URL"`
+	Ref  string `json:"Ref"`
+	Hash string `json:"Hash"`
+}
+
+// proxy is one entry of GOPROXY.
+type proxy struct {
+	url string
+	// fallback is set when the next proxy is tried after any error, as
+	// with a "|" separator, rather than only when the module is not found.
+	fallback bool
+}
+
+// Client queries the module proxies configured like the go command's.
+type Client struct {
+	httpClient *http.Client
+	proxies    []proxy
+	// private are GONOPROXY patterns of modules that are never looked up.
+	private string
+}
+
+// New returns a client for the proxies in goproxy, a GOPROXY value, that
+// skips modules matching noproxy, a GONOPROXY value. "direct" and "off"
+// entries are ignored, as modules are never fetched from version control.
+func New(httpClient *http.Client, goproxy, noproxy string) *Client {
+	if goproxy == "" {
+		goproxy = DefaultURL
+	}
+
+	c := &Client{httpClient: httpClient, private: noproxy}
+
+	for goproxy != "" {
+		end := strings.IndexAny(goproxy, ",|")
+
+		entry, sep := goproxy, byte(0)
+		if end >= 0 {
+			entry, sep = goproxy[:end], goproxy[end]
+			goproxy = goproxy[end+1:]
+		} else {
+			goproxy = ""
+		}
+
+		entry = strings.TrimSpace(entry)
+		if entry == "" || entry == "direct" || entry == "off" {
+			continue
+		}
+
+		c.proxies = append(c.proxies, proxy{url: strings.TrimSuffix(entry, "/"), fallback: sep == '|'})
+	}
+
+	return c
+}
+
+// FromEnv returns a client configured by GOPROXY and GONOPROXY, which
+// defaults to GOPRIVATE.
+func FromEnv(httpClient *http.Client) *Client {
+	noproxy := os.Getenv("GONOPROXY")
+	if noproxy == "" {
+		noproxy = os.Getenv("GOPRIVATE")
+	}
+
+	return New(httpClient, os.Getenv("GOPROXY"), noproxy)
+}
+
+// Info returns the metadata of version of the module at path, or of its
+// latest version if version is empty.
+func (c *Client) Info(ctx context.Context, path, version string) (Info, error) {
+	endpoint := "@latest"
+
+	if version != "" {
+		escaped, err := module.EscapeVersion(version)
+		if err != nil {
+			return Info{}, fmt.Errorf("invalid version %s of %s: %w", version, path, err)
+		}
+
+		endpoint = "@v/" + escaped + ".info"
+	}
+
+	var info Info
+
+	body, err := c.get(ctx, path, endpoint)
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := json.Unmarshal(body, &info); err != nil {
+		return Info{}, fmt.Errorf("failed to decode info of %s: %w", path, err)
+	}
+
+	return info, nil
+}
+
+// Versions returns the tagged versions of the module at path, in the order
+// the proxy lists them.
+func (c *Client) Versions(ctx context.Context, path string) ([]string, error) {
+	body, err := c.get(ctx, path, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(body)), nil
+}
+
+// Repo returns the "owner/repo" name of the GitHub repository version of the
+// module at path was fetched from, or ErrNoOrigin.
+func (c *Client) Repo(ctx context.Context, path, version string) (string, error) {
+	info, err := c.Info(ctx, path, version)
+	if err != nil {
+		return "", err
+	}
+
+	if info.Origin == nil {
+		return "", fmt.Errorf("%w: %s", ErrNoOrigin, path)
+	}
+
+	repo, ok := client.RepoFromURL(info.Origin.URL)
+	if !ok {
+		return "", fmt.Errorf("%w: %s is hosted at %s", ErrNoOrigin, path, info.Origin.URL)
+	}
+
+	return repo, nil
+}
+
+// Repositories returns the GitHub repository of each module version. Modules
+// the proxies do not know, or whose origin is not GitHub, are omitted.
+func (c *Client) Repositories(ctx context.Context, mods []module.Version) map[module.Version]string {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		sem   = make(chan struct{}, concurrency)
+		repos = map[module.Version]string{}
+	)
+
+	for _, mod := range mods {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			repo, err := c.Repo(ctx, mod.Path, mod.Version)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("could not resolve %s through the module proxy: %v", mod, err))
+
+				return
+			}
+
+			mu.Lock()
+			repos[mod] = repo
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return repos
+}
+
+// get fetches endpoint of the module at path from the first proxy that has
+// it.
+func (c *Client) get(ctx context.Context, path, endpoint string) ([]byte, error) {
+	if module.MatchPrefixPatterns(c.private, path) {
+		return nil, fmt.Errorf("%w: %s matches GONOPROXY", ErrNotFound, path)
+	}
+
+	escaped, err := module.EscapePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module path %s: %w", path, err)
+	}
+
+	err = fmt.Errorf("%w: %s", ErrNotFound, path)
+
+	for _, p := range c.proxies {
+		var body []byte
+
+		body, err = c.fetch(ctx, p.url+"/"+escaped+"/"+endpoint)
+		if err == nil {
+			return body, nil
+		}
+
+		if !p.fallback && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, url)
+	default:
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,128 @@
+package goproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/module"
+)
+
+func newProxy(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	c := New(http.DefaultClient, "https://a.example,https://b.example/|direct,off", "")
+	require.Equal(t, []proxy{{url: "https://a.example", fallback: false}, {url: "https://b.example", fallback: true}}, c.proxies)
+
+	require.Equal(t, []proxy{{url: DefaultURL}}, New(http.DefaultClient, "", "").proxies)
+	require.Empty(t, New(http.DefaultClient, "direct", "").proxies)
+}
+
+func TestClient_Info(t *testing.T) {
+	t.Parallel()
+
+	srv := newProxy(t, map[string]string{
+		"/go.example.org/!vanity/@v/v1.0.0.info": `{"Version":"v1.0.0","Time":"2024-01-02T03:04:05Z","Origin":{"VCS":"git","URL":"https://github.com/example/vanity","Ref":"refs/tags/v1.0.0","Hash":"abc"}}`,
+		"/go.example.org/!vanity/@latest":        `{"Version":"v1.1.0"}`,
+		"/go.example.org/!vanity/@v/list":        "v1.0.0\nv1.1.0\n",
+	})
+
+	c := New(srv.Client(), srv.URL, "")
+	ctx := context.Background()
+
+	info, err := c.Info(ctx, "go.example.org/Vanity", "v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, &Origin{VCS: "git", URL: "https://github.com/example/vanity", Ref: "refs/tags/v1.0.0", Hash: "abc"}, info.Origin)
+
+	info, err = c.Info(ctx, "go.example.org/Vanity", "")
+	require.NoError(t, err)
+	require.Equal(t, "v1.1.0", info.Version)
+	require.Nil(t, info.Origin)
+
+	versions, err := c.Versions(ctx, "go.example.org/Vanity")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.0.0", "v1.1.0"}, versions)
+
+	_, err = c.Info(ctx, "go.example.org/missing", "v1.0.0")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Repo(t *testing.T) {
+	t.Parallel()
+
+	srv := newProxy(t, map[string]string{
+		"/go.example.org/vanity/@v/v1.0.0.info": `{"Version":"v1.0.0","Origin":{"VCS":"git","URL":"https://github.com/example/vanity.git"}}`,
+		"/go.example.org/gitlab/@v/v1.0.0.info": `{"Version":"v1.0.0","Origin":{"VCS":"git","URL":"https://gitlab.com/example/gitlab"}}`,
+		"/go.example.org/old/@v/v1.0.0.info":    `{"Version":"v1.0.0"}`,
+	})
+
+	c := New(srv.Client(), srv.URL, "")
+	ctx := context.Background()
+
+	repo, err := c.Repo(ctx, "go.example.org/vanity", "v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "example/vanity", repo)
+
+	_, err = c.Repo(ctx, "go.example.org/gitlab", "v1.0.0")
+	require.ErrorIs(t, err, ErrNoOrigin)
+
+	_, err = c.Repo(ctx, "go.example.org/old", "v1.0.0")
+	require.ErrorIs(t, err, ErrNoOrigin)
+
+	repos := c.Repositories(ctx, []module.Version{
+		{Path: "go.example.org/vanity", Version: "v1.0.0"},
+		{Path: "go.example.org/gitlab", Version: "v1.0.0"},
+	})
+	require.Equal(t, map[module.Version]string{{Path: "go.example.org/vanity", Version: "v1.0.0"}: "example/vanity"}, repos)
+}
+
+func TestClient_fallback(t *testing.T) {
+	t.Parallel()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(broken.Close)
+
+	empty := newProxy(t, map[string]string{})
+	good := newProxy(t, map[string]string{
+		"/example.com/mod/@v/list": "v1.0.0\n",
+	})
+
+	ctx := context.Background()
+
+	versions, err := New(http.DefaultClient, empty.URL+","+good.URL, "").Versions(ctx, "example.com/mod")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.0.0"}, versions)
+
+	_, err = New(http.DefaultClient, broken.URL+","+good.URL, "").Versions(ctx, "example.com/mod")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotFound)
+
+	versions, err = New(http.DefaultClient, broken.URL+"|"+good.URL, "").Versions(ctx, "example.com/mod")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.0.0"}, versions)
+
+	_, err = New(http.DefaultClient, good.URL, "example.com/*").Versions(ctx, "example.com/mod")
+	require.ErrorIs(t, err, ErrNotFound)
+}
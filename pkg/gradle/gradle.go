@@ -0,0 +1,153 @@
+// Package gradle provides commands for scanning Java dependencies declared in
+// Gradle build scripts and gradle.lockfile files and reporting archived GitHub
+// repositories.
+package gradle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/mavencentral"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+// Manifests are the build scripts that declare the dependencies of a Gradle
+// project, in the Groovy and Kotlin DSLs.
+var Manifests = []string{"build.gradle", "build.gradle.kts"}
+
+// LockFile is the file that records the resolved dependencies of a Gradle
+// project when dependency locking is enabled.
+const LockFile = "gradle.lockfile"
+
+var (
+	// coordinates matches a dependency declared in string notation, such
+	// as `implementation "group:artifact:version"`, optionally wrapped in
+	// platform(...), and captures its group, artifact and version.
+	coordinates = regexp.MustCompile(`^\w+\s*\(?\s*(?:(?:enforcedPlatform|platform)\s*\(\s*)?["']([^"'\s:]+):([^"'\s:]+)(?::([^"'\s:@]+))?(?:@\w+)?["']`)
+	// mapNotation matches a dependency declared in map notation, such as
+	// `implementation group: "group", name: "artifact", version: "1.0"`,
+	// or with named arguments in the Kotlin DSL.
+	mapNotation = regexp.MustCompile(`^\w+\s*\(?\s*group\s*[:=]\s*["']([^"']+)["']\s*,\s*name\s*[:=]\s*["']([^"']+)["'](?:\s*,\s*version\s*[:=]\s*["']([^"']+)["'])?`)
+	// lockEntry matches a dependency recorded in a gradle.lockfile, and
+	// captures its group, artifact and version.
+	lockEntry = regexp.MustCompile(`^([^#:\s]+):([^:\s]+):([^=\s]+)=`)
+)
+
+// parseBuildScript returns the external dependencies declared in a
+// build.gradle or build.gradle.kts file. Project, file and version catalog
+// dependencies are omitted.
+func parseBuildScript(name string, data []byte) []resolve.Dependency {
+	var deps []resolve.Dependency
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		m := coordinates.FindStringSubmatch(line)
+		if m == nil {
+			m = mapNotation.FindStringSubmatch(line)
+		}
+
+		if m == nil {
+			continue
+		}
+
+		deps = append(deps, resolve.Dependency{
+			Name:    mavencentral.Name(m[1], m[2]),
+			Version: m[3],
+			File:    name,
+			Line:    lineNo,
+		})
+	}
+
+	return deps
+}
+
+// parseLock returns the dependencies recorded in a gradle.lockfile.
+func parseLock(name string, data []byte) []resolve.Dependency {
+	var deps []resolve.Dependency
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		m := lockEntry.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		deps = append(deps, resolve.Dependency{
+			Name:     mavencentral.Name(m[1], m[2]),
+			Version:  m[3],
+			File:     name,
+			Line:     lineNo,
+			Indirect: true,
+		})
+	}
+
+	return deps
+}
+
+// Discover parses the given build scripts and gradle.lockfile files.
+// Dependencies declared in a build script are direct dependencies and take
+// their version from a lock file when one records them; dependencies only
+// found in a gradle.lockfile are indirect.
+func Discover(ctx context.Context, manifests, lockfiles []string) []resolve.Dependency {
+	var direct, locked []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		direct = append(direct, parseBuildScript(name, data)...)
+	}
+
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		locked = append(locked, parseLock(name, data)...)
+	}
+
+	return resolve.Merge(direct, locked, func(name string) string {
+		return name
+	})
+}
+
+// Repos returns the repositories of the dependencies declared in opts.Scope,
+// looking them up on Maven Central.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	manifests, err := files.RecursiveMatch(ctx, opts.Scope, func(name string) bool {
+		return slices.Contains(Manifests, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find build scripts: %w", err)
+	}
+
+	lockfiles, err := files.RecursiveFind(ctx, opts.Scope, LockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", LockFile, err)
+	}
+
+	return resolve.Repos(ctx, mavencentral.NewWithHTTPClient(opts.Timeouts.HTTPClient(mavencentral.DefaultBaseURL), mavencentral.DefaultBaseURL), Discover(ctx, manifests, lockfiles), opts)
+}
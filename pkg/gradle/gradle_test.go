@@ -0,0 +1,67 @@
+package gradle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+func TestParseBuildScript(t *testing.T) {
+	t.Parallel()
+
+	data := `plugins {
+    id 'java'
+}
+
+group = 'com.example'
+
+dependencies {
+    implementation 'com.google.guava:guava:33.0.0-jre'
+    implementation platform("org.springframework.boot:spring-boot-dependencies:3.2.1")
+    testImplementation("org.junit.jupiter:junit-jupiter")
+    runtimeOnly group: 'org.postgresql', name: 'postgresql', version: '42.7.1'
+    compileOnly(group = "org.projectlombok", name = "lombok")
+    implementation project(':core')
+    implementation libs.jackson.databind
+    // implementation 'commons-io:commons-io:2.15.1'
+}
+`
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "com.google.guava:guava", Version: "33.0.0-jre", File: "build.gradle", Line: 8},
+		{Name: "org.springframework.boot:spring-boot-dependencies", Version: "3.2.1", File: "build.gradle", Line: 9},
+		{Name: "org.junit.jupiter:junit-jupiter", File: "build.gradle", Line: 10},
+		{Name: "org.postgresql:postgresql", Version: "42.7.1", File: "build.gradle", Line: 11},
+		{Name: "org.projectlombok:lombok", File: "build.gradle", Line: 12},
+	}, parseBuildScript("build.gradle", []byte(data)))
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	manifest := filepath.Join(dir, "build.gradle.kts")
+	lock := filepath.Join(dir, "gradle.lockfile")
+
+	require.NoError(t, os.WriteFile(manifest, []byte(`dependencies {
+    implementation("com.google.guava:guava:33.+")
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(lock, []byte(`# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:failureaccess:1.0.2=compileClasspath,runtimeClasspath
+com.google.guava:guava:33.0.0-jre=compileClasspath,runtimeClasspath
+empty=annotationProcessor
+`), 0o600))
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "com.google.guava:guava", Version: "33.0.0-jre", File: manifest, Line: 2},
+		{Name: "com.google.guava:failureaccess", Version: "1.0.2", File: lock, Line: 4, Indirect: true},
+	}, Discover(context.Background(), []string{manifest, filepath.Join(dir, "missing")}, []string{lock}))
+}
@@ -0,0 +1,148 @@
+// Package helm provides commands for scanning Helm charts for dependencies on
+// charts whose source repositories are archived.
+package helm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/chartrepo"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifests are the files that declare the dependencies of a chart: Chart.yaml
+// for apiVersion v2 charts, and requirements.yaml for v1 charts.
+var Manifests = []string{"Chart.yaml", "requirements.yaml"}
+
+// LockFiles are the files that record the resolved dependencies of a chart.
+var LockFiles = []string{"Chart.lock", "requirements.lock"}
+
+// parse returns the dependencies of a chart listed in a manifest or lock
+// file. Only dependencies on charts in HTTP repositories can be resolved:
+// OCI registries have no index, file:// dependencies are local, and
+// repositories referenced by the name they were added under with helm repo
+// add are only known to the local helm configuration, so all of them are
+// skipped.
+func parse(name string, data []byte, indirect bool) ([]resolve.Dependency, error) {
+	var doc struct {
+		Dependencies yaml.Node `yaml:"dependencies"`
+	}
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	var deps []resolve.Dependency
+
+	for _, item := range doc.Dependencies.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var (
+			chart, version, repository string
+			line                       int
+		)
+
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			key, value := item.Content[i], item.Content[i+1]
+
+			switch key.Value {
+			case "name":
+				chart, line = value.Value, value.Line
+			case "version":
+				version = value.Value
+			case "repository":
+				repository = value.Value
+			}
+		}
+
+		if chart == "" || (!strings.HasPrefix(repository, "https://") && !strings.HasPrefix(repository, "http://")) {
+			continue
+		}
+
+		deps = append(deps, resolve.Dependency{
+			Name:     chartrepo.Name(repository, chart),
+			Version:  version,
+			File:     name,
+			Line:     line,
+			Indirect: indirect,
+		})
+	}
+
+	return deps, nil
+}
+
+// Discover parses the given chart manifests and lock files. Charts declared in
+// a manifest are direct dependencies and take their version from a lock file
+// when one records them.
+func Discover(ctx context.Context, manifests, lockfiles []string) []resolve.Dependency {
+	var direct, locked []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		deps, err := parse(name, data, false)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		direct = append(direct, deps...)
+	}
+
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		deps, err := parse(name, data, true)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		locked = append(locked, deps...)
+	}
+
+	return resolve.Merge(direct, locked, func(name string) string {
+		return name
+	})
+}
+
+// Repos returns the repositories of the chart dependencies in opts.Scope,
+// looking them up in the index of their chart repository.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	manifests, err := files.RecursiveMatch(ctx, opts.Scope, func(name string) bool {
+		return slices.Contains(Manifests, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find chart manifests: %w", err)
+	}
+
+	lockfiles, err := files.RecursiveMatch(ctx, opts.Scope, func(name string) bool {
+		return slices.Contains(LockFiles, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find chart lock files: %w", err)
+	}
+
+	return resolve.Repos(ctx, chartrepo.NewWithHTTPClient(opts.Timeouts.HTTPClient("")), Discover(ctx, manifests, lockfiles), opts)
+}
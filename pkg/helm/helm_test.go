@@ -0,0 +1,58 @@
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	data := `apiVersion: v2
+name: app
+version: 1.0.0
+dependencies:
+  - name: postgresql
+    version: ~12.1.0
+    repository: https://charts.bitnami.com/bitnami
+  - name: redis
+    version: 17.0.0
+    repository: oci://registry-1.docker.io/bitnamicharts
+  - name: common
+    version: 1.0.0
+    repository: file://../common
+  - name: nginx
+    version: 1.0.0
+    repository: "@stable"
+`
+
+	deps, err := parse("Chart.yaml", []byte(data), false)
+	require.NoError(t, err)
+	require.Equal(t, []resolve.Dependency{
+		{Name: "https://charts.bitnami.com/bitnami/postgresql", Version: "~12.1.0", File: "Chart.yaml", Line: 5},
+	}, deps)
+
+	_, err = parse("Chart.yaml", []byte("dependencies: ["), false)
+	require.Error(t, err)
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	manifest := filepath.Join(dir, "Chart.yaml")
+	lock := filepath.Join(dir, "Chart.lock")
+
+	require.NoError(t, os.WriteFile(manifest, []byte("dependencies:\n  - name: postgresql\n    version: ~12.1.0\n    repository: https://charts.bitnami.com/bitnami\n"), 0o600))
+	require.NoError(t, os.WriteFile(lock, []byte("dependencies:\n- name: postgresql\n  repository: https://charts.bitnami.com/bitnami\n  version: 12.1.2\ndigest: sha256:abc\n"), 0o600))
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "https://charts.bitnami.com/bitnami/postgresql", Version: "12.1.2", File: manifest, Line: 2},
+	}, Discover(context.Background(), []string{manifest}, []string{lock}))
+}
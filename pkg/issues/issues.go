@@ -0,0 +1,131 @@
+// Package issues opens a GitHub issue for every archived dependency that is
+// not already tracked by an open issue, so archived dependencies are picked
+// up like any other work.
+package issues
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Label is added to every issue opened, and only open issues with it are
+// considered when deduplicating.
+const Label = "gh-arc"
+
+// API lists and opens issues. client.Client implements it.
+type API interface {
+	OpenIssues(ctx context.Context, repo, label string) ([]client.Issue, error)
+	CreateIssue(ctx context.Context, repo string, issue client.NewIssue) (client.Issue, error)
+}
+
+// marker identifies the archived repository an issue is about. It is hidden
+// in the issue body, so issues are still recognized after being retitled.
+func marker(repo string) string {
+	return "<!-- gh-arc: " + repo + " -->"
+}
+
+// Title returns the title of the issue about the archived repository repo.
+func Title(repo string) string {
+	return "Archived dependency: " + repo
+}
+
+// Body describes where the archived repository of findings is required and
+// what to do about it.
+func Body(repo string, findings []finding.Finding) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "The upstream repository https://github.com/%s is archived and no longer maintained.\n\n", repo)
+
+	if pushedAt := findings[0].PushedAt; pushedAt != "" {
+		fmt.Fprintf(&b, "Last push: %s\n\n", pushedAt)
+	}
+
+	b.WriteString("| Dependency | Version | Location |\n|---|---|---|\n")
+
+	for _, f := range findings {
+		location := cmp.Or(f.File, "-")
+		if f.Line > 0 {
+			location += ":" + strconv.Itoa(f.Line)
+		}
+
+		if f.Indirect {
+			location += " (indirect)"
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n", cmp.Or(f.Module, repo), cmp.Or(f.Version, "-"), location)
+	}
+
+	b.WriteString("\n### Next steps\n\n")
+	fmt.Fprintf(&b, "- Check the README and forks of https://github.com/%s for a maintained successor.\n", repo)
+
+	if module := findings[0].Module; strings.HasPrefix(module, "github.com/") {
+		fmt.Fprintf(&b, "- See what similar projects use instead: https://pkg.go.dev/%s?tab=importedby\n", module)
+	}
+
+	b.WriteString("- Replace or remove the dependency, then close this issue.\n\n")
+	b.WriteString(marker(repo) + "\n")
+
+	return b.String()
+}
+
+// tracked reports whether an open issue is already about repo.
+func tracked(open []client.Issue, repo string) bool {
+	return slices.ContainsFunc(open, func(issue client.Issue) bool {
+		return strings.Contains(issue.Body, marker(repo)) || issue.Title == Title(repo)
+	})
+}
+
+// Create opens an issue in repo for every archived dependency in report that
+// no open issue labeled Label is about yet, and writes a line about each
+// issue opened to w. Informational and baselined findings are left out.
+func Create(ctx context.Context, api API, repo string, report finding.Report, w io.Writer) ([]client.Issue, error) {
+	archived := map[string][]finding.Finding{}
+
+	for _, f := range report.Enforced().Findings {
+		if f.Status == status.Archived {
+			archived[f.Repo] = append(archived[f.Repo], f)
+		}
+	}
+
+	if len(archived) == 0 {
+		return nil, nil
+	}
+
+	open, err := api.OpenIssues(ctx, repo, Label)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []client.Issue
+
+	for _, dep := range slices.Sorted(maps.Keys(archived)) {
+		if tracked(open, dep) {
+			continue
+		}
+
+		issue, err := api.CreateIssue(ctx, repo, client.NewIssue{
+			Title:  Title(dep),
+			Body:   Body(dep, archived[dep]),
+			Labels: []string{Label},
+		})
+		if err != nil {
+			return created, err
+		}
+
+		fmt.Fprintf(w, "opened issue #%d for %s: %s\n", issue.Number, dep, issue.HTMLURL)
+
+		created = append(created, issue)
+	}
+
+	return created, nil
+}
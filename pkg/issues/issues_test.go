@@ -0,0 +1,75 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// mockAPI records the issues opened in a repository with existing open
+// issues.
+type mockAPI struct {
+	open    []client.Issue
+	created []client.NewIssue
+}
+
+func (m *mockAPI) OpenIssues(_ context.Context, _, _ string) ([]client.Issue, error) {
+	return m.open, nil
+}
+
+func (m *mockAPI) CreateIssue(_ context.Context, repo string, issue client.NewIssue) (client.Issue, error) {
+	m.created = append(m.created, issue)
+	n := len(m.created) + 10
+
+	return client.Issue{Number: n, HTMLURL: fmt.Sprintf("https://github.com/%s/issues/%d", repo, n)}, nil
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Module: "github.com/old/tracked", Repo: "old/tracked", File: "go.mod", Line: 4, Status: status.Archived},
+		{Module: "github.com/old/retitled", Repo: "old/retitled", File: "go.mod", Line: 5, Status: status.Archived},
+		{Module: "github.com/old/new", Version: "v1.2.3", Repo: "old/new", File: "go.mod", Line: 6, Status: status.Archived, PushedAt: "2021-01-01T00:00:00Z"},
+		{Module: "github.com/old/new", Version: "v1.2.3", Repo: "old/new", File: "tools/go.mod", Line: 3, Indirect: true, Status: status.Archived},
+		{Module: "github.com/old/stale", Repo: "old/stale", File: "go.mod", Line: 7, Status: status.Stale},
+		{Module: "github.com/old/accepted", Repo: "old/accepted", File: "go.mod", Line: 8, Status: status.Archived, Baselined: true},
+	}}
+
+	api := &mockAPI{open: []client.Issue{
+		{Number: 1, Title: "Archived dependency: old/tracked"},
+		{Number: 2, Title: "Replace retitled", Body: "...\n<!-- gh-arc: old/retitled -->\n"},
+	}}
+
+	var buf bytes.Buffer
+
+	created, err := Create(context.Background(), api, "me/app", report, &buf)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "opened issue #11 for old/new: https://github.com/me/app/issues/11\n", buf.String())
+
+	require.Len(t, api.created, 1)
+	require.Equal(t, "Archived dependency: old/new", api.created[0].Title)
+	require.Equal(t, []string{Label}, api.created[0].Labels)
+	require.Contains(t, api.created[0].Body, "Last push: 2021-01-01T00:00:00Z")
+	require.Contains(t, api.created[0].Body, "| `github.com/old/new` | v1.2.3 | go.mod:6 |\n")
+	require.Contains(t, api.created[0].Body, "| `github.com/old/new` | v1.2.3 | tools/go.mod:3 (indirect) |\n")
+	require.Contains(t, api.created[0].Body, "https://pkg.go.dev/github.com/old/new?tab=importedby")
+	require.Contains(t, api.created[0].Body, "<!-- gh-arc: old/new -->")
+}
+
+func TestCreate_NothingArchived(t *testing.T) {
+	t.Parallel()
+
+	api := &mockAPI{}
+
+	created, err := Create(context.Background(), api, "me/app", finding.Report{}, &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Empty(t, created)
+}
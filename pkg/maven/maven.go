@@ -0,0 +1,156 @@
+// Package maven provides commands for scanning Java dependencies declared in
+// pom.xml files and reporting archived GitHub repositories.
+package maven
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/mavencentral"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+// Manifest is the file that declares the dependencies of a Maven project.
+const Manifest = "pom.xml"
+
+// property matches a ${name} property reference.
+var property = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// dependency is a dependency element of a pom.xml file.
+type dependency struct {
+	groupID, artifactID, version string
+	line                         int
+}
+
+// parsePOM returns the dependencies declared in a pom.xml file, including
+// managed dependencies and the dependencies of plugins. Versions referencing
+// properties of the same file are expanded. Modules of the same build,
+// referenced by ${project.groupId} or ${project.version}, are omitted.
+func parsePOM(name string, data []byte) ([]resolve.Dependency, error) {
+	var (
+		deps    []dependency
+		current *dependency
+		path    []string
+		text    strings.Builder
+		props   = map[string]string{}
+	)
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name.Local)
+			text.Reset()
+
+			if t.Name.Local == "dependency" && len(path) > 1 && path[len(path)-2] == "dependencies" {
+				line := bytes.Count(data[:decoder.InputOffset()], []byte("\n")) + 1
+				current = &dependency{line: line}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			value := strings.TrimSpace(text.String())
+
+			switch {
+			case len(path) == 3 && path[0] == "project" && path[1] == "properties":
+				props[t.Name.Local] = value
+			case current != nil && t.Name.Local == "dependency":
+				deps = append(deps, *current)
+				current = nil
+			case current != nil && path[len(path)-2] == "dependency":
+				switch t.Name.Local {
+				case "groupId":
+					current.groupID = value
+				case "artifactId":
+					current.artifactID = value
+				case "version":
+					current.version = value
+				}
+			}
+
+			path = path[:len(path)-1]
+			text.Reset()
+		}
+	}
+
+	var found []resolve.Dependency
+
+	for _, dep := range deps {
+		if dep.groupID == "${project.groupId}" || dep.version == "${project.version}" {
+			continue
+		}
+
+		version := property.ReplaceAllStringFunc(dep.version, func(ref string) string {
+			if v, ok := props[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+
+			return ref
+		})
+
+		found = append(found, resolve.Dependency{
+			Name:    mavencentral.Name(dep.groupID, dep.artifactID),
+			Version: version,
+			File:    name,
+			Line:    dep.line,
+		})
+	}
+
+	return found, nil
+}
+
+// Discover parses the given pom.xml files.
+func Discover(ctx context.Context, manifests []string) []resolve.Dependency {
+	var deps []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		found, err := parsePOM(name, data)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		deps = append(deps, found...)
+	}
+
+	return deps
+}
+
+// Repos returns the repositories of the dependencies declared in opts.Scope,
+// looking them up on Maven Central.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	manifests, err := files.RecursiveFind(ctx, opts.Scope, Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", Manifest, err)
+	}
+
+	return resolve.Repos(ctx, mavencentral.NewWithHTTPClient(opts.Timeouts.HTTPClient(mavencentral.DefaultBaseURL), mavencentral.DefaultBaseURL), Discover(ctx, manifests), opts)
+}
@@ -0,0 +1,107 @@
+package maven
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+func TestParsePOM(t *testing.T) {
+	t.Parallel()
+
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <properties>
+    <jackson.version>2.16.1</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>33.0.0-jre</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>${junit.version}</version>
+      <scope>test</scope>
+      <exclusions>
+        <exclusion>
+          <groupId>org.hamcrest</groupId>
+          <artifactId>hamcrest-core</artifactId>
+        </exclusion>
+      </exclusions>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <dependencies>
+          <dependency><groupId>org.junit.platform</groupId><artifactId>junit-platform-launcher</artifactId></dependency>
+        </dependencies>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+	deps, err := parsePOM("pom.xml", []byte(data))
+	require.NoError(t, err)
+	require.Equal(t, []resolve.Dependency{
+		{Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.16.1", File: "pom.xml", Line: 10},
+		{Name: "com.google.guava:guava", Version: "33.0.0-jre", File: "pom.xml", Line: 18},
+		{Name: "junit:junit", Version: "${junit.version}", File: "pom.xml", Line: 23},
+		{Name: "org.junit.platform:junit-platform-launcher", File: "pom.xml", Line: 47},
+	}, deps)
+}
+
+func TestParsePOM_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := parsePOM("pom.xml", []byte("<project><dependencies>"))
+	require.Error(t, err)
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	manifest := filepath.Join(dir, "pom.xml")
+	invalid := filepath.Join(dir, "invalid", "pom.xml")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(invalid), 0o750))
+	require.NoError(t, os.WriteFile(manifest, []byte(`<project>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>2.0.9</version></dependency>
+  </dependencies>
+</project>
+`), 0o600))
+	require.NoError(t, os.WriteFile(invalid, []byte("<project>"), 0o600))
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "org.slf4j:slf4j-api", Version: "2.0.9", File: manifest, Line: 3},
+	}, Discover(context.Background(), []string{manifest, invalid, filepath.Join(dir, "missing.xml")}))
+}
@@ -0,0 +1,209 @@
+// Package mavencentral provides a minimal client for Maven Central, used to
+// resolve Java artifacts to their source repositories through the SCM links
+// in their POM files.
+package mavencentral
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// DefaultBaseURL is the Maven Central repository.
+const DefaultBaseURL = "https://repo1.maven.org/maven2"
+
+// concurrency is the number of artifacts looked up at the same time. Maven
+// Central has no batch endpoint.
+const concurrency = 8
+
+// maxParents is the number of parent POMs followed to find inherited SCM
+// links.
+const maxParents = 5
+
+// Client reads artifact metadata from a Maven repository.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a Client for Maven Central.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 10 * time.Second}, DefaultBaseURL)
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client and endpoint (for testing).
+func NewWithHTTPClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{httpClient: httpClient, baseURL: baseURL}
+}
+
+// Name returns the name an artifact is resolved by, e.g.
+// "com.google.guava:guava".
+func Name(groupID, artifactID string) string {
+	return groupID + ":" + artifactID
+}
+
+// pom is the part of a POM file that links to its source.
+type pom struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	URL        string `xml:"url"`
+	SCM        struct {
+		URL                 string `xml:"url"`
+		Connection          string `xml:"connection"`
+		DeveloperConnection string `xml:"developerConnection"`
+	} `xml:"scm"`
+	Parent struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+}
+
+// Repositories returns the GitHub repository URL of each named artifact, see
+// Name. Artifacts that do not exist or do not link to GitHub are omitted.
+func (c *Client) Repositories(ctx context.Context, names []string) (map[string]string, error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, concurrency)
+		urls = map[string]string{}
+	)
+
+	for _, name := range names {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			u, err := c.repository(ctx, name)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching artifact %s: %v", name, err))
+
+				return
+			}
+
+			if u == "" {
+				return
+			}
+
+			mu.Lock()
+			urls[name] = u
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return urls, nil
+}
+
+// repository returns the first GitHub URL among the SCM and project links of
+// the latest release of an artifact, or of the closest parent POM declaring
+// any, or an empty string if none links to GitHub.
+func (c *Client) repository(ctx context.Context, name string) (string, error) {
+	groupID, artifactID, ok := strings.Cut(name, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid artifact %q", name)
+	}
+
+	version, err := c.latest(ctx, groupID, artifactID)
+	if err != nil {
+		return "", err
+	}
+
+	for range maxParents + 1 {
+		var p pom
+
+		if err := c.get(ctx, c.path(groupID, artifactID)+"/"+version+"/"+artifactID+"-"+version+".pom", &p); err != nil {
+			return "", err
+		}
+
+		for _, u := range []string{p.SCM.URL, p.SCM.Connection, p.SCM.DeveloperConnection, p.URL} {
+			u = strings.NewReplacer("${project.artifactId}", artifactID, "${project.groupId}", groupID).Replace(u)
+			u = strings.TrimPrefix(u, "scm:git:")
+
+			if _, ok := client.RepoFromURL(u); ok {
+				return u, nil
+			}
+		}
+
+		if p.Parent.ArtifactID == "" {
+			return "", nil
+		}
+
+		groupID, artifactID, version = p.Parent.GroupID, p.Parent.ArtifactID, p.Parent.Version
+	}
+
+	return "", nil
+}
+
+// latest returns the latest release of an artifact, as listed by its
+// maven-metadata.xml.
+func (c *Client) latest(ctx context.Context, groupID, artifactID string) (string, error) {
+	var metadata struct {
+		Versioning struct {
+			Latest   string   `xml:"latest"`
+			Release  string   `xml:"release"`
+			Versions []string `xml:"versions>version"`
+		} `xml:"versioning"`
+	}
+
+	if err := c.get(ctx, c.path(groupID, artifactID)+"/maven-metadata.xml", &metadata); err != nil {
+		return "", err
+	}
+
+	v := metadata.Versioning
+
+	switch {
+	case v.Release != "":
+		return v.Release, nil
+	case v.Latest != "":
+		return v.Latest, nil
+	case len(v.Versions) > 0:
+		return v.Versions[len(v.Versions)-1], nil
+	}
+
+	return "", fmt.Errorf("no versions of %s listed", Name(groupID, artifactID))
+}
+
+// path returns the URL of the directory of an artifact in the repository.
+func (c *Client) path(groupID, artifactID string) string {
+	return c.baseURL + "/" + strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID
+}
+
+// get fetches and decodes the XML document at u into v.
+func (c *Client) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: %s", u, resp.Status)
+	}
+
+	if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", u, err)
+	}
+
+	return nil
+}
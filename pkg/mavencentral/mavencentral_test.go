@@ -0,0 +1,69 @@
+package mavencentral
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"/com/google/guava/guava/maven-metadata.xml": `<metadata><versioning><latest>33.0.0-jre</latest><release>33.0.0-jre</release></versioning></metadata>`,
+		"/com/google/guava/guava/33.0.0-jre/guava-33.0.0-jre.pom": `<project>
+  <artifactId>guava</artifactId>
+  <parent><groupId>com.google.guava</groupId><artifactId>guava-parent</artifactId><version>33.0.0-jre</version></parent>
+  <url>https://github.com/google/guava</url>
+</project>`,
+		"/org/example/child/maven-metadata.xml": `<metadata><versioning><versions><version>1.0</version><version>1.1</version></versions></versioning></metadata>`,
+		"/org/example/child/1.1/child-1.1.pom": `<project>
+  <parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>2</version></parent>
+  <artifactId>child</artifactId>
+  <url>https://example.org</url>
+</project>`,
+		"/org/example/parent/2/parent-2.pom": `<project>
+  <groupId>org.example</groupId>
+  <artifactId>parent</artifactId>
+  <scm><connection>scm:git:git@github.com:example/parent.git</connection></scm>
+</project>`,
+		"/org/example/templated/maven-metadata.xml": `<metadata><versioning><release>3</release></versioning></metadata>`,
+		"/org/example/templated/3/templated-3.pom": `<project>
+  <scm><url>https://github.com/example/${project.artifactId}</url></scm>
+</project>`,
+		"/org/example/internal/maven-metadata.xml": `<metadata><versioning><release>1</release></versioning></metadata>`,
+		"/org/example/internal/1/internal-1.pom":   `<project><scm><url>https://gitlab.com/example/internal</url></scm></project>`,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	got, err := c.Repositories(context.Background(), []string{
+		"com.google.guava:guava",
+		"org.example:child",
+		"org.example:templated",
+		"org.example:internal",
+		"org.example:missing",
+		"invalid",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"com.google.guava:guava": "https://github.com/google/guava",
+		"org.example:child":      "git@github.com:example/parent.git",
+		"org.example:templated":  "https://github.com/example/templated",
+	}, got)
+}
@@ -0,0 +1,65 @@
+// Package mirror recognizes read-only mirrors, such as the github.com/golang
+// mirrors of go.googlesource.com, whose archived status and activity do not
+// describe the project they mirror.
+package mirror
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+var (
+	// description matches repository descriptions of mirrors, such as
+	// "[mirror] Go supplementary cryptography libraries" or "Read-only
+	// mirror of https://git.example.org/project". A mention of a mirror
+	// alone, as in "Mirror your files to S3", is not enough.
+	description = regexp.MustCompile(`(?i)^\s*\[mirror\]|\bread[- ]?only\s+mirror\b|\bmirror\s+of\s+(?:https?|git)://|\bis\s+a\s+mirror\b`)
+	// link matches a repository URL in a description.
+	link = regexp.MustCompile(`(?:https?|git)://[^\s,;)\]]+`)
+)
+
+// googlesourceOwners are GitHub owners whose "[mirror]" repositories mirror
+// the repository of the same name on go.googlesource.com.
+var googlesourceOwners = []string{"golang"}
+
+// Detect reports whether the repository described by result, named repo, is
+// a mirror, and returns the URL of its upstream if it is known. GitHub sets
+// mirror_url for repositories it mirrors itself; other mirrors are recognized
+// by their description.
+func Detect(repo string, result client.RepoResult) (string, bool) {
+	if result.MirrorURL != "" {
+		return result.MirrorURL, true
+	}
+
+	if !description.MatchString(result.Description) {
+		return "", false
+	}
+
+	if u := link.FindString(result.Description); u != "" {
+		return strings.TrimRight(u, "."), true
+	}
+
+	owner, name, _ := strings.Cut(repo, "/")
+
+	for _, o := range googlesourceOwners {
+		if strings.EqualFold(owner, o) && strings.HasPrefix(strings.ToLower(strings.TrimSpace(result.Description)), "[mirror]") {
+			return "https://go.googlesource.com/" + name, true
+		}
+	}
+
+	return "", true
+}
+
+// GitHubUpstream returns the GitHub repository a mirror of repo is mirrored
+// from, or false if its upstream is unknown, hosted elsewhere, or repo
+// itself.
+func GitHubUpstream(repo, upstream string) (string, bool) {
+	found, ok := client.RepoFromURL(upstream)
+	if !ok || strings.EqualFold(found, repo) {
+		return "", false
+	}
+
+	return found, true
+}
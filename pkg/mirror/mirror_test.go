@@ -0,0 +1,50 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		repo     string
+		result   client.RepoResult
+		upstream string
+		mirror   bool
+	}{
+		{"golang/sys", client.RepoResult{Description: "[mirror] Go packages for low-level interaction with the operating system"}, "https://go.googlesource.com/sys", true},
+		{"acme/lib", client.RepoResult{Description: "Read-only mirror of https://git.example.org/lib."}, "https://git.example.org/lib", true},
+		{"acme/lib", client.RepoResult{Description: "Mirror of https://github.com/upstream/lib"}, "https://github.com/upstream/lib", true},
+		{"acme/lib", client.RepoResult{Description: "This repository is a mirror; send patches upstream"}, "", true},
+		{"acme/lib", client.RepoResult{MirrorURL: "https://svn.example.org/lib"}, "https://svn.example.org/lib", true},
+		{"acme/lib", client.RepoResult{Description: "[mirror] Something"}, "", true},
+		{"acme/mirrors", client.RepoResult{Description: "A tool for mirroring repositories"}, "", false},
+		{"acme/sync", client.RepoResult{Description: "Mirror your files to S3"}, "", false},
+		{"acme/docs", client.RepoResult{Description: "A mirror of the team's conventions"}, "", false},
+		{"acme/lib", client.RepoResult{}, "", false},
+	}
+
+	for _, tt := range tests {
+		upstream, mirror := Detect(tt.repo, tt.result)
+		require.Equal(t, tt.mirror, mirror, tt.result.Description)
+		require.Equal(t, tt.upstream, upstream, tt.result.Description)
+	}
+}
+
+func TestGitHubUpstream(t *testing.T) {
+	t.Parallel()
+
+	got, ok := GitHubUpstream("acme/lib", "https://github.com/upstream/lib.git")
+	require.True(t, ok)
+	require.Equal(t, "upstream/lib", got)
+
+	_, ok = GitHubUpstream("acme/lib", "https://go.googlesource.com/lib")
+	require.False(t, ok)
+
+	_, ok = GitHubUpstream("acme/lib", "https://github.com/Acme/Lib")
+	require.False(t, ok)
+}
@@ -0,0 +1,185 @@
+// Package modpath maps Go module paths, including vanity import paths such
+// as go.uber.org/zap, to the GitHub repositories that host them, and module
+// versions to the tags they were released from.
+package modpath
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ErrNotGitHub is returned for modules hosted somewhere other than GitHub.
+var ErrNotGitHub = errors.New("not hosted on github")
+
+// Repo is the GitHub repository of a module.
+type Repo struct {
+	// Name is the "owner/repo" name of the repository.
+	Name string
+	// Root is the module path prefix that corresponds to the root of the
+	// repository, e.g. "github.com/owner/repo" or "go.uber.org/zap".
+	Root string
+}
+
+// Resolver resolves module paths. Vanity import paths are looked up like
+// the go command does, by fetching the page at the path with ?go-get=1.
+type Resolver struct {
+	HTTPClient *http.Client
+	// Scheme is the scheme of go-get requests. Empty means "https".
+	Scheme string
+}
+
+// Resolve returns the GitHub repository of the module at path, or
+// ErrNotGitHub if it is hosted elsewhere.
+func (r Resolver) Resolve(ctx context.Context, path string) (Repo, error) {
+	if repo, ok := known(path); ok {
+		return repo, nil
+	}
+
+	scheme := r.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+path+"?go-get=1", nil)
+	if err != nil {
+		return Repo{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Repo{}, fmt.Errorf("failed to fetch go-import metadata of %s: %w", path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return Repo{}, fmt.Errorf("failed to fetch go-import metadata of %s: %s", path, resp.Status)
+	}
+
+	// Meta tags are in the head, which is well within the first megabyte.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Repo{}, fmt.Errorf("failed to read go-import metadata of %s: %w", path, err)
+	}
+
+	return parseGoImport(string(body), path)
+}
+
+// known resolves the paths of GitHub and of hosts whose repositories are
+// mirrored on GitHub without a go-import tag pointing there.
+func known(path string) (Repo, bool) {
+	parts := strings.Split(path, "/")
+
+	switch {
+	case parts[0] == "github.com" && len(parts) >= 3:
+		return Repo{Name: parts[1] + "/" + parts[2], Root: strings.Join(parts[:3], "/")}, true
+	case parts[0] == "golang.org" && len(parts) >= 3 && parts[1] == "x":
+		// golang.org/x repositories are served from go.googlesource.com
+		// and mirrored to the golang organization.
+		return Repo{Name: "golang/" + parts[2], Root: strings.Join(parts[:3], "/")}, true
+	case parts[0] == "gopkg.in" && len(parts) == 2:
+		// gopkg.in/pkg.v3 is github.com/go-pkg/pkg.
+		name, _, _ := strings.Cut(parts[1], ".")
+
+		return Repo{Name: "go-" + name + "/" + name, Root: path}, true
+	case parts[0] == "gopkg.in" && len(parts) >= 3:
+		// gopkg.in/user/pkg.v3 is github.com/user/pkg.
+		name, _, _ := strings.Cut(parts[2], ".")
+
+		return Repo{Name: parts[1] + "/" + name, Root: strings.Join(parts[:3], "/")}, true
+	}
+
+	return Repo{}, false
+}
+
+var (
+	metaTag   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attribute = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*["']([^"']*)["']`)
+)
+
+// parseGoImport finds the go-import meta tag of a go-get page whose prefix
+// matches path and returns its repository if that is on GitHub.
+func parseGoImport(body, path string) (Repo, error) {
+	for _, tag := range metaTag.FindAllString(body, -1) {
+		attrs := map[string]string{}
+
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2]
+		}
+
+		if attrs["name"] != "go-import" {
+			continue
+		}
+
+		fields := strings.Fields(attrs["content"])
+		if len(fields) != 3 || fields[1] == "mod" {
+			continue
+		}
+
+		prefix, repoURL := fields[0], fields[2]
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(strings.TrimPrefix(strings.TrimPrefix(repoURL, "https://"), "http://"), "github.com/")
+		if !ok {
+			return Repo{}, fmt.Errorf("%s is hosted at %s: %w", path, repoURL, ErrNotGitHub)
+		}
+
+		owner, name, _ := strings.Cut(strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git"), "/")
+		if owner == "" || name == "" {
+			return Repo{}, fmt.Errorf("invalid repository url %s of %s", repoURL, path)
+		}
+
+		return Repo{Name: owner + "/" + name, Root: prefix}, nil
+	}
+
+	return Repo{}, fmt.Errorf("no go-import meta tag found for %s", path)
+}
+
+// Tag returns the tag version of the module at path was released from, or
+// false for pseudo-versions, which are not tagged. Modules in a subdirectory
+// of the repository have tags prefixed with the subdirectory, without any
+// major version suffix, e.g. "sub/v2.1.0" for github.com/owner/repo/sub/v2.
+func Tag(path, root, version string) (string, bool) {
+	version = strings.TrimSuffix(version, "+incompatible")
+
+	if !semver.IsValid(version) || module.IsPseudoVersion(version) {
+		return "", false
+	}
+
+	return TagPrefix(path, root) + version, true
+}
+
+// TagPrefix returns the prefix of the tags of the module at path in the
+// repository whose root is root, e.g. "sub/" for github.com/owner/repo/sub/v2,
+// or an empty string for modules at the root of the repository.
+func TagPrefix(path, root string) string {
+	prefix, _, ok := module.SplitPathVersion(path)
+	if !ok {
+		prefix = path
+	}
+
+	dir, found := strings.CutPrefix(prefix, root+"/")
+	if !found {
+		// The module is at the root of the repository, or its path is
+		// the root with a major version suffix.
+		return ""
+	}
+
+	return dir + "/"
+}
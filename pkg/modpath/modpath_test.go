@@ -0,0 +1,91 @@
+package modpath
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve_Known(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Repo{
+		"github.com/owner/repo":        {Name: "owner/repo", Root: "github.com/owner/repo"},
+		"github.com/owner/repo/sub/v2": {Name: "owner/repo", Root: "github.com/owner/repo"},
+		"golang.org/x/mod/semver":      {Name: "golang/mod", Root: "golang.org/x/mod"},
+		"gopkg.in/yaml.v3":             {Name: "go-yaml/yaml", Root: "gopkg.in/yaml.v3"},
+		"gopkg.in/owner/pkg.v1":        {Name: "owner/pkg", Root: "gopkg.in/owner/pkg.v1"},
+	}
+
+	for path, want := range tests {
+		got, err := Resolver{}.Resolve(context.Background(), path)
+		require.NoError(t, err, path)
+		require.Equal(t, want, got, path)
+	}
+}
+
+func TestResolve_Vanity(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "1", r.URL.Query().Get("go-get"))
+
+		host := r.Host
+
+		switch r.URL.Path {
+		case "/zap", "/zap/zapcore":
+			fmt.Fprintf(w, `<html><head>
+<meta name="go-import" content="%[1]s/zap mod https://proxy.example.com">
+<meta content="%[1]s/zap git https://github.com/uber-go/zap.git" name="go-import">
+</head></html>`, host)
+		case "/internal":
+			fmt.Fprintf(w, `<meta name="go-import" content="%s/internal git https://git.example.com/internal">`, host)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	r := Resolver{HTTPClient: srv.Client(), Scheme: "http"}
+
+	repo, err := r.Resolve(context.Background(), host+"/zap/zapcore")
+	require.NoError(t, err)
+	require.Equal(t, Repo{Name: "uber-go/zap", Root: host + "/zap"}, repo)
+
+	_, err = r.Resolve(context.Background(), host+"/internal")
+	require.ErrorIs(t, err, ErrNotGitHub)
+
+	_, err = r.Resolve(context.Background(), host+"/missing")
+	require.Error(t, err)
+}
+
+func TestTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path, root, version string
+		want                string
+		ok                  bool
+	}{
+		{"github.com/owner/repo", "github.com/owner/repo", "v1.2.3", "v1.2.3", true},
+		{"github.com/owner/repo/v2", "github.com/owner/repo", "v2.0.1", "v2.0.1", true},
+		{"github.com/owner/repo/sub", "github.com/owner/repo", "v0.3.0", "sub/v0.3.0", true},
+		{"github.com/owner/repo/sub/v2", "github.com/owner/repo", "v2.1.0", "sub/v2.1.0", true},
+		{"github.com/owner/repo", "github.com/owner/repo", "v3.0.0+incompatible", "v3.0.0", true},
+		{"gopkg.in/yaml.v3", "gopkg.in/yaml.v3", "v3.0.1", "v3.0.1", true},
+		{"github.com/owner/repo", "github.com/owner/repo", "v0.0.0-20240101000000-abcdefabcdef", "", false},
+		{"github.com/owner/repo", "github.com/owner/repo", "latest", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Tag(tt.path, tt.root, tt.version)
+		require.Equal(t, tt.ok, ok, tt.path+"@"+tt.version)
+		require.Equal(t, tt.want, got, tt.path+"@"+tt.version)
+	}
+}
@@ -0,0 +1,178 @@
+// Package notify posts a summary of a report to a webhook, such as a Slack
+// incoming webhook, so scheduled audits can announce their findings.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Payload formats.
+const (
+	// FormatAuto selects FormatSlack for Slack webhook URLs and FormatJSON
+	// otherwise.
+	FormatAuto = "auto"
+	// FormatSlack posts a message with Block Kit sections.
+	FormatSlack = "slack"
+	// FormatJSON posts the summary, per-status counts and findings. Its
+	// text field is understood by most chat webhooks.
+	FormatJSON = "json"
+)
+
+// Formats lists every supported payload format.
+var Formats = []string{FormatAuto, FormatSlack, FormatJSON}
+
+// maxListed is the most findings listed in a Slack message, which limits
+// the length of a section.
+const maxListed = 20
+
+// Payload is the generic JSON webhook body.
+type Payload struct {
+	Text     string            `json:"text"`
+	Counts   map[string]int    `json:"counts"`
+	Findings []finding.Finding `json:"findings"`
+}
+
+// slackMessage is a Slack incoming webhook body.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// resolve returns the payload format to post to endpoint.
+func resolve(format, endpoint string) (string, error) {
+	switch format {
+	case FormatSlack, FormatJSON:
+		return format, nil
+	case FormatAuto, "":
+		if u, err := url.Parse(endpoint); err == nil && u.Host == "hooks.slack.com" {
+			return FormatSlack, nil
+		}
+
+		return FormatJSON, nil
+	}
+
+	return "", fmt.Errorf("unsupported notification format %q, expected one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// Body returns the webhook body for report in format, which must not be
+// FormatAuto.
+func Body(format string, report finding.Report) ([]byte, error) {
+	text := "gh-arc: " + report.Summary()
+
+	var v any
+
+	switch format {
+	case FormatSlack:
+		msg := slackMessage{Text: text, Blocks: []slackBlock{
+			{Type: "section", Text: slackText{Type: "mrkdwn", Text: "*gh-arc:* " + report.Summary()}},
+		}}
+
+		if list := slackList(report); list != "" {
+			msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: slackText{Type: "mrkdwn", Text: list}})
+		}
+
+		v = msg
+	default:
+		counts := map[string]int{}
+		for st, n := range report.Counts() {
+			counts[st.String()] = n
+		}
+
+		v = Payload{Text: text, Counts: counts, Findings: report.Findings}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	return data, nil
+}
+
+// slackList lists the findings that fail the run, most severe first, as
+// links to their repositories.
+func slackList(report finding.Report) string {
+	findings := slices.Clone(report.Enforced().Findings)
+	findings = slices.DeleteFunc(findings, func(f finding.Finding) bool {
+		return !f.Status.Failing()
+	})
+
+	slices.SortStableFunc(findings, func(a, b finding.Finding) int {
+		return slices.Index(status.All, a.Status) - slices.Index(status.All, b.Status)
+	})
+
+	var b strings.Builder
+
+	for i, f := range findings {
+		if i == maxListed {
+			fmt.Fprintf(&b, "…and %d more\n", len(findings)-maxListed)
+
+			break
+		}
+
+		fmt.Fprintf(&b, "• <%s|%s> %s", f.URL(), f.Repo, f.Status)
+
+		if f.File != "" {
+			fmt.Fprintf(&b, " in `%s`", f.File)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Post sends report to endpoint in format.
+func Post(ctx context.Context, hc *http.Client, endpoint, format string, report finding.Report) error {
+	format, err := resolve(format, endpoint)
+	if err != nil {
+		return err
+	}
+
+	data, err := Body(format, report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to send notification: %s", resp.Status)
+	}
+
+	return nil
+}
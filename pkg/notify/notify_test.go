@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+var report = finding.Report{Findings: []finding.Finding{
+	{Module: "github.com/a/stale", Repo: "a/stale", File: "go.mod", Status: status.Stale},
+	{Module: "github.com/a/old", Repo: "a/old", File: "go.mod", Status: status.Archived},
+	{Module: "github.com/a/moved", Repo: "a/moved", File: "go.mod", Status: status.Moved},
+}}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	format, err := resolve(FormatAuto, "https://hooks.slack.com/services/T/B/X")
+	require.NoError(t, err)
+	require.Equal(t, FormatSlack, format)
+
+	format, err = resolve(FormatAuto, "https://example.com/hook")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSON, format)
+
+	_, err = resolve("xml", "https://example.com/hook")
+	require.Error(t, err)
+}
+
+func TestBody_Slack(t *testing.T) {
+	t.Parallel()
+
+	data, err := Body(FormatSlack, report)
+	require.NoError(t, err)
+
+	var msg slackMessage
+	require.NoError(t, json.Unmarshal(data, &msg))
+	require.Equal(t, "gh-arc: 1 archived, 1 stale, 1 moved across 3 modules", msg.Text)
+	require.Len(t, msg.Blocks, 2)
+	require.Equal(t, "• <https://github.com/a/old|a/old> archived in `go.mod`\n• <https://github.com/a/stale|a/stale> stale in `go.mod`\n", msg.Blocks[1].Text.Text)
+}
+
+func TestPost(t *testing.T) {
+	t.Parallel()
+
+	var got Payload
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &got))
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	require.NoError(t, Post(context.Background(), srv.Client(), srv.URL, FormatAuto, report))
+	require.Equal(t, "gh-arc: 1 archived, 1 stale, 1 moved across 3 modules", got.Text)
+	require.Equal(t, map[string]int{"archived": 1, "stale": 1, "moved": 1}, got.Counts)
+	require.Len(t, got.Findings, 3)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(failing.Close)
+
+	require.Error(t, Post(context.Background(), failing.Client(), failing.URL, FormatJSON, report))
+}
@@ -0,0 +1,236 @@
+// Package org scans every Go repository of a GitHub organization through the
+// API, without cloning them, for a fleet-level view of archived dependencies.
+package org
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/output"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// API is the part of the GitHub API needed to read the go.mod files of an
+// organization. client.Client implements it.
+type API interface {
+	OrgRepos(ctx context.Context, org string) ([]client.OrgRepo, error)
+	Files(ctx context.Context, repo, ref string) ([]string, error)
+	FileContents(ctx context.Context, repo, path, ref string) ([]byte, error)
+}
+
+// repoConcurrency is the number of repositories whose files are read at the
+// same time, which keeps large organizations clear of the secondary rate
+// limits of the API.
+const repoConcurrency = 8
+
+// goMod is a go.mod file read from a repository.
+type goMod struct {
+	name string
+	data []byte
+}
+
+// Repositories returns the repositories of org that are scanned: those whose
+// primary language is Go, except archived repositories and forks.
+func Repositories(ctx context.Context, api API, org string) ([]client.OrgRepo, error) {
+	all, err := api.OrgRepos(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []client.OrgRepo
+
+	for _, repo := range all {
+		if repo.Language == "Go" && !repo.Archived && !repo.Fork {
+			repos = append(repos, repo)
+		}
+	}
+
+	slices.SortFunc(repos, func(a, b client.OrgRepo) int {
+		return strings.Compare(a.FullName, b.FullName)
+	})
+
+	return repos, nil
+}
+
+// Discover returns the GitHub dependencies of every go.mod file on the
+// default branch of the Go repositories of org. Files are named after their
+// repository, e.g. "org/repo/cmd/go.mod", so findings are grouped by
+// repository. Repositories whose files cannot be listed, or whose go.mod
+// files cannot all be fetched, are returned as unreadable, sorted, with the
+// dependencies of the go.mod files that could be read.
+func Discover(ctx context.Context, api API, org string, progressWriter io.Writer) (map[string][]gomod.RepoInfo, []string, error) {
+	repos, err := Repositories(ctx, api, org)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slog.DebugContext(ctx, fmt.Sprintf("found %d go repositories in %s", len(repos), org))
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, repoConcurrency)
+
+		mu         sync.Mutex
+		unreadable []string
+	)
+
+	skip := func(repo string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !slices.Contains(unreadable, repo) {
+			unreadable = append(unreadable, repo)
+		}
+	}
+
+	collected := output.Start[goMod](progress.New(progressWriter, len(repos)))
+
+	for _, repo := range repos {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(repo client.OrgRepo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer collected.Done(repo.FullName)
+
+			paths, err := api.Files(ctx, repo.FullName, repo.DefaultBranch)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error listing files of %s: %v", repo.FullName, err))
+				skip(repo.FullName)
+
+				return
+			}
+
+			for _, p := range paths {
+				if !isGoMod(p) {
+					continue
+				}
+
+				data, err := api.FileContents(ctx, repo.FullName, p, repo.DefaultBranch)
+				if err != nil {
+					slog.DebugContext(ctx, fmt.Sprintf("error fetching %s of %s: %v", p, repo.FullName, err))
+					skip(repo.FullName)
+
+					continue
+				}
+
+				collected.Add(goMod{name: path.Join(repo.FullName, p), data: data})
+			}
+		}(repo)
+	}
+
+	wg.Wait()
+
+	deps := map[string][]gomod.RepoInfo{}
+
+	for _, f := range collected.Close() {
+		if err := gomod.ParseGoMod(f.name, f.data, deps); err != nil {
+			slog.DebugContext(ctx, err.Error())
+		}
+	}
+
+	slices.Sort(unreadable)
+
+	return deps, unreadable, nil
+}
+
+// isGoMod reports whether p is a go.mod file outside vendor and testdata
+// directories, which the go command ignores.
+func isGoMod(p string) bool {
+	if path.Base(p) != "go.mod" {
+		return false
+	}
+
+	for dir := range strings.SplitSeq(path.Dir(p), "/") {
+		if dir == "vendor" || dir == "testdata" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListArchived scans the Go repositories of org and renders the findings.
+// Repositories that could not be read are logged as a warning and make the
+// report partial.
+func ListArchived(ctx context.Context, api API, org string, opts gomod.Options) (finding.Report, error) {
+	repos, unreadable, err := Discover(ctx, api, org, opts.Progress)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	if len(unreadable) > 0 {
+		slog.WarnContext(ctx, fmt.Sprintf("could not read %d repositories of %s: %s", len(unreadable), org, strings.Join(unreadable, ", ")))
+	}
+
+	s := gomod.NewScanner(opts, opts.Output)
+	s.Unreadable = len(unreadable)
+
+	return s.Check(ctx, repos)
+}
+
+// Summary is the number of findings per status in one repository of the
+// organization.
+type Summary struct {
+	Repo   string
+	Counts status.Counts
+}
+
+// Summarize counts the findings of a report per scanned repository, the one
+// named by the first two elements of their file, most failing findings first.
+func Summarize(report finding.Report) []Summary {
+	index := map[string]int{}
+
+	var summaries []Summary
+
+	for _, f := range report.Findings {
+		parts := strings.SplitN(f.File, "/", 3)
+		if len(parts) < 3 {
+			continue
+		}
+
+		repo := parts[0] + "/" + parts[1]
+
+		i, ok := index[repo]
+		if !ok {
+			i = len(summaries)
+			index[repo] = i
+
+			summaries = append(summaries, Summary{Repo: repo, Counts: status.Counts{}})
+		}
+
+		summaries[i].Counts[f.Status]++
+	}
+
+	slices.SortStableFunc(summaries, func(a, b Summary) int {
+		return cmp.Or(cmp.Compare(b.Counts.Failing(), a.Counts.Failing()), strings.Compare(a.Repo, b.Repo))
+	})
+
+	return summaries
+}
+
+// WriteSummary writes one line per repository, e.g. "org/repo: 2 archived".
+func WriteSummary(w io.Writer, summaries []Summary) {
+	if len(summaries) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nper repository:")
+
+	for _, s := range summaries {
+		fmt.Fprintf(w, "  %s: %s\n", s.Repo, s.Counts)
+	}
+}
@@ -0,0 +1,139 @@
+package org
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// fakeAPI serves an organization from memory, keyed by repository and path.
+type fakeAPI struct {
+	repos []client.OrgRepo
+	files map[string]map[string]string
+}
+
+func (f fakeAPI) OrgRepos(context.Context, string) ([]client.OrgRepo, error) { return f.repos, nil }
+
+func (f fakeAPI) Files(_ context.Context, repo, _ string) ([]string, error) {
+	files, ok := f.files[repo]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+func (f fakeAPI) FileContents(_ context.Context, repo, path, _ string) ([]byte, error) {
+	return []byte(f.files[repo][path]), nil
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	api := fakeAPI{
+		repos: []client.OrgRepo{
+			{FullName: "acme/api", Language: "Go", DefaultBranch: "main"},
+			{FullName: "acme/web", Language: "TypeScript", DefaultBranch: "main"},
+			{FullName: "acme/old", Language: "Go", Archived: true, DefaultBranch: "main"},
+			{FullName: "acme/fork", Language: "Go", Fork: true, DefaultBranch: "main"},
+			{FullName: "acme/broken", Language: "Go", DefaultBranch: "main"},
+		},
+		files: map[string]map[string]string{
+			"acme/api": {
+				"go.mod":                   "module acme/api\n\nrequire github.com/owner/a v1.0.0\n",
+				"tools/go.mod":             "module acme/api/tools\n\nrequire github.com/owner/b v1.0.0\n",
+				"vendor/x/go.mod":          "module x\n\nrequire github.com/owner/c v1.0.0\n",
+				"internal/testdata/go.mod": "module y\n\nrequire github.com/owner/d v1.0.0\n",
+				"main.go":                  "package main\n",
+			},
+			"acme/old": {"go.mod": "module acme/old\n\nrequire github.com/owner/e v1.0.0\n"},
+		},
+	}
+
+	repos, unreadable, err := Discover(context.Background(), api, "acme", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme/broken"}, unreadable)
+	require.Equal(t, map[string][]gomod.RepoInfo{
+		"owner/a": {gomod.NewRepoInfo("acme/api/go.mod", 3, 1, "github.com/owner/a", "v1.0.0", false)},
+		"owner/b": {gomod.NewRepoInfo("acme/api/tools/go.mod", 3, 1, "github.com/owner/b", "v1.0.0", false)},
+	}, repos)
+}
+
+// concurrencyAPI records how many repositories are read at the same time.
+type concurrencyAPI struct {
+	fakeAPI
+
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (c *concurrencyAPI) Files(ctx context.Context, repo, ref string) ([]string, error) {
+	c.mu.Lock()
+	c.current++
+	c.max = max(c.max, c.current)
+	c.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	c.mu.Lock()
+	c.current--
+	c.mu.Unlock()
+
+	return c.fakeAPI.Files(ctx, repo, ref)
+}
+
+func TestDiscover_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	api := &concurrencyAPI{fakeAPI: fakeAPI{files: map[string]map[string]string{}}}
+
+	for i := range 5 * repoConcurrency {
+		name := fmt.Sprintf("acme/repo%d", i)
+
+		api.repos = append(api.repos, client.OrgRepo{FullName: name, Language: "Go", DefaultBranch: "main"})
+		api.files[name] = map[string]string{"go.mod": "module " + name + "\n\nrequire github.com/owner/a v1.0.0\n"}
+	}
+
+	repos, unreadable, err := Discover(context.Background(), api, "acme", nil)
+	require.NoError(t, err)
+	require.Empty(t, unreadable)
+	require.Len(t, repos["owner/a"], 5*repoConcurrency)
+	require.LessOrEqual(t, api.max, repoConcurrency)
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{File: "acme/api/go.mod", Status: status.Moved},
+		{File: "acme/cli/go.mod", Status: status.Archived},
+		{File: "acme/cli/tools/go.mod", Status: status.Missing},
+	}}
+
+	summaries := Summarize(report)
+	require.Equal(t, []Summary{
+		{Repo: "acme/cli", Counts: status.Counts{status.Archived: 1, status.Missing: 1}},
+		{Repo: "acme/api", Counts: status.Counts{status.Moved: 1}},
+	}, summaries)
+
+	var buf bytes.Buffer
+
+	WriteSummary(&buf, summaries)
+	require.Equal(t, "\nper repository:\n  acme/cli: 1 missing, 1 archived\n  acme/api: 1 moved\n", buf.String())
+}
@@ -0,0 +1,86 @@
+// Package output serializes everything a scan writes while it runs. Workers
+// send results and progress over a channel to a single coordinator
+// goroutine, the only one that redraws the progress line, appends to the
+// collected results and calls sinks, so writes never interleave and sinks
+// need no locking of their own.
+package output
+
+import (
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+)
+
+// Sink receives every result as it is collected, on the coordinator
+// goroutine.
+type Sink[T any] func(T)
+
+// event is either a result or, if done is set, a finished item for the
+// progress line.
+type event[T any] struct {
+	result T
+	done   string
+}
+
+// Coordinator collects results of type T sent by concurrent workers. Its
+// methods other than Close are safe for concurrent use.
+type Coordinator[T any] struct {
+	events  chan event[T]
+	stopped chan struct{}
+	bar     *progress.Bar
+	sinks   []Sink[T]
+	results []T
+}
+
+// Start starts a coordinator that reports progress on bar, which may be nil,
+// and passes every result to sinks.
+func Start[T any](bar *progress.Bar, sinks ...Sink[T]) *Coordinator[T] {
+	c := &Coordinator[T]{
+		events:  make(chan event[T]),
+		stopped: make(chan struct{}),
+		bar:     bar,
+		sinks:   sinks,
+	}
+
+	go c.run()
+
+	return c
+}
+
+func (c *Coordinator[T]) run() {
+	defer close(c.stopped)
+
+	for e := range c.events {
+		if e.done != "" {
+			c.bar.Done(e.done)
+
+			continue
+		}
+
+		c.results = append(c.results, e.result)
+
+		for _, sink := range c.sinks {
+			sink(e.result)
+		}
+	}
+
+	c.bar.Clear()
+}
+
+// Done counts an item, such as a repository, as finished.
+func (c *Coordinator[T]) Done(name string) {
+	c.events <- event[T]{done: name}
+}
+
+// Add collects a result.
+func (c *Coordinator[T]) Add(result T) {
+	c.events <- event[T]{result: result}
+}
+
+// Close waits for every sent event to be handled, clears the progress line
+// and returns the results in the order they were received. It must be
+// called once, after every worker has finished.
+func (c *Coordinator[T]) Close() []T {
+	close(c.events)
+	<-c.stopped
+
+	return c.results
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/progress"
+)
+
+func TestCoordinator(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf  bytes.Buffer
+		seen []int
+		wg   sync.WaitGroup
+	)
+
+	c := Start(progress.New(&buf, 50), func(i int) { seen = append(seen, i) })
+
+	for i := range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			c.Add(i)
+			c.Done("owner/repo")
+		}()
+	}
+
+	wg.Wait()
+
+	results := c.Close()
+	require.ElementsMatch(t, results, seen)
+	require.Len(t, results, 50)
+	require.Equal(t, 50, strings.Count(buf.String(), "checked "))
+	require.True(t, strings.HasSuffix(buf.String(), "checked 50/50 repositories: owner/repo\r\033[K"))
+}
+
+func TestCoordinator_NilBar(t *testing.T) {
+	t.Parallel()
+
+	c := Start[string](nil)
+	c.Add("a")
+	c.Done("owner/repo")
+
+	require.Equal(t, []string{"a"}, c.Close())
+}
@@ -0,0 +1,142 @@
+// Package owners summarizes a report per repository owner, so that
+// concentrated risk stands out, such as depending on many projects of an
+// organization that stopped maintaining all of them.
+package owners
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Owner summarizes the findings in repositories of one user or organization.
+// A repository required in several places is counted once.
+type Owner struct {
+	// Owner is the user or organization, e.g. "owner" of "owner/repo".
+	Owner string `json:"owner"`
+	// Repos counts the repositories of Owner with findings.
+	Repos int `json:"repos"`
+	// Archived counts the archived repositories of Owner.
+	Archived int `json:"archived"`
+	// EarliestPush is the oldest last push across the repositories, if any
+	// is known.
+	EarliestPush string `json:"earliest_push,omitempty"`
+	// Counts counts the repositories per status.
+	Counts status.Counts `json:"counts"`
+}
+
+// Summarize returns one summary per owner with findings in report, those
+// with the most archived repositories first, then those with the most
+// repositories.
+func Summarize(report finding.Report) []Owner {
+	type repoStatus struct {
+		repo   string
+		status status.Status
+	}
+
+	var summaries []Owner
+
+	repos := map[string]bool{}
+	seen := map[repoStatus]bool{}
+
+	for _, f := range report.Findings {
+		key := repoStatus{repo: f.Repo, status: f.Status}
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		owner, _, _ := strings.Cut(f.Repo, "/")
+
+		i := slices.IndexFunc(summaries, func(o Owner) bool { return o.Owner == owner })
+		if i < 0 {
+			summaries = append(summaries, Owner{Owner: owner, Counts: status.Counts{}})
+			i = len(summaries) - 1
+		}
+
+		o := &summaries[i]
+
+		if !repos[f.Repo] {
+			repos[f.Repo] = true
+			o.Repos++
+		}
+
+		o.Counts[f.Status]++
+
+		if f.Status == status.Archived {
+			o.Archived++
+		}
+
+		if earlier(f.PushedAt, o.EarliestPush) {
+			o.EarliestPush = f.PushedAt
+		}
+	}
+
+	slices.SortFunc(summaries, func(a, b Owner) int {
+		return cmp.Or(
+			cmp.Compare(b.Archived, a.Archived),
+			cmp.Compare(b.Repos, a.Repos),
+			strings.Compare(a.Owner, b.Owner),
+		)
+	})
+
+	return summaries
+}
+
+// earlier reports whether the RFC 3339 time a is before b, treating an
+// unparsable b as later than any valid a.
+func earlier(a, b string) bool {
+	at, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+
+	bt, err := time.Parse(time.RFC3339, b)
+	if err != nil {
+		return true
+	}
+
+	return at.Before(bt)
+}
+
+// Text writes the summaries as a table.
+func Text(w io.Writer, summaries []Owner) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "OWNER\tREPOS\tARCHIVED\tEARLIEST PUSH\tFINDINGS")
+
+	for _, o := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", o.Owner, o.Repos, o.Archived, cmp.Or(o.EarliestPush, "-"), o.Counts)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write owners: %w", err)
+	}
+
+	return nil
+}
+
+// JSON writes the summaries as an indented JSON array.
+func JSON(w io.Writer, summaries []Owner) error {
+	if summaries == nil {
+		summaries = []Owner{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(summaries); err != nil {
+		return fmt.Errorf("failed to encode owners: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package owners
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "acme/a", File: "go.mod", Status: status.Missing},
+		{Repo: "vendor/a", File: "go.mod", Status: status.Archived, PushedAt: "2021-01-01T00:00:00Z"},
+		{Repo: "vendor/a", File: "tools/go.mod", Status: status.Archived, PushedAt: "2021-01-01T00:00:00Z"},
+		{Repo: "vendor/b", File: "go.mod", Status: status.Archived, PushedAt: "2019-06-01T02:00:00+02:00"},
+		{Repo: "vendor/c", File: "go.mod", Status: status.Stale, PushedAt: "2022-01-01T00:00:00Z"},
+		{Repo: "other/a", File: "go.mod", Status: status.Stale, PushedAt: "2023-01-01T00:00:00Z"},
+		{Repo: "other/b", File: "go.mod", Status: status.Stale},
+	}}
+
+	require.Equal(t, []Owner{
+		{Owner: "vendor", Repos: 3, Archived: 2, EarliestPush: "2019-06-01T02:00:00+02:00", Counts: status.Counts{status.Archived: 2, status.Stale: 1}},
+		{Owner: "other", Repos: 2, EarliestPush: "2023-01-01T00:00:00Z", Counts: status.Counts{status.Stale: 2}},
+		{Owner: "acme", Repos: 1, Counts: status.Counts{status.Missing: 1}},
+	}, Summarize(report))
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Text(&buf, []Owner{
+		{Owner: "vendor", Repos: 2, Archived: 2, EarliestPush: "2019-06-01T00:00:00Z", Counts: status.Counts{status.Archived: 2}},
+		{Owner: "acme", Repos: 1, Counts: status.Counts{status.Missing: 1}},
+	}))
+	require.Equal(t, ""+
+		"OWNER   REPOS  ARCHIVED  EARLIEST PUSH         FINDINGS\n"+
+		"vendor  2      2         2019-06-01T00:00:00Z  2 archived\n"+
+		"acme    1      0         -                     1 missing\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, JSON(&buf, nil))
+	require.Equal(t, "[]\n", buf.String())
+}
@@ -0,0 +1,146 @@
+// Package packagist provides a minimal client for the Packagist metadata API,
+// used to resolve PHP packages to their source repositories.
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// DefaultBaseURL is the Packagist metadata endpoint.
+const DefaultBaseURL = "https://repo.packagist.org"
+
+// concurrency is the number of packages looked up at the same time. Packagist
+// has no batch endpoint.
+const concurrency = 8
+
+// Client queries the Packagist metadata API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a Client for the public Packagist API.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 10 * time.Second}, DefaultBaseURL)
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client and endpoint (for testing).
+func NewWithHTTPClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{httpClient: httpClient, baseURL: baseURL}
+}
+
+// Normalize returns the normalized form of a package name. Packagist names
+// are case-insensitive and always lowercase.
+func Normalize(name string) string {
+	return strings.ToLower(name)
+}
+
+// Repositories returns the GitHub repository URL of each named package.
+// Packages that do not exist or do not link to GitHub are omitted.
+func (c *Client) Repositories(ctx context.Context, names []string) (map[string]string, error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, concurrency)
+		urls = map[string]string{}
+	)
+
+	for _, name := range names {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			u, err := c.repository(ctx, name)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching package %s: %v", name, err))
+
+				return
+			}
+
+			if u == "" {
+				return
+			}
+
+			mu.Lock()
+			urls[name] = u
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return urls, nil
+}
+
+// repository returns the first GitHub URL among the source, support and
+// homepage links of the newest version of a package, or an empty string if
+// it links to none.
+func (c *Client) repository(ctx context.Context, name string) (string, error) {
+	vendor, pkg, ok := strings.Cut(Normalize(name), "/")
+	if !ok {
+		return "", fmt.Errorf("invalid package name %q", name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/p2/"+url.PathEscape(vendor)+"/"+url.PathEscape(pkg)+".json", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch package %s: %w", name, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch package %s: %s", name, resp.Status)
+	}
+
+	// Versions are listed newest first, and only the first is complete;
+	// the others only list what changed from the previous one.
+	var body struct {
+		Packages map[string][]struct {
+			Homepage string `json:"homepage"`
+			Source   struct {
+				URL string `json:"url"`
+			} `json:"source"`
+			Support struct {
+				Source string `json:"source"`
+			} `json:"support"`
+		} `json:"packages"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode package %s: %w", name, err)
+	}
+
+	versions := body.Packages[vendor+"/"+pkg]
+	if len(versions) == 0 {
+		return "", nil
+	}
+
+	for _, u := range []string{versions[0].Source.URL, versions[0].Support.Source, versions[0].Homepage} {
+		if _, ok := client.RepoFromURL(u); ok {
+			return u, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,32 @@
+package packagist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/p2/monolog/monolog.json":
+			_, _ = w.Write([]byte(`{"packages":{"monolog/monolog":[{"version":"3.5.0","homepage":"https://github.com/Seldaek/monolog","source":{"type":"git","url":"https://github.com/Seldaek/monolog.git"}},{"version":"3.4.0"}]}}`))
+		case "/p2/acme/internal.json":
+			_, _ = w.Write([]byte(`{"packages":{"acme/internal":[{"version":"1.0.0","source":{"url":"https://gitlab.com/acme/internal.git"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	got, err := c.Repositories(context.Background(), []string{"Monolog/Monolog", "acme/internal", "acme/missing"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Monolog/Monolog": "https://github.com/Seldaek/monolog.git"}, got)
+}
@@ -0,0 +1,358 @@
+// Package pip provides commands for scanning Python dependencies declared in
+// requirements.txt, pyproject.toml and poetry.lock files and reporting
+// archived GitHub repositories.
+package pip
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/pypi"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+	"github.com/wayneashleyberry/gh-arc/pkg/tomlscan"
+)
+
+// Manifests are the file names read for direct dependencies.
+var Manifests = []string{"requirements.txt", "pyproject.toml"}
+
+// LockFile is the file name read for locked and indirect dependencies.
+const LockFile = "poetry.lock"
+
+// projectName matches the project name at the start of a PEP 508 requirement.
+var projectName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+// eggName matches the project name of an editable VCS requirement.
+var eggName = regexp.MustCompile(`#egg=([A-Za-z0-9._-]+)`)
+
+// parseRequirement parses a PEP 508 requirement such as
+// `requests[socks]==2.32.0; python_version > "3.8"` or
+// `pkg @ git+https://github.com/owner/pkg`. It returns false for
+// requirements without a project name.
+func parseRequirement(s string) (resolve.Dependency, bool) {
+	s, _, _ = strings.Cut(s, ";")
+	s = strings.TrimSpace(s)
+
+	name := projectName.FindString(s)
+	if name == "" {
+		return resolve.Dependency{}, false
+	}
+
+	rest := strings.TrimSpace(s[len(name):])
+	if strings.HasPrefix(rest, ":") {
+		// A bare URL or path, such as https://example.com/pkg.whl.
+		return resolve.Dependency{}, false
+	}
+
+	dep := resolve.Dependency{Name: name}
+
+	if i := strings.Index(rest, "]"); strings.HasPrefix(rest, "[") && i >= 0 {
+		rest = strings.TrimSpace(rest[i+1:])
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "@"):
+		dep.URL = strings.TrimSpace(rest[1:])
+	case strings.HasPrefix(rest, "=="):
+		dep.Version = strings.TrimSpace(rest[2:])
+	default:
+		dep.Version = rest
+	}
+
+	return dep, true
+}
+
+// parseRequirements returns the dependencies listed in a requirements.txt
+// file. Options such as -r and -c are skipped, except for editable VCS
+// requirements.
+func parseRequirements(name string, data []byte) []resolve.Dependency {
+	var deps []resolve.Dependency
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if editable, ok := strings.CutPrefix(line, "-e "); ok {
+			m := eggName.FindStringSubmatch(editable)
+			if m == nil {
+				continue
+			}
+
+			deps = append(deps, resolve.Dependency{Name: m[1], URL: strings.TrimSpace(editable), File: name, Line: lineNo})
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		dep, ok := parseRequirement(line)
+		if !ok {
+			continue
+		}
+
+		dep.File = name
+		dep.Line = lineNo
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
+
+// isPoetryDependencyTable reports whether a pyproject.toml table lists
+// Poetry dependencies, e.g. [tool.poetry.dependencies] or
+// [tool.poetry.group.dev.dependencies].
+func isPoetryDependencyTable(keys []string) bool {
+	if len(keys) < 3 || keys[0] != "tool" || keys[1] != "poetry" {
+		return false
+	}
+
+	last := keys[len(keys)-1]
+
+	return last == "dependencies" || last == "dev-dependencies"
+}
+
+// parsePyproject returns the dependencies declared in a pyproject.toml file,
+// as PEP 621 [project] requirements, PEP 735 dependency groups and in
+// Poetry's dependency tables.
+func parsePyproject(name string, data []byte) []resolve.Dependency {
+	var (
+		deps []resolve.Dependency
+		keys []string
+		// inArray is set while reading the lines of a multi-line array of
+		// requirements.
+		inArray bool
+	)
+
+	addRequirements := func(s string, lineNo int) {
+		for _, req := range tomlscan.Strings(s) {
+			dep, ok := parseRequirement(req)
+			if !ok {
+				continue
+			}
+
+			dep.File = name
+			dep.Line = lineNo
+			deps = append(deps, dep)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := tomlscan.StripComment(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if inArray {
+			addRequirements(line, lineNo)
+
+			inArray = !strings.HasPrefix(line, "]") && !strings.HasSuffix(line, "]")
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			keys = tomlscan.TableKeys(line)
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case slices.Equal(keys, []string{"project"}) && key == "dependencies",
+			slices.Equal(keys, []string{"project", "optional-dependencies"}),
+			slices.Equal(keys, []string{"dependency-groups"}):
+			if !strings.HasPrefix(value, "[") {
+				continue
+			}
+
+			addRequirements(value, lineNo)
+
+			inArray = !strings.HasSuffix(value, "]")
+		case isPoetryDependencyTable(keys):
+			if key == "python" {
+				continue
+			}
+
+			dep := resolve.Dependency{Name: strings.Trim(key, `"'`), File: name, Line: lineNo}
+
+			if strings.HasPrefix(value, "{") {
+				local := false
+
+				for _, f := range tomlscan.StringFields(value) {
+					switch f.Key {
+					case "version":
+						dep.Version = f.Value
+					case "git":
+						dep.URL = f.Value
+					case "path":
+						local = true
+					}
+				}
+
+				if local && dep.URL == "" {
+					continue
+				}
+			} else {
+				dep.Version = strings.Trim(value, `"'`)
+			}
+
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
+
+// parsePoetryLock returns the packages recorded in a poetry.lock file.
+// Packages installed from local directories, files or URLs are omitted.
+func parsePoetryLock(name string, data []byte) []resolve.Dependency {
+	var (
+		deps       []resolve.Dependency
+		dep        *resolve.Dependency
+		sourceType string
+		sourceURL  string
+		inSource   bool
+	)
+
+	flush := func() {
+		if dep == nil {
+			return
+		}
+
+		switch sourceType {
+		case "", "legacy":
+			deps = append(deps, *dep)
+		case "git":
+			dep.URL = sourceURL
+			deps = append(deps, *dep)
+		}
+
+		dep, sourceType, sourceURL = nil, "", ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			keys := tomlscan.TableKeys(line)
+			inSource = slices.Equal(keys, []string{"package", "source"})
+
+			if line == "[[package]]" {
+				flush()
+
+				dep = &resolve.Dependency{File: name, Line: lineNo, Indirect: true}
+			}
+
+			continue
+		}
+
+		if dep == nil {
+			continue
+		}
+
+		for _, f := range tomlscan.StringFields(line) {
+			switch {
+			case inSource && f.Key == "type":
+				sourceType = f.Value
+			case inSource && f.Key == "url":
+				sourceURL = f.Value
+			case !inSource && f.Key == "name" && dep.Name == "":
+				dep.Name = f.Value
+				dep.Line = lineNo
+			case !inSource && f.Key == "version" && dep.Version == "":
+				dep.Version = f.Value
+			}
+		}
+	}
+
+	flush()
+
+	return deps
+}
+
+// Discover parses the given requirements.txt, pyproject.toml and poetry.lock
+// files. Projects declared in a manifest are direct dependencies and take
+// their version from a lock file when one records them; projects only found
+// in a poetry.lock are indirect.
+func Discover(ctx context.Context, manifests, lockfiles []string) []resolve.Dependency {
+	var direct, locked []resolve.Dependency
+
+	for _, name := range manifests {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		if strings.HasSuffix(name, ".toml") {
+			direct = append(direct, parsePyproject(name, data)...)
+		} else {
+			direct = append(direct, parseRequirements(name, data)...)
+		}
+	}
+
+	for _, name := range lockfiles {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		locked = append(locked, parsePoetryLock(name, data)...)
+	}
+
+	return resolve.Merge(direct, locked, pypi.Normalize)
+}
+
+// Repos returns the repositories of the Python projects required in
+// opts.Scope, looking them up on PyPI.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	var manifests []string
+
+	for _, name := range Manifests {
+		found, err := files.RecursiveFind(ctx, opts.Scope, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s files: %w", name, err)
+		}
+
+		manifests = append(manifests, found...)
+	}
+
+	lockfiles, err := files.RecursiveFind(ctx, opts.Scope, LockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", LockFile, err)
+	}
+
+	return resolve.Repos(ctx, pypi.NewWithHTTPClient(opts.Timeouts.HTTPClient(pypi.DefaultBaseURL), pypi.DefaultBaseURL), Discover(ctx, manifests, lockfiles), opts)
+}
@@ -0,0 +1,146 @@
+package pip
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/resolve"
+)
+
+func TestParseRequirement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want resolve.Dependency
+	}{
+		{"requests==2.32.0", resolve.Dependency{Name: "requests", Version: "2.32.0"}},
+		{"requests[socks] >= 2.0 ; python_version > '3.8'", resolve.Dependency{Name: "requests", Version: ">= 2.0"}},
+		{"flask", resolve.Dependency{Name: "flask"}},
+		{"pkg @ git+https://github.com/owner/pkg.git@main", resolve.Dependency{Name: "pkg", URL: "git+https://github.com/owner/pkg.git@main"}},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRequirement(tt.in)
+		require.True(t, ok, tt.in)
+		require.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, ok := parseRequirement("https://example.com/pkg.whl")
+	require.False(t, ok)
+}
+
+func TestParseRequirements(t *testing.T) {
+	t.Parallel()
+
+	data := `# comment
+-r base.txt
+requests==2.32.0  # pinned
+
+-e git+https://github.com/owner/editable.git#egg=editable
+--index-url https://example.com
+`
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "requests", Version: "2.32.0", File: "requirements.txt", Line: 3},
+		{Name: "editable", URL: "git+https://github.com/owner/editable.git#egg=editable", File: "requirements.txt", Line: 5},
+	}, parseRequirements("requirements.txt", []byte(data)))
+}
+
+func TestParsePyproject(t *testing.T) {
+	t.Parallel()
+
+	data := `[project]
+name = "app"
+dependencies = [
+    "requests>=2", # http
+    "rich",
+]
+
+[project.optional-dependencies]
+test = ["pytest==8.0.0"]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+httpx = "^0.27"
+forked = { git = "https://github.com/owner/forked.git", branch = "main" }
+local = { path = "../local" }
+
+[tool.poetry.group.dev.dependencies]
+black = { version = "^24.0" }
+`
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "requests", Version: ">=2", File: "pyproject.toml", Line: 4},
+		{Name: "rich", File: "pyproject.toml", Line: 5},
+		{Name: "pytest", Version: "8.0.0", File: "pyproject.toml", Line: 9},
+		{Name: "httpx", Version: "^0.27", File: "pyproject.toml", Line: 13},
+		{Name: "forked", URL: "https://github.com/owner/forked.git", File: "pyproject.toml", Line: 14},
+		{Name: "black", Version: "^24.0", File: "pyproject.toml", Line: 18},
+	}, parsePyproject("pyproject.toml", []byte(data)))
+}
+
+const poetryLock = `[[package]]
+name = "httpx"
+version = "0.27.0"
+description = "The next generation HTTP client."
+
+[package.dependencies]
+anyio = "*"
+
+[[package]]
+name = "anyio"
+version = "4.4.0"
+
+[[package]]
+name = "forked"
+version = "1.0.0"
+
+[package.source]
+type = "git"
+url = "https://github.com/owner/forked.git"
+reference = "main"
+
+[[package]]
+name = "local"
+version = "0.1.0"
+
+[package.source]
+type = "directory"
+url = "../local"
+
+[metadata]
+python-versions = "^3.11"
+`
+
+func TestParsePoetryLock(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "httpx", Version: "0.27.0", File: "poetry.lock", Line: 2, Indirect: true},
+		{Name: "anyio", Version: "4.4.0", File: "poetry.lock", Line: 10, Indirect: true},
+		{Name: "forked", Version: "1.0.0", File: "poetry.lock", Line: 14, Indirect: true, URL: "https://github.com/owner/forked.git"},
+	}, parsePoetryLock("poetry.lock", []byte(poetryLock)))
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	requirements := filepath.Join(dir, "requirements.txt")
+	lock := filepath.Join(dir, "poetry.lock")
+
+	require.NoError(t, os.WriteFile(requirements, []byte("HTTPX>=0.20\n"), 0o600))
+	require.NoError(t, os.WriteFile(lock, []byte(poetryLock), 0o600))
+
+	deps := Discover(context.Background(), []string{requirements}, []string{lock})
+
+	require.Equal(t, []resolve.Dependency{
+		{Name: "HTTPX", Version: "0.27.0", File: requirements, Line: 1},
+		{Name: "anyio", Version: "4.4.0", File: lock, Line: 10, Indirect: true},
+		{Name: "forked", Version: "1.0.0", File: lock, Line: 14, Indirect: true, URL: "https://github.com/owner/forked.git"},
+	}, deps)
+}
@@ -0,0 +1,59 @@
+// Package policy decides whether a run fails based on its findings, so teams
+// can adopt arc gradually: warn only at first, then enforce.
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Supported values for Policy.FailOn.
+const (
+	// FailOnNone never fails the run.
+	FailOnNone = "none"
+	// FailOnDirect fails the run only for findings in direct dependencies.
+	FailOnDirect = "direct"
+	// FailOnAny fails the run for findings in any dependency.
+	FailOnAny = "any"
+)
+
+// FailOnValues lists every supported value for Policy.FailOn.
+var FailOnValues = []string{FailOnNone, FailOnDirect, FailOnAny}
+
+// Policy configures when a run fails.
+type Policy struct {
+	// FailOn is one of FailOnValues. An empty value means FailOnAny.
+	FailOn string
+	// MaxFindings is the number of failing findings tolerated before the run
+	// fails.
+	MaxFindings int
+}
+
+// Validate reports whether the policy is well formed.
+func (p Policy) Validate() error {
+	if p.FailOn != "" && !slices.Contains(FailOnValues, p.FailOn) {
+		return fmt.Errorf("unsupported fail-on value %q, expected one of: %s", p.FailOn, strings.Join(FailOnValues, ", "))
+	}
+
+	if p.MaxFindings < 0 {
+		return fmt.Errorf("max findings must not be negative: %d", p.MaxFindings)
+	}
+
+	return nil
+}
+
+// Failed reports whether a run with the given findings should fail. all counts
+// every finding and direct counts only findings in direct dependencies.
+func (p Policy) Failed(all, direct status.Counts) bool {
+	switch p.FailOn {
+	case FailOnNone:
+		return false
+	case FailOnDirect:
+		return direct.Failing() > p.MaxFindings
+	}
+
+	return all.Failing() > p.MaxFindings
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestPolicy_Failed(t *testing.T) {
+	t.Parallel()
+
+	all := status.Counts{status.Archived: 2, status.Moved: 4}
+	direct := status.Counts{status.Archived: 1}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   bool
+	}{
+		{"default", Policy{}, true},
+		{"none", Policy{FailOn: FailOnNone}, false},
+		{"any", Policy{FailOn: FailOnAny}, true},
+		{"direct", Policy{FailOn: FailOnDirect}, true},
+		{"direct under threshold", Policy{FailOn: FailOnDirect, MaxFindings: 1}, false},
+		{"any at threshold", Policy{FailOn: FailOnAny, MaxFindings: 2}, false},
+		{"any over threshold", Policy{FailOn: FailOnAny, MaxFindings: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.policy.Failed(all, direct))
+		})
+	}
+
+	require.False(t, Policy{}.Failed(status.Counts{status.Moved: 1}, status.Counts{}))
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Policy{}.Validate())
+	require.NoError(t, Policy{FailOn: FailOnDirect, MaxFindings: 3}.Validate())
+	require.Error(t, Policy{FailOn: "sometimes"}.Validate())
+	require.Error(t, Policy{MaxFindings: -1}.Validate())
+}
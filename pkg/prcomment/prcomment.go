@@ -0,0 +1,123 @@
+// Package prcomment publishes a report as a single sticky comment on a pull
+// request, which is edited on every run instead of a new comment being added.
+package prcomment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+)
+
+// Auto is the --comment-pr value that detects the pull request from the
+// GitHub Actions environment.
+const Auto = "auto"
+
+// Marker identifies comments published by arc. It is hidden in the rendered
+// comment.
+const Marker = "<!-- gh-arc -->"
+
+// ErrNoPullRequest is returned when the pull request cannot be detected.
+var ErrNoPullRequest = errors.New("not running for a pull request")
+
+// API lists, creates and edits comments. client.Client implements it.
+type API interface {
+	IssueComments(ctx context.Context, repo string, number int) ([]client.Comment, error)
+	CreateComment(ctx context.Context, repo string, number int, body string) (client.Comment, error)
+	UpdateComment(ctx context.Context, repo string, id int64, body string) (client.Comment, error)
+}
+
+// pullRef matches the ref GitHub Actions checks out for pull requests.
+var pullRef = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// Detect returns the number of the pull request a GitHub Actions workflow
+// runs for, read with getenv, from GITHUB_REF or the event payload at
+// GITHUB_EVENT_PATH.
+func Detect(getenv func(string) string) (int, error) {
+	if m := pullRef.FindStringSubmatch(getenv("GITHUB_REF")); m != nil {
+		return strconv.Atoi(m[1])
+	}
+
+	path := getenv("GITHUB_EVENT_PATH")
+	if path == "" {
+		return 0, ErrNoPullRequest
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return 0, fmt.Errorf("failed to read event payload: %w", err)
+	}
+
+	var event struct {
+		PullRequest *struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0, fmt.Errorf("failed to decode event payload: %w", err)
+	}
+
+	if event.PullRequest == nil || event.PullRequest.Number == 0 {
+		return 0, ErrNoPullRequest
+	}
+
+	return event.PullRequest.Number, nil
+}
+
+// Number parses a --comment-pr value: a pull request number, or Auto to
+// detect it with Detect.
+func Number(value string, getenv func(string) string) (int, error) {
+	if value == Auto {
+		return Detect(getenv)
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid pull request %q, expected a number or %q", value, Auto)
+	}
+
+	return n, nil
+}
+
+// Body returns the comment publishing report.
+func Body(report finding.Report) string {
+	var buf bytes.Buffer
+
+	buf.WriteString(Marker + "\n")
+	render.Markdown(&buf, report)
+
+	return buf.String()
+}
+
+// Publish comments report on pull request number of repo, editing the
+// comment published by an earlier run if there is one.
+func Publish(ctx context.Context, api API, repo string, number int, report finding.Report) (client.Comment, error) {
+	comments, err := api.IssueComments(ctx, repo, number)
+	if err != nil {
+		return client.Comment{}, err
+	}
+
+	body := Body(report)
+
+	for _, c := range comments {
+		if strings.HasPrefix(c.Body, Marker) {
+			if c.Body == body {
+				return c, nil
+			}
+
+			return api.UpdateComment(ctx, repo, c.ID, body)
+		}
+	}
+
+	return api.CreateComment(ctx, repo, number, body)
+}
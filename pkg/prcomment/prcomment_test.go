@@ -0,0 +1,113 @@
+package prcomment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// mockAPI keeps the comments of a single pull request.
+type mockAPI struct {
+	comments []client.Comment
+	updates  int
+}
+
+func (m *mockAPI) IssueComments(context.Context, string, int) ([]client.Comment, error) {
+	return m.comments, nil
+}
+
+func (m *mockAPI) CreateComment(_ context.Context, _ string, _ int, body string) (client.Comment, error) {
+	c := client.Comment{ID: int64(len(m.comments) + 1), Body: body}
+	m.comments = append(m.comments, c)
+
+	return c, nil
+}
+
+func (m *mockAPI) UpdateComment(_ context.Context, _ string, id int64, body string) (client.Comment, error) {
+	m.updates++
+
+	for i := range m.comments {
+		if m.comments[i].ID == id {
+			m.comments[i].Body = body
+
+			return m.comments[i], nil
+		}
+	}
+
+	return client.Comment{}, os.ErrNotExist
+}
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	n, err := Detect(env(map[string]string{"GITHUB_REF": "refs/pull/42/merge"}))
+	require.NoError(t, err)
+	require.Equal(t, 42, n)
+
+	event := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(event, []byte(`{"pull_request": {"number": 7}}`), 0o600))
+
+	n, err = Detect(env(map[string]string{"GITHUB_REF": "refs/heads/main", "GITHUB_EVENT_PATH": event}))
+	require.NoError(t, err)
+	require.Equal(t, 7, n)
+
+	push := filepath.Join(t.TempDir(), "push.json")
+	require.NoError(t, os.WriteFile(push, []byte(`{"ref": "refs/heads/main"}`), 0o600))
+
+	_, err = Detect(env(map[string]string{"GITHUB_EVENT_PATH": push}))
+	require.ErrorIs(t, err, ErrNoPullRequest)
+
+	_, err = Detect(env(nil))
+	require.ErrorIs(t, err, ErrNoPullRequest)
+}
+
+func TestNumber(t *testing.T) {
+	t.Parallel()
+
+	n, err := Number("#12", env(nil))
+	require.NoError(t, err)
+	require.Equal(t, 12, n)
+
+	n, err = Number(Auto, env(map[string]string{"GITHUB_REF": "refs/pull/3/merge"}))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	_, err = Number("main", env(nil))
+	require.Error(t, err)
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	api := &mockAPI{comments: []client.Comment{{ID: 1, Body: "looks good"}}}
+	report := finding.Report{Findings: []finding.Finding{{Repo: "owner/repo", File: "go.mod", Line: 3, Status: status.Archived}}}
+
+	c, err := Publish(context.Background(), api, "me/app", 5, report)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), c.ID)
+	require.Contains(t, c.Body, Marker)
+	require.Contains(t, c.Body, "[owner/repo](https://github.com/owner/repo)")
+
+	c, err = Publish(context.Background(), api, "me/app", 5, finding.Report{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), c.ID, "the earlier comment is edited")
+	require.Contains(t, c.Body, "No archived dependencies found.")
+	require.Len(t, api.comments, 2)
+	require.Equal(t, 1, api.updates)
+
+	_, err = Publish(context.Background(), api, "me/app", 5, finding.Report{})
+	require.NoError(t, err)
+	require.Equal(t, 1, api.updates, "an unchanged comment is not edited")
+}
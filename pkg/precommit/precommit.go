@@ -0,0 +1,105 @@
+// Package precommit provides commands for scanning pre-commit configurations
+// for archived hook repositories.
+package precommit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the file that lists the hook repositories of a project.
+const Config = ".pre-commit-config.yaml"
+
+// Reference is a hook repository listed in a pre-commit configuration.
+type Reference struct {
+	// URL is the repository URL as written in the configuration.
+	URL string
+	// Rev is the tag or commit the hooks are pinned to.
+	Rev string
+	// Repo is the GitHub repository in the form "owner/repo".
+	Repo string
+	// File is the pre-commit configuration.
+	File string
+	// Line is the line of the repo value in File.
+	Line int
+	// Column is the 1-based column of the repo value on Line.
+	Column int
+}
+
+// parse returns the GitHub hook repositories listed by a pre-commit
+// configuration. Local and meta hooks, and repositories hosted elsewhere, are
+// omitted.
+func parse(name string, data []byte) ([]Reference, error) {
+	var config struct {
+		Repos []struct {
+			Repo yaml.Node `yaml:"repo"`
+			Rev  string    `yaml:"rev"`
+		} `yaml:"repos"`
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	var refs []Reference
+
+	for _, r := range config.Repos {
+		repo, ok := client.RepoFromURL(r.Repo.Value)
+		if !ok {
+			continue
+		}
+
+		refs = append(refs, Reference{r.Repo.Value, r.Rev, repo, name, r.Repo.Line, r.Repo.Column})
+	}
+
+	return refs, nil
+}
+
+// Discover parses the given pre-commit configurations.
+func Discover(ctx context.Context, names []string) []Reference {
+	var refs []Reference
+
+	for _, name := range names {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		found, err := parse(name, data)
+		if err != nil {
+			slog.DebugContext(ctx, err.Error())
+
+			continue
+		}
+
+		refs = append(refs, found...)
+	}
+
+	return refs
+}
+
+// Repos returns the hook repositories of the pre-commit configurations in
+// opts.Scope.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	names, err := files.RecursiveFind(ctx, opts.Scope, Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s files: %w", Config, err)
+	}
+
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, ref := range Discover(ctx, names) {
+		repos[ref.Repo] = append(repos[ref.Repo], gomod.NewRepoInfo(ref.File, ref.Line, ref.Column, ref.URL, ref.Rev, false))
+	}
+
+	return repos, nil
+}
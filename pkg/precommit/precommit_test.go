@@ -0,0 +1,50 @@
+package precommit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	data := `default_stages: [pre-commit]
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.5.0
+    hooks:
+      - id: trailing-whitespace
+  - repo: git@github.com:psf/black.git
+    rev: 24.1.1
+    hooks:
+      - id: black
+  - repo: https://gitlab.com/pycqa/flake8
+    rev: 3.9.2
+    hooks:
+      - id: flake8
+  - repo: local
+    hooks:
+      - id: lint
+        name: lint
+        entry: make lint
+        language: system
+  - repo: meta
+    hooks:
+      - id: check-hooks-apply
+`
+
+	refs, err := parse(Config, []byte(data))
+	require.NoError(t, err)
+	require.Equal(t, []Reference{
+		{"https://github.com/pre-commit/pre-commit-hooks", "v4.5.0", "pre-commit/pre-commit-hooks", Config, 3, 11},
+		{"git@github.com:psf/black.git", "24.1.1", "psf/black", Config, 7, 11},
+	}, refs)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := parse(Config, []byte("repos: [unclosed"))
+	require.Error(t, err)
+}
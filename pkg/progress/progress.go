@@ -0,0 +1,58 @@
+// Package progress reports how far a scan has got on interactive terminals.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+	"golang.org/x/term"
+)
+
+// Interactive reports whether a progress indicator should be shown: both
+// stdout and stderr must be terminals, and findings must be printed as text
+// or a table rather than a format meant for machines.
+func Interactive(format string) bool {
+	return (format == render.FormatText || format == render.FormatTable) && term.IsTerminal(int(os.Stdout.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Bar redraws a single status line such as "checked 12/340 repositories:
+// owner/repo". A nil Bar does nothing, so callers need not check whether
+// progress is enabled. It is not safe for concurrent use: scans drive it
+// from a single output.Coordinator goroutine.
+type Bar struct {
+	w     io.Writer
+	total int
+	done  int
+}
+
+// New returns a Bar for total items that writes to w, or nil if w is nil.
+func New(w io.Writer, total int) *Bar {
+	if w == nil {
+		return nil
+	}
+
+	return &Bar{w: w, total: total}
+}
+
+// Done counts an item as finished and shows its name.
+func (b *Bar) Done(name string) {
+	if b == nil {
+		return
+	}
+
+	b.done++
+
+	// Carriage return and erase line, so the status is redrawn in place.
+	fmt.Fprintf(b.w, "\r\033[Kchecked %d/%d repositories: %s", b.done, b.total, name)
+}
+
+// Clear erases the status line so findings can be printed in its place.
+func (b *Bar) Clear() {
+	if b == nil {
+		return
+	}
+
+	fmt.Fprint(b.w, "\r\033[K")
+}
@@ -0,0 +1,38 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/render"
+)
+
+func TestBar(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	b := New(&buf, 2)
+	b.Done("owner/a")
+	b.Done("owner/b")
+	b.Clear()
+
+	require.Equal(t, "\r\033[Kchecked 1/2 repositories: owner/a\r\033[Kchecked 2/2 repositories: owner/b\r\033[K", buf.String())
+}
+
+func TestBar_Nil(t *testing.T) {
+	t.Parallel()
+
+	b := New(nil, 2)
+	require.Nil(t, b)
+
+	b.Done("owner/a")
+	b.Clear()
+}
+
+func TestInteractive(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, Interactive(render.FormatGitHubActions))
+}
@@ -0,0 +1,165 @@
+// Package pypi provides a minimal client for the PyPI JSON API, used to
+// resolve Python projects to their source repositories.
+package pypi
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// DefaultBaseURL is the PyPI endpoint.
+const DefaultBaseURL = "https://pypi.org"
+
+// concurrency is the number of projects looked up at the same time. PyPI has
+// no batch endpoint.
+const concurrency = 8
+
+// sourceLabels are the project URL labels most likely to point at the source
+// repository, in order of preference.
+var sourceLabels = []string{"source", "source code", "repository", "code", "github", "homepage"}
+
+// Client queries the PyPI JSON API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a Client for the public PyPI API.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 10 * time.Second}, DefaultBaseURL)
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client and endpoint (for testing).
+func NewWithHTTPClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{httpClient: httpClient, baseURL: baseURL}
+}
+
+// Normalize returns the normalized form of a project name, as defined by
+// PEP 503, so that "Foo_Bar" and "foo-bar" compare equal.
+func Normalize(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	}), "-")
+}
+
+// Repositories returns the GitHub repository URL of each named project.
+// Projects that do not exist or do not link to GitHub are omitted.
+func (c *Client) Repositories(ctx context.Context, names []string) (map[string]string, error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, concurrency)
+		urls = map[string]string{}
+	)
+
+	for _, name := range names {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			u, err := c.repository(ctx, name)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching project %s: %v", name, err))
+
+				return
+			}
+
+			if u == "" {
+				return
+			}
+
+			mu.Lock()
+			urls[name] = u
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return urls, nil
+}
+
+// rank orders project URL labels by how likely they are to point at the
+// source repository.
+func rank(label string) int {
+	if i := slices.Index(sourceLabels, strings.ToLower(label)); i >= 0 {
+		return i
+	}
+
+	return len(sourceLabels)
+}
+
+// repository returns the repository URL of a project, preferring GitHub
+// URLs, or an empty string if it links to none.
+func (c *Client) repository(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/pypi/"+url.PathEscape(name)+"/json", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch project %s: %w", name, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch project %s: %s", name, resp.Status)
+	}
+
+	var body struct {
+		Info struct {
+			HomePage    string            `json:"home_page"`
+			ProjectURLs map[string]string `json:"project_urls"`
+		} `json:"info"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode project %s: %w", name, err)
+	}
+
+	var candidates []string
+
+	labels := make([]string, 0, len(body.Info.ProjectURLs))
+
+	for label := range body.Info.ProjectURLs {
+		labels = append(labels, label)
+	}
+
+	// Preferred labels first, then the rest in a stable order.
+	slices.SortFunc(labels, func(a, b string) int {
+		return cmp.Or(cmp.Compare(rank(a), rank(b)), strings.Compare(a, b))
+	})
+
+	for _, label := range labels {
+		candidates = append(candidates, body.Info.ProjectURLs[label])
+	}
+
+	candidates = append(candidates, body.Info.HomePage)
+
+	for _, u := range candidates {
+		if _, ok := client.RepoFromURL(u); ok {
+			return u, nil
+		}
+	}
+
+	return "", nil
+}
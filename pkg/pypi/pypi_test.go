@@ -0,0 +1,44 @@
+package pypi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pypi/requests/json":
+			_, _ = w.Write([]byte(`{"info":{"home_page":"https://requests.readthedocs.io","project_urls":{"Documentation":"https://github.com/psf/requests/wiki","Source":"https://github.com/psf/requests"}}}`))
+		case "/pypi/legacy/json":
+			_, _ = w.Write([]byte(`{"info":{"home_page":"https://github.com/owner/legacy","project_urls":null}}`))
+		case "/pypi/elsewhere/json":
+			_, _ = w.Write([]byte(`{"info":{"home_page":"https://gitlab.com/owner/elsewhere"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	got, err := c.Repositories(context.Background(), []string{"requests", "legacy", "elsewhere", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"requests": "https://github.com/psf/requests",
+		"legacy":   "https://github.com/owner/legacy",
+	}, got)
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "foo-bar-baz", Normalize("Foo_Bar.baz"))
+	require.Equal(t, "foo-bar", Normalize("foo--bar"))
+}
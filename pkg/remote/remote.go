@@ -0,0 +1,147 @@
+// Package remote scans a GitHub repository through the contents API without
+// cloning it, to audit third-party projects before adopting them. Manifests
+// are fetched into a temporary directory and scanned like a local checkout.
+package remote
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/check"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// API is the part of the GitHub API needed to read a repository's
+// manifests. client.Client implements it.
+type API interface {
+	GetRepoResult(ctx context.Context, repo string) (client.RepoResult, error)
+	Files(ctx context.Context, repo, ref string) ([]string, error)
+	FileContents(ctx context.Context, repo, path, ref string) ([]byte, error)
+}
+
+// Parse splits "owner/repo[@ref]" into the repository and the ref, which is
+// empty if not given.
+func Parse(arg string) (string, string, error) {
+	repo, ref, _ := strings.Cut(arg, "@")
+
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/repo[@ref]", arg)
+	}
+
+	return repo, ref, nil
+}
+
+// IsManifest reports whether the file at p, relative to the root of a
+// repository, is read by any ecosystem. vendor/modules.txt files are only
+// read when vendor is set.
+func IsManifest(p string, vendor bool) bool {
+	if vendor && path.Base(p) == "modules.txt" && path.Base(path.Dir(p)) == "vendor" {
+		return true
+	}
+
+	return slices.ContainsFunc(check.Ecosystems, func(eco check.Ecosystem) bool {
+		return eco.IsManifest != nil && eco.IsManifest(p)
+	})
+}
+
+// Fetch writes the manifests of repo at ref into dir, keeping their paths.
+// Manifests that cannot be fetched are skipped.
+func Fetch(ctx context.Context, api API, repo, ref, dir string, vendor bool) error {
+	paths, err := api.Files(ctx, repo, ref)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		if !IsManifest(p, vendor) || !filepath.IsLocal(filepath.FromSlash(p)) {
+			continue
+		}
+
+		data, err := api.FileContents(ctx, repo, p, ref)
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("error fetching %s of %s: %v", p, repo, err))
+
+			continue
+		}
+
+		name := filepath.Join(dir, filepath.FromSlash(p))
+
+		if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", p, err)
+		}
+
+		if err := os.WriteFile(name, data, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p, err)
+		}
+	}
+
+	return nil
+}
+
+// Repos returns the repositories of the dependencies of every ecosystem in
+// repo at ref, or at its default branch if ref is empty. Files are named
+// after the repository and ref, e.g. "owner/repo@main/go.mod".
+func Repos(ctx context.Context, api API, ecosystems []check.Ecosystem, repo, ref string, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	if ref == "" {
+		result, err := api.GetRepoResult(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up default branch of %s: %w", repo, err)
+		}
+
+		ref = result.DefaultBranch
+	}
+
+	dir, err := os.MkdirTemp("", "gh-arc-remote-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	if err := Fetch(ctx, api, repo, ref, dir, opts.Vendor); err != nil {
+		return nil, err
+	}
+
+	opts.Scope = files.Scope{Paths: []string{dir}, NoIgnore: true}
+
+	repos, err := check.Repos(ctx, ecosystems, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	gomod.Rebase(repos, dir, repo+"@"+ref)
+
+	return repos, nil
+}
+
+// ListArchived scans the repository named by arg, "owner/repo[@ref]", and
+// renders the findings.
+func ListArchived(ctx context.Context, api API, arg string, opts gomod.Options) (finding.Report, error) {
+	repo, ref, err := Parse(arg)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	repos, err := Repos(ctx, api, check.Ecosystems, repo, ref, opts)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	// The self-check is about the current directory, not the remote
+	// repository.
+	opts.SelfCheck = nil
+
+	return gomod.NewScanner(opts, opts.Output).Check(ctx, repos)
+}
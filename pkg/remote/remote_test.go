@@ -0,0 +1,100 @@
+package remote
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/actions"
+	"github.com/wayneashleyberry/gh-arc/pkg/check"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// fakeAPI serves one repository from memory, keyed by path.
+type fakeAPI map[string]string
+
+func (fakeAPI) GetRepoResult(context.Context, string) (client.RepoResult, error) {
+	return client.RepoResult{DefaultBranch: "main"}, nil
+}
+
+func (f fakeAPI) Files(context.Context, string, string) ([]string, error) {
+	paths := make([]string, 0, len(f))
+	for p := range f {
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+func (f fakeAPI) FileContents(_ context.Context, _, p, _ string) ([]byte, error) {
+	return []byte(f[p]), nil
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	repo, ref, err := Parse("owner/repo@v1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, "owner/repo", repo)
+	require.Equal(t, "v1.2.3", ref)
+
+	repo, ref, err = Parse("owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, "owner/repo", repo)
+	require.Empty(t, ref)
+
+	for _, arg := range []string{"owner", "owner/repo/sub", "/repo@main"} {
+		_, _, err := Parse(arg)
+		require.Error(t, err, arg)
+	}
+}
+
+func TestIsManifest(t *testing.T) {
+	t.Parallel()
+
+	for p, want := range map[string]bool{
+		"go.mod":                     true,
+		"cmd/tool/go.sum":            true,
+		"Cargo.lock":                 true,
+		"api/requirements.txt":       true,
+		".github/workflows/ci.yml":   true,
+		".github/workflows/notes.md": false,
+		"docs/ci.yml":                false,
+		"build/Dockerfile.dev":       true,
+		"action.yaml":                true,
+		"infra/main.tf":              true,
+		"charts/app/Chart.lock":      true,
+		"Gemfile.lock":               true,
+		"web/composer.json":          true,
+		"api/pom.xml":                true,
+		"app/build.gradle.kts":       true,
+		"gradle.lockfile":            true,
+		".pre-commit-config.yaml":    true,
+		"vendor/modules.txt":         false,
+		"main.go":                    false,
+	} {
+		require.Equal(t, want, IsManifest(p, false), p)
+	}
+
+	require.True(t, IsManifest("vendor/modules.txt", true))
+}
+
+func TestRepos(t *testing.T) {
+	t.Parallel()
+
+	api := fakeAPI{
+		"go.mod":                   "module example.com/app\n\nrequire github.com/owner/lib v1.0.0\n",
+		".github/workflows/ci.yml": "jobs:\n  build:\n    steps:\n      - uses: actions/checkout@v4\n",
+		"main.go":                  "package main\n",
+	}
+
+	ecosystems := []check.Ecosystem{{Name: "gomod", Repos: gomod.Repos}, {Name: "actions", Repos: actions.Repos}}
+
+	repos, err := Repos(context.Background(), api, ecosystems, "third/app", "", gomod.Options{})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]gomod.RepoInfo{
+		"owner/lib":        {gomod.NewRepoInfo("third/app@main/go.mod", 3, 1, "github.com/owner/lib", "v1.0.0", false)},
+		"actions/checkout": {gomod.NewRepoInfo("third/app@main/.github/workflows/ci.yml", 4, 15, "actions/checkout", "v4", false)},
+	}, repos)
+}
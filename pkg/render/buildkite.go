@@ -0,0 +1,61 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// buildkiteContext identifies gh-arc's annotation, so reruns replace it.
+const buildkiteContext = "gh-arc"
+
+// Buildkite annotates the Buildkite build with a markdown table of findings
+// using buildkite-agent, styled by the most severe finding. When not running
+// in Buildkite the table is written to w instead.
+func Buildkite(w io.Writer, report finding.Report) error {
+	var buf bytes.Buffer
+
+	markdownSummary(&buf, report)
+
+	if os.Getenv("BUILDKITE") != "true" {
+		_, err := buf.WriteTo(w)
+
+		return err
+	}
+
+	// #nosec G204
+	cmd := exec.Command("buildkite-agent", "annotate", "--style", buildkiteStyle(report), "--context", buildkiteContext)
+	cmd.Stdin = &buf
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to annotate buildkite build: %w", err)
+	}
+
+	return nil
+}
+
+// buildkiteStyle returns the annotation style for the most severe finding.
+func buildkiteStyle(report finding.Report) string {
+	style := "success"
+
+	for _, f := range report.Findings {
+		switch f.Severity() {
+		case finding.SeverityError:
+			return "error"
+		case finding.SeverityWarning:
+			style = "warning"
+		case finding.SeverityInfo:
+			if style == "success" {
+				style = "info"
+			}
+		}
+	}
+
+	return style
+}
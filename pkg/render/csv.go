@@ -0,0 +1,63 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// csvHeader names the columns of CSV and TSV output. Columns are only ever
+// appended, so spreadsheets importing by position keep working.
+var csvHeader = []string{
+	"severity", "status", "repo", "module", "version", "latest", "file", "line", "column",
+	"indirect", "informational", "baselined", "archived", "pushed_at", "reason",
+	"code_owners", "tool",
+}
+
+// CSV writes a header row followed by one row per finding, for importing
+// into spreadsheets.
+func CSV(w io.Writer, report finding.Report) error {
+	return delimited(w, report, ',')
+}
+
+// TSV is CSV with tab-separated columns.
+func TSV(w io.Writer, report finding.Report) error {
+	return delimited(w, report, '\t')
+}
+
+func delimited(w io.Writer, report finding.Report, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	_ = cw.Write(csvHeader)
+
+	for _, f := range report.Findings {
+		line, column := "", ""
+		if f.Line > 0 {
+			line = strconv.Itoa(f.Line)
+		}
+
+		if f.Column > 0 {
+			column = strconv.Itoa(f.Column)
+		}
+
+		_ = cw.Write([]string{
+			string(f.Severity()), f.Status.String(), f.Repo, f.Module, f.Version, f.Latest, f.File, line, column,
+			strconv.FormatBool(f.Indirect), strconv.FormatBool(f.Informational), strconv.FormatBool(f.Baselined),
+			strconv.FormatBool(f.Archived), f.PushedAt, f.Reason,
+			strings.Join(f.CodeOwners, " "), strconv.FormatBool(f.Tool),
+		})
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write findings: %w", err)
+	}
+
+	return nil
+}
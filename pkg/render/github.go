@@ -0,0 +1,220 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// workflowDataEscaper escapes the message of a workflow command, so a
+// multi-line reason or a crafted repository description cannot end it early
+// or start another command.
+var workflowDataEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// workflowPropertyEscaper escapes property values of a workflow command,
+// such as file names.
+var workflowPropertyEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+	":", "%3A",
+	",", "%2C",
+)
+
+// GitHubActions writes a workflow command per finding so that GitHub shows
+// them as annotations on the line that requires the dependency. The
+// annotation level follows the finding's severity.
+func GitHubActions(w io.Writer, report finding.Report) error {
+	for _, f := range report.Findings {
+		level := "warning"
+
+		switch f.Severity() {
+		case finding.SeverityError:
+			level = "error"
+		case finding.SeverityInfo:
+			level = "notice"
+		case finding.SeverityWarning:
+			level = "warning"
+		}
+
+		location := "file=" + workflowPropertyEscaper.Replace(f.File)
+		if f.Line > 0 {
+			location += fmt.Sprintf(",line=%d", f.Line)
+		}
+
+		if f.Line > 0 && f.Column > 0 {
+			location += fmt.Sprintf(",col=%d", f.Column)
+		}
+
+		// Findings about repositories checked by name have no file.
+		if f.File == "" {
+			fmt.Fprintf(w, "::%s::%s\n", level, workflowDataEscaper.Replace("github.com/"+f.Repo+" "+annotation(f)))
+
+			continue
+		}
+
+		fmt.Fprintf(w, "::%s %s::%s\n", level, location, workflowDataEscaper.Replace("github.com/"+f.Repo+" "+annotation(f)))
+	}
+
+	if note := report.PartialNote(); note != "" {
+		fmt.Fprintf(w, "::warning::%s\n", workflowDataEscaper.Replace(note))
+	}
+
+	return nil
+}
+
+// annotation returns the message used for a finding in workflow commands.
+func annotation(f finding.Finding) string {
+	result := f.Metadata
+
+	switch f.Status {
+	case status.Missing:
+		return "is missing"
+	case status.Moved:
+		return "has moved to github.com/" + result.FullName + pathNote(f)
+	case status.Stale:
+		return "is stale (last push: " + result.PushedAt + ")"
+	case status.Deprecated:
+		return "is deprecated (last push: " + result.PushedAt + ")"
+	case status.Archived:
+		return "is archived (last push: " + result.PushedAt + forkNote(result) + advisoryNote(f) + ")"
+	case status.UpstreamArchived:
+		return "is a fork of archived github.com/" + result.Parent.FullName + " (last push: " + result.PushedAt + ")"
+	case status.Unknown, status.UnsupportedGo, status.NoLicense, status.DisallowedLicense, status.Copyleft, status.MissingVersion, status.Outdated:
+		return f.Reason
+	}
+
+	return "is " + f.Status.String()
+}
+
+// GitHubSummary appends a markdown table of findings to $GITHUB_STEP_SUMMARY,
+// or writes it to w when not running in GitHub Actions.
+func GitHubSummary(w io.Writer, report finding.Report) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		markdownSummary(w, report)
+
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) // #nosec G302 G304
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+
+	markdownSummary(f, report)
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+
+	return nil
+}
+
+// markdownSummary writes a markdown table of findings, followed by a warning
+// if the report is partial.
+func markdownSummary(w io.Writer, report finding.Report) {
+	fmt.Fprintln(w, "## Archived dependencies")
+	fmt.Fprintln(w)
+
+	if note := report.PartialNote(); note != "" {
+		defer fmt.Fprintf(w, "\n> [!WARNING]\n> %s\n", note)
+	}
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(w, "No archived dependencies found.")
+
+		return
+	}
+
+	fmt.Fprintln(w, "| Severity | Status | Repository | File | Details |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+
+	for _, f := range report.Findings {
+		location := f.File
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, f.Line)
+		}
+
+		if f.Line > 0 && f.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, f.Column)
+		}
+
+		details := Detail(f)
+		if f.Indirect {
+			details += " (indirect)"
+		}
+
+		if f.Informational {
+			details += " (informational)"
+		}
+
+		if f.Baselined {
+			details += " (baselined)"
+		}
+
+		fmt.Fprintf(w, "| %s | %s | [%s](%s) | `%s` | %s |\n", f.Severity(), f.Status, f.Repo, f.URL(), location, details)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "**Total:** %s\n", report.Counts())
+}
+
+// Markdown writes the markdown table of findings used by job summaries, for
+// publishing it elsewhere, such as in a pull request comment.
+func Markdown(w io.Writer, report finding.Report) {
+	report.Findings = slices.Clone(report.Findings)
+	finding.Sort(report.Findings)
+
+	markdownSummary(w, report)
+}
+
+// GitHubOutputs appends step outputs describing the report to $GITHUB_OUTPUT
+// so later workflow steps can branch on the results without parsing logs.
+// reportPath is the file the report was written to, if any. Outside of GitHub
+// Actions it does nothing.
+func GitHubOutputs(report finding.Report, reportPath string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) // #nosec G302 G304
+	if err != nil {
+		return fmt.Errorf("failed to open step outputs: %w", err)
+	}
+
+	writeOutputs(f, report, reportPath)
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write step outputs: %w", err)
+	}
+
+	return nil
+}
+
+// writeOutputs writes a "<status>_count" output for every status, such as
+// archived_count and upstream_archived_count, the total finding_count, and
+// report_path when the report was written to a file.
+func writeOutputs(w io.Writer, report finding.Report, reportPath string) {
+	counts := report.Counts()
+
+	for _, st := range status.All {
+		fmt.Fprintf(w, "%s_count=%d\n", strings.ReplaceAll(st.String(), "-", "_"), counts[st])
+	}
+
+	fmt.Fprintf(w, "finding_count=%d\n", counts.Total())
+
+	if reportPath != "" {
+		fmt.Fprintf(w, "report_path=%s\n", reportPath)
+	}
+}
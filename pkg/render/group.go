@@ -0,0 +1,31 @@
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// grouped writes text or table output with the findings of each group of
+// opts.GroupBy under a heading with the group's counts, followed by the
+// counts of the whole report.
+func grouped(w io.Writer, format string, report finding.Report, opts Options) {
+	for i, g := range finding.GroupBy(report.Findings, opts.GroupBy) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "%s (%s)\n", g.Key, finding.Report{Findings: g.Findings}.Counts())
+
+		if format == FormatTable {
+			tableFindings(w, g.Findings, opts.Width, opts.Color)
+
+			continue
+		}
+
+		textFindings(w, g.Findings, opts.Color)
+	}
+
+	footer(w, report)
+}
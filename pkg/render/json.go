@@ -0,0 +1,29 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+// JSON writes the report as an indented JSON document, which `arc diff` can
+// read back. Reports are stamped with the running build unless they already
+// name one.
+func JSON(w io.Writer, report finding.Report) error {
+	if report.Tool == nil {
+		info := version.Get()
+		report.Tool = &info
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	return nil
+}
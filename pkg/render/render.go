@@ -0,0 +1,119 @@
+// Package render writes scan reports in the supported output formats.
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// Supported output formats.
+const (
+	// FormatText prints one human-readable line per finding.
+	FormatText = "text"
+	// FormatJSON prints the report as JSON for other programs.
+	FormatJSON = "json"
+	// FormatGitHubActions prints GitHub Actions workflow commands so findings
+	// are shown as inline annotations on pull requests.
+	FormatGitHubActions = "github-actions"
+	// FormatGitHubSummary renders a markdown table for GitHub Actions job
+	// summaries. It is appended to $GITHUB_STEP_SUMMARY when that is set and
+	// written to the output otherwise.
+	FormatGitHubSummary = "github-summary"
+	// FormatTeamCity prints TeamCity service messages so findings are shown
+	// as inspections of the build.
+	FormatTeamCity = "teamcity"
+	// FormatTable prints findings as aligned columns, truncated to fit the
+	// terminal.
+	FormatTable = "table"
+	// FormatBuildkite annotates the Buildkite build with a markdown table
+	// when running in Buildkite and writes the table to the output otherwise.
+	FormatBuildkite = "buildkite"
+	// FormatCycloneDX prints a CycloneDX JSON SBOM of the unhealthy
+	// dependencies for SBOM tooling and artifact stores.
+	FormatCycloneDX = "cyclonedx"
+	// FormatSPDX prints an SPDX JSON document of the unhealthy dependencies.
+	FormatSPDX = "spdx"
+	// FormatCSV prints a header row and one comma-separated row per finding
+	// for spreadsheets.
+	FormatCSV = "csv"
+	// FormatTSV is FormatCSV with tab-separated columns.
+	FormatTSV = "tsv"
+)
+
+// Formats lists every supported output format.
+var Formats = []string{FormatText, FormatTable, FormatJSON, FormatGitHubActions, FormatGitHubSummary, FormatTeamCity, FormatBuildkite, FormatCycloneDX, FormatSPDX, FormatCSV, FormatTSV}
+
+// Validate reports whether format is supported.
+func Validate(format string) error {
+	if !slices.Contains(Formats, format) {
+		return fmt.Errorf("unsupported format %q, expected one of: %s", format, strings.Join(Formats, ", "))
+	}
+
+	return nil
+}
+
+// Options configures how reports are rendered.
+type Options struct {
+	// Color highlights text and table output with ANSI colors by severity.
+	// Other formats are never colored.
+	Color bool
+	// Width is the maximum line width of table output. Zero means columns
+	// are never truncated.
+	Width int
+	// Order is one of finding.Orders. An empty value means
+	// finding.OrderFile.
+	Order string
+	// GroupBy is one of finding.Groupings, printing text and table output
+	// in groups with their own counts. Other formats are never grouped. An
+	// empty value disables grouping.
+	GroupBy string
+}
+
+// Render writes the report to w in the given format. Findings are sorted by
+// file, line and repository.
+func Render(w io.Writer, format string, report finding.Report) error {
+	return RenderWith(w, format, report, Options{})
+}
+
+// RenderWith is like Render with the given options.
+func RenderWith(w io.Writer, format string, report finding.Report, opts Options) error {
+	report.Findings = slices.Clone(report.Findings)
+	finding.SortBy(report.Findings, opts.Order)
+
+	if opts.GroupBy != "" && (format == FormatText || format == FormatTable) {
+		grouped(w, format, report, opts)
+
+		return nil
+	}
+
+	switch format {
+	case FormatText:
+		return text(w, report, opts.Color)
+	case FormatTable:
+		return table(w, report, opts.Width, opts.Color)
+	case FormatJSON:
+		return JSON(w, report)
+	case FormatGitHubActions:
+		return GitHubActions(w, report)
+	case FormatGitHubSummary:
+		return GitHubSummary(w, report)
+	case FormatTeamCity:
+		return TeamCity(w, report)
+	case FormatBuildkite:
+		return Buildkite(w, report)
+	case FormatCycloneDX:
+		return CycloneDX(w, report)
+	case FormatSPDX:
+		return SPDX(w, report)
+	case FormatCSV:
+		return CSV(w, report)
+	case FormatTSV:
+		return TSV(w, report)
+	}
+
+	return Validate(format)
+}
@@ -0,0 +1,510 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/advisory"
+	"github.com/wayneashleyberry/gh-arc/pkg/automation"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/depsdev"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/forks"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+func TestRender_Text(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		finding  finding.Finding
+		expected string
+	}{
+		{
+			"direct",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n\n1 archived\n",
+		},
+		{
+			"indirect",
+			finding.Finding{Repo: "owner/repo", File: "bar/go.mod", Indirect: true, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+			"bar/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z) // indirect\n\n1 archived\n",
+		},
+		{
+			"missing",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Missing},
+			"foo/go.mod: https://github.com/owner/repo (repository missing)\n\n1 missing\n",
+		},
+		{
+			"fork",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.UpstreamArchived, Metadata: client.RepoResult{
+				PushedAt: "2025-07-18T12:00:00Z",
+				Fork:     true,
+				Parent:   &client.RepoResult{FullName: "upstream/repo", Archived: true},
+			}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z, fork of upstream/repo (archived))\n\n1 upstream-archived\n",
+		},
+		{
+			"alternatives",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Alternatives: &finding.Alternatives{
+				Dependents: &depsdev.Dependents{DependentCount: 10, DirectDependentCount: 3},
+				DepsDevURL: "https://deps.dev/go/x",
+				ImportedBy: "https://pkg.go.dev/x?tab=importedby",
+				SearchURL:  "https://pkg.go.dev/search?q=x",
+			}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  dependents: 10 (3 direct) https://deps.dev/go/x\n" +
+				"  importers: https://pkg.go.dev/x?tab=importedby\n" +
+				"  alternatives: https://pkg.go.dev/search?q=x\n\n1 archived\n",
+		},
+		{
+			"forks",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Alternatives: &finding.Alternatives{
+				Forks: []forks.Candidate{{Repo: "fork/repo", Score: 4.5, Breakdown: []forks.Component{
+					{Name: "activity", Points: 4, Max: 4, Detail: "31 commits in 90 days"},
+					{Name: "popularity", Points: 0.5, Max: 1, Detail: "30 stars"},
+				}}},
+			}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  fork: https://github.com/fork/repo 4.5/10 (activity 4/4: 31 commits in 90 days, popularity 0.5/1: 30 stars)\n\n1 archived\n",
+		},
+		{
+			"no file",
+			finding.Finding{Repo: "owner/repo", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+			"https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n\n1 archived\n",
+		},
+		{
+			"tool",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Tool: true},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z) // tool\n\n1 archived\n",
+		},
+		{
+			"code owners",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, CodeOwners: []string{"@acme/foo", "@alice"}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  code owners: @acme/foo @alice\n\n1 archived\n",
+		},
+		{
+			"automation",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Stale, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Automation: automation.Uncovered},
+			"foo/go.mod: https://github.com/owner/repo (stale, last push: 2025-07-18T12:00:00Z)\n" +
+				"  update automation: uncovered\n\n1 stale\n",
+		},
+		{
+			"unattended",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Archived: true, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Automation: automation.Excluded},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  update automation: excluded, archived and ignored by update bots\n\n1 archived\n",
+		},
+		{
+			"moved",
+			finding.Finding{Module: "github.com/Old/repo/v2", Repo: "old/repo", Canonical: "new/repo", File: "go.mod", Status: status.Moved, Metadata: client.RepoResult{FullName: "new/repo"}},
+			"go.mod: https://github.com/old/repo (moved to https://github.com/new/repo, module path is out of date)\n\n1 moved\n",
+		},
+		{
+			"archived and renamed",
+			finding.Finding{Module: "old/repo/sub", Repo: "old/repo", Canonical: "new/repo", File: "ci.yml", Status: status.Archived, Metadata: client.RepoResult{FullName: "new/repo", PushedAt: "2025-07-18T12:00:00Z"}},
+			"ci.yml: https://github.com/old/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  moved to: https://github.com/new/repo, module path is out of date\n\n1 archived\n",
+		},
+		{
+			"mirror",
+			finding.Finding{Repo: "golang/old", File: "go.mod", Status: status.Archived, Informational: true, Mirror: true, Upstream: "https://go.googlesource.com/old", Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+			"go.mod: https://github.com/golang/old (last push: 2025-07-18T12:00:00Z) // informational\n" +
+				"  mirror of: https://go.googlesource.com/old\n\n1 archived\n",
+		},
+		{
+			"successor",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Successor: "owner/next"},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  successor: https://github.com/owner/next\n\n1 archived\n",
+		},
+		{
+			"details",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Details: &finding.Details{
+				OpenIssues: 12, DefaultBranch: "main", LatestRelease: "v1.2.3", LatestReleaseAt: "2024-01-02T03:04:05Z",
+			}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z)\n" +
+				"  details: 12 open issues, latest release v1.2.3 (2024-01-02T03:04:05Z), default branch main\n\n1 archived\n",
+		},
+		{
+			"advisories",
+			finding.Finding{Repo: "owner/repo", File: "foo/go.mod", Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}, Advisories: []advisory.Advisory{
+				{ID: "GHSA-aaaa-bbbb-cccc", Severity: "high", Summary: "Path traversal", URL: "https://github.com/advisories/GHSA-aaaa-bbbb-cccc", FirstPatched: "1.2.0"},
+			}},
+			"foo/go.mod: https://github.com/owner/repo (last push: 2025-07-18T12:00:00Z, 1 unpatched advisory)\n" +
+				"  advisory: GHSA-aaaa-bbbb-cccc (high) Path traversal, fixed in 1.2.0 https://github.com/advisories/GHSA-aaaa-bbbb-cccc\n\n1 archived\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			err := Render(&buf, FormatText, finding.Report{Findings: []finding.Finding{tt.finding}})
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestRender_GitHubActions(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{
+		Findings: []finding.Finding{
+			{Repo: "owner/repo", File: "foo/go.mod", Line: 4, Column: 2, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+		},
+		Unchecked:   2,
+		MaxAPICalls: 1,
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Render(&buf, FormatGitHubActions, report))
+
+	expected := "::error file=foo/go.mod,line=4,col=2::github.com/owner/repo is archived (last push: 2025-07-18T12:00:00Z)\n" +
+		"::warning::partial report: 2 repositories not checked, API call budget of 1 exhausted\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestRender_GitHubActions_Escaping(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{
+		Findings: []finding.Finding{
+			{Repo: "owner/repo", File: "a,b:c/go.mod", Line: 4, Status: status.Unknown, Reason: "api error\n::error::injected 100%\r"},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Render(&buf, FormatGitHubActions, report))
+
+	expected := "::notice file=a%2Cb%3Ac/go.mod,line=4::github.com/owner/repo api error%0A::error::injected 100%25%0D\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestRenderWith_Color(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "owner/repo", File: "go.mod", Line: 1, Status: status.Missing},
+		{Repo: "other/repo", File: "go.mod", Line: 2, Status: status.Unknown, Reason: "could not be checked"},
+	}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, RenderWith(&buf, FormatText, report, Options{Color: true}))
+
+	expected := "go.mod: \033[31mhttps://github.com/owner/repo (repository missing)\033[0m\n" +
+		"go.mod: \033[36mhttps://github.com/other/repo (could not be checked)\033[0m\n" +
+		"\n1 missing, 1 unknown\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestRenderWith_Table(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "owner/repo", Module: "github.com/owner/repo", File: "services/payments/go.mod", Line: 4, Status: status.Missing},
+		{Repo: "other/repo", Module: "github.com/other/repo/v2", File: "go.mod", Line: 12, Indirect: true, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2020-01-01T00:00:00Z"}},
+	}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, RenderWith(&buf, FormatTable, report, Options{}))
+	require.Equal(t, ""+
+		"SEVERITY  STATUS    REPOSITORY  MODULE                    LOCATION                    DETAIL\n"+
+		"warning   archived  other/repo  github.com/other/repo/v2  go.mod:12                   last push: 2020-01-01T00:00:00Z, indirect\n"+
+		"error     missing   owner/repo  github.com/owner/repo     services/payments/go.mod:4  repository missing\n"+
+		"\n1 missing, 1 archived\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, RenderWith(&buf, FormatTable, report, Options{Width: 80}))
+	require.Equal(t, ""+
+		"SEVERITY  STATUS    REPOSITORY  MODULE          LOCATION         DETAIL\n"+
+		"warning   archived  other/repo  github.com/ot…  go.mod:12        last push: 202…\n"+
+		"error     missing   owner/repo  github.com/ow…  …ments/go.mod:4  repository mis…\n"+
+		"\n1 missing, 1 archived\n", buf.String())
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		require.LessOrEqual(t, utf8.RuneCountInString(line), 80)
+	}
+}
+
+func TestRenderWith_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "vendor/a", File: "go.mod", Line: 3, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2020-01-01T00:00:00Z"}},
+		{Repo: "acme/b", File: "go.mod", Line: 4, Status: status.Missing},
+		{Repo: "vendor/c", File: "tools/go.mod", Line: 5, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2021-01-01T00:00:00Z"}},
+	}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, RenderWith(&buf, FormatText, report, Options{GroupBy: finding.GroupOwner}))
+	require.Equal(t, ""+
+		"acme (1 missing)\n"+
+		"go.mod: https://github.com/acme/b (repository missing)\n"+
+		"\n"+
+		"vendor (2 archived)\n"+
+		"go.mod: https://github.com/vendor/a (last push: 2020-01-01T00:00:00Z)\n"+
+		"tools/go.mod: https://github.com/vendor/c (last push: 2021-01-01T00:00:00Z)\n"+
+		"\n1 missing, 2 archived\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, RenderWith(&buf, FormatTable, report, Options{GroupBy: finding.GroupSeverity}))
+	require.Equal(t, ""+
+		"error (1 missing, 2 archived)\n"+
+		"SEVERITY  STATUS    REPOSITORY  MODULE  LOCATION        DETAIL\n"+
+		"error     archived  vendor/a            go.mod:3        last push: 2020-01-01T00:00:00Z\n"+
+		"error     missing   acme/b              go.mod:4        repository missing\n"+
+		"error     archived  vendor/c            tools/go.mod:5  last push: 2021-01-01T00:00:00Z\n"+
+		"\n1 missing, 2 archived\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, RenderWith(&buf, FormatCSV, report, Options{GroupBy: finding.GroupOwner}))
+	require.NotContains(t, buf.String(), "vendor (2 archived)")
+}
+
+func TestRender_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	require.Error(t, Render(&bytes.Buffer{}, "xml", finding.Report{}))
+}
+
+func TestRender_JSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Render(&buf, FormatJSON, finding.Report{Findings: []finding.Finding{{Repo: "owner/repo", File: "go.mod", Status: status.Archived}}}))
+
+	var report finding.Report
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.Equal(t, version.Get(), *report.Tool)
+	require.Len(t, report.Findings, 1)
+
+	stamped := version.Info{Version: "v1.0.0"}
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, FormatJSON, finding.Report{Tool: &stamped}))
+	require.Contains(t, buf.String(), `"version": "v1.0.0"`)
+}
+
+func TestMarkdownSummary(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "other/repo", File: "go.mod", Line: 7, Indirect: true, Status: status.Missing},
+		{Repo: "owner/repo", File: "go.mod", Line: 4, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+	}}
+	finding.Sort(report.Findings)
+
+	var buf bytes.Buffer
+
+	markdownSummary(&buf, report)
+
+	expected := "## Archived dependencies\n\n" +
+		"| Severity | Status | Repository | File | Details |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| error | archived | [owner/repo](https://github.com/owner/repo) | `go.mod:4` | last push: 2025-07-18T12:00:00Z |\n" +
+		"| warning | missing | [other/repo](https://github.com/other/repo) | `go.mod:7` | repository missing (indirect) |\n" +
+		"\n**Total:** 1 missing, 1 archived\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestMarkdownSummary_Empty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	markdownSummary(&buf, finding.Report{Unchecked: 1, MaxAPICalls: 1})
+
+	require.Equal(t, "## Archived dependencies\n\nNo archived dependencies found.\n\n> [!WARNING]\n> partial report: 1 repositories not checked, API call budget of 1 exhausted\n", buf.String())
+}
+
+func TestWriteOutputs(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Repo: "owner/repo", Status: status.Archived},
+		{Repo: "other/repo", Status: status.Stale},
+		{Repo: "third/repo", Status: status.Archived},
+	}}
+
+	var buf bytes.Buffer
+
+	writeOutputs(&buf, report, "summary.md")
+
+	expected := "missing_count=0\n" +
+		"archived_count=2\n" +
+		"missing_version_count=0\n" +
+		"deprecated_count=0\n" +
+		"upstream_archived_count=0\n" +
+		"stale_count=1\n" +
+		"outdated_count=0\n" +
+		"unsupported_go_count=0\n" +
+		"disallowed_license_count=0\n" +
+		"no_license_count=0\n" +
+		"copyleft_count=0\n" +
+		"moved_count=0\n" +
+		"unknown_count=0\n" +
+		"finding_count=3\n" +
+		"report_path=summary.md\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestRender_TeamCity(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{
+		Findings: []finding.Finding{
+			{Repo: "owner/repo", File: "foo/go.mod", Line: 4, Status: status.Archived, Metadata: client.RepoResult{PushedAt: "2025-07-18T12:00:00Z"}},
+			{Repo: "other/repo", File: "go.mod", Line: 7, Status: status.Unknown, Reason: "could not be checked: 'boom' [502]"},
+		},
+		Unchecked:   2,
+		MaxAPICalls: 1,
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Render(&buf, FormatTeamCity, report))
+
+	expected := "##teamcity[inspectionType id='archived' name='archived' category='Dependencies' description='archived dependencies']\n" +
+		"##teamcity[inspectionType id='unknown' name='unknown' category='Dependencies' description='unknown dependencies']\n" +
+		"##teamcity[inspection typeId='archived' message='github.com/owner/repo is archived (last push: 2025-07-18T12:00:00Z)' file='foo/go.mod' line='4' SEVERITY='ERROR']\n" +
+		"##teamcity[inspection typeId='unknown' message='github.com/other/repo could not be checked: |'boom|' |[502|]' file='go.mod' line='7' SEVERITY='INFO']\n" +
+		"##teamcity[message text='partial report: 2 repositories not checked, API call budget of 1 exhausted' status='WARNING']\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestRender_CSV(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{
+		Findings: []finding.Finding{
+			{Module: "github.com/owner/repo", Version: "v1.0.0", Repo: "owner/repo", File: "foo/go.mod", Line: 4, Column: 2, Status: status.Archived, Archived: true, PushedAt: "2025-07-18T12:00:00Z", Reason: "repository archived", CodeOwners: []string{"@acme/foo", "@alice"}},
+			{Module: "github.com/other/repo", Repo: "other/repo", File: "go.mod", Line: 7, Indirect: true, Status: status.Unknown, Reason: "could not be checked: boom, \"502\""},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Render(&buf, FormatCSV, report))
+
+	expected := "severity,status,repo,module,version,latest,file,line,column,indirect,informational,baselined,archived,pushed_at,reason,code_owners,tool\n" +
+		"error,archived,owner/repo,github.com/owner/repo,v1.0.0,,foo/go.mod,4,2,false,false,false,true,2025-07-18T12:00:00Z,repository archived,@acme/foo @alice,false\n" +
+		"info,unknown,other/repo,github.com/other/repo,,,go.mod,7,,true,false,false,false,,\"could not be checked: boom, \"\"502\"\"\",,false\n"
+	require.Equal(t, expected, buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, Render(&buf, FormatTSV, finding.Report{}))
+	require.Equal(t, "severity\tstatus\trepo\tmodule\tversion\tlatest\tfile\tline\tcolumn\tindirect\tinformational\tbaselined\tarchived\tpushed_at\treason\tcode_owners\ttool\n", buf.String())
+}
+
+func TestBuildkiteStyle(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "success", buildkiteStyle(finding.Report{}))
+	require.Equal(t, "info", buildkiteStyle(finding.Report{Findings: []finding.Finding{{Status: status.Moved}}}))
+	require.Equal(t, "warning", buildkiteStyle(finding.Report{Findings: []finding.Finding{{Status: status.Moved}, {Status: status.Stale}}}))
+	require.Equal(t, "error", buildkiteStyle(finding.Report{Findings: []finding.Finding{{Status: status.Stale}, {Status: status.Archived}}}))
+}
+
+func TestRender_SBOM(t *testing.T) {
+	t.Parallel()
+
+	report := finding.Report{Findings: []finding.Finding{
+		{Module: "github.com/owner/repo", Version: "v1.0.0", Repo: "owner/repo", File: "a/go.mod", Line: 5, Status: status.Archived, Archived: true, Reason: "archived"},
+		{Module: "github.com/owner/repo", Version: "v1.0.0", Repo: "owner/repo", File: "b/go.mod", Line: 7, Status: status.Archived, Archived: true, Reason: "archived"},
+		{Module: "github.com/gone/repo", Repo: "Gone/Repo", File: "a/go.mod", Status: status.Missing, Reason: "repository missing"},
+	}}
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		require.NoError(t, Render(&buf, FormatCycloneDX, report))
+
+		var doc struct {
+			BOMFormat  string               `json:"bomFormat"`
+			Components []cycloneDXComponent `json:"components"`
+		}
+
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		require.Equal(t, "CycloneDX", doc.BOMFormat)
+		require.Len(t, doc.Components, 2)
+		require.Equal(t, "pkg:github/gone/repo", doc.Components[0].PURL)
+		require.Equal(t, cycloneDXComponent{
+			Type:       "library",
+			Ref:        "pkg:github/owner/repo@v1.0.0",
+			Name:       "github.com/owner/repo",
+			Version:    "v1.0.0",
+			PURL:       "pkg:github/owner/repo@v1.0.0",
+			References: []cycloneDXReference{{"vcs", "https://github.com/owner/repo"}},
+			Properties: []cycloneDXProperty{
+				{"gh-arc:status", "archived"},
+				{"gh-arc:reason", "archived"},
+				{"gh-arc:archived", "true"},
+				{"gh-arc:severity", "error"},
+				{"gh-arc:location", "a/go.mod:5"},
+				{"gh-arc:location", "b/go.mod:7"},
+			},
+		}, doc.Components[1])
+	})
+
+	t.Run("spdx", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		require.NoError(t, Render(&buf, FormatSPDX, report))
+
+		var doc struct {
+			SPDXVersion string        `json:"spdxVersion"`
+			Describes   []string      `json:"documentDescribes"`
+			Packages    []spdxPackage `json:"packages"`
+		}
+
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		require.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+		require.Equal(t, []string{"SPDXRef-Package-1", "SPDXRef-Package-2"}, doc.Describes)
+		require.Len(t, doc.Packages, 2)
+
+		pkg := doc.Packages[0]
+		require.Equal(t, "github.com/gone/repo", pkg.Name)
+		require.Equal(t, "git+https://github.com/Gone/Repo.git", pkg.DownloadLocation)
+		require.Equal(t, []spdxExternalRef{{"PACKAGE-MANAGER", "purl", "pkg:github/gone/repo"}}, pkg.ExternalRefs)
+
+		var comments []string
+
+		for _, a := range pkg.Annotations {
+			comments = append(comments, a.Comment)
+		}
+
+		require.Equal(t, []string{
+			"gh-arc:status=missing",
+			"gh-arc:reason=repository missing",
+			"gh-arc:archived=false",
+			"gh-arc:severity=error",
+			"gh-arc:location=a/go.mod",
+		}, comments)
+	})
+}
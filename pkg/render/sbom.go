@@ -0,0 +1,274 @@
+package render
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+// propertyPrefix namespaces the properties and annotations added to SBOM
+// components, following the CycloneDX property taxonomy convention.
+const propertyPrefix = "gh-arc:"
+
+// sbomComponent is a dependency with every location it is required at.
+type sbomComponent struct {
+	name    string
+	version string
+	purl    string
+	repoURL string
+	// finding is the first finding of the dependency. All findings of a
+	// repository share its status.
+	finding   finding.Finding
+	locations []string
+}
+
+// properties returns the status of the component as name and value pairs.
+func (c sbomComponent) properties() [][2]string {
+	f := c.finding
+
+	props := [][2]string{
+		{propertyPrefix + "status", f.Status.String()},
+		{propertyPrefix + "reason", f.Reason},
+		{propertyPrefix + "archived", strconv.FormatBool(f.Archived)},
+	}
+
+	if f.PushedAt != "" {
+		props = append(props, [2]string{propertyPrefix + "pushed_at", f.PushedAt})
+	}
+
+	props = append(props, [2]string{propertyPrefix + "severity", string(f.Severity())})
+
+	for _, location := range c.locations {
+		props = append(props, [2]string{propertyPrefix + "location", location})
+	}
+
+	return props
+}
+
+// sbomComponents groups findings by repository, module and version, so each
+// dependency is listed once however many manifests require it. Dependencies
+// are identified by a pkg:github package URL, which every finding has
+// regardless of its ecosystem.
+func sbomComponents(report finding.Report) []sbomComponent {
+	var components []sbomComponent
+
+	index := map[string]int{}
+
+	for _, f := range report.Findings {
+		name := f.Module
+		if name == "" {
+			name = f.Repo
+		}
+
+		purl := "pkg:github/" + strings.ToLower(f.Repo)
+		if f.Version != "" {
+			purl += "@" + f.Version
+		}
+
+		key := f.Repo + "\x00" + name + "\x00" + f.Version
+
+		i, ok := index[key]
+		if !ok {
+			i = len(components)
+			index[key] = i
+
+			components = append(components, sbomComponent{
+				name:    name,
+				version: f.Version,
+				purl:    purl,
+				repoURL: "https://github.com/" + f.Repo,
+				finding: f,
+			})
+		}
+
+		location := f.File
+		if f.Line > 0 {
+			location += ":" + strconv.Itoa(f.Line)
+		}
+
+		components[i].locations = append(components[i].locations, location)
+	}
+
+	slices.SortFunc(components, func(a, b sbomComponent) int {
+		return strings.Compare(a.purl+"\x00"+a.name, b.purl+"\x00"+b.name)
+	})
+
+	return components
+}
+
+// toolName is how gh-arc identifies itself as the creator of an SBOM.
+func toolName() string {
+	v := version.Get().Version
+	if v == "" {
+		return "gh-arc"
+	}
+
+	return "gh-arc-" + v
+}
+
+// randomUUID returns a random version 4 UUID.
+func randomUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	h := hex.EncodeToString(b)
+
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+func encodeSBOM(w io.Writer, doc any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode sbom: %w", err)
+	}
+
+	return nil
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXComponent struct {
+	Type       string               `json:"type"`
+	Ref        string               `json:"bom-ref"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version,omitempty"`
+	PURL       string               `json:"purl"`
+	References []cycloneDXReference `json:"externalReferences"`
+	Properties []cycloneDXProperty  `json:"properties"`
+}
+
+// CycloneDX writes the report as a CycloneDX 1.5 JSON SBOM with a component
+// for every unhealthy dependency. The status, reason and locations of each
+// finding are recorded as "gh-arc:" properties of its component.
+func CycloneDX(w io.Writer, report finding.Report) error {
+	components := []cycloneDXComponent{}
+
+	for _, c := range sbomComponents(report) {
+		out := cycloneDXComponent{
+			Type:       "library",
+			Ref:        c.purl,
+			Name:       c.name,
+			Version:    c.version,
+			PURL:       c.purl,
+			References: []cycloneDXReference{{"vcs", c.repoURL}},
+		}
+
+		for _, p := range c.properties() {
+			out.Properties = append(out.Properties, cycloneDXProperty{Name: p[0], Value: p[1]})
+		}
+
+		components = append(components, out)
+	}
+
+	doc := map[string]any{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": "urn:uuid:" + randomUUID(),
+		"version":      1,
+		"metadata": map[string]any{
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"tools": map[string]any{
+				"components": []map[string]string{
+					{"type": "application", "name": "gh-arc", "version": version.Get().Version},
+				},
+			},
+		},
+		"components": components,
+	}
+
+	return encodeSBOM(w, doc)
+}
+
+type spdxExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type spdxAnnotation struct {
+	Date      string `json:"annotationDate"`
+	Type      string `json:"annotationType"`
+	Annotator string `json:"annotator"`
+	Comment   string `json:"comment"`
+}
+
+type spdxPackage struct {
+	ID               string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	Version          string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+	Annotations      []spdxAnnotation  `json:"annotations"`
+}
+
+// SPDX writes the report as an SPDX 2.3 JSON document with a package for
+// every unhealthy dependency. The status, reason and locations of each
+// finding are recorded as "gh-arc:" review annotations of its package.
+func SPDX(w io.Writer, report finding.Report) error {
+	created := time.Now().UTC().Format(time.RFC3339)
+	annotator := "Tool: " + toolName()
+
+	packages := []spdxPackage{}
+	describes := []string{}
+
+	for i, c := range sbomComponents(report) {
+		pkg := spdxPackage{
+			ID:               fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			Name:             c.name,
+			Version:          c.version,
+			DownloadLocation: "git+" + c.repoURL + ".git",
+			ExternalRefs:     []spdxExternalRef{{"PACKAGE-MANAGER", "purl", c.purl}},
+		}
+
+		for _, p := range c.properties() {
+			pkg.Annotations = append(pkg.Annotations, spdxAnnotation{
+				Date:      created,
+				Type:      "REVIEW",
+				Annotator: annotator,
+				Comment:   p[0] + "=" + p[1],
+			})
+		}
+
+		packages = append(packages, pkg)
+		describes = append(describes, pkg.ID)
+	}
+
+	doc := map[string]any{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              "gh-arc findings",
+		"documentNamespace": "https://github.com/wayneashleyberry/gh-arc/spdx/" + randomUUID(),
+		"creationInfo": map[string]any{
+			"created":  created,
+			"creators": []string{annotator},
+		},
+		"documentDescribes": describes,
+		"packages":          packages,
+	}
+
+	return encodeSBOM(w, doc)
+}
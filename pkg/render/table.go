@@ -0,0 +1,161 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// tableHeader names the columns of table output.
+var tableHeader = []string{"SEVERITY", "STATUS", "REPOSITORY", "MODULE", "LOCATION", "DETAIL"}
+
+// minColumnWidth is the narrowest a column is truncated to.
+const minColumnWidth = 8
+
+// columnGap separates the columns of table output.
+const columnGap = "  "
+
+// Table writes findings as aligned columns followed by the per-status counts.
+// If width is positive, the widest columns are truncated until every line
+// fits, keeping the end of locations and the start of everything else.
+func Table(w io.Writer, report finding.Report, width int) error {
+	return table(w, report, width, false)
+}
+
+// table is Table with optional ANSI colors by severity.
+func table(w io.Writer, report finding.Report, width int, color bool) error {
+	tableFindings(w, report.Findings, width, color)
+	footer(w, report)
+
+	return nil
+}
+
+// tableFindings writes findings as aligned columns under a header row.
+func tableFindings(w io.Writer, findings []finding.Finding, width int, color bool) {
+	rows := [][]string{tableHeader}
+
+	for _, f := range findings {
+		location := f.File
+		if f.Line > 0 {
+			location += ":" + strconv.Itoa(f.Line)
+		}
+
+		detail := Detail(f)
+		if f.Indirect {
+			detail += ", indirect"
+		}
+
+		if f.Tool {
+			detail += ", tool"
+		}
+
+		if f.Informational {
+			detail += ", informational"
+		}
+
+		if f.Baselined {
+			detail += ", baselined"
+		}
+
+		if len(f.CodeOwners) > 0 {
+			detail += ", owned by " + strings.Join(f.CodeOwners, " ")
+		}
+
+		if f.Mirror {
+			detail += ", mirror"
+		}
+
+		if f.Canonical != "" && f.Status != status.Moved {
+			detail += ", moved to " + f.Canonical + pathNote(f)
+		}
+
+		if f.Successor != "" {
+			detail += ", succeeded by " + f.Successor
+		}
+
+		if f.Unattended() {
+			detail += ", ignored by update bots"
+		}
+
+		rows = append(rows, []string{string(f.Severity()), f.Status.String(), f.Repo, f.Module, location, detail})
+	}
+
+	widths := columnWidths(rows, width)
+
+	for i, row := range rows {
+		cells := make([]string, len(row))
+
+		for col, cell := range row {
+			cell = truncate(cell, widths[col], col == 4)
+
+			if col < len(row)-1 {
+				cell += strings.Repeat(" ", widths[col]-utf8.RuneCountInString(cell))
+			}
+
+			if col == 0 && i > 0 && color {
+				cell = colorize(cell, findings[i-1].Severity())
+			}
+
+			cells[col] = cell
+		}
+
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, columnGap), " "))
+	}
+}
+
+// columnWidths returns the width of the widest cell of each column, shrinking
+// the widest column one rune at a time until a row fits in maxWidth. Zero
+// means no limit.
+func columnWidths(rows [][]string, maxWidth int) []int {
+	widths := make([]int, len(rows[0]))
+
+	for _, row := range rows {
+		for col, cell := range row {
+			widths[col] = max(widths[col], utf8.RuneCountInString(cell))
+		}
+	}
+
+	if maxWidth <= 0 {
+		return widths
+	}
+
+	for {
+		total := len(columnGap) * (len(widths) - 1)
+		widest := -1
+
+		for col, width := range widths {
+			total += width
+
+			if width > minColumnWidth && (widest < 0 || width > widths[widest]) {
+				widest = col
+			}
+		}
+
+		if total <= maxWidth || widest < 0 {
+			return widths
+		}
+
+		widths[widest]--
+	}
+}
+
+// truncate shortens s to width runes, replacing the cut off part with an
+// ellipsis. fromLeft keeps the end of s instead of the start, as the end of a
+// path is usually what tells paths apart.
+func truncate(s string, width int, fromLeft bool) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+
+	if fromLeft {
+		return "…" + string(runes[len(runes)-width+1:])
+	}
+
+	return string(runes[:width-1]) + "…"
+}
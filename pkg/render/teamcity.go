@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// teamCityEscaper escapes values in TeamCity service messages.
+var teamCityEscaper = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"\n", "|n",
+	"\r", "|r",
+	"[", "|[",
+	"]", "|]",
+)
+
+// TeamCity writes TeamCity service messages that register an inspection
+// type per status and report every finding as an inspection, so findings are
+// listed on the build's Inspections tab.
+func TeamCity(w io.Writer, report finding.Report) error {
+	counts := report.Counts()
+
+	for _, st := range status.All {
+		if counts[st] == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "##teamcity[inspectionType id='%s' name='%s' category='Dependencies' description='%s dependencies']\n",
+			st, st, st)
+	}
+
+	for _, f := range report.Findings {
+		severity := "WARNING"
+
+		switch f.Severity() {
+		case finding.SeverityError:
+			severity = "ERROR"
+		case finding.SeverityInfo:
+			severity = "INFO"
+		case finding.SeverityWarning:
+			severity = "WARNING"
+		}
+
+		message := "github.com/" + f.Repo + " " + annotation(f)
+
+		fmt.Fprintf(w, "##teamcity[inspection typeId='%s' message='%s' file='%s' line='%d' SEVERITY='%s']\n",
+			f.Status, teamCityEscaper.Replace(message), teamCityEscaper.Replace(f.File), f.Line, severity)
+	}
+
+	if note := report.PartialNote(); note != "" {
+		fmt.Fprintf(w, "##teamcity[message text='%s' status='WARNING']\n", teamCityEscaper.Replace(note))
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/advisory"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/forks"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// ANSI escape sequences used to color text output.
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+)
+
+// Text writes one line per finding followed by the per-status counts.
+func Text(w io.Writer, report finding.Report) error {
+	return text(w, report, false)
+}
+
+// colorize wraps s in the ANSI color of severity.
+func colorize(s string, severity finding.Severity) string {
+	color := colorYellow
+
+	switch severity {
+	case finding.SeverityError:
+		color = colorRed
+	case finding.SeverityInfo:
+		color = colorCyan
+	case finding.SeverityWarning:
+		color = colorYellow
+	}
+
+	return color + s + colorReset
+}
+
+// text is Text with optional ANSI colors by severity.
+func text(w io.Writer, report finding.Report, color bool) error {
+	textFindings(w, report.Findings, color)
+	footer(w, report)
+
+	return nil
+}
+
+// textFindings writes one line per finding.
+func textFindings(w io.Writer, findings []finding.Finding, color bool) {
+	for _, f := range findings {
+		suffix := ""
+		if f.Indirect {
+			suffix = " // indirect"
+		}
+
+		if f.Tool {
+			suffix += " // tool"
+		}
+
+		if f.Informational {
+			suffix += " // informational"
+		}
+
+		if f.Baselined {
+			suffix += " // baselined"
+		}
+
+		if len(f.CodeOwners) > 0 {
+			suffix += "\n  code owners: " + strings.Join(f.CodeOwners, " ")
+		}
+
+		if f.Automation != "" {
+			suffix += "\n  update automation: " + string(f.Automation)
+
+			if f.Unattended() {
+				suffix += ", archived and ignored by update bots"
+			}
+		}
+
+		if f.Mirror {
+			suffix += "\n  mirror of: " + cmp.Or(f.Upstream, "unknown upstream")
+		}
+
+		if f.Canonical != "" && f.Status != status.Moved {
+			suffix += "\n  moved to: https://github.com/" + f.Canonical + pathNote(f)
+		}
+
+		if f.Successor != "" {
+			suffix += "\n  successor: https://github.com/" + f.Successor
+		}
+
+		if f.Details != nil {
+			suffix += "\n  details: " + detailsLine(*f.Details)
+		}
+
+		if alt := f.Alternatives; alt != nil {
+			if alt.Dependents != nil {
+				suffix += fmt.Sprintf("\n  dependents: %d (%d direct) %s", alt.Dependents.DependentCount, alt.Dependents.DirectDependentCount, alt.DepsDevURL)
+			}
+
+			if alt.SearchURL != "" {
+				suffix += "\n  importers: " + alt.ImportedBy + "\n  alternatives: " + alt.SearchURL
+			}
+
+			for _, fork := range alt.Forks {
+				suffix += "\n  fork: " + forkLine(fork)
+			}
+		}
+
+		for _, a := range f.Advisories {
+			suffix += "\n  advisory: " + advisoryLine(a)
+		}
+
+		line := fmt.Sprintf("%s (%s)", f.URL(), Detail(f))
+		if color {
+			line = colorize(line, f.Severity())
+		}
+
+		if f.File == "" {
+			fmt.Fprintf(w, "%s%s\n", line, suffix)
+
+			continue
+		}
+
+		fmt.Fprintf(w, "%s: %s%s\n", f.File, line, suffix)
+	}
+}
+
+// footer writes the per-status counts of text and table output, and why the
+// report is partial if it is.
+func footer(w io.Writer, report finding.Report) {
+	if counts := report.Counts(); counts.Total() > 0 {
+		fmt.Fprintf(w, "\n%s\n", counts)
+	}
+
+	if note := report.PartialNote(); note != "" {
+		fmt.Fprintf(w, "\n%s\n", note)
+	}
+}
+
+// Detail returns the parenthetical text printed after a finding in text output.
+func Detail(f finding.Finding) string {
+	result := f.Metadata
+
+	switch f.Status {
+	case status.Missing:
+		return "repository missing"
+	case status.Moved:
+		return "moved to https://github.com/" + result.FullName + pathNote(f) + forkNote(result)
+	case status.Stale, status.Deprecated:
+		if result.Degraded {
+			return fmt.Sprintf("%s, last commit: %s, via git", f.Status, result.PushedAt)
+		}
+
+		return fmt.Sprintf("%s, last push: %s%s", f.Status, result.PushedAt, forkNote(result))
+	case status.Archived:
+		return "last push: " + result.PushedAt + forkNote(result) + advisoryNote(f)
+	case status.UpstreamArchived:
+		return "last push: " + result.PushedAt + forkNote(result)
+	case status.Unknown, status.UnsupportedGo, status.NoLicense, status.DisallowedLicense, status.Copyleft, status.MissingVersion, status.Outdated:
+		return f.Reason
+	}
+
+	return f.Status.String()
+}
+
+// forkLine describes a candidate fork with its score breakdown, e.g.
+// "https://github.com/fork/repo 7.5/10 (activity 4/4: 31 commits in 90 days, ...)".
+func forkLine(fork forks.Candidate) string {
+	parts := make([]string, 0, len(fork.Breakdown))
+
+	for _, c := range fork.Breakdown {
+		parts = append(parts, fmt.Sprintf("%s %g/%g: %s", c.Name, c.Points, c.Max, c.Detail))
+	}
+
+	return fmt.Sprintf("https://github.com/%s %g/10 (%s)", fork.Repo, fork.Score, strings.Join(parts, ", "))
+}
+
+// pathNote returns a note for findings whose module path names their
+// repository by its old name, or an empty string.
+func pathNote(f finding.Finding) string {
+	if !f.OutdatedPath() {
+		return ""
+	}
+
+	return ", module path is out of date"
+}
+
+// detailsLine describes the state of a repository, e.g.
+// "12 open issues, latest release v1.2.3 (2024-01-02T03:04:05Z), default branch main".
+func detailsLine(d finding.Details) string {
+	line := fmt.Sprintf("%d open issues", d.OpenIssues)
+	if d.OpenIssues == 1 {
+		line = "1 open issue"
+	}
+
+	if d.LatestRelease != "" {
+		line += fmt.Sprintf(", latest release %s (%s)", d.LatestRelease, d.LatestReleaseAt)
+	} else {
+		line += ", no releases"
+	}
+
+	if d.DefaultBranch != "" {
+		line += ", default branch " + d.DefaultBranch
+	}
+
+	return line
+}
+
+// advisoryLine describes an advisory, e.g.
+// "GHSA-xxxx-xxxx-xxxx (high) Summary, fixed in 1.2.3 https://github.com/advisories/GHSA-xxxx-xxxx-xxxx".
+func advisoryLine(a advisory.Advisory) string {
+	line := fmt.Sprintf("%s (%s) %s", a.ID, a.Severity, a.Summary)
+	if a.FirstPatched != "" {
+		line += ", fixed in " + a.FirstPatched
+	}
+
+	return line + " " + a.URL
+}
+
+// advisoryNote counts the unpatched advisories of a finding, e.g.
+// ", 2 unpatched advisories".
+func advisoryNote(f finding.Finding) string {
+	switch n := len(f.Advisories); n {
+	case 0:
+		return ""
+	case 1:
+		return ", 1 unpatched advisory"
+	default:
+		return fmt.Sprintf(", %d unpatched advisories", n)
+	}
+}
+
+// forkNote describes the parent of a forked repository, e.g.
+// ", fork of owner/repo (archived)".
+func forkNote(result client.RepoResult) string {
+	if !result.Fork || result.Parent == nil {
+		return ""
+	}
+
+	note := ", fork of " + result.Parent.FullName
+	if result.Parent.Archived {
+		note += " (archived)"
+	}
+
+	return note
+}
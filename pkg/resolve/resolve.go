@@ -0,0 +1,140 @@
+// Package resolve maps the packages of other ecosystems to the GitHub
+// repositories they are developed in, so they can be checked like Go modules.
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// Resolver looks up the repository URLs of packages in a package registry,
+// such as crates.io or PyPI.
+type Resolver interface {
+	// Repositories returns the repository URL of each named package.
+	// Packages that do not exist or do not declare a repository are
+	// omitted.
+	Repositories(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// Dependency is a package required by a manifest or recorded in a lock file.
+type Dependency struct {
+	// Name is the package name in its registry.
+	Name string
+	// Version is the locked version, or the version requirement if the
+	// package is not locked.
+	Version string
+	// File is the manifest or lock file the dependency was found in.
+	File string
+	// Line is the line of the dependency in File.
+	Line int
+	// Indirect is set for packages that are only found in a lock file.
+	Indirect bool
+	// URL is the repository URL of dependencies fetched from version
+	// control, which are resolved without a registry lookup.
+	URL string
+}
+
+// Resolve maps dependencies to the GitHub repositories they are developed in,
+// ready for gomod.Scanner.Check. Dependencies with a URL are resolved from it
+// and the others from the repository declared in the registry. Packages
+// hosted elsewhere are omitted.
+func Resolve(ctx context.Context, r Resolver, deps []Dependency) (map[string][]gomod.RepoInfo, error) {
+	var names []string
+
+	for _, dep := range deps {
+		if dep.URL == "" {
+			names = append(names, dep.Name)
+		}
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	urls, err := r.Repositories(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve packages: %w", err)
+	}
+
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, dep := range deps {
+		rawURL := dep.URL
+		if rawURL == "" {
+			rawURL = urls[dep.Name]
+		}
+
+		repo, ok := client.RepoFromURL(rawURL)
+		if !ok {
+			slog.DebugContext(ctx, fmt.Sprintf("no github repository for package %s", dep.Name))
+
+			continue
+		}
+
+		repos[repo] = append(repos[repo], gomod.NewRepoInfo(dep.File, dep.Line, 0, dep.Name, dep.Version, dep.Indirect))
+	}
+
+	return repos, nil
+}
+
+// Merge combines direct dependencies declared in manifests with the packages
+// recorded in lock files. Direct dependencies take their version from a lock
+// file when one records them; packages only found in lock files are kept as
+// indirect. Names are compared after applying normalize.
+func Merge(direct, locked []Dependency, normalize func(string) string) []Dependency {
+	versions := map[string]string{}
+
+	for _, dep := range locked {
+		if _, ok := versions[normalize(dep.Name)]; !ok {
+			versions[normalize(dep.Name)] = dep.Version
+		}
+	}
+
+	declared := map[string]bool{}
+
+	for i, dep := range direct {
+		declared[normalize(dep.Name)] = true
+
+		if v, ok := versions[normalize(dep.Name)]; ok {
+			direct[i].Version = v
+		}
+	}
+
+	deps := direct
+
+	for _, dep := range locked {
+		if !declared[normalize(dep.Name)] {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
+
+// Repos resolves deps to their repositories. Indirect dependencies are
+// dropped before resolving unless opts.Indirect is set.
+func Repos(ctx context.Context, r Resolver, deps []Dependency, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	if !opts.Indirect {
+		deps = slices.DeleteFunc(deps, func(dep Dependency) bool {
+			return dep.Indirect
+		})
+	}
+
+	return Resolve(ctx, r, deps)
+}
+
+// ListArchived resolves deps and checks their repositories, printing findings
+// according to opts.
+func ListArchived(ctx context.Context, r Resolver, deps []Dependency, opts gomod.Options) (finding.Report, error) {
+	repos, err := Repos(ctx, r, deps, opts)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return gomod.NewScanner(opts, opts.Output).Check(ctx, repos)
+}
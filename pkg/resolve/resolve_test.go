@@ -0,0 +1,63 @@
+package resolve
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockResolver answers lookups from a fixed map of repository URLs.
+type mockResolver map[string]string
+
+func (m mockResolver) Repositories(_ context.Context, names []string) (map[string]string, error) {
+	urls := map[string]string{}
+
+	for _, name := range names {
+		if u, ok := m[name]; ok {
+			urls[name] = u
+		}
+	}
+
+	return urls, nil
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	deps := []Dependency{
+		{Name: "requests", Version: "2.32.0", File: "requirements.txt", Line: 1},
+		{Name: "local", File: "requirements.txt", Line: 2},
+		{Name: "gitlab", File: "requirements.txt", Line: 3},
+		{Name: "fork", File: "requirements.txt", Line: 4, URL: "git+https://github.com/owner/fork.git"},
+	}
+
+	repos, err := Resolve(context.Background(), mockResolver{
+		"requests": "https://github.com/psf/requests",
+		"gitlab":   "https://gitlab.com/owner/gitlab",
+	}, deps)
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	require.Contains(t, repos, "psf/requests")
+	require.Contains(t, repos, "owner/fork")
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	direct := []Dependency{{Name: "Foo_Bar", Version: ">=1", File: "pyproject.toml", Line: 3}}
+	locked := []Dependency{
+		{Name: "foo-bar", Version: "1.2.0", File: "poetry.lock", Line: 2, Indirect: true},
+		{Name: "baz", Version: "0.1.0", File: "poetry.lock", Line: 8, Indirect: true},
+	}
+
+	got := Merge(direct, locked, func(name string) string {
+		return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	})
+
+	require.Equal(t, []Dependency{
+		{Name: "Foo_Bar", Version: "1.2.0", File: "pyproject.toml", Line: 3},
+		{Name: "baz", Version: "0.1.0", File: "poetry.lock", Line: 8, Indirect: true},
+	}, got)
+}
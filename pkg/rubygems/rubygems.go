@@ -0,0 +1,136 @@
+// Package rubygems provides a minimal client for the RubyGems.org API, used to
+// resolve Ruby gems to their source repositories.
+package rubygems
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+// DefaultBaseURL is the RubyGems.org endpoint.
+const DefaultBaseURL = "https://rubygems.org"
+
+// concurrency is the number of gems looked up at the same time. RubyGems.org
+// has no batch endpoint.
+const concurrency = 8
+
+// Client queries the RubyGems.org API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a Client for the public RubyGems.org API.
+func New() *Client {
+	return NewWithHTTPClient(&http.Client{Timeout: 10 * time.Second}, DefaultBaseURL)
+}
+
+// NewWithHTTPClient allows injecting a custom HTTP client and endpoint (for testing).
+func NewWithHTTPClient(httpClient *http.Client, baseURL string) *Client {
+	return &Client{httpClient: httpClient, baseURL: baseURL}
+}
+
+// IsSource reports whether a Gemfile source or Gemfile.lock remote is
+// RubyGems.org, whose gems can be looked up with a Client.
+func IsSource(source string) bool {
+	u, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	return host == "rubygems.org" && strings.Trim(u.Path, "/") == ""
+}
+
+// Repositories returns the GitHub repository URL of each named gem. Gems that
+// do not exist or do not link to GitHub are omitted.
+func (c *Client) Repositories(ctx context.Context, names []string) (map[string]string, error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, concurrency)
+		urls = map[string]string{}
+	)
+
+	for _, name := range names {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			u, err := c.repository(ctx, name)
+			if err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("error fetching gem %s: %v", name, err))
+
+				return
+			}
+
+			if u == "" {
+				return
+			}
+
+			mu.Lock()
+			urls[name] = u
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return urls, nil
+}
+
+// repository returns the first GitHub URL among the links of a gem, the
+// source code link first, or an empty string if it links to none.
+func (c *Client) repository(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/gems/"+url.PathEscape(name)+".json", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch gem %s: %w", name, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch gem %s: %s", name, resp.Status)
+	}
+
+	var body struct {
+		SourceCodeURI    string `json:"source_code_uri"`
+		HomepageURI      string `json:"homepage_uri"`
+		BugTrackerURI    string `json:"bug_tracker_uri"`
+		ChangelogURI     string `json:"changelog_uri"`
+		DocumentationURI string `json:"documentation_uri"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode gem %s: %w", name, err)
+	}
+
+	for _, u := range []string{body.SourceCodeURI, body.HomepageURI, body.BugTrackerURI, body.ChangelogURI, body.DocumentationURI} {
+		if _, ok := client.RepoFromURL(u); ok {
+			return u, nil
+		}
+	}
+
+	return "", nil
+}
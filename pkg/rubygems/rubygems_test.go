@@ -0,0 +1,47 @@
+package rubygems
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/gems/rails.json":
+			_, _ = w.Write([]byte(`{"name":"rails","homepage_uri":"https://rubyonrails.org","source_code_uri":"https://github.com/rails/rails/tree/v7.1.2"}`))
+		case "/api/v1/gems/rake.json":
+			_, _ = w.Write([]byte(`{"name":"rake","homepage_uri":"https://github.com/ruby/rake","source_code_uri":null}`))
+		case "/api/v1/gems/internal.json":
+			_, _ = w.Write([]byte(`{"name":"internal","homepage_uri":"https://example.com"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewWithHTTPClient(srv.Client(), srv.URL)
+
+	got, err := c.Repositories(context.Background(), []string{"rails", "rake", "internal", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"rails": "https://github.com/rails/rails/tree/v7.1.2",
+		"rake":  "https://github.com/ruby/rake",
+	}, got)
+}
+
+func TestIsSource(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsSource("https://rubygems.org"))
+	require.True(t, IsSource("https://rubygems.org/"))
+	require.True(t, IsSource("https://www.rubygems.org/"))
+	require.False(t, IsSource("https://gems.example.com/"))
+	require.False(t, IsSource("https://rubygems.org/private"))
+}
@@ -0,0 +1,300 @@
+// Package sbom reads CycloneDX software bills of materials, in JSON or XML,
+// and reports archived GitHub repositories of their components. It lets
+// teams that already generate SBOMs check them instead of rescanning source.
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// component is a CycloneDX component, read from either encoding.
+type component struct {
+	Ref        string
+	Group      string
+	Name       string
+	Version    string
+	PURL       string
+	References []reference
+	Components []component
+}
+
+// reference is an external reference of a component, such as its "vcs" or
+// "website" URL.
+type reference struct {
+	Type string
+	URL  string
+}
+
+// bom is the part of a CycloneDX document that is read.
+type bom struct {
+	// Root is the bom-ref of the component the SBOM describes.
+	Root       string
+	Components []component
+	// DependsOn maps bom-refs to the bom-refs they depend on.
+	DependsOn map[string][]string
+}
+
+// jsonComponent is a component in the JSON encoding.
+type jsonComponent struct {
+	Ref        string `json:"bom-ref"`
+	Group      string `json:"group"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	PURL       string `json:"purl"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"externalReferences"`
+	Components []jsonComponent `json:"components"`
+}
+
+func (c jsonComponent) component() component {
+	out := component{Ref: c.Ref, Group: c.Group, Name: c.Name, Version: c.Version, PURL: c.PURL}
+
+	for _, r := range c.References {
+		out.References = append(out.References, reference{Type: r.Type, URL: r.URL})
+	}
+
+	for _, child := range c.Components {
+		out.Components = append(out.Components, child.component())
+	}
+
+	return out
+}
+
+// parseJSON parses a CycloneDX JSON document.
+func parseJSON(data []byte) (bom, error) {
+	var doc struct {
+		Metadata struct {
+			Component jsonComponent `json:"component"`
+		} `json:"metadata"`
+		Components   []jsonComponent `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return bom{}, err
+	}
+
+	b := bom{Root: doc.Metadata.Component.Ref, DependsOn: map[string][]string{}}
+
+	for _, c := range doc.Components {
+		b.Components = append(b.Components, c.component())
+	}
+
+	for _, dep := range doc.Dependencies {
+		b.DependsOn[dep.Ref] = dep.DependsOn
+	}
+
+	return b, nil
+}
+
+// xmlComponent is a component in the XML encoding.
+type xmlComponent struct {
+	Ref        string `xml:"bom-ref,attr"`
+	Group      string `xml:"group"`
+	Name       string `xml:"name"`
+	Version    string `xml:"version"`
+	PURL       string `xml:"purl"`
+	References []struct {
+		Type string `xml:"type,attr"`
+		URL  string `xml:"url"`
+	} `xml:"externalReferences>reference"`
+	Components []xmlComponent `xml:"components>component"`
+}
+
+func (c xmlComponent) component() component {
+	out := component{Ref: c.Ref, Group: c.Group, Name: c.Name, Version: c.Version, PURL: c.PURL}
+
+	for _, r := range c.References {
+		out.References = append(out.References, reference{Type: r.Type, URL: strings.TrimSpace(r.URL)})
+	}
+
+	for _, child := range c.Components {
+		out.Components = append(out.Components, child.component())
+	}
+
+	return out
+}
+
+// xmlDependency is a node of the dependency graph in the XML encoding, where
+// the dependencies of a component are nested elements.
+type xmlDependency struct {
+	Ref       string          `xml:"ref,attr"`
+	DependsOn []xmlDependency `xml:"dependency"`
+}
+
+// parseXML parses a CycloneDX XML document.
+func parseXML(data []byte) (bom, error) {
+	var doc struct {
+		Metadata struct {
+			Component xmlComponent `xml:"component"`
+		} `xml:"metadata"`
+		Components   []xmlComponent  `xml:"components>component"`
+		Dependencies []xmlDependency `xml:"dependencies>dependency"`
+	}
+
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return bom{}, err
+	}
+
+	b := bom{Root: doc.Metadata.Component.Ref, DependsOn: map[string][]string{}}
+
+	for _, c := range doc.Components {
+		b.Components = append(b.Components, c.component())
+	}
+
+	for _, dep := range doc.Dependencies {
+		for _, child := range dep.DependsOn {
+			b.DependsOn[dep.Ref] = append(b.DependsOn[dep.Ref], child.Ref)
+		}
+	}
+
+	return b, nil
+}
+
+// parse parses a CycloneDX document in either encoding.
+func parse(data []byte) (bom, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return parseXML(data)
+	}
+
+	return parseJSON(data)
+}
+
+// purlRepo returns the GitHub repository of a package URL such as
+// "pkg:github/owner/repo@v1" or "pkg:golang/github.com/owner/repo/v2@v2.0.0".
+func purlRepo(purl string) (string, bool) {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return "", false
+	}
+
+	if i := strings.IndexAny(rest, "@?#"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	typ, path, _ := strings.Cut(rest, "/")
+
+	path, err := url.PathUnescape(path)
+	if err != nil {
+		return "", false
+	}
+
+	switch typ {
+	case "github":
+		return client.RepoFromURL("https://github.com/" + path)
+	case "golang":
+		return client.RepoFromURL("https://" + path)
+	}
+
+	return "", false
+}
+
+// repo returns the GitHub repository of a component: its "vcs" reference,
+// then its package URL, then any other reference.
+func (c component) repo() (string, bool) {
+	for _, r := range c.References {
+		if r.Type == "vcs" {
+			if repo, ok := client.RepoFromURL(r.URL); ok {
+				return repo, true
+			}
+		}
+	}
+
+	if repo, ok := purlRepo(c.PURL); ok {
+		return repo, true
+	}
+
+	for _, r := range c.References {
+		if repo, ok := client.RepoFromURL(r.URL); ok {
+			return repo, true
+		}
+	}
+
+	return "", false
+}
+
+// module returns the name findings report for a component, such as
+// "github.com/owner/repo" or "org.example:lib".
+func (c component) module() string {
+	if c.Group == "" {
+		return c.Name
+	}
+
+	if strings.HasPrefix(c.PURL, "pkg:maven/") {
+		return c.Group + ":" + c.Name
+	}
+
+	return c.Group + "/" + c.Name
+}
+
+// Discover parses the given CycloneDX files and returns the GitHub
+// repositories of their components, including nested ones. If an SBOM has a
+// dependency graph, only components its root depends on are direct;
+// otherwise every component is treated as direct.
+func Discover(paths []string) (map[string][]gomod.RepoInfo, error) {
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sbom: %w", err)
+		}
+
+		b, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sbom %s: %w", path, err)
+		}
+
+		direct, hasGraph := b.DependsOn[b.Root]
+		hasGraph = hasGraph && b.Root != ""
+
+		var add func(components []component)
+
+		add = func(components []component) {
+			for _, c := range components {
+				add(c.Components)
+
+				repo, ok := c.repo()
+				if !ok {
+					continue
+				}
+
+				indirect := hasGraph && !slices.Contains(direct, c.Ref)
+
+				repos[repo] = append(repos[repo], gomod.NewRepoInfo(path, 0, 0, c.module(), c.Version, indirect))
+			}
+		}
+
+		add(b.Components)
+	}
+
+	return repos, nil
+}
+
+// ListArchived lists archived, missing and otherwise unhealthy repositories of
+// the components of the given SBOMs, printing findings to stdout.
+func ListArchived(ctx context.Context, paths []string, opts gomod.Options) (finding.Report, error) {
+	repos, err := Discover(paths)
+	if err != nil {
+		return finding.Report{}, err
+	}
+
+	return gomod.NewScanner(opts, opts.Output).Check(ctx, repos)
+}
@@ -0,0 +1,143 @@
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+const bomJSON = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {
+    "component": {"bom-ref": "app", "type": "application", "name": "app"}
+  },
+  "components": [
+    {
+      "bom-ref": "pkg:golang/github.com/owner/direct@v1.0.0",
+      "type": "library",
+      "name": "github.com/owner/direct",
+      "version": "v1.0.0",
+      "purl": "pkg:golang/github.com/owner/direct@v1.0.0"
+    },
+    {
+      "bom-ref": "left-pad",
+      "type": "library",
+      "name": "left-pad",
+      "version": "1.3.0",
+      "purl": "pkg:npm/left-pad@1.3.0",
+      "externalReferences": [
+        {"type": "website", "url": "https://example.com"},
+        {"type": "vcs", "url": "git+https://github.com/left-pad/left-pad.git"}
+      ],
+      "components": [
+        {
+          "bom-ref": "nested",
+          "type": "library",
+          "group": "org.example",
+          "name": "lib",
+          "version": "2.0",
+          "purl": "pkg:maven/org.example/lib@2.0",
+          "externalReferences": [{"type": "website", "url": "https://github.com/example/lib"}]
+        }
+      ]
+    },
+    {
+      "bom-ref": "unknown",
+      "type": "library",
+      "name": "unknown",
+      "purl": "pkg:npm/unknown@1.0.0"
+    }
+  ],
+  "dependencies": [
+    {"ref": "app", "dependsOn": ["pkg:golang/github.com/owner/direct@v1.0.0"]},
+    {"ref": "pkg:golang/github.com/owner/direct@v1.0.0", "dependsOn": ["left-pad"]}
+  ]
+}
+`
+
+const bomXML = `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">
+  <metadata>
+    <component type="application" bom-ref="app">
+      <name>app</name>
+    </component>
+  </metadata>
+  <components>
+    <component type="library" bom-ref="direct">
+      <name>direct</name>
+      <version>1.0.0</version>
+      <purl>pkg:github/owner/direct@1.0.0</purl>
+    </component>
+    <component type="library" bom-ref="left-pad">
+      <name>left-pad</name>
+      <version>1.3.0</version>
+      <externalReferences>
+        <reference type="vcs">
+          <url>https://github.com/left-pad/left-pad</url>
+        </reference>
+      </externalReferences>
+    </component>
+  </components>
+  <dependencies>
+    <dependency ref="app">
+      <dependency ref="direct"/>
+    </dependency>
+  </dependencies>
+</bom>
+`
+
+func writeBOM(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestDiscover_JSON(t *testing.T) {
+	t.Parallel()
+
+	path := writeBOM(t, "bom.json", bomJSON)
+
+	repos, err := Discover([]string{path})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]gomod.RepoInfo{
+		"owner/direct":      {gomod.NewRepoInfo(path, 0, 0, "github.com/owner/direct", "v1.0.0", false)},
+		"left-pad/left-pad": {gomod.NewRepoInfo(path, 0, 0, "left-pad", "1.3.0", true)},
+		"example/lib":       {gomod.NewRepoInfo(path, 0, 0, "org.example:lib", "2.0", true)},
+	}, repos)
+}
+
+func TestDiscover_XML(t *testing.T) {
+	t.Parallel()
+
+	path := writeBOM(t, "bom.xml", bomXML)
+
+	repos, err := Discover([]string{path})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]gomod.RepoInfo{
+		"owner/direct":      {gomod.NewRepoInfo(path, 0, 0, "direct", "1.0.0", false)},
+		"left-pad/left-pad": {gomod.NewRepoInfo(path, 0, 0, "left-pad", "1.3.0", true)},
+	}, repos)
+}
+
+func TestPURLRepo(t *testing.T) {
+	t.Parallel()
+
+	for purl, want := range map[string]string{
+		"pkg:github/owner/repo@v1.0.0":                "owner/repo",
+		"pkg:golang/github.com/owner/repo/v2@v2.0.0":  "owner/repo",
+		"pkg:golang/github.com%2Fowner%2Frepo@v1.0.0": "owner/repo",
+		"pkg:golang/golang.org/x/mod@v0.20.0":         "",
+		"pkg:npm/left-pad@1.3.0":                      "",
+		"not a purl":                                  "",
+	} {
+		got, _ := purlRepo(purl)
+		require.Equal(t, want, got, purl)
+	}
+}
@@ -0,0 +1,115 @@
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// maxBodySize is the largest request body the API reads.
+const maxBodySize = 1 << 20
+
+// ErrInvalidRequest marks errors caused by the request rather than the scan,
+// which the API answers with 400 Bad Request.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// API scans dependencies on request, so other services can query archived
+// status through one shared instance and its cache. Scans use the GitHub
+// credentials of the server, so every request must be authenticated.
+type API struct {
+	// Token is the bearer token every request must carry in its
+	// Authorization header. Empty rejects every request.
+	Token string
+	// GoMod scans the dependencies of the given go.mod file.
+	GoMod func(ctx context.Context, data []byte) (finding.Report, error)
+	// Repo scans the repository named "owner/repo[@ref]" without cloning it.
+	Repo func(ctx context.Context, arg string) (finding.Report, error)
+}
+
+// RepoRequest is the body of a repository scan request.
+type RepoRequest struct {
+	// Repo is "owner/repo", optionally followed by "@ref".
+	Repo string `json:"repo"`
+}
+
+// errorResponse is the body of a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// register adds the API routes to mux.
+func (a *API) register(mux *http.ServeMux) {
+	mux.Handle("POST /api/gomod", authenticate(a.Token, func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("failed to read go.mod: %v", err)})
+
+			return
+		}
+
+		report, err := a.GoMod(r.Context(), data)
+		writeReport(w, report, err)
+	}))
+
+	mux.Handle("POST /api/repo", authenticate(a.Token, func(w http.ResponseWriter, r *http.Request) {
+		var req RepoRequest
+
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err := dec.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("failed to decode request: %v", err)})
+
+			return
+		}
+
+		report, err := a.Repo(r.Context(), req.Repo)
+		writeReport(w, report, err)
+	}))
+}
+
+// authenticate answers requests without want as their bearer token with 401
+// Unauthorized, and passes the others on to next. An empty want rejects
+// every request.
+func authenticate(want string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || want == "" || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid bearer token"})
+
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+// writeReport writes report, or err with a status telling whether the
+// request or the scan was at fault.
+func writeReport(w http.ResponseWriter, report finding.Report, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, report)
+	}
+}
+
+// writeJSON writes v as an indented JSON document with the given status.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	_ = enc.Encode(v)
+}
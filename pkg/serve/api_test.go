@@ -0,0 +1,139 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+func TestAPI(t *testing.T) {
+	t.Parallel()
+
+	s := New(func(context.Context) (finding.Report, error) {
+		return finding.Report{}, nil
+	}, DefaultInterval)
+
+	s.API = &API{
+		Token: "secret",
+		GoMod: func(_ context.Context, data []byte) (finding.Report, error) {
+			if !strings.HasPrefix(string(data), "module ") {
+				return finding.Report{}, fmt.Errorf("%w: go.mod has no module directive", ErrInvalidRequest)
+			}
+
+			return report, nil
+		},
+		Repo: func(_ context.Context, arg string) (finding.Report, error) {
+			if arg == "a/down" {
+				return finding.Report{}, fmt.Errorf("failed to look up default branch of %s", arg)
+			}
+
+			return finding.Report{Findings: report.Findings[:1]}, nil
+		},
+	}
+
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	post := func(path, body string) (int, map[string]any) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		req.Header.Set("Authorization", "Bearer secret")
+
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		var got map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+
+		return resp.StatusCode, got
+	}
+
+	code, got := post("/api/gomod", "module example.com/app\n")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, got["findings"], 5)
+
+	code, got = post("/api/gomod", "nonsense")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "invalid request: go.mod has no module directive", got["error"])
+
+	code, got = post("/api/repo", `{"repo": "a/app@main"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, got["findings"], 1)
+
+	code, _ = post("/api/repo", `{"repo":`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, got = post("/api/repo", `{"repo": "a/down"}`)
+	require.Equal(t, http.StatusBadGateway, code)
+	require.Equal(t, "failed to look up default branch of a/down", got["error"])
+
+	resp, err := srv.Client().Get(srv.URL + "/api/repo")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestAPI_Disabled(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(New(nil, DefaultInterval).Handler())
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Post(srv.URL+"/api/gomod", "text/plain", strings.NewReader("module x\n"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestAPI_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	for name, api := range map[string]*API{
+		"token":    {Token: "secret"},
+		"no token": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := New(nil, DefaultInterval)
+			s.API = api
+			s.API.GoMod = func(context.Context, []byte) (finding.Report, error) {
+				t.Fatal("unauthorized request was scanned")
+
+				return finding.Report{}, nil
+			}
+
+			srv := httptest.NewServer(s.Handler())
+			t.Cleanup(srv.Close)
+
+			for _, header := range []string{"", "Bearer wrong", "Bearer ", "secret", "Basic secret"} {
+				req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/api/gomod", strings.NewReader("module x\n"))
+				require.NoError(t, err)
+
+				if header != "" {
+					req.Header.Set("Authorization", header)
+				}
+
+				resp, err := srv.Client().Do(req)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+				require.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package serve runs scans on a schedule and exposes the latest result as
+// Prometheus metrics and a JSON status document, so dependency rot can be
+// alerted on without parsing command output.
+package serve
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// DefaultInterval is how often scans run.
+const DefaultInterval = 6 * time.Hour
+
+// Scan runs a scan without rendering its findings.
+type Scan func(ctx context.Context) (finding.Report, error)
+
+// Status describes the latest scan.
+type Status struct {
+	// Scans is the number of scans run, including failed ones.
+	Scans int `json:"scans"`
+	// ScannedAt is when the latest scan finished, or the zero time before
+	// the first scan.
+	ScannedAt time.Time `json:"scanned_at"`
+	// Duration is how long the latest scan took.
+	Duration time.Duration `json:"duration_ns"`
+	// Error is why the latest scan failed. Report is then the report of
+	// the last scan that succeeded.
+	Error string `json:"error,omitempty"`
+	// Summary describes Report in one line.
+	Summary string `json:"summary"`
+	// Report is the report of the last scan that succeeded.
+	Report *finding.Report `json:"report,omitempty"`
+}
+
+// Server runs scans and serves their results.
+type Server struct {
+	// API, if set, is served beneath /api.
+	API *API
+	// Token, if set, is the bearer token requests to /metrics and /status
+	// must carry in their Authorization header, since both name the
+	// dependencies of everything scanned. Without it they are served to
+	// anyone who can connect, so the server should only listen on a
+	// loopback address.
+	Token string
+
+	scan     Scan
+	interval time.Duration
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New returns a server running scan every interval.
+func New(scan Scan, interval time.Duration) *Server {
+	return &Server{scan: scan, interval: interval, status: Status{Summary: "not scanned yet"}}
+}
+
+// Run scans immediately and then every interval until ctx is done.
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run scans once and records the result. A failed scan keeps the report of
+// the previous one, so metrics do not drop to zero on a transient error.
+func (s *Server) run(ctx context.Context) {
+	start := time.Now()
+	report, err := s.scan(ctx)
+	elapsed := time.Since(start)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Scans++
+	s.status.ScannedAt = start.Add(elapsed)
+	s.status.Duration = elapsed
+	s.status.Error = ""
+
+	if err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("scan failed: %v", err))
+
+		s.status.Error = err.Error()
+
+		return
+	}
+
+	slog.InfoContext(ctx, fmt.Sprintf("scan finished in %s: %s", elapsed.Round(time.Millisecond), report.Summary()))
+
+	s.status.Report = &report
+	s.status.Summary = report.Summary()
+}
+
+// Status returns the status of the latest scan.
+func (s *Server) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.status
+}
+
+// Handler serves metrics at /metrics, the status at /status and, if s.API is
+// set, scans requested with POST /api/gomod and POST /api/repo.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /metrics", s.protect(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		_ = WriteMetrics(w, s.Status())
+	}))
+
+	mux.Handle("GET /status", s.protect(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.Status())
+	}))
+
+	if s.API != nil {
+		s.API.register(mux)
+	}
+
+	return mux
+}
+
+// protect requires s.Token as the bearer token of requests to next, if it is
+// set.
+func (s *Server) protect(next http.HandlerFunc) http.Handler {
+	if s.Token == "" {
+		return next
+	}
+
+	return authenticate(s.Token, next)
+}
+
+// Loopback reports whether the listen address addr, such as "127.0.0.1:9464",
+// only accepts connections from the local machine. Addresses without a
+// host, such as ":9464", listen on every interface.
+func Loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// WriteMetrics writes st in the Prometheus text exposition format. Every
+// archived dependency that is not baselined or informational is a series of
+// arc_archived_dependencies, and arc_findings counts findings per status.
+func WriteMetrics(w io.Writer, st Status) error {
+	var b strings.Builder
+
+	var report finding.Report
+	if st.Report != nil {
+		report = st.Report.Enforced()
+	}
+
+	b.WriteString("# HELP arc_archived_dependencies Archived dependencies, labeled by module and repository.\n")
+	b.WriteString("# TYPE arc_archived_dependencies gauge\n")
+
+	type series struct{ module, repo string }
+
+	var archived []series
+
+	for _, f := range report.Findings {
+		s := series{module: f.Module, repo: f.Repo}
+		if f.Status == status.Archived && !slices.Contains(archived, s) {
+			archived = append(archived, s)
+		}
+	}
+
+	slices.SortFunc(archived, func(a, b series) int {
+		return strings.Compare(a.module+"\x00"+a.repo, b.module+"\x00"+b.repo)
+	})
+
+	for _, s := range archived {
+		fmt.Fprintf(&b, "arc_archived_dependencies{module=%s,repo=%s} 1\n", label(s.module), label(s.repo))
+	}
+
+	b.WriteString("# HELP arc_findings Findings of the latest scan per status, excluding baselined and informational ones.\n")
+	b.WriteString("# TYPE arc_findings gauge\n")
+
+	counts := report.Counts()
+	for _, s := range status.All {
+		fmt.Fprintf(&b, "arc_findings{status=%s} %d\n", label(s.String()), counts[s])
+	}
+
+	success := 1
+	if st.Error != "" {
+		success = 0
+	}
+
+	b.WriteString("# HELP arc_scans_total Scans run since the server started.\n")
+	b.WriteString("# TYPE arc_scans_total counter\n")
+	fmt.Fprintf(&b, "arc_scans_total %d\n", st.Scans)
+	b.WriteString("# HELP arc_last_scan_success Whether the latest scan succeeded.\n")
+	b.WriteString("# TYPE arc_last_scan_success gauge\n")
+	fmt.Fprintf(&b, "arc_last_scan_success %d\n", success)
+
+	if !st.ScannedAt.IsZero() {
+		b.WriteString("# HELP arc_last_scan_timestamp_seconds When the latest scan finished.\n")
+		b.WriteString("# TYPE arc_last_scan_timestamp_seconds gauge\n")
+		fmt.Fprintf(&b, "arc_last_scan_timestamp_seconds %d\n", st.ScannedAt.Unix())
+		b.WriteString("# HELP arc_last_scan_duration_seconds How long the latest scan took.\n")
+		b.WriteString("# TYPE arc_last_scan_duration_seconds gauge\n")
+		fmt.Fprintf(&b, "arc_last_scan_duration_seconds %g\n", st.Duration.Seconds())
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return nil
+}
+
+// label quotes a label value, escaping backslashes, double quotes and line
+// feeds.
+func label(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
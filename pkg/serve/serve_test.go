@@ -0,0 +1,144 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+var report = finding.Report{Findings: []finding.Finding{
+	{Module: "github.com/a/old", Repo: "a/old", File: "go.mod", Status: status.Archived},
+	{Module: "github.com/a/old", Repo: "a/old", File: "tools/go.mod", Status: status.Archived},
+	{Module: "github.com/a/\"quoted\"", Repo: "a/quoted", File: "go.mod", Status: status.Archived},
+	{Module: "github.com/a/known", Repo: "a/known", File: "go.mod", Status: status.Archived, Baselined: true},
+	{Module: "github.com/a/stale", Repo: "a/stale", File: "go.mod", Status: status.Stale},
+}}
+
+func TestWriteMetrics(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+
+	require.NoError(t, WriteMetrics(&b, Status{Scans: 2, Error: "boom", Report: &report}))
+
+	out := b.String()
+	require.Contains(t, out, "# TYPE arc_archived_dependencies gauge\n"+
+		`arc_archived_dependencies{module="github.com/a/\"quoted\"",repo="a/quoted"} 1`+"\n"+
+		`arc_archived_dependencies{module="github.com/a/old",repo="a/old"} 1`+"\n#")
+	require.NotContains(t, out, "a/known")
+	require.Contains(t, out, `arc_findings{status="archived"} 3`+"\n")
+	require.Contains(t, out, `arc_findings{status="stale"} 1`+"\n")
+	require.Contains(t, out, `arc_findings{status="missing"} 0`+"\n")
+	require.Contains(t, out, "arc_scans_total 2\n")
+	require.Contains(t, out, "arc_last_scan_success 0\n")
+	require.NotContains(t, out, "arc_last_scan_timestamp_seconds")
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	fail := false
+	s := New(func(context.Context) (finding.Report, error) {
+		if fail {
+			return finding.Report{}, errors.New("rate limited")
+		}
+
+		return report, nil
+	}, DefaultInterval)
+
+	s.run(context.Background())
+
+	fail = true
+
+	s.run(context.Background())
+
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL + "/status")
+	require.NoError(t, err)
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var st Status
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&st))
+	require.Equal(t, 2, st.Scans)
+	require.Equal(t, "rate limited", st.Error)
+	require.Equal(t, report.Summary(), st.Summary)
+	require.NotNil(t, st.Report)
+	require.Len(t, st.Report.Findings, 5)
+
+	metrics, err := srv.Client().Get(srv.URL + "/metrics")
+	require.NoError(t, err)
+
+	defer func() {
+		_ = metrics.Body.Close()
+	}()
+
+	data, err := io.ReadAll(metrics.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, metrics.StatusCode)
+	require.Contains(t, string(data), `arc_archived_dependencies{module="github.com/a/old",repo="a/old"} 1`)
+	require.Contains(t, string(data), "arc_last_scan_success 0\n")
+	require.Contains(t, string(data), "arc_last_scan_timestamp_seconds ")
+}
+
+func TestServer_Token(t *testing.T) {
+	t.Parallel()
+
+	s := New(func(context.Context) (finding.Report, error) { return report, nil }, DefaultInterval)
+	s.Token = "secret"
+
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	for _, path := range []string{"/metrics", "/status"} {
+		for auth, want := range map[string]int{
+			"":              http.StatusUnauthorized,
+			"Bearer wrong":  http.StatusUnauthorized,
+			"Bearer secret": http.StatusOK,
+		} {
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+path, nil)
+			require.NoError(t, err)
+
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+
+			resp, err := srv.Client().Do(req)
+			require.NoError(t, err)
+
+			_ = resp.Body.Close()
+
+			require.Equal(t, want, resp.StatusCode, "%s with %q", path, auth)
+		}
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	t.Parallel()
+
+	for addr, want := range map[string]bool{
+		"127.0.0.1:9464": true,
+		"[::1]:9464":     true,
+		"localhost:9464": true,
+		":9464":          false,
+		"0.0.0.0:9464":   false,
+		"10.0.0.1:9464":  false,
+		"example.com:80": false,
+		"invalid":        false,
+	} {
+		require.Equal(t, want, Loopback(addr), addr)
+	}
+}
@@ -0,0 +1,158 @@
+// Package status defines the categories of findings reported for dependencies
+// and helpers for tallying them.
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status classifies why a dependency was reported.
+type Status int
+
+const (
+	// Archived means the upstream repository is archived and read-only.
+	Archived Status = iota + 1
+	// Missing means the upstream repository no longer exists.
+	Missing
+	// Stale means the upstream repository has not been pushed to recently.
+	Stale
+	// Moved means the upstream repository has been renamed or transferred.
+	Moved
+	// Deprecated means the upstream repository describes itself as deprecated.
+	Deprecated
+	// UpstreamArchived means the repository is a fork whose parent is archived.
+	UpstreamArchived
+	// Unknown means the repository could not be checked, for example because
+	// the API kept failing after retries.
+	Unknown
+	// UnsupportedGo means a go.mod requires a Go release outside the
+	// supported range.
+	UnsupportedGo
+	// NoLicense means no license was detected in the upstream repository.
+	NoLicense
+	// DisallowedLicense means the upstream repository's license is on the
+	// configured list of disallowed licenses.
+	DisallowedLicense
+	// Copyleft means the upstream repository has a copyleft license that is
+	// not disallowed. It is reported as a warning only.
+	Copyleft
+	// MissingVersion means the required version has no tag in the upstream
+	// repository, because it was deleted or the version was retagged.
+	MissingVersion
+	// Outdated means the required version is several major versions behind
+	// the latest tag in the upstream repository.
+	Outdated
+)
+
+// All lists every status, ordered from most to least severe.
+var All = []Status{Missing, Archived, MissingVersion, Deprecated, UpstreamArchived, Stale, Outdated, UnsupportedGo, DisallowedLicense, NoLicense, Copyleft, Moved, Unknown}
+
+// String returns the lowercase name of the status.
+func (s Status) String() string {
+	switch s {
+	case Archived:
+		return "archived"
+	case Missing:
+		return "missing"
+	case Stale:
+		return "stale"
+	case Moved:
+		return "moved"
+	case Deprecated:
+		return "deprecated"
+	case UpstreamArchived:
+		return "upstream-archived"
+	case Unknown:
+		return "unknown"
+	case UnsupportedGo:
+		return "unsupported-go"
+	case NoLicense:
+		return "no-license"
+	case DisallowedLicense:
+		return "disallowed-license"
+	case Copyleft:
+		return "copyleft"
+	case MissingVersion:
+		return "missing-version"
+	case Outdated:
+		return "outdated"
+	}
+
+	return fmt.Sprintf("status(%d)", int(s))
+}
+
+// MarshalText encodes the status as its name.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a status from its name.
+func (s *Status) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = parsed
+
+	return nil
+}
+
+// Failing reports whether findings with this status should fail a run by
+// default. Moved repositories still resolve, unknown ones may be healthy and
+// copyleft licenses are only a warning, so all are informational.
+func (s Status) Failing() bool {
+	return s != Moved && s != Unknown && s != Copyleft
+}
+
+// Parse returns the status with the given name.
+func Parse(name string) (Status, error) {
+	for _, s := range All {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown status: %s", name)
+}
+
+// Counts tallies findings per status.
+type Counts map[Status]int
+
+// Total returns the number of findings across all statuses.
+func (c Counts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+
+	return total
+}
+
+// Failing returns the number of findings whose status fails a run.
+func (c Counts) Failing() int {
+	total := 0
+
+	for s, n := range c {
+		if s.Failing() {
+			total += n
+		}
+	}
+
+	return total
+}
+
+// String summarises the counts in severity order, e.g. "1 missing, 2 archived".
+// Statuses without findings are omitted.
+func (c Counts) String() string {
+	parts := make([]string, 0, len(All))
+
+	for _, s := range All {
+		if n := c[s]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, s))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
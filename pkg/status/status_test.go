@@ -0,0 +1,45 @@
+package status
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range All {
+		got, err := Parse(s.String())
+		require.NoError(t, err)
+		require.Equal(t, s, got)
+	}
+
+	_, err := Parse("bogus")
+	require.Error(t, err)
+}
+
+func TestStatus_JSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(Archived)
+	require.NoError(t, err)
+	require.JSONEq(t, `"archived"`, string(data))
+
+	var s Status
+
+	require.NoError(t, json.Unmarshal([]byte(`"missing"`), &s))
+	require.Equal(t, Missing, s)
+}
+
+func TestCounts(t *testing.T) {
+	t.Parallel()
+
+	counts := Counts{Archived: 2, Missing: 1, Moved: 3, Unknown: 1}
+
+	require.Equal(t, 7, counts.Total())
+	require.Equal(t, 3, counts.Failing())
+	require.Equal(t, "1 missing, 2 archived, 3 moved, 1 unknown", counts.String())
+	require.Empty(t, Counts{}.String())
+}
@@ -0,0 +1,71 @@
+// Package successor finds the replacement an archived repository points its
+// users to, such as "This project has moved to owner/new-repo", in its
+// description, homepage or README.
+package successor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+)
+
+var (
+	// phrase matches wording that introduces a replacement.
+	phrase = regexp.MustCompile(`(?i)\b(?:(?:moved|migrated|relocated|renamed|transferred)\s+(?:over\s+)?(?:to|into)|continued?\s+(?:at|in|on)|continues\s+(?:at|in|on)|superseded\s+by|replaced\s+by|succeeded\s+by|successor(?:\s+(?:is|project|repository))?|in\s+favou?r\s+of|new\s+(?:home|location|repository|repo)(?:\s+is)?|now\s+(?:lives|maintained|hosted|developed)\s+(?:at|in|on))\b`)
+	// githubURL matches a link to a GitHub repository.
+	githubURL = regexp.MustCompile(`(?:https?://)?(?:www\.)?github\.com/[A-Za-z0-9-]+/[A-Za-z0-9_.-]+`)
+	// bare matches an owner/repo reference directly after a phrase,
+	// possibly wrapped in Markdown emphasis, code or link syntax.
+	bare = regexp.MustCompile(`^[\s:*_` + "`" + `\[(]*([A-Za-z0-9-]+/[A-Za-z0-9_.-]+)`)
+)
+
+// window is how far after a phrase a link to the replacement is looked for.
+const window = 200
+
+// Find returns the repository, in the form "owner/repo", that text names as
+// the replacement of repo, or false if it names none. Only links and
+// references following wording such as "moved to" or "superseded by" are
+// considered, and references back to repo itself are ignored.
+func Find(repo, text string) (string, bool) {
+	for _, loc := range phrase.FindAllStringIndex(text, -1) {
+		rest := text[loc[1]:min(len(text), loc[1]+window)]
+
+		if para, _, ok := strings.Cut(rest, "\n\n"); ok {
+			rest = para
+		}
+
+		var candidates []string
+
+		if m := bare.FindStringSubmatch(rest); m != nil {
+			candidates = append(candidates, "github.com/"+m[1])
+		}
+
+		candidates = append(candidates, githubURL.FindAllString(rest, -1)...)
+
+		for _, candidate := range candidates {
+			if found, ok := fromURL(repo, candidate); ok {
+				return found, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// FromHomepage returns the repository a homepage links to, or false if it
+// does not link to a GitHub repository other than repo. Archived
+// repositories often point their homepage at their replacement.
+func FromHomepage(repo, homepage string) (string, bool) {
+	return fromURL(repo, homepage)
+}
+
+// fromURL returns the repository rawURL links to, unless it is repo itself.
+func fromURL(repo, rawURL string) (string, bool) {
+	found, ok := client.RepoFromURL(strings.TrimRight(rawURL, ".,;:)"))
+	if !ok || strings.EqualFold(found, repo) {
+		return "", false
+	}
+
+	return found, true
+}
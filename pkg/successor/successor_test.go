@@ -0,0 +1,48 @@
+package successor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"This project has moved to https://github.com/acme/new-repo.", "acme/new-repo"},
+		{"DEPRECATED: superseded by acme/widgets", "acme/widgets"},
+		{"Development continues at [acme/widgets](https://github.com/acme/widgets)", "acme/widgets"},
+		{"# old\n\n**This repository is archived.** Use the successor project `acme/next` instead.", "acme/next"},
+		{"Deprecated in favour of github.com/acme/better.git", "acme/better"},
+		{"The new home of this project is\nhttps://github.com/acme/home", "acme/home"},
+		{"Moved to https://gitlab.com/acme/old", ""},
+		{"A tool for moving files to and/or from S3. See github.com/acme/other", ""},
+		{"Renamed to acme/old", ""},
+		{"Moved to acme/old, see https://github.com/acme/newer", "acme/newer"},
+		{"Moved to a new location.\n\nhttps://github.com/acme/unrelated", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := Find("acme/old", tt.text)
+		require.Equal(t, tt.want != "", ok, tt.text)
+		require.Equal(t, tt.want, got, tt.text)
+	}
+}
+
+func TestFromHomepage(t *testing.T) {
+	t.Parallel()
+
+	got, ok := FromHomepage("acme/old", "https://github.com/acme/new")
+	require.True(t, ok)
+	require.Equal(t, "acme/new", got)
+
+	_, ok = FromHomepage("acme/old", "https://github.com/Acme/Old")
+	require.False(t, ok)
+
+	_, ok = FromHomepage("acme/old", "https://acme.dev")
+	require.False(t, ok)
+}
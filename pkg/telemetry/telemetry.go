@@ -0,0 +1,322 @@
+// Package telemetry keeps anonymous, aggregate usage counters for users who
+// opt in. Nothing is recorded or sent until telemetry is enabled, and only
+// counts are kept: no paths, module names, repositories or findings.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/version"
+)
+
+// FileName is the name of the telemetry state file in files.CacheDir.
+const FileName = "telemetry.json"
+
+// SendInterval is the minimum time between two reports to an endpoint.
+const SendInterval = 24 * time.Hour
+
+const (
+	// lockWait is how long a run waits for another to release the state
+	// before giving up on recording its scan.
+	lockWait = 2 * time.Second
+	// lockStale is the age after which a lock is assumed to be left behind
+	// by a run that crashed, and is broken.
+	lockStale = time.Minute
+)
+
+// ErrCorrupt is returned by Load when the state file cannot be parsed.
+var ErrCorrupt = errors.New("corrupt telemetry state")
+
+// State is the locally stored opt-in flag and the counters collected since
+// the last report.
+type State struct {
+	// Enabled is set by "arc telemetry enable".
+	Enabled bool `json:"enabled"`
+	// Since is when the current counters started.
+	Since time.Time `json:"since"`
+	// LastSent is when the counters were last reported to an endpoint.
+	LastSent time.Time `json:"last_sent"`
+	// Scans counts the scans run per command, such as "gomod" or "cargo".
+	Scans map[string]int `json:"scans"`
+	// Formats counts the scans run per output format.
+	Formats map[string]int `json:"formats"`
+}
+
+// Payload is exactly what is sent to a telemetry endpoint.
+type Payload struct {
+	Version string         `json:"version"`
+	OS      string         `json:"os"`
+	Arch    string         `json:"arch"`
+	Since   time.Time      `json:"since"`
+	Scans   map[string]int `json:"scans"`
+	Formats map[string]int `json:"formats"`
+}
+
+// Path returns the location of the telemetry state file.
+func Path() (string, error) {
+	dir, err := files.CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the state at path. A missing file is a disabled state, and one
+// that cannot be parsed returns an error wrapping ErrCorrupt.
+func Load(path string) (State, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return State{}, nil
+	}
+
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read telemetry state: %w", err)
+	}
+
+	var st State
+
+	if err := json.Unmarshal(data, &st); err != nil {
+		return State{}, fmt.Errorf("%w %s: %w", ErrCorrupt, path, err)
+	}
+
+	return st, nil
+}
+
+// Save writes the state to path, creating its directory if needed. The state
+// is written to a temporary file and renamed into place, so a run reading it
+// at the same time never sees a partial file.
+func Save(path string, st State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write telemetry state: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("failed to write telemetry state: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write telemetry state: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write telemetry state: %w", err)
+	}
+
+	return nil
+}
+
+// lock takes the lock file beside path, so concurrent runs, such as watch
+// mode next to a CI scan, update the state one at a time instead of losing
+// each other's counts. It waits up to lockWait for another run to release
+// it and returns a function releasing it.
+func lock(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockWait)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304
+		if err == nil {
+			_ = f.Close()
+
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock telemetry state: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			_ = os.Remove(lockPath)
+
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New("failed to lock telemetry state: locked by another run")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SetEnabled opts in or out. Opting out also discards the counters. A
+// corrupt state is replaced.
+func SetEnabled(path string, enabled bool, now time.Time) error {
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	st, err := Load(path)
+	if err != nil && !errors.Is(err, ErrCorrupt) {
+		return err
+	}
+
+	if enabled && !st.Enabled {
+		st = State{Enabled: true, Since: now}
+	}
+
+	if !enabled {
+		st = State{}
+	}
+
+	return Save(path, st)
+}
+
+// Payload returns the report the counters would be sent as.
+func (st State) Payload() Payload {
+	p := Payload{
+		Version: version.Get().Version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Since:   st.Since,
+		Scans:   st.Scans,
+		Formats: st.Formats,
+	}
+
+	if p.Scans == nil {
+		p.Scans = map[string]int{}
+	}
+
+	if p.Formats == nil {
+		p.Formats = map[string]int{}
+	}
+
+	return p
+}
+
+// Record counts a scan by command with the given output format, if
+// telemetry is enabled. When an endpoint is given and SendInterval has passed
+// since the last report, the counters are sent and reset. A corrupt state
+// is left alone without counting, since whether the user opted in is
+// unknown, until telemetry is enabled or disabled again.
+func Record(ctx context.Context, path, command, format string, hc *http.Client, endpoint string, now time.Time) error {
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	st, err := Load(path)
+	if err != nil || !st.Enabled {
+		return err
+	}
+
+	if st.Scans == nil {
+		st.Scans = map[string]int{}
+	}
+
+	if st.Formats == nil {
+		st.Formats = map[string]int{}
+	}
+
+	st.Scans[command]++
+	st.Formats[format]++
+
+	if endpoint != "" && now.Sub(st.LastSent) >= SendInterval {
+		if err := Send(ctx, hc, endpoint, st.Payload()); err != nil {
+			// Keep counting, the next scan retries.
+			if saveErr := Save(path, st); saveErr != nil {
+				return saveErr
+			}
+
+			return err
+		}
+
+		st = State{Enabled: true, Since: now, LastSent: now}
+	}
+
+	return Save(path, st)
+}
+
+// Send posts the payload to endpoint as JSON.
+func Send(ctx context.Context, hc *http.Client, endpoint string, p Payload) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to send telemetry: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Show writes whether telemetry is enabled, where it is sent and the exact
+// payload that would be sent.
+func Show(w io.Writer, st State, endpoint string) error {
+	state := "disabled"
+	if st.Enabled {
+		state = "enabled"
+	}
+
+	if endpoint == "" {
+		endpoint = "none, counters are kept locally"
+	}
+
+	fmt.Fprintf(w, "telemetry: %s\n", state)
+	fmt.Fprintf(w, "endpoint: %s\n", endpoint)
+	fmt.Fprintln(w, "payload:")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(st.Payload()); err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC)
+
+	// Nothing is recorded before opting in.
+	require.NoError(t, Record(ctx, path, "gomod", "text", nil, "", now))
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, State{}, st)
+
+	require.NoError(t, SetEnabled(path, true, now))
+	require.NoError(t, Record(ctx, path, "gomod", "text", nil, "", now))
+	require.NoError(t, Record(ctx, path, "cargo", "github-actions", nil, "", now))
+	require.NoError(t, Record(ctx, path, "gomod", "text", nil, "", now))
+
+	st, err = Load(path)
+	require.NoError(t, err)
+	require.True(t, st.Enabled)
+	require.Equal(t, map[string]int{"gomod": 2, "cargo": 1}, st.Scans)
+	require.Equal(t, map[string]int{"text": 2, "github-actions": 1}, st.Formats)
+
+	require.NoError(t, SetEnabled(path, false, now))
+
+	st, err = Load(path)
+	require.NoError(t, err)
+	require.Equal(t, State{}, st)
+}
+
+func TestRecord_Corrupt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC)
+
+	// A state truncated by a run that was killed while writing it.
+	require.NoError(t, os.WriteFile(path, []byte(`{"enabled": tr`), 0o600))
+
+	require.ErrorIs(t, Record(ctx, path, "gomod", "text", nil, "", now), ErrCorrupt)
+	require.NoFileExists(t, path+".lock", "the lock is released")
+
+	require.NoError(t, SetEnabled(path, true, now))
+	require.NoError(t, Record(ctx, path, "gomod", "text", nil, "", now))
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"gomod": 1}, st.Scans)
+}
+
+func TestRecord_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	now := time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, SetEnabled(path, true, now))
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 20)
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs <- Record(ctx, path, "gomod", "text", nil, "", now)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"gomod": 20}, st.Scans, "no count is lost")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary and lock files are removed")
+}
+
+func TestRecord_Send(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Date(2025, 7, 18, 12, 0, 0, 0, time.UTC)
+
+	var received []Payload
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var p Payload
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+
+		received = append(received, p)
+	}))
+	t.Cleanup(srv.Close)
+
+	require.NoError(t, SetEnabled(path, true, now))
+	require.NoError(t, Record(ctx, path, "gomod", "text", srv.Client(), srv.URL, now))
+	require.NoError(t, Record(ctx, path, "gomod", "text", srv.Client(), srv.URL, now.Add(time.Hour)))
+
+	require.Len(t, received, 1)
+	require.Equal(t, map[string]int{"gomod": 1}, received[0].Scans)
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, now, st.LastSent)
+	require.Equal(t, map[string]int{"gomod": 1}, st.Scans)
+}
+
+func TestShow(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, Show(&buf, State{}, ""))
+	require.Contains(t, buf.String(), "telemetry: disabled\n")
+	require.Contains(t, buf.String(), "endpoint: none, counters are kept locally\n")
+	require.Contains(t, buf.String(), `"scans": {}`)
+}
+
+func TestFileName_SurvivesClean(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+
+	require.NoError(t, SetEnabled(path, true, time.Now()))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "repos"), 0o750))
+
+	paths, err := files.CacheEntries(dir, FileName)
+	require.NoError(t, err)
+	require.NoError(t, files.Clean(io.Discard, paths, false))
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	require.True(t, st.Enabled, "cleaning keeps the telemetry choice")
+	require.NoDirExists(t, filepath.Join(dir, "repos"))
+}
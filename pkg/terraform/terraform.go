@@ -0,0 +1,394 @@
+// Package terraform provides commands for scanning Terraform configurations
+// for modules and providers whose source repositories are archived.
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+)
+
+// Kinds of references found in Terraform configurations.
+const (
+	// KindModule is the source of a module block.
+	KindModule = "module"
+	// KindProvider is the source of a provider in a required_providers
+	// block.
+	KindProvider = "provider"
+)
+
+// RegistryHosts are the public registries whose modules and providers are
+// published from GitHub repositories named after them. Sources on other
+// hosts, such as private registries, cannot be mapped to a repository.
+var RegistryHosts = []string{"registry.terraform.io", "registry.opentofu.org"}
+
+// Reference is a module or provider in a Terraform configuration whose source
+// repository is on GitHub.
+type Reference struct {
+	// Kind is KindModule or KindProvider.
+	Kind string
+	// Source is the module source without its query, or the provider
+	// source address.
+	Source string
+	// Version is the ref of a module source, or the version constraint of
+	// a registry module or provider, if any.
+	Version string
+	// Repo is the GitHub repository in the form "owner/repo".
+	Repo string
+	// File is the configuration file the reference was found in.
+	File string
+	// Line is the line of the reference in File.
+	Line int
+	// Column is the 1-based column of the reference on Line.
+	Column int
+}
+
+// IsConfig reports whether a file name is a Terraform configuration file.
+func IsConfig(name string) bool {
+	return strings.HasSuffix(name, ".tf")
+}
+
+// Kinds of blocks the parser keeps track of. Other blocks have no kind.
+const (
+	blockModule            = "module"
+	blockTerraform         = "terraform"
+	blockRequiredProviders = "required_providers"
+	blockProvider          = "provider"
+)
+
+var (
+	// attribute matches the string valued source and version attributes.
+	attribute = regexp.MustCompile(`\b(source|version)\s*=\s*"([^"]*)"`)
+	// legacyProvider matches a provider in required_providers given only
+	// as a version constraint, which Terraform reads as a hashicorp
+	// provider.
+	legacyProvider = regexp.MustCompile(`^\s*([A-Za-z][\w-]*)\s*=\s*"([^"]*)"\s*,?\s*$`)
+	// moduleHeader, terraformHeader, requiredProvidersHeader and
+	// providerHeader match the text before the brace opening a block.
+	moduleHeader            = regexp.MustCompile(`^\s*module\s+"[^"]*"\s*$`)
+	terraformHeader         = regexp.MustCompile(`^\s*terraform\s*$`)
+	requiredProvidersHeader = regexp.MustCompile(`^\s*required_providers\s*$`)
+	providerHeader          = regexp.MustCompile(`^\s*([A-Za-z][\w-]*)\s*=\s*$`)
+	// heredocStart matches the start of a heredoc string at the end of a
+	// line.
+	heredocStart = regexp.MustCompile(`<<-?\s*([A-Za-z_]\w*)\s*$`)
+)
+
+// block is a block the parser is in.
+type block struct {
+	kind string
+	// name is the local name of a provider.
+	name            string
+	source, version string
+	// line and column are of the source attribute, or of the block when
+	// it has none.
+	line, column int
+}
+
+// segment is the text of a line up to an unquoted brace.
+type segment struct {
+	text   string
+	offset int
+	// brace is '{', '}' or 0 for the text after the last brace.
+	brace byte
+}
+
+// segments splits a line at every brace outside of a string.
+func segments(line string) []segment {
+	var (
+		segs     []segment
+		start    int
+		inString bool
+	)
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && inString:
+			i++
+		case c == '"':
+			inString = !inString
+		case !inString && (c == '{' || c == '}'):
+			segs = append(segs, segment{line[start:i], start, c})
+			start = i + 1
+		}
+	}
+
+	return append(segs, segment{line[start:], start, 0})
+}
+
+// stripComments removes comments from a line. inBlock reports whether the
+// line starts inside a /* */ comment, and the returned bool whether the next
+// one does. Removed comments are replaced with spaces to keep columns.
+func stripComments(line string, inBlock bool) (string, bool) {
+	b := []byte(line)
+	inString := false
+
+	for i := 0; i < len(b); i++ {
+		switch {
+		case inBlock:
+			if b[i] == '*' && i+1 < len(b) && b[i+1] == '/' {
+				b[i], b[i+1] = ' ', ' '
+				inBlock = false
+				i++
+
+				continue
+			}
+
+			b[i] = ' '
+		case inString:
+			if b[i] == '\\' {
+				i++
+			} else if b[i] == '"' {
+				inString = false
+			}
+		case b[i] == '"':
+			inString = true
+		case b[i] == '#' || (b[i] == '/' && i+1 < len(b) && b[i+1] == '/'):
+			return string(b[:i]), false
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			b[i] = ' '
+			inBlock = true
+		}
+	}
+
+	return string(b), inBlock
+}
+
+// moduleRepo returns the GitHub repository of a module source, the source
+// without its query, and the ref it names. Registry modules are published
+// from repositories named terraform-<provider>-<name> in their namespace.
+// Local paths and sources elsewhere return false.
+func moduleRepo(source string) (repo, name, ref string, ok bool) {
+	name, query, _ := strings.Cut(strings.TrimPrefix(source, "git::"), "?")
+
+	if values, err := url.ParseQuery(query); err == nil {
+		ref = values.Get("ref")
+	}
+
+	if strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../") {
+		return "", "", "", false
+	}
+
+	if repo, ok := client.RepoFromURL(name); ok {
+		return repo, name, ref, true
+	}
+
+	if strings.Contains(name, "::") || strings.Contains(name, "://") {
+		return "", "", "", false
+	}
+
+	address, _, _ := strings.Cut(name, "//")
+
+	parts, ok := registryAddress(address, 3)
+	if !ok {
+		return "", "", "", false
+	}
+
+	return parts[0] + "/terraform-" + parts[2] + "-" + parts[1], name, ref, true
+}
+
+// providerRepo returns the GitHub repository of a provider source address.
+// Providers are published from repositories named terraform-provider-<type>
+// in their namespace.
+func providerRepo(source string) (string, bool) {
+	parts, ok := registryAddress(source, 2)
+	if !ok {
+		return "", false
+	}
+
+	return parts[0] + "/terraform-provider-" + parts[1], true
+}
+
+// registryAddress splits an address on a registry in RegistryHosts into its
+// n parts after the optional host.
+func registryAddress(address string, n int) ([]string, bool) {
+	parts := strings.Split(address, "/")
+
+	if len(parts) == n+1 {
+		if !slices.Contains(RegistryHosts, strings.ToLower(parts[0])) {
+			return nil, false
+		}
+
+		parts = parts[1:]
+	}
+
+	if len(parts) != n {
+		return nil, false
+	}
+
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, ".:@ ") {
+			return nil, false
+		}
+	}
+
+	return parts, true
+}
+
+// reference returns the reference of a module or provider block.
+func reference(name string, b *block) (Reference, bool) {
+	if b.kind == blockProvider {
+		source := b.source
+		if source == "" {
+			source = "hashicorp/" + b.name
+		}
+
+		repo, ok := providerRepo(source)
+		if !ok {
+			return Reference{}, false
+		}
+
+		return Reference{KindProvider, source, b.version, repo, name, b.line, b.column}, true
+	}
+
+	repo, source, ref, ok := moduleRepo(b.source)
+	if !ok {
+		return Reference{}, false
+	}
+
+	return Reference{KindModule, source, cmp.Or(ref, b.version), repo, name, b.line, b.column}, true
+}
+
+// parse returns the references in a Terraform configuration file.
+func parse(name string, data []byte) []Reference {
+	var (
+		refs    []Reference
+		stack   []*block
+		heredoc string
+		comment bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+
+		if heredoc != "" {
+			if strings.TrimSpace(line) == heredoc {
+				heredoc = ""
+			}
+
+			continue
+		}
+
+		line, comment = stripComments(line, comment)
+
+		if loc := heredocStart.FindStringSubmatchIndex(line); loc != nil {
+			heredoc = line[loc[2]:loc[3]]
+			line = line[:loc[0]]
+		}
+
+		for _, seg := range segments(line) {
+			var top *block
+			if len(stack) > 0 {
+				top = stack[len(stack)-1]
+			}
+
+			switch {
+			case top == nil:
+			case top.kind == blockModule || top.kind == blockProvider:
+				for _, m := range attribute.FindAllStringSubmatchIndex(seg.text, -1) {
+					value := seg.text[m[4]:m[5]]
+
+					if seg.text[m[2]:m[3]] == "version" {
+						top.version = value
+
+						continue
+					}
+
+					top.source, top.line, top.column = value, lineNo, seg.offset+m[4]+1
+				}
+			case top.kind == blockRequiredProviders && seg.brace != '{':
+				if m := legacyProvider.FindStringSubmatchIndex(seg.text); m != nil {
+					b := block{blockProvider, seg.text[m[2]:m[3]], "", seg.text[m[4]:m[5]], lineNo, seg.offset + m[4] + 1}
+					if ref, ok := reference(name, &b); ok {
+						refs = append(refs, ref)
+					}
+				}
+			}
+
+			switch seg.brace {
+			case '{':
+				b := &block{line: lineNo, column: seg.offset + len(seg.text) - len(strings.TrimLeft(seg.text, " \t")) + 1}
+
+				switch {
+				case top == nil && moduleHeader.MatchString(seg.text):
+					b.kind = blockModule
+				case top == nil && terraformHeader.MatchString(seg.text):
+					b.kind = blockTerraform
+				case top != nil && top.kind == blockTerraform && requiredProvidersHeader.MatchString(seg.text):
+					b.kind = blockRequiredProviders
+				case top != nil && top.kind == blockRequiredProviders:
+					if m := providerHeader.FindStringSubmatch(seg.text); m != nil {
+						b.kind, b.name = blockProvider, m[1]
+					}
+				}
+
+				stack = append(stack, b)
+			case '}':
+				if top == nil {
+					continue
+				}
+
+				stack = stack[:len(stack)-1]
+
+				if top.kind != blockModule && top.kind != blockProvider {
+					continue
+				}
+
+				if ref, ok := reference(name, top); ok {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// Discover parses the given Terraform configuration files.
+func Discover(ctx context.Context, configs []string) []Reference {
+	var refs []Reference
+
+	for _, name := range configs {
+		data, err := os.ReadFile(name) // #nosec G304
+		if err != nil {
+			slog.DebugContext(ctx, fmt.Sprintf("could not open %s: %v", name, err))
+
+			continue
+		}
+
+		refs = append(refs, parse(name, data)...)
+	}
+
+	return refs
+}
+
+// Repos returns the repositories of the modules and providers referenced by
+// Terraform configurations in opts.Scope.
+func Repos(ctx context.Context, opts gomod.Options) (map[string][]gomod.RepoInfo, error) {
+	configs, err := files.RecursiveMatch(ctx, opts.Scope, IsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Terraform configurations: %w", err)
+	}
+
+	repos := map[string][]gomod.RepoInfo{}
+
+	for _, ref := range Discover(ctx, configs) {
+		repos[ref.Repo] = append(repos[ref.Repo], gomod.NewRepoInfo(ref.File, ref.Line, ref.Column, ref.Source, ref.Version, false))
+	}
+
+	return repos, nil
+}
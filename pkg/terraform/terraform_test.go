@@ -0,0 +1,108 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	data := `terraform {
+  required_version = ">= 1.5"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+    github = { source = "integrations/github", version = "6.2.1" }
+    private = {
+      source = "app.terraform.io/acme/private"
+    }
+    random = "~> 3.0"
+  }
+}
+
+# module "commented" { source = "github.com/ignored/repo" }
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.0"
+
+  tags = {
+    Name = "${var.name}-vpc"
+  }
+}
+
+module "network" {
+  source = "git::https://github.com/owner/network.git//modules/vpc?ref=v1.2.0"
+}
+
+module "local" {
+  source = "./modules/local"
+}
+
+resource "aws_iam_policy" "policy" {
+  policy = <<EOF
+{
+  "source": "github.com/ignored/heredoc"
+}
+EOF
+}
+
+/* module "block" {
+  source = "github.com/ignored/block"
+} */
+
+module "ssh" {
+  source = "git@github.com:owner/ssh.git"
+}
+`
+
+	require.Equal(t, []Reference{
+		{KindProvider, "hashicorp/aws", "~> 5.0", "hashicorp/terraform-provider-aws", "main.tf", 6, 18},
+		{KindProvider, "integrations/github", "6.2.1", "integrations/terraform-provider-github", "main.tf", 9, 26},
+		{KindProvider, "hashicorp/random", "~> 3.0", "hashicorp/terraform-provider-random", "main.tf", 13, 15},
+		{KindModule, "terraform-aws-modules/vpc/aws", "5.1.0", "terraform-aws-modules/terraform-aws-vpc", "main.tf", 20, 14},
+		{KindModule, "https://github.com/owner/network.git//modules/vpc", "v1.2.0", "owner/network", "main.tf", 29, 13},
+		{KindModule, "git@github.com:owner/ssh.git", "", "owner/ssh", "main.tf", 49, 13},
+	}, parse("main.tf", []byte(data)))
+}
+
+func TestModuleRepo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source string
+		repo   string
+		ok     bool
+	}{
+		{"github.com/owner/repo", "owner/repo", true},
+		{"github.com/owner/repo//sub?ref=v1", "owner/repo", true},
+		{"registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster", "hashicorp/terraform-aws-consul", true},
+		{"app.terraform.io/acme/network/aws", "", false},
+		{"git::https://gitlab.com/owner/repo.git", "", false},
+		{"s3::https://s3.amazonaws.com/bucket/module.zip", "", false},
+		{"../shared", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			t.Parallel()
+
+			repo, _, _, ok := moduleRepo(tt.source)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.repo, repo)
+		})
+	}
+}
+
+func TestIsConfig(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsConfig("main.tf"))
+	require.False(t, IsConfig("terraform.tfvars"))
+	require.False(t, IsConfig("main.tf.json"))
+}
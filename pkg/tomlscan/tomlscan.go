@@ -0,0 +1,93 @@
+// Package tomlscan reads TOML documents line by line, keeping the line numbers
+// that a full decoder discards. It understands the subset of TOML used by
+// package manifests and lock files: table headers, string values, inline
+// tables and arrays of strings.
+package tomlscan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Field is a `key = "value"` pair.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// stringField matches `key = "value"` pairs, including inside inline tables.
+var stringField = regexp.MustCompile(`([A-Za-z_-]+)\s*=\s*"([^"]*)"`)
+
+// quoted matches double or single quoted strings.
+var quoted = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// TableKeys splits a table header such as [target.'cfg(unix)'.dependencies]
+// into its keys, removing brackets and quotes.
+func TableKeys(header string) []string {
+	var (
+		keys  []string
+		key   strings.Builder
+		quote rune
+	)
+
+	for _, r := range strings.Trim(strings.TrimSpace(header), "[] ") {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			key.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			keys = append(keys, strings.TrimSpace(key.String()))
+			key.Reset()
+		default:
+			key.WriteRune(r)
+		}
+	}
+
+	return append(keys, strings.TrimSpace(key.String()))
+}
+
+// StringFields returns the string valued fields in s, such as a line or an
+// inline table.
+func StringFields(s string) []Field {
+	var fields []Field
+
+	for _, m := range stringField.FindAllStringSubmatch(s, -1) {
+		fields = append(fields, Field{Key: m[1], Value: m[2]})
+	}
+
+	return fields
+}
+
+// Strings returns every quoted string in s, such as the elements of an array.
+func Strings(s string) []string {
+	var values []string
+
+	for _, m := range quoted.FindAllStringSubmatch(s, -1) {
+		values = append(values, m[1]+m[2])
+	}
+
+	return values
+}
+
+// StripComment removes a trailing comment from line, ignoring # characters
+// inside quoted strings.
+func StripComment(line string) string {
+	var quote rune
+
+	for i, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,36 @@
+package tomlscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableKeys(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"dependencies"}, TableKeys("[dependencies]"))
+	require.Equal(t, []string{"target", "cfg(unix)", "dependencies"}, TableKeys("[target.'cfg(unix)'.dependencies]"))
+	require.Equal(t, []string{"package"}, TableKeys("[[package]]"))
+	require.Equal(t, []string{"tool", "poetry", "group", "a.b", "dependencies"}, TableKeys(`[tool.poetry.group."a.b".dependencies]`))
+}
+
+func TestStringFields(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []Field{{"version", "1"}, {"git", "https://github.com/o/r"}}, StringFields(`{ version = "1", git = "https://github.com/o/r", features = ["x"] }`))
+	require.Empty(t, StringFields("workspace = true"))
+}
+
+func TestStrings(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"requests>=2", "rich"}, Strings(`dependencies = ["requests>=2", 'rich']`))
+}
+
+func TestStripComment(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `url = "https://example.com/#frag"`, StripComment(`url = "https://example.com/#frag" # comment`))
+	require.Empty(t, StripComment("# comment"))
+}
@@ -0,0 +1,381 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// Filters that are not a status.
+const (
+	FilterAll       = "all"
+	FilterUnhealthy = "unhealthy"
+	FilterHealthy   = "healthy"
+)
+
+// Msg is an event the model reacts to: a Key, a Checked repository, Done or
+// a Resize.
+type Msg any
+
+// Key is a key press, named like "up", "enter" or "ctrl+c", or the
+// character typed.
+type Key string
+
+// Checked is a repository that has been looked up.
+type Checked gomod.CheckedRepo
+
+// Done is sent once the scan has finished, with its error if it failed.
+type Done struct {
+	Err error
+}
+
+// Resize is sent when the size of the terminal is known or changes.
+type Resize struct {
+	Width  int
+	Height int
+}
+
+// Model is the state of the interface. Update returns a new model for every
+// event and View renders it, so both can be tested without a terminal.
+type Model struct {
+	items  []gomod.CheckedRepo
+	filter string
+	// cursor is the selected row among the visible items, and offset the
+	// first visible row drawn.
+	cursor int
+	offset int
+	detail bool
+	done   bool
+	err    error
+	width  int
+	height int
+}
+
+// New returns the model of a scan that has not checked anything yet.
+func New() Model {
+	return Model{filter: FilterAll, width: 80, height: 24}
+}
+
+// itemStatus returns the filter an item belongs to besides FilterAll and
+// FilterUnhealthy: the most severe status of its findings, or FilterHealthy.
+func itemStatus(item gomod.CheckedRepo) string {
+	if len(item.Findings) == 0 {
+		return FilterHealthy
+	}
+
+	st := item.Findings[0].Status
+
+	for _, f := range item.Findings[1:] {
+		if slices.Index(status.All, f.Status) < slices.Index(status.All, st) {
+			st = f.Status
+		}
+	}
+
+	return st.String()
+}
+
+// matches reports whether item is shown with filter.
+func matches(item gomod.CheckedRepo, filter string) bool {
+	switch filter {
+	case FilterAll:
+		return true
+	case FilterUnhealthy:
+		return len(item.Findings) > 0
+	}
+
+	return itemStatus(item) == filter
+}
+
+// Filters returns the filters that can be cycled through: every item,
+// unhealthy and healthy ones, and each status found so far from most to
+// least severe.
+func (m Model) Filters() []string {
+	filters := []string{FilterAll, FilterUnhealthy, FilterHealthy}
+
+	for _, st := range status.All {
+		if slices.ContainsFunc(m.items, func(item gomod.CheckedRepo) bool { return itemStatus(item) == st.String() }) {
+			filters = append(filters, st.String())
+		}
+	}
+
+	return filters
+}
+
+// visible returns the items shown with the current filter, in the order
+// they were checked.
+func (m Model) visible() []gomod.CheckedRepo {
+	var items []gomod.CheckedRepo
+
+	for _, item := range m.items {
+		if matches(item, m.filter) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// listHeight is the number of rows available to the list, below the header
+// and above the footer.
+func (m Model) listHeight() int {
+	return max(m.height-3, 1)
+}
+
+// Update applies msg and returns the new model, and whether the interface
+// should quit.
+func (m Model) Update(msg Msg) (Model, bool) {
+	switch msg := msg.(type) {
+	case Checked:
+		m.items = append(m.items, gomod.CheckedRepo(msg))
+	case Done:
+		m.done, m.err = true, msg.Err
+	case Resize:
+		m.width, m.height = msg.Width, msg.Height
+	case Key:
+		return m.key(msg)
+	}
+
+	return m.clamp(), false
+}
+
+// key handles a key press.
+func (m Model) key(k Key) (Model, bool) {
+	switch k {
+	case "q", "ctrl+c":
+		return m, true
+	case "esc", "backspace", "left", "h":
+		m.detail = false
+	case "enter", "right", "l":
+		m.detail = len(m.visible()) > 0
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "pgup":
+		m.cursor -= m.listHeight()
+	case "pgdown":
+		m.cursor += m.listHeight()
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.visible()) - 1
+	case "tab", "f":
+		m = m.cycleFilter(1)
+	case "backtab", "F":
+		m = m.cycleFilter(-1)
+	}
+
+	return m.clamp(), false
+}
+
+// cycleFilter selects the filter delta positions after the current one and
+// moves the cursor to the top of the list.
+func (m Model) cycleFilter(delta int) Model {
+	filters := m.Filters()
+
+	i := slices.Index(filters, m.filter)
+	m.filter = filters[(i+delta+len(filters))%len(filters)]
+	m.cursor, m.offset, m.detail = 0, 0, false
+
+	return m
+}
+
+// clamp keeps the cursor on a visible item and scrolls the list to it.
+func (m Model) clamp() Model {
+	n := len(m.visible())
+
+	m.cursor = max(min(m.cursor, n-1), 0)
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+
+	if h := m.listHeight(); m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+
+	return m
+}
+
+// View renders the list, or the details of the selected item, as lines
+// that fit the terminal.
+func (m Model) View() []string {
+	var lines []string
+
+	if m.detail {
+		lines = m.detailView()
+	} else {
+		lines = m.listView()
+	}
+
+	// Details longer than the terminal lose their last lines, but never
+	// the footer.
+	if m.height > 1 && len(lines) > m.height {
+		lines = append(lines[:m.height-1], lines[len(lines)-1])
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(line, m.width)
+	}
+
+	return lines
+}
+
+// header summarizes the scan.
+func (m Model) header() string {
+	state := "checking..."
+
+	switch {
+	case m.err != nil:
+		state = "failed: " + m.err.Error()
+	case m.done:
+		state = "done"
+	}
+
+	unhealthy := 0
+
+	for _, item := range m.items {
+		if len(item.Findings) > 0 {
+			unhealthy++
+		}
+	}
+
+	return fmt.Sprintf("gh-arc: %d checked, %d unhealthy, %s  [filter: %s]", len(m.items), unhealthy, state, m.filter)
+}
+
+// listView renders the header, one row per visible item and the footer,
+// padded to the height of the terminal.
+func (m Model) listView() []string {
+	lines := []string{m.header()}
+
+	items := m.visible()
+	h := m.listHeight()
+
+	for i := m.offset; i < len(items) && i < m.offset+h; i++ {
+		marker := "  "
+		if i == m.cursor {
+			marker = "> "
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%-18s %s", marker, itemStatus(items[i]), items[i].Repo))
+	}
+
+	if len(items) == 0 {
+		lines = append(lines, "  no dependencies match the filter")
+	}
+
+	for len(lines) < h+1 {
+		lines = append(lines, "")
+	}
+
+	return append(lines, "", "up/down move, enter details, tab filter, q quit")
+}
+
+// detailView renders everything known about the selected item.
+func (m Model) detailView() []string {
+	items := m.visible()
+	if len(items) == 0 {
+		return m.listView()
+	}
+
+	item := items[m.cursor]
+	result := item.Result
+
+	lines := []string{item.Repo + " (" + itemStatus(item) + ")", ""}
+
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, f := range item.Findings {
+		if !seen[f.Reason] {
+			seen[f.Reason] = true
+			add("Finding", f.Reason)
+		}
+	}
+
+	add("Modules", strings.Join(item.Modules, ", "))
+	add("Description", result.Description)
+	add("Homepage", result.Homepage)
+	add("Topics", strings.Join(result.Topics, ", "))
+	add("Last push", result.PushedAt)
+	add("Default branch", result.DefaultBranch)
+
+	if result.FullName != "" && !result.Degraded {
+		add("Open issues and pull requests", fmt.Sprint(result.OpenIssues))
+	}
+
+	if result.LatestRelease != nil {
+		add("Latest release", strings.TrimSpace(result.LatestRelease.TagName+" "+result.LatestRelease.PublishedAt))
+	}
+
+	if result.License != nil {
+		add("License", result.License.SPDXID)
+	}
+
+	for _, f := range item.Findings {
+		if f.Successor != "" {
+			add("Successor", f.Successor)
+
+			break
+		}
+	}
+
+	var advisories []string
+
+	for _, f := range item.Findings {
+		for _, a := range f.Advisories {
+			if seen[a.ID] {
+				continue
+			}
+
+			seen[a.ID] = true
+
+			line := fmt.Sprintf("  %s (%s) %s", a.ID, a.Severity, a.Summary)
+			if a.FirstPatched != "" {
+				line += ", fixed in " + a.FirstPatched
+			}
+
+			advisories = append(advisories, line)
+		}
+	}
+
+	if len(advisories) > 0 {
+		lines = append(lines, "", "Advisories:")
+		lines = append(lines, advisories...)
+	}
+
+	lines = append(lines, "", "Required by:")
+
+	for _, req := range item.Requirements {
+		location := req.GoModPath
+		if req.Line > 0 {
+			location += fmt.Sprintf(":%d", req.Line)
+		}
+
+		lines = append(lines, strings.TrimRight("  "+location+" "+req.Version, " "))
+	}
+
+	return append(lines, "", "esc back, q quit")
+}
+
+// truncate cuts line to at most width characters.
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+
+	return string(runes[:width])
+}
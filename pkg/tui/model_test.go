@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/advisory"
+	"github.com/wayneashleyberry/gh-arc/pkg/client"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/gomod"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+// update applies msgs to m in order.
+func update(t *testing.T, m Model, msgs ...Msg) Model {
+	t.Helper()
+
+	for _, msg := range msgs {
+		var quit bool
+
+		m, quit = m.Update(msg)
+		require.False(t, quit)
+	}
+
+	return m
+}
+
+var (
+	archived = Checked{
+		Repo:         "owner/archived",
+		Modules:      []string{"github.com/owner/archived"},
+		Requirements: []gomod.Requirement{{GoModPath: "go.mod", Line: 4, Version: "v1.0.0"}},
+		Result: client.RepoResult{
+			Archived:      true,
+			FullName:      "owner/archived",
+			Description:   "An old library",
+			PushedAt:      "2020-01-01T00:00:00Z",
+			LatestRelease: &client.Release{TagName: "v1.0.0", PublishedAt: "2019-12-01T00:00:00Z"},
+		},
+		Findings: []finding.Finding{{
+			Repo:       "owner/archived",
+			Status:     status.Archived,
+			Reason:     "repository archived",
+			Advisories: []advisory.Advisory{{ID: "GHSA-1234", Severity: "high", Summary: "Remote code execution", FirstPatched: "v1.0.1"}},
+		}},
+	}
+	healthy = Checked{
+		Repo:         "owner/healthy",
+		Modules:      []string{"github.com/owner/healthy"},
+		Requirements: []gomod.Requirement{{GoModPath: "go.mod", Line: 5, Version: "v1.0.0"}},
+		Result:       client.RepoResult{FullName: "owner/healthy"},
+	}
+	stale = Checked{
+		Repo:     "owner/stale",
+		Findings: []finding.Finding{{Repo: "owner/stale", Status: status.Stale, Reason: "no push for longer than 8760h0m0s"}},
+	}
+)
+
+func TestModel_List(t *testing.T) {
+	t.Parallel()
+
+	m := update(t, New(), Resize{60, 7}, archived, healthy)
+
+	require.Equal(t, []string{
+		"gh-arc: 2 checked, 1 unhealthy, checking...  [filter: all]",
+		"> archived           owner/archived",
+		"  healthy            owner/healthy",
+		"",
+		"",
+		"",
+		"up/down move, enter details, tab filter, q quit",
+	}, m.View())
+
+	m = update(t, m, stale, Done{}, Key("down"), Key("down"), Key("down"))
+
+	require.Equal(t, []string{
+		"gh-arc: 3 checked, 2 unhealthy, done  [filter: all]",
+		"  archived           owner/archived",
+		"  healthy            owner/healthy",
+		"> stale              owner/stale",
+		"",
+		"",
+		"up/down move, enter details, tab filter, q quit",
+	}, m.View())
+
+	m = update(t, m, Done{Err: errors.New("boom")})
+	require.Equal(t, "gh-arc: 3 checked, 2 unhealthy, failed: boom  [filter: all]", m.View()[0])
+
+	_, quit := m.Update(Key("q"))
+	require.True(t, quit)
+}
+
+func TestModel_Scroll(t *testing.T) {
+	t.Parallel()
+
+	m := update(t, New(), Resize{60, 5}, archived, healthy, stale, Key("end"))
+
+	require.Equal(t, []string{
+		"gh-arc: 3 checked, 2 unhealthy, checking...  [filter: all]",
+		"  healthy            owner/healthy",
+		"> stale              owner/stale",
+		"",
+		"up/down move, enter details, tab filter, q quit",
+	}, m.View())
+}
+
+func TestModel_Filter(t *testing.T) {
+	t.Parallel()
+
+	m := update(t, New(), Resize{40, 6}, archived, healthy, stale)
+	require.Equal(t, []string{FilterAll, FilterUnhealthy, FilterHealthy, "archived", "stale"}, m.Filters())
+
+	m = update(t, m, Key("tab"))
+	require.Equal(t, []string{
+		"gh-arc: 3 checked, 2 unhealthy, checking",
+		"> archived           owner/archived",
+		"  stale              owner/stale",
+		"",
+		"",
+		"up/down move, enter details, tab filter,",
+	}, m.View())
+
+	m = update(t, m, Key("tab"), Key("tab"), Key("tab"))
+	require.Equal(t, "> stale              owner/stale", m.View()[1])
+	require.Equal(t, "", m.View()[2])
+
+	m = update(t, m, Key("tab"))
+	require.Equal(t, FilterAll, m.filter)
+
+	m = update(t, m, Key("backtab"))
+	require.Equal(t, "stale", m.filter)
+}
+
+func TestModel_Detail(t *testing.T) {
+	t.Parallel()
+
+	m := update(t, New(), Resize{80, 40}, archived, healthy, Key("enter"))
+
+	require.Equal(t, []string{
+		"owner/archived (archived)",
+		"",
+		"Finding: repository archived",
+		"Modules: github.com/owner/archived",
+		"Description: An old library",
+		"Last push: 2020-01-01T00:00:00Z",
+		"Open issues and pull requests: 0",
+		"Latest release: v1.0.0 2019-12-01T00:00:00Z",
+		"",
+		"Advisories:",
+		"  GHSA-1234 (high) Remote code execution, fixed in v1.0.1",
+		"",
+		"Required by:",
+		"  go.mod:4 v1.0.0",
+		"",
+		"esc back, q quit",
+	}, m.View())
+
+	m = update(t, m, Key("esc"), Key("down"), Key("enter"))
+	require.Equal(t, "owner/healthy (healthy)", m.View()[0])
+
+	m = update(t, m, Resize{80, 5})
+	require.Equal(t, []string{
+		"owner/healthy (healthy)",
+		"",
+		"Modules: github.com/owner/healthy",
+		"Open issues and pull requests: 0",
+		"esc back, q quit",
+	}, m.View())
+}
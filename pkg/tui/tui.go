@@ -0,0 +1,234 @@
+// Package tui is an interactive terminal interface listing dependencies as
+// they are checked. Like an Elm architecture, events update an immutable
+// Model that is rendered to the whole screen after every change, so the
+// interface needs nothing beyond raw terminal mode and ANSI escape
+// sequences.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences controlling the screen.
+const (
+	altScreenOn  = "\033[?1049h"
+	altScreenOff = "\033[?1049l"
+	cursorHide   = "\033[?25l"
+	cursorShow   = "\033[?25h"
+	clearScreen  = "\033[H\033[2J"
+)
+
+// resizeInterval is how often the terminal size is polled, which works on
+// every platform unlike SIGWINCH.
+const resizeInterval = 250 * time.Millisecond
+
+// Scan checks dependencies, calling send with every repository as soon as
+// it has been looked up.
+type Scan func(ctx context.Context, send func(Checked)) error
+
+// Run draws the interface on out, reads keys from in, which must both be
+// terminals, and runs scan until the user quits. Quitting cancels the scan.
+// It returns the scan's error, unless the user quit before it finished.
+func Run(ctx context.Context, in, out *os.File, scan Scan) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return errors.New("the interactive interface requires a terminal")
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to enable raw terminal mode: %w", err)
+	}
+
+	defer func() { _ = term.Restore(int(in.Fd()), state) }()
+
+	fmt.Fprint(out, altScreenOn+cursorHide)
+	defer fmt.Fprint(out, cursorShow+altScreenOff)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan Msg)
+
+	send := func(msg Msg) {
+		select {
+		case msgs <- msg:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		err := scan(ctx, func(c Checked) { send(c) })
+		send(Done{Err: err})
+	}()
+
+	// Reading stdin cannot be interrupted, so the reader is left blocked
+	// once the interface quits. The process exits soon after.
+	go readKeys(in, send)
+
+	m := New()
+
+	if w, h, err := term.GetSize(int(out.Fd())); err == nil {
+		m, _ = m.Update(Resize{w, h})
+	}
+
+	ticker := time.NewTicker(resizeInterval)
+	defer ticker.Stop()
+
+	var scanErr error
+
+	draw(out, m)
+
+	for {
+		var msg Msg
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-ticker.C:
+			w, h, err := term.GetSize(int(out.Fd()))
+			if err != nil || (w == m.width && h == m.height) {
+				continue
+			}
+
+			msg = Resize{w, h}
+		case msg = <-msgs:
+		}
+
+		if done, ok := msg.(Done); ok {
+			scanErr = done.Err
+		}
+
+		var quit bool
+
+		m, quit = m.Update(msg)
+		if quit {
+			if !m.done {
+				return nil
+			}
+
+			return scanErr
+		}
+
+		draw(out, m)
+	}
+}
+
+// draw renders m to the whole screen. Raw mode does not translate newlines,
+// so lines end with a carriage return too.
+func draw(out *os.File, m Model) {
+	fmt.Fprint(out, clearScreen+strings.Join(m.View(), "\r\n"))
+}
+
+// readKeys sends every key read from in until reading fails.
+func readKeys(in *os.File, send func(Msg)) {
+	buf := make([]byte, 64)
+
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for _, k := range ParseKeys(buf[:n]) {
+			send(k)
+		}
+	}
+}
+
+// escapeKeys maps the escape sequences terminals send for special keys to
+// their names.
+var escapeKeys = map[string]Key{
+	"\033[A":  "up",
+	"\033[B":  "down",
+	"\033[C":  "right",
+	"\033[D":  "left",
+	"\033OA":  "up",
+	"\033OB":  "down",
+	"\033OC":  "right",
+	"\033OD":  "left",
+	"\033[H":  "home",
+	"\033[F":  "end",
+	"\033[1~": "home",
+	"\033[4~": "end",
+	"\033[5~": "pgup",
+	"\033[6~": "pgdown",
+	"\033[Z":  "backtab",
+}
+
+// ParseKeys splits input read from a terminal in raw mode into keys.
+// Unrecognized escape sequences are dropped.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+
+	s := string(b)
+
+	for len(s) > 0 {
+		if s[0] == '\033' {
+			if len(s) == 1 {
+				keys = append(keys, "esc")
+
+				break
+			}
+
+			matched := false
+
+			for seq, k := range escapeKeys {
+				if strings.HasPrefix(s, seq) {
+					keys = append(keys, k)
+					s = s[len(seq):]
+					matched = true
+
+					break
+				}
+			}
+
+			if matched {
+				continue
+			}
+
+			if s[1] != '[' && s[1] != 'O' {
+				keys = append(keys, "esc")
+				s = s[1:]
+
+				continue
+			}
+
+			// Skip an unknown sequence up to its final byte.
+			end := strings.IndexFunc(s[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+			if end < 0 {
+				break
+			}
+
+			s = s[end+3:]
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		default:
+			keys = append(keys, Key(string(r)))
+		}
+
+		s = s[size:]
+	}
+
+	return keys
+}
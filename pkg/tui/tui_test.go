@@ -0,0 +1,15 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []Key{"up", "down", "enter", "tab", "backtab", "q", "pgdown", "esc"}, ParseKeys([]byte("\033[A\033OB\r\t\033[Zq\033[6~\033")))
+	require.Equal(t, []Key{"j", "ctrl+c"}, ParseKeys([]byte("\033[2;5Xj\x03")))
+	require.Equal(t, []Key{"esc", "k"}, ParseKeys([]byte("\033k")))
+}
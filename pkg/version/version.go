@@ -0,0 +1,79 @@
+// Package version exposes build metadata for the arc binary. Values are
+// injected at build time with ldflags, for example:
+//
+//	go build -ldflags "-X github.com/wayneashleyberry/gh-arc/pkg/version.Version=v1.2.3"
+//
+// and otherwise fall back to what the Go toolchain recorded in the binary.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata set via ldflags.
+var (
+	Version = ""
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes the running binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build metadata of the running binary. Values not injected
+// via ldflags are read from the embedded build info when available.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+
+	return info
+}
+
+// String formats the build metadata on a single line.
+func (i Info) String() string {
+	s := "arc " + i.Version
+
+	if i.Commit != "" {
+		s += " (" + i.Commit + ")"
+	}
+
+	if i.Date != "" {
+		s += " built " + i.Date
+	}
+
+	return fmt.Sprintf("%s %s", s, i.GoVersion)
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo_String(t *testing.T) {
+	t.Parallel()
+
+	info := Info{Version: "v1.2.3", Commit: "abc123", Date: "2025-07-18T12:00:00Z", GoVersion: "go1.24.5"}
+	require.Equal(t, "arc v1.2.3 (abc123) built 2025-07-18T12:00:00Z go1.24.5", info.String())
+
+	info = Info{Version: "dev", GoVersion: "go1.24.5"}
+	require.Equal(t, "arc dev go1.24.5", info.String())
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	info := Get()
+	require.NotEmpty(t, info.Version)
+	require.NotEmpty(t, info.GoVersion)
+}
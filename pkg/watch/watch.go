@@ -0,0 +1,196 @@
+// Package watch re-runs a scan whenever Go module files change and reports
+// the findings that were introduced or resolved since the previous scan.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
+	"github.com/wayneashleyberry/gh-arc/pkg/diff"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+)
+
+// DefaultInterval is how long module files must stop changing before they
+// are scanned again, so saving several files at once runs a single scan.
+const DefaultInterval = 2 * time.Second
+
+// Names are the base names of the files watched for changes.
+var Names = []string{"go.mod", "go.sum", "go.work", "go.work.sum"}
+
+// Scan runs a scan without rendering its findings.
+type Scan func(ctx context.Context) (finding.Report, error)
+
+// state identifies a version of a watched file.
+type state struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshot returns the size and modification time of every watched file in
+// scope, keyed by path. The search is left out of the audit log, since it
+// repeats on every change and the scan records the files it reads itself.
+func snapshot(ctx context.Context, scope files.Scope) (map[string]state, error) {
+	paths, err := files.RecursiveMatch(audit.WithLog(ctx, nil), scope, func(name string) bool {
+		return slices.Contains(Names, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find module files: %w", err)
+	}
+
+	found := make(map[string]state, len(paths))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			// The file was removed after it was found, which the next
+			// snapshot picks up.
+			continue
+		}
+
+		found[path] = state{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	return found, nil
+}
+
+// dirs returns the directories to subscribe to: the roots of scope, where
+// new modules appear, and every directory holding a module file.
+func dirs(scope files.Scope, found map[string]state) []string {
+	roots := scope.Paths
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	dirs := slices.Clone(roots)
+
+	for path := range found {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+
+	slices.Sort(dirs)
+
+	return slices.Compact(dirs)
+}
+
+// relevant reports whether event changed a module file.
+func relevant(event fsnotify.Event) bool {
+	return slices.Contains(Names, filepath.Base(event.Name)) && event.Op != fsnotify.Chmod
+}
+
+// Watch subscribes to changes of the module files in scope and, once they
+// have stopped changing for interval, runs scan if any were added, removed
+// or modified and writes the findings added and resolved since the previous
+// report to w. Scans that fail are logged and retried on the next change,
+// since module files are often briefly invalid while being edited. Watch
+// returns when ctx is done.
+func Watch(ctx context.Context, w io.Writer, scope files.Scope, interval time.Duration, previous finding.Report, scan Scan) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch module files: %w", err)
+	}
+
+	defer watcher.Close()
+
+	last, err := snapshot(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	subscribe := func(found map[string]state) {
+		for _, dir := range dirs(scope, found) {
+			if err := watcher.Add(dir); err != nil {
+				slog.DebugContext(ctx, fmt.Sprintf("failed to watch %s: %v", dir, err))
+			}
+		}
+	}
+
+	subscribe(last)
+
+	fmt.Fprintf(w, "watching %d module files for changes\n", len(last))
+
+	// settle fires once events have stopped arriving for interval.
+	settle := time.NewTimer(interval)
+	settle.Stop()
+
+	defer settle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			slog.DebugContext(ctx, fmt.Sprintf("error watching module files: %v", err))
+
+			continue
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if relevant(event) {
+				settle.Reset(interval)
+			}
+
+			// Module files may be added to a new directory, or may have
+			// been before it was subscribed to.
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() && event.Has(fsnotify.Create) {
+				if err := watcher.Add(event.Name); err != nil {
+					slog.DebugContext(ctx, fmt.Sprintf("failed to watch %s: %v", event.Name, err))
+				}
+
+				settle.Reset(interval)
+			}
+
+			continue
+		case <-settle.C:
+		}
+
+		current, err := snapshot(ctx, scope)
+		if err != nil {
+			return err
+		}
+
+		subscribe(current)
+
+		if maps.Equal(last, current) {
+			continue
+		}
+
+		last = current
+
+		slog.DebugContext(ctx, "module files changed, scanning again")
+
+		report, err := scan(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			fmt.Fprintf(w, "\nscan failed: %v\n", err)
+
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s\n", time.Now().Format(time.TimeOnly))
+
+		if err := diff.Text(w, diff.Compare(previous, report)); err != nil {
+			return err
+		}
+
+		previous = report
+	}
+}
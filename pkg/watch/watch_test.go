@@ -0,0 +1,126 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wayneashleyberry/gh-arc/pkg/audit"
+	"github.com/wayneashleyberry/gh-arc/pkg/files"
+	"github.com/wayneashleyberry/gh-arc/pkg/finding"
+	"github.com/wayneashleyberry/gh-arc/pkg/status"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	goMod := filepath.Join(dir, "go.mod")
+	require.NoError(t, os.WriteFile(goMod, []byte("module example.com/app\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stale := finding.Finding{Module: "github.com/a/stale", Repo: "a/stale", File: "go.mod", Status: status.Stale, Reason: "stale"}
+	archived := finding.Finding{Module: "github.com/a/old", Repo: "a/old", File: "go.mod", Status: status.Archived, Reason: "archived"}
+
+	// Keep editing go.mod until a scan runs, since the first edit may land
+	// before Watch takes its initial snapshot.
+	done := make(chan struct{})
+
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			_ = os.WriteFile(goMod, []byte("module example.com/app\n"+strings.Repeat("\n", i)), 0o600)
+		}
+	}()
+
+	scans := 0
+	scan := func(context.Context) (finding.Report, error) {
+		scans++
+
+		close(done)
+		cancel()
+
+		return finding.Report{Findings: []finding.Finding{archived}}, nil
+	}
+
+	var out bytes.Buffer
+
+	err := Watch(ctx, &out, files.Scope{Paths: []string{dir}}, 5*time.Millisecond, finding.Report{Findings: []finding.Finding{stale}}, scan)
+	require.NoError(t, err)
+	require.Equal(t, 1, scans)
+	require.Contains(t, out.String(), "watching 1 module files for changes\n")
+	require.Contains(t, out.String(), "+ go.mod: https://github.com/a/old (archived)\n")
+	require.Contains(t, out.String(), "- go.mod: https://github.com/a/stale (stale)\n")
+	require.Contains(t, out.String(), "1 new, 1 resolved\n")
+}
+
+func TestWatch_NewModule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0o600))
+
+	var log bytes.Buffer
+
+	ctx, cancel := context.WithCancel(audit.WithLog(context.Background(), audit.New(&log)))
+	defer cancel()
+
+	// Keep adding modules in new directories until a scan runs, since the
+	// first may be added before Watch subscribes to changes.
+	done := make(chan struct{})
+
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			sub := filepath.Join(dir, fmt.Sprintf("svc%d", i))
+			_ = os.Mkdir(sub, 0o750)
+			_ = os.WriteFile(filepath.Join(sub, "go.mod"), []byte("module example.com/svc\n"), 0o600)
+		}
+	}()
+
+	scan := func(context.Context) (finding.Report, error) {
+		close(done)
+		cancel()
+
+		return finding.Report{}, nil
+	}
+
+	var out bytes.Buffer
+
+	require.NoError(t, Watch(ctx, &out, files.Scope{Paths: []string{dir}}, 5*time.Millisecond, finding.Report{}, scan))
+	require.Contains(t, out.String(), "0 new, 0 resolved\n")
+	require.Empty(t, log.String(), "finding changed files is not audited")
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "go.sum", "main.go", filepath.Join("sub", "go.mod")} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600))
+	}
+
+	got, err := snapshot(context.Background(), files.Scope{Paths: []string{dir}})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	require.NotContains(t, got, filepath.Join(dir, "main.go"))
+}